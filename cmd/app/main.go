@@ -1,21 +1,69 @@
 package main
 
 import (
+	"context"
 	"log"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"filmoteka/config"
 	"filmoteka/internal/app"
 )
 
 func main() {
+	// Управление миграциями: app migrate up|down|goto|force|status
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		migrate(os.Args[2:])
+		return
+	}
+
+	// Проверка готовности запущенного сервера для healthcheck контейнера: app healthcheck
+	if len(os.Args) > 1 && os.Args[1] == "healthcheck" {
+		healthcheck()
+		return
+	}
+
 	// Configuration
 	cfg, err := config.NewConfig()
 	if err != nil {
 		log.Fatalf("Config error: %s", err)
 	}
 
-	log.Println("Config:\n", cfg)
+	// Секреты (пароли, ключи API) в журнал не попадают
+	log.Println("Config:\n", cfg.Redacted())
 
 	// Run
 	app.Run(cfg)
 }
+
+func migrate(args []string) {
+	// Для миграций нужен только адрес БД
+	sc, err := config.NewStorageConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if err := app.Migrate(ctx, sc, args, os.Stdout); err != nil {
+		stop()
+		log.Fatalf("Migrate error: %s", err)
+	}
+}
+
+func healthcheck() {
+	cfg, err := config.NewConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.HTTPServer.Timeout)
+	defer cancel()
+
+	if err := app.Healthcheck(ctx, cfg, os.Stdout); err != nil {
+		cancel()
+		log.Fatalf("Healthcheck error: %s", err)
+	}
+}
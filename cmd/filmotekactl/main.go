@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"filmoteka/config"
+	"filmoteka/internal/ctl"
+	"filmoteka/pkg/client"
+	"filmoteka/pkg/postgres"
+)
+
+// Утилита администратора для обслуживания каталога. Работает либо с запущенным сервером
+// через HTTP API, либо напрямую с БД.
+//
+//	filmotekactl -server http://localhost:8080 -user admin -pass secret movie list
+//	PG_URL=postgres://... filmotekactl -o csv export actors > actors.csv
+func main() {
+	server := flag.String("server", os.Getenv("FILMOTEKA_SERVER"), "API server URL, e.g. http://localhost:8080 (env FILMOTEKA_SERVER)")
+	user := flag.String("user", os.Getenv("FILMOTEKA_USER"), "admin user for the API server (env FILMOTEKA_USER)")
+	pass := flag.String("pass", os.Getenv("FILMOTEKA_PASS"), "admin password for the API server (env FILMOTEKA_PASS)")
+	dbURL := flag.String("db", os.Getenv("PG_URL"), "database URL for direct access when -server is empty (env PG_URL)")
+	format := flag.String("o", "", "output format: table, json or csv (default json for export, table otherwise)")
+
+	flag.Usage = func() {
+		fmt.Fprint(flag.CommandLine.Output(), ctl.Usage)
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var cat ctl.Catalogue
+
+	switch {
+	case *server != "":
+		var opts []client.Option
+		if *user != "" {
+			opts = append(opts, client.WithBasicAuth(*user, *pass))
+		}
+
+		c, err := client.New(*server, opts...)
+		if err != nil {
+			fatal(err)
+		}
+
+		cat = ctl.NewRemote(c)
+	case *dbURL != "":
+		db, err := postgres.New(config.StorageConfig{URL: *dbURL})
+		if err != nil {
+			fatal(err)
+		}
+		defer db.Close()
+
+		cat = ctl.NewDirect(db)
+	default:
+		fatal(errors.New("either -server or -db should be specified"))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	err := ctl.New(cat, os.Stdin, os.Stdout, *format).Run(ctx, flag.Args())
+
+	if errors.Is(err, ctl.ErrUsage) {
+		fmt.Fprintf(os.Stderr, "filmotekactl: %s\n\n", err)
+		flag.Usage()
+		stop()
+		os.Exit(2)
+	}
+
+	if err != nil {
+		stop()
+		fatal(err)
+	}
+}
+
+func fatal(err error) {
+	fmt.Fprintf(os.Stderr, "filmotekactl: %s\n", err)
+	os.Exit(1)
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"filmoteka/config"
+	"filmoteka/internal/imdb"
+	"filmoteka/pkg/logger"
+	"filmoteka/pkg/postgres"
+)
+
+// Импорт датасетов IMDb (https://datasets.imdbws.com) в БД filmoteka.
+//
+//	PG_URL=postgres://... go run ./cmd/import-imdb -dir ./imdb
+func main() {
+	dir := flag.String("dir", ".", "directory with title.basics, name.basics and title.principals TSV files")
+	batch := flag.Int("batch", 5000, "number of rows inserted in one transaction")
+	types := flag.String("types", "movie,tvMovie", "comma separated list of imported title types")
+	restart := flag.Bool("restart", false, "ignore saved progress and import datasets from the beginning")
+	flag.Parse()
+
+	sc, err := config.NewStorageConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	lc, err := config.NewLogConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+	l := logger.New(lc.Env)
+
+	db, err := postgres.New(sc)
+	if err != nil {
+		log.Fatalf("Storage error: %s", err)
+	}
+	defer db.Close()
+
+	// Прерванный импорт можно продолжить повторным запуском
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	importer := imdb.New(db, imdb.Config{
+		Dir:        *dir,
+		BatchSize:  *batch,
+		TitleTypes: strings.Split(*types, ","),
+		Restart:    *restart,
+	}, l)
+
+	if err = importer.Run(ctx); err != nil {
+		l.Error("import failed", l.Err(err))
+		os.Exit(1)
+	}
+
+	l.Info("import finished")
+}
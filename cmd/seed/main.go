@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"golang.org/x/exp/slog"
+
+	"filmoteka/config"
+	"filmoteka/internal/seed"
+	"filmoteka/pkg/logger"
+	"filmoteka/pkg/postgres"
+)
+
+// Наполнение БД filmoteka сгенерированными данными для разработки и нагрузочных тестов.
+//
+//	PG_URL=postgres://... go run ./cmd/seed -actors 1000000 -movies 1000000 -truncate
+func main() {
+	seedValue := flag.Int64("seed", 1, "random seed: the same seed produces the same data")
+	actors := flag.Int("actors", 1000, "number of generated actors")
+	movies := flag.Int("movies", 500, "number of generated movies")
+	cast := flag.Int("cast", 5, "average number of actors in a movie")
+	truncate := flag.Bool("truncate", false, "delete all actors and movies before seeding")
+	flag.Parse()
+
+	if *actors < 0 || *movies < 0 || *cast < 0 {
+		log.Fatalf("Flags error: -actors, -movies and -cast should not be negative")
+	}
+
+	sc, err := config.NewStorageConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+
+	lc, err := config.NewLogConfig()
+	if err != nil {
+		log.Fatalf("Config error: %s", err)
+	}
+	l := logger.New(lc.Env)
+
+	db, err := postgres.New(sc)
+	if err != nil {
+		log.Fatalf("Storage error: %s", err)
+	}
+	defer db.Close()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	seeder := seed.New(db, seed.Config{
+		Seed:         *seedValue,
+		Actors:       *actors,
+		Movies:       *movies,
+		CastPerMovie: *cast,
+		Truncate:     *truncate,
+	}, l)
+
+	if err = seeder.Run(ctx); err != nil {
+		l.Error("seed failed", l.Err(err))
+		os.Exit(1)
+	}
+
+	l.Info("seed finished", slog.Int64("seed", *seedValue), slog.Int("actors", *actors), slog.Int("movies", *movies))
+}
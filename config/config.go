@@ -1,7 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"net/netip"
+	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/ilyakaznacheev/cleanenv"
@@ -9,13 +14,43 @@ import (
 
 type (
 	Config struct {
-		Log        `yaml:"logger"`
-		HTTPServer `yaml:"http_server"`
-		StorageConfig
+		Log           `yaml:"logger"`
+		HTTPServer    `yaml:"http_server"`
+		GraphQL       `yaml:"graphql"`
+		GRPC          GRPCServer  `yaml:"grpc_server"`
+		Admin         AdminServer `yaml:"admin_server"`
+		Cache         `yaml:"cache"`
+		Tracing       `yaml:"tracing"`
+		Health        Health    `yaml:"health"`
+		RateLimit     RateLimit `yaml:"rate_limit"`
+		StorageConfig `yaml:",inline"`
 	}
 
+	// Log - журнал приложения. Env выбирает формат записей (текст для local, JSON для остальных)
+	// и уровень по умолчанию: debug для local и dev, info для prod.
 	Log struct {
 		Env string `env-required:"true" yaml:"env" env:"LOG_LEVEL"`
+		// Level - уровень компонентов вместо уровня окружения: debug, info, warn или error
+		Level string `yaml:"level" env:"LOG_DEFAULT_LEVEL"`
+		// Components - уровни отдельных компонентов (app, http, grpc, usecase, repo),
+		// в переменной окружения - через запятую: http:debug,repo:warn
+		Components map[string]string `yaml:"components" env:"LOG_COMPONENTS"`
+		// File - файл, в который записи дописываются вместе с выводом в stdout. Пустой путь выключает файл.
+		File string `yaml:"file" env:"LOG_FILE"`
+		// Размер файла в мегабайтах, после которого он переименовывается в File.1, и число хранимых старых файлов.
+		// При ротации хранится хотя бы один старый файл.
+		FileMaxSize    int `yaml:"file_max_size_mb" env:"LOG_FILE_MAX_SIZE_MB" env-default:"100"`
+		FileMaxBackups int `yaml:"file_max_backups" env:"LOG_FILE_MAX_BACKUPS" env-default:"5"`
+
+		Sampling LogSampling `yaml:"sampling"`
+	}
+
+	// LogSampling ограничивает повторяющиеся записи debug и info: за интервал Tick записываются первые First
+	// записей с одним сообщением и каждая Thereafter-я из остальных. First = 0 выключает выборку.
+	LogSampling struct {
+		First      int           `yaml:"first" env:"LOG_SAMPLING_FIRST" env-default:"100"`
+		Thereafter int           `yaml:"thereafter" env:"LOG_SAMPLING_THEREAFTER" env-default:"100"`
+		Tick       time.Duration `yaml:"tick" env:"LOG_SAMPLING_TICK" env-default:"1s"`
 	}
 
 	HTTPServer struct {
@@ -26,15 +61,118 @@ type (
 		Pass string `env-required:"true" yaml:"pass" env:"HTTP_PASS"`
 	}
 
+	// GRPCServer - сервер gRPC, работает рядом с HTTP сервером на отдельном порту
+	GRPCServer struct {
+		Address string `yaml:"address" env:"GRPC_ADDRESS" env-default:":9090"`
+	}
+
+	// AdminServer - служебный сервер с метриками Prometheus (/metrics), отдельно от API.
+	// Его порт не следует открывать наружу.
+	AdminServer struct {
+		// Address - адрес служебного сервера, пустой адрес выключает его
+		Address string `yaml:"address" env:"ADMIN_ADDRESS" env-default:":8081"`
+	}
+
+	// GraphQL ограничивает запросы к /graphql
+	GraphQL struct {
+		MaxDepth      int `yaml:"max_depth" env:"GRAPHQL_MAX_DEPTH" env-default:"7"`
+		MaxComplexity int `yaml:"max_complexity" env:"GRAPHQL_MAX_COMPLEXITY" env-default:"1000"`
+	}
+
+	// Cache - кеш ответов на частые запросы чтения в памяти процесса
+	Cache struct {
+		// Size - сколько ответов хранить, 0 выключает кеш
+		Size int `yaml:"size" env:"CACHE_SIZE" env-default:"1000"`
+		// TTL - сколько хранится ответ. Изменения через приложение сбрасывают ответы сразу,
+		// а изменения в обход него (другие экземпляры, команды ctl) видны по истечении TTL.
+		TTL time.Duration `yaml:"ttl" env:"CACHE_TTL" env-default:"1m"`
+	}
+
+	// Health - проверки готовности (/readyz) и остановка приложения
+	Health struct {
+		// Timeout - сколько ждать ответа каждой зависимости при проверке готовности
+		Timeout time.Duration `yaml:"timeout" env:"HEALTH_TIMEOUT" env-default:"2s"`
+		// ShutdownDelay - сколько после сигнала остановки отвечать "не готов", продолжая обслуживать запросы,
+		// чтобы балансировщик успел перестать направлять их приложению
+		ShutdownDelay time.Duration `yaml:"shutdown_delay" env:"HEALTH_SHUTDOWN_DELAY" env-default:"5s"`
+	}
+
+	// RateLimit - ограничение частоты запросов к HTTP API и gRPC корзиной токенов. Клиент определяется по пользователю,
+	// ключу API (заголовок X-API-Key) или адресу. Проверки состояния и документация не ограничиваются.
+	RateLimit struct {
+		Enabled bool `yaml:"enabled" env:"RATE_LIMIT_ENABLED" env-default:"true"`
+		// TrustedProxies - адреса и подсети прокси, от которых принимается X-Forwarded-For. По адресу клиента
+		// за ними хранилище узнает и клиента, изменившего данные, даже если ограничение выключено.
+		TrustedProxies []string `yaml:"trusted_proxies" env:"RATE_LIMIT_TRUSTED_PROXIES" env-separator:","`
+		// APIKeys - ключи клиентов по названиям, в переменной окружения - через запятую: mobile:key1,partner:key2
+		APIKeys map[string]string `yaml:"api_keys" env:"RATE_LIMIT_API_KEYS"`
+		// Default - ограничение маршрутов, которых нет в Routes. Корзина клиента у них общая.
+		Default RateLimitRule `yaml:"default"`
+		// Routes - ограничения маршрутов по ключу "METHOD /pattern" с отдельной корзиной для каждого.
+		// Шаблон записывается как поле route журнала и метрик: "GET /movies/list", методы gRPC -
+		// "POST /filmoteka.v1.MovieService/SearchMovies".
+		Routes map[string]RateLimitRule `yaml:"routes"`
+		// AuthFailures - ограничение неудачных попыток входа администратора с одного адреса
+		AuthFailures RateLimitRule `yaml:"auth_failures"`
+	}
+
+	// RateLimitRule - корзина токенов: Rate запросов в секунду, не больше Burst подряд. Rate = 0 снимает ограничение.
+	RateLimitRule struct {
+		Rate  float64 `yaml:"rate"`
+		Burst int     `yaml:"burst"`
+	}
+
+	// Tracing - трассировка OpenTelemetry. Спаны отправляются коллектору по OTLP, если задан его адрес,
+	// иначе пишутся в файл File или в stdout.
+	Tracing struct {
+		// Адрес коллектора OTLP/HTTP, например http://otel-collector:4318. Остальные параметры
+		// экспорта берутся из стандартных переменных окружения OTEL_EXPORTER_OTLP_*.
+		Endpoint string `env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+		// File - файл, в который дописываются спаны, если коллектор не задан. Пустой путь - stdout.
+		File string `yaml:"file" env:"TRACING_FILE"`
+		// SampleRatio - доля записываемых трасс от 0 до 1. Трассы, начатые клиентом,
+		// записываются, если клиент их записывает (флаг sampled в traceparent).
+		SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" env-default:"1"`
+	}
+
 	StorageConfig struct {
-		URL string `env-required:"true" env:"PG_URL"`
+		// Storage - хранилище данных: postgres, sqlite (файл БД рядом с сервером)
+		// или memory (данные в памяти процесса пропадают при остановке)
+		Storage string `yaml:"storage" env:"STORAGE" env-default:"postgres"`
+		// Адрес БД, обязателен для хранилища postgres
+		URL string `env:"PG_URL"`
+		// Путь к файлу БД хранилища sqlite. Если файла нет, он будет создан.
+		SQLitePath string `yaml:"sqlite_path" env:"SQLITE_PATH" env-default:"filmoteka.db"`
+		// Применять миграции при запуске сервера, как команда migrate up
+		AutoMigrate bool `env:"PG_AUTO_MIGRATE" env-default:"false"`
+		// Сколько ждать, пока миграции применяет другой экземпляр приложения
+		MigrateLockTimeout time.Duration `env:"PG_MIGRATE_LOCK_TIMEOUT" env-default:"5m"`
+		// Пул соединений с каждой БД postgres (основной и репликами)
+		MaxOpenConns    int           `env:"PG_MAX_OPEN_CONNS" env-default:"25"`
+		MaxIdleConns    int           `env:"PG_MAX_IDLE_CONNS" env-default:"10"`
+		ConnMaxLifetime time.Duration `env:"PG_CONN_MAX_LIFETIME" env-default:"30m"`
+		// Адреса реплик через запятую. Если они заданы, чтение идет с реплик, а запись - в основную БД.
+		ReplicaURLs []string `env:"PG_REPLICA_URLS" env-separator:","`
+		// Сколько после изменения данных клиент читает из основной БД, чтобы увидеть свои изменения,
+		// пока они не дошли до реплик
+		ReplicaStickiness time.Duration `env:"PG_REPLICA_STICKINESS" env-default:"5s"`
 	}
 )
 
+// Хранилища данных
+const (
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+	StorageMemory   = "memory"
+)
+
+// path - файл конфигурации относительно рабочего каталога
+const path = "./config/config.yml"
+
 func NewConfig() (*Config, error) {
 	cfg := &Config{}
 
-	err := cleanenv.ReadConfig("./config/config.yml", cfg)
+	err := cleanenv.ReadConfig(path, cfg)
 	if err != nil {
 		return nil, fmt.Errorf("config error: %w", err)
 	}
@@ -44,5 +182,174 @@ func NewConfig() (*Config, error) {
 		return nil, err
 	}
 
+	err = cfg.StorageConfig.Validate()
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.RateLimit.Validate()
+	if err != nil {
+		return nil, err
+	}
+
 	return cfg, nil
 }
+
+// redacted заменяет секреты в копии конфигурации, которую можно записать в журнал
+const redacted = "***"
+
+// Redacted возвращает копию конфигурации без секретов: пароля администратора, ключей API
+// и паролей в адресах БД
+func (cfg Config) Redacted() Config {
+	cfg.HTTPServer.Pass = redacted
+
+	if cfg.RateLimit.APIKeys != nil {
+		keys := make(map[string]string, len(cfg.RateLimit.APIKeys))
+		for name := range cfg.RateLimit.APIKeys {
+			keys[name] = redacted
+		}
+		cfg.RateLimit.APIKeys = keys
+	}
+
+	cfg.URL = redactURL(cfg.URL)
+
+	replicas := make([]string, len(cfg.ReplicaURLs))
+	for i, u := range cfg.ReplicaURLs {
+		replicas[i] = redactURL(u)
+	}
+	cfg.ReplicaURLs = replicas
+
+	return cfg
+}
+
+// redactURL скрывает пароль в адресе БД. Адрес, который не удалось разобрать, скрывается целиком.
+func redactURL(s string) string {
+	if s == "" {
+		return s
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return redacted
+	}
+
+	return u.Redacted()
+}
+
+// NewLogConfig читает параметры журнала так же, как NewConfig: из config.yml и окружения, поэтому команды
+// seed и import-imdb пишут журнал в том же формате, что и сервер. Если config.yml нет, окружение по умолчанию - local.
+func NewLogConfig() (Log, error) {
+	cfg := struct {
+		Log `yaml:"logger"`
+	}{}
+
+	if _, err := os.Stat(path); err == nil {
+		if err = cleanenv.ReadConfig(path, &cfg); err != nil {
+			return cfg.Log, fmt.Errorf("config error: %w", err)
+		}
+	} else {
+		cfg.Env = "local"
+		if err = cleanenv.ReadEnv(&cfg); err != nil {
+			return cfg.Log, err
+		}
+	}
+
+	return cfg.Log, nil
+}
+
+// NewStorageConfig читает из окружения параметры postgres для команд,
+// которые работают только с postgres (migrate, seed, import-imdb)
+func NewStorageConfig() (StorageConfig, error) {
+	sc := StorageConfig{}
+
+	err := cleanenv.ReadEnv(&sc)
+	if err != nil {
+		return sc, err
+	}
+
+	if sc.URL == "" {
+		return sc, errors.New("PG_URL is required")
+	}
+
+	return sc, nil
+}
+
+// Validate проверяет, что выбранное хранилище известно и для него заданы все параметры
+func (sc StorageConfig) Validate() error {
+	switch sc.Storage {
+	case StoragePostgres:
+		if sc.URL == "" {
+			return errors.New("PG_URL is required for postgres storage")
+		}
+		for _, url := range sc.ReplicaURLs {
+			if url == "" {
+				return errors.New("PG_REPLICA_URLS contains an empty url")
+			}
+		}
+	case StorageSQLite:
+		if sc.SQLitePath == "" {
+			return errors.New("SQLITE_PATH is required for sqlite storage")
+		}
+	case StorageMemory:
+	default:
+		return fmt.Errorf("unknown storage %q, want %s, %s or %s", sc.Storage, StoragePostgres, StorageSQLite, StorageMemory)
+	}
+
+	return nil
+}
+
+// Validate проверяет адреса прокси, ограничения и ключи маршрутов
+func (rl RateLimit) Validate() error {
+	for _, s := range rl.TrustedProxies {
+		var err error
+		if strings.Contains(s, "/") {
+			_, err = netip.ParsePrefix(s)
+		} else {
+			_, err = netip.ParseAddr(s)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid trusted proxy %q: %w", s, err)
+		}
+	}
+
+	for name, key := range rl.APIKeys {
+		if key == "" {
+			return fmt.Errorf("empty api key %q", name)
+		}
+	}
+
+	if err := rl.Default.validate(); err != nil {
+		return fmt.Errorf("invalid default rate limit: %w", err)
+	}
+
+	for route, rule := range rl.Routes {
+		method, path, ok := strings.Cut(route, " ")
+		// Шаблон маршрута не заканчивается на /, иначе ограничение ни к чему не применится
+		if !ok || method == "" || method != strings.ToUpper(method) || !strings.HasPrefix(path, "/") ||
+			(path != "/" && strings.HasSuffix(path, "/")) {
+			return fmt.Errorf("invalid rate limit route %q, want \"METHOD /pattern\" without trailing slash", route)
+		}
+
+		if err := rule.validate(); err != nil {
+			return fmt.Errorf("invalid rate limit of route %q: %w", route, err)
+		}
+	}
+
+	if err := rl.AuthFailures.validate(); err != nil {
+		return fmt.Errorf("invalid auth failures rate limit: %w", err)
+	}
+
+	return nil
+}
+
+func (r RateLimitRule) validate() error {
+	if r.Rate < 0 {
+		return errors.New("rate must not be negative")
+	}
+
+	if r.Rate > 0 && r.Burst < 1 {
+		return errors.New("burst must be at least 1")
+	}
+
+	return nil
+}
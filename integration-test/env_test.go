@@ -0,0 +1,82 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"testing"
+
+	"filmoteka/internal/seed"
+	"filmoteka/internal/usecase/repo/repotest"
+)
+
+// env - БД одного теста: данные из фикстур repotest внутри транзакции, которая откатывается после теста
+type env struct {
+	db *sql.DB
+}
+
+func newEnv(t *testing.T) *env {
+	t.Helper()
+
+	if databaseURL == "" {
+		t.Skip(skipReason)
+	}
+
+	ctx := context.Background()
+
+	db, rollback, err := openTx(ctx, databaseURL)
+	if err != nil {
+		t.Fatalf("failed to open test transaction: %s", err)
+	}
+
+	t.Cleanup(func() {
+		if err := rollback(); err != nil {
+			t.Errorf("failed to roll back test transaction: %s", err)
+		}
+	})
+
+	if err = loadFixtures(ctx, db); err != nil {
+		t.Fatalf("failed to load fixtures: %s", err)
+	}
+
+	return &env{db: db}
+}
+
+func loadFixtures(ctx context.Context, db *sql.DB) error {
+	err := repotest.Load(ctx, func(ctx context.Context, table string, row map[string]any) error {
+		return insert(ctx, db, table, row)
+	})
+	if err != nil {
+		return err
+	}
+
+	// id записей заданы явно: новые записи должны получать следующие за ними
+	_, err = db.ExecContext(ctx, seed.QueryResetSequences)
+
+	return err
+}
+
+func insert(ctx context.Context, db *sql.DB, table string, row map[string]any) error {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	placeholders := make([]string, len(columns))
+	values := make([]any, len(columns))
+
+	for i, column := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		values[i] = row[column]
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+
+	_, err := db.ExecContext(ctx, query, values...)
+
+	return err
+}
@@ -0,0 +1,440 @@
+package integration_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"golang.org/x/exp/slog"
+
+	"filmoteka/config"
+	"filmoteka/internal/controller/api"
+	"filmoteka/internal/health"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/cache"
+	"filmoteka/internal/usecase/repo"
+	"filmoteka/pkg/logger"
+)
+
+const (
+	adminUser = "admin"
+	adminPass = "secret"
+)
+
+type httpCase struct {
+	name string
+	// Метод и шаблон маршрута, как их регистрирует chi: по ним проверяется, что тестами покрыт каждый маршрут
+	method string
+	route  string
+	// Адрес запроса
+	target string
+	body   string
+	// Запрос отправляется с учетными данными администратора
+	admin bool
+
+	status int
+	// Заголовки и фрагменты, которые должны быть в ответе
+	header   map[string]string
+	contains []string
+}
+
+var httpCases = []httpCase{
+	// Документация
+	{name: "openapi", method: "GET", route: "/openapi.json", target: "/openapi.json",
+		status: 200, contains: []string{`"openapi"`, `"/api/v2/movies/{id}"`}},
+	{name: "docs redirect", method: "GET", route: "/docs", target: "/docs",
+		status: 301, header: map[string]string{"Location": "/docs/"}},
+	{name: "swagger ui", method: "GET", route: "/docs/*", target: "/docs/swagger-initializer.js",
+		status: 200, contains: []string{"/openapi.json"}},
+
+	// v1: актеры
+	{name: "find actor", method: "GET", route: "/actor/find/{id}", target: "/actor/find/1",
+		status: 200, contains: []string{`"name":"Иван"`, `"external_ids":{"imdb":"nm0000001"}`},
+		header: map[string]string{"Link": `</api/v2/actors>; rel="successor-version"`}},
+	{name: "find merged actor", method: "GET", route: "/actor/find/{id}", target: "/actor/find/100",
+		status: 200, contains: []string{`"id":3`}},
+	{name: "find missing actor", method: "GET", route: "/actor/find/{id}", target: "/actor/find/42",
+		status: 404, contains: []string{`"code":"actor_not_found"`}},
+	{name: "find actor by invalid id", method: "GET", route: "/actor/find/{id}", target: "/actor/find/abc",
+		status: 400, contains: []string{`"code":"invalid_id"`}},
+	{name: "find actor by external id", method: "GET", route: "/actor/find_by_external_id/{ref}", target: "/actor/find_by_external_id/tmdb:6384",
+		status: 200, contains: []string{`"name":"John"`}},
+	{name: "find actor by invalid external id", method: "GET", route: "/actor/find_by_external_id/{ref}", target: "/actor/find_by_external_id/6384",
+		status: 400, contains: []string{`"code":"invalid_external_id"`}},
+	{name: "find actor in unknown source", method: "GET", route: "/actor/find_by_external_id/{ref}", target: "/actor/find_by_external_id/wikidata:Q1",
+		status: 400, contains: []string{`"code":"unknown_external_source"`}},
+	{name: "save actor", method: "POST", route: "/actor/save", target: "/actor/save", admin: true,
+		body:   `{"name":"Олег","surname":"Меньшиков","date_of_birth":"08.11.1960"}`,
+		status: 200, contains: []string{`"id":6`, `"date_of_birth":"1960-11-08"`}},
+	{name: "save existing actor", method: "POST", route: "/actor/save", target: "/actor/save", admin: true,
+		body:   `{"name":"Иван","surname":"Петров"}`,
+		status: 409, contains: []string{`"code":"actor_already_exists"`}},
+	{name: "save invalid actor", method: "POST", route: "/actor/save", target: "/actor/save", admin: true,
+		body:   `{"name":"","gender":"unknown"}`,
+		status: 400, contains: []string{`"code":"invalid_input"`, `"field":"surname"`, `"field":"gender"`}},
+	{name: "save actor without auth", method: "POST", route: "/actor/save", target: "/actor/save",
+		body:   `{"name":"Олег","surname":"Меньшиков"}`,
+		status: 401},
+	{name: "upsert existing actor", method: "PUT", route: "/actor/upsert", target: "/actor/upsert", admin: true,
+		body:   `{"name":"Иван","surname":"Петров","external_ids":{"imdb":"nm0000001","tmdb":"1"}}`,
+		status: 200, contains: []string{`"id":1`, `"tmdb":"1"`}},
+	{name: "upsert new actor", method: "PUT", route: "/actor/upsert", target: "/actor/upsert", admin: true,
+		body:   `{"name":"Олег","surname":"Меньшиков","external_ids":{"kinopoisk":"7063"}}`,
+		status: 200, contains: []string{`"id":6`}},
+	{name: "upsert actor without external ids", method: "PUT", route: "/actor/upsert", target: "/actor/upsert", admin: true,
+		body:   `{"name":"Олег","surname":"Меньшиков"}`,
+		status: 400, contains: []string{`"code":"external_ids_required"`}},
+	{name: "update actor", method: "PUT", route: "/actor/update", target: "/actor/update", admin: true,
+		body:   `{"id":2,"surname":"Смирнова-Петрова"}`,
+		status: 200, contains: []string{`"surname":"Смирнова-Петрова"`, `"date_of_birth":"1985-07"`}},
+	{name: "update missing actor", method: "PUT", route: "/actor/update", target: "/actor/update", admin: true,
+		body:   `{"id":42,"name":"Олег"}`,
+		status: 404, contains: []string{`"code":"actor_not_found"`}},
+	{name: "update actor without id", method: "PUT", route: "/actor/update", target: "/actor/update", admin: true,
+		body:   `{"name":"Олег"}`,
+		status: 400, contains: []string{`"code":"id_required"`}},
+	{name: "delete actor", method: "DELETE", route: "/actor/delete/{id}", target: "/actor/delete/5", admin: true,
+		status: 200, contains: []string{`"name":"Мария"`}},
+	{name: "delete actor in cast", method: "DELETE", route: "/actor/delete/{id}", target: "/actor/delete/1", admin: true,
+		status: 409, contains: []string{`"code":"actor_referenced"`}},
+	{name: "merge actors", method: "POST", route: "/actor/merge", target: "/actor/merge", admin: true,
+		body:   `{"source_id":4,"target_id":3}`,
+		status: 200, contains: []string{`"id":3`, `"name":"John"`}},
+	{name: "merge actor with itself", method: "POST", route: "/actor/merge", target: "/actor/merge", admin: true,
+		body:   `{"source_id":3,"target_id":3}`,
+		status: 400, contains: []string{`"code":"actor_merge_with_itself"`}},
+	{name: "merge missing actor", method: "POST", route: "/actor/merge", target: "/actor/merge", admin: true,
+		body:   `{"source_id":42,"target_id":3}`,
+		status: 404, contains: []string{`"code":"actor_not_found"`}},
+	{name: "actor duplicates", method: "GET", route: "/actors/duplicates", target: "/actors/duplicates?min_score=0", admin: true,
+		status: 200, contains: []string{`"status":"OK"`, `"duplicates":`}},
+	{name: "actor duplicates with invalid score", method: "GET", route: "/actors/duplicates", target: "/actors/duplicates?min_score=2", admin: true,
+		status: 400, contains: []string{`"code":"invalid_min_score"`}},
+	{name: "actor duplicates without auth", method: "GET", route: "/actors/duplicates", target: "/actors/duplicates",
+		status: 401},
+	{name: "list actors", method: "GET", route: "/actors/list/", target: "/actors/list/",
+		status: 200, contains: []string{`"name":"Иван"`, `"name":"Мария"`, `"next_actor_id":6`}},
+	{name: "list actors by filter", method: "GET", route: "/actors/list/", target: "/actors/list/?gender=female",
+		status: 200, contains: []string{`"name":"Анна"`, `"next_actor_id":6`}},
+	{name: "list actors with no result", method: "GET", route: "/actors/list/", target: "/actors/list/?name=" + url.QueryEscape("Никто"),
+		status: 200, contains: []string{`"error":"No data"`}},
+	{name: "next actors", method: "GET", route: "/actors/list/next", target: "/actors/list/next?next_person_id=4",
+		status: 200, contains: []string{`"name":"Jon"`, `"next_actor_id":6`}},
+	{name: "next actors with invalid id", method: "GET", route: "/actors/list/next", target: "/actors/list/next?next_person_id=x",
+		status: 400, contains: []string{`"code":"invalid_pagination"`}},
+
+	// v1: связи актеров и фильмов
+	{name: "list movies of actors", method: "GET", route: "/actor_movie/list", target: "/actor_movie/list",
+		status: 200, contains: []string{`"movies_of_actor"`, `"Сибирский цирюльник"`}},
+	{name: "add actor to movie", method: "POST", route: "/actor_movie/save", target: "/actor_movie/save", admin: true,
+		body:   `{"actor_id":5,"movie_id":4}`,
+		status: 200, contains: []string{`"actor_movie":{"actor_id":5,"movie_id":4}`}},
+	{name: "add missing actor to movie", method: "POST", route: "/actor_movie/save", target: "/actor_movie/save", admin: true,
+		body:   `{"actor_id":42,"movie_id":4}`,
+		status: 404, contains: []string{`"code":"actor_movie_not_found"`}},
+	{name: "add actor to movie twice", method: "POST", route: "/actor_movie/save", target: "/actor_movie/save", admin: true,
+		body:   `{"actor_id":1,"movie_id":1}`,
+		status: 409, contains: []string{`"code":"actor_movie_already_exists"`}},
+
+	// v1: фильмы
+	{name: "find movie", method: "GET", route: "/movie/find_by_id/{id}", target: "/movie/find_by_id/2",
+		status: 200, contains: []string{`"title":"Сибирский цирюльник"`, `"release_date":"1998"`}},
+	{name: "find movie in old date format", method: "GET", route: "/movie/find_by_id/{id}", target: "/movie/find_by_id/1?date_format=ru",
+		status: 200, contains: []string{`"release_date":"12.12.1997"`}},
+	{name: "find missing movie", method: "GET", route: "/movie/find_by_id/{id}", target: "/movie/find_by_id/42",
+		status: 404, contains: []string{`"code":"movie_not_found"`}},
+	{name: "find movie by external id", method: "GET", route: "/movie/find_by_external_id/{ref}", target: "/movie/find_by_external_id/kinopoisk:301",
+		status: 200, contains: []string{`"title":"The Matrix"`}},
+	{name: "find missing movie by external id", method: "GET", route: "/movie/find_by_external_id/{ref}", target: "/movie/find_by_external_id/tmdb:603",
+		status: 404, contains: []string{`"code":"movie_not_found"`}},
+	{name: "search movies", method: "GET", route: "/movie/find/", target: "/movie/find/?actor_name=jo",
+		status: 200, contains: []string{`"title":"The Matrix"`}},
+	{name: "search movies with no result", method: "GET", route: "/movie/find/", target: "/movie/find/?title=" + url.QueryEscape("Брат 2"),
+		status: 404, contains: []string{`"code":"movies_not_found"`}},
+	{name: "save movie", method: "POST", route: "/movie/save", target: "/movie/save", admin: true,
+		body:   `{"title":"Курьер","release_date":"1986","rating":8}`,
+		status: 200, contains: []string{`"id":5`, `"release_date":"1986"`}},
+	{name: "save invalid movie", method: "POST", route: "/movie/save", target: "/movie/save", admin: true,
+		body:   `{"title":"Курьер","rating":11}`,
+		status: 400, contains: []string{`"code":"invalid_input"`, `"field":"rating"`}},
+	{name: "save movie with invalid body", method: "POST", route: "/movie/save", target: "/movie/save", admin: true,
+		body:   `{"title":`,
+		status: 400, contains: []string{`"code":"invalid_body"`}},
+	{name: "upsert existing movie", method: "PUT", route: "/movie/upsert", target: "/movie/upsert", admin: true,
+		body:   `{"title":"Брат","rating":9,"external_ids":{"imdb":"tt0118767"}}`,
+		status: 200, contains: []string{`"id":1`, `"rating":9`}},
+	{name: "upsert new movie", method: "PUT", route: "/movie/upsert", target: "/movie/upsert", admin: true,
+		body:   `{"title":"Курьер","external_ids":{"tmdb":"20992"}}`,
+		status: 200, contains: []string{`"id":5`}},
+	{name: "update movie", method: "PUT", route: "/movie/update", target: "/movie/update", admin: true,
+		body:   `{"id":3,"rating":10}`,
+		status: 200, contains: []string{`"rating":10`, `"title":"The Matrix"`}},
+	{name: "update missing movie", method: "PUT", route: "/movie/update", target: "/movie/update", admin: true,
+		body:   `{"id":42,"rating":10}`,
+		status: 404, contains: []string{`"code":"movie_not_found"`}},
+	{name: "delete movie", method: "DELETE", route: "/movie/delete/{id}", target: "/movie/delete/4", admin: true,
+		status: 200, contains: []string{`"title":"Короткометражка"`}},
+	{name: "delete movie with cast", method: "DELETE", route: "/movie/delete/{id}", target: "/movie/delete/1", admin: true,
+		status: 409, contains: []string{`"code":"movie_referenced"`}},
+	{name: "delete movie without auth", method: "DELETE", route: "/movie/delete/{id}", target: "/movie/delete/4",
+		status: 401},
+	{name: "list movies", method: "GET", route: "/movies/list/", target: "/movies/list/",
+		status: 200, contains: []string{`"title":"The Matrix"`, `"next_movie_id":5`}},
+	{name: "list movies sorted", method: "GET", route: "/movies/list/", target: "/movies/list/?sort_by=release_date&sort_order=asc",
+		status: 200, contains: []string{`"next_movie_id":5`}},
+	{name: "next movies", method: "GET", route: "/movies/list/next", target: "/movies/list/next?next_person_id=3",
+		status: 200, contains: []string{`"title":"The Matrix"`, `"next_movie_id":5`}},
+
+	// v2: фильмы
+	{name: "v2 list movies", method: "GET", route: "/api/v2/movies/", target: "/api/v2/movies/",
+		status: 200, contains: []string{`"title":"Брат"`, `"next_id":5`}},
+	{name: "v2 list movies from id", method: "GET", route: "/api/v2/movies/", target: "/api/v2/movies/?next_person_id=4",
+		status: 200, contains: []string{`"movies":[{"id":4`}},
+	{name: "v2 list movies after last", method: "GET", route: "/api/v2/movies/", target: "/api/v2/movies/?next_person_id=5",
+		status: 200, contains: []string{`{"movies":[]}`}},
+	{name: "v2 create movie", method: "POST", route: "/api/v2/movies/", target: "/api/v2/movies/", admin: true,
+		body:   `{"title":"Курьер"}`,
+		status: 201, contains: []string{`"id":5`}, header: map[string]string{"Location": "/api/v2/movies/5"}},
+	{name: "v2 create invalid movie", method: "POST", route: "/api/v2/movies/", target: "/api/v2/movies/", admin: true,
+		body:   `{"rating":5}`,
+		status: 400, contains: []string{`"field":"title"`}},
+	{name: "v2 get movie", method: "GET", route: "/api/v2/movies/{id}", target: "/api/v2/movies/3",
+		status: 200, contains: []string{`"title":"The Matrix"`, `"kinopoisk":"301"`}},
+	{name: "v2 get movie by invalid id", method: "GET", route: "/api/v2/movies/{id}", target: "/api/v2/movies/0",
+		status: 400, contains: []string{`"code":"invalid_id"`}},
+	{name: "v2 patch movie", method: "PATCH", route: "/api/v2/movies/{id}", target: "/api/v2/movies/4", admin: true,
+		body:   `{"release_date":"2001"}`,
+		status: 200, contains: []string{`"release_date":"2001"`, `"rating":5`}},
+	{name: "v2 patch missing movie", method: "PATCH", route: "/api/v2/movies/{id}", target: "/api/v2/movies/42", admin: true,
+		body:   `{"rating":1}`,
+		status: 404, contains: []string{`"code":"movie_not_found"`}},
+	{name: "v2 delete movie", method: "DELETE", route: "/api/v2/movies/{id}", target: "/api/v2/movies/4", admin: true,
+		status: 204},
+	{name: "v2 delete movie with cast", method: "DELETE", route: "/api/v2/movies/{id}", target: "/api/v2/movies/1", admin: true,
+		status: 409, contains: []string{`"code":"movie_referenced"`}},
+	{name: "v2 movie cast", method: "GET", route: "/api/v2/movies/{id}/cast", target: "/api/v2/movies/3/cast",
+		status: 200, contains: []string{`"name":"John"`, `"name":"Jon"`}},
+	{name: "v2 empty movie cast", method: "GET", route: "/api/v2/movies/{id}/cast", target: "/api/v2/movies/4/cast",
+		status: 200, contains: []string{`{"actors":[]}`}},
+	{name: "v2 cast of missing movie", method: "GET", route: "/api/v2/movies/{id}/cast", target: "/api/v2/movies/42/cast",
+		status: 404, contains: []string{`"code":"movie_not_found"`}},
+	{name: "v2 add cast", method: "POST", route: "/api/v2/movies/{id}/cast", target: "/api/v2/movies/4/cast", admin: true,
+		body:   `{"actor_id":5}`,
+		status: 204},
+	{name: "v2 add missing actor to cast", method: "POST", route: "/api/v2/movies/{id}/cast", target: "/api/v2/movies/4/cast", admin: true,
+		body:   `{"actor_id":42}`,
+		status: 404, contains: []string{`"code":"actor_movie_not_found"`}},
+	{name: "v2 remove cast", method: "DELETE", route: "/api/v2/movies/{id}/cast/{actor_id}", target: "/api/v2/movies/1/cast/2", admin: true,
+		status: 204},
+	{name: "v2 remove actor not in cast", method: "DELETE", route: "/api/v2/movies/{id}/cast/{actor_id}", target: "/api/v2/movies/1/cast/5", admin: true,
+		status: 404, contains: []string{`"code":"actor_movie_not_found"`}},
+	{name: "v2 remove cast by invalid actor id", method: "DELETE", route: "/api/v2/movies/{id}/cast/{actor_id}", target: "/api/v2/movies/1/cast/x", admin: true,
+		status: 400, contains: []string{`"code":"invalid_actor_id"`}},
+
+	// v2: актеры
+	{name: "v2 list actors", method: "GET", route: "/api/v2/actors/", target: "/api/v2/actors/",
+		status: 200, contains: []string{`"actors":[{"id":1`, `"next_id":6`}},
+	{name: "v2 list actors by filter", method: "GET", route: "/api/v2/actors/", target: "/api/v2/actors/?surname=Smith",
+		status: 200, contains: []string{`"name":"John"`, `"name":"Jon"`, `"next_id":5`}},
+	{name: "v2 create actor", method: "POST", route: "/api/v2/actors/", target: "/api/v2/actors/", admin: true,
+		body:   `{"name":"Олег","surname":"Меньшиков","gender":"male"}`,
+		status: 201, contains: []string{`"id":6`}, header: map[string]string{"Location": "/api/v2/actors/6"}},
+	{name: "v2 create actor born in future", method: "POST", route: "/api/v2/actors/", target: "/api/v2/actors/", admin: true,
+		body:   `{"name":"Олег","surname":"Меньшиков","date_of_birth":"2999-01-01"}`,
+		status: 400, contains: []string{`"field":"date_of_birth"`}},
+	{name: "v2 get actor", method: "GET", route: "/api/v2/actors/{id}", target: "/api/v2/actors/2",
+		status: 200, contains: []string{`"name":"Анна"`, `"date_of_birth":"1985-07"`}},
+	{name: "v2 get missing actor", method: "GET", route: "/api/v2/actors/{id}", target: "/api/v2/actors/42",
+		status: 404, contains: []string{`"code":"actor_not_found"`}},
+	{name: "v2 patch actor", method: "PATCH", route: "/api/v2/actors/{id}", target: "/api/v2/actors/5", admin: true,
+		body:   `{"date_of_birth":"1990"}`,
+		status: 200, contains: []string{`"date_of_birth":"1990"`, `"name":"Мария"`}},
+	{name: "v2 patch actor without fields", method: "PATCH", route: "/api/v2/actors/{id}", target: "/api/v2/actors/5", admin: true,
+		body:   `{}`,
+		status: 400, contains: []string{`"code":"invalid_input"`}},
+	{name: "v2 delete actor", method: "DELETE", route: "/api/v2/actors/{id}", target: "/api/v2/actors/5", admin: true,
+		status: 204},
+	{name: "v2 delete actor in cast", method: "DELETE", route: "/api/v2/actors/{id}", target: "/api/v2/actors/3", admin: true,
+		status: 409, contains: []string{`"code":"actor_referenced"`}},
+	{name: "v2 actor movies", method: "GET", route: "/api/v2/actors/{id}/movies", target: "/api/v2/actors/1/movies",
+		status: 200, contains: []string{`"movies":[{"id":1`, `"title":"Сибирский цирюльник"`}},
+	{name: "v2 movies of merged actor", method: "GET", route: "/api/v2/actors/{id}/movies", target: "/api/v2/actors/100/movies",
+		status: 200, contains: []string{`"title":"The Matrix"`}},
+	{name: "v2 movies of missing actor", method: "GET", route: "/api/v2/actors/{id}/movies", target: "/api/v2/actors/42/movies",
+		status: 404, contains: []string{`"code":"actor_not_found"`}},
+
+	// GraphQL
+	{name: "graphql query", method: "GET", route: "/graphql",
+		target: "/graphql?query=" + url.QueryEscape(`{movie(id: 3) {title cast {name}}}`),
+		status: 200, contains: []string{`"title":"The Matrix"`, `"name":"Jon"`}},
+	{name: "graphql mutation via get", method: "GET", route: "/graphql",
+		target: "/graphql?query=" + url.QueryEscape(`mutation {deleteActor(id: 5) {id}}`), admin: true,
+		status: 405, contains: []string{`"code":"mutation_requires_post"`}},
+	{name: "graphql query via post", method: "POST", route: "/graphql", target: "/graphql",
+		body:   `{"query":"{actor(id: 1) {name movies {title}}}"}`,
+		status: 200, contains: []string{`"name":"Иван"`, `"title":"Брат"`}},
+	{name: "graphql mutation", method: "POST", route: "/graphql", target: "/graphql", admin: true,
+		body:   `{"query":"mutation {addCast(movieId: 4, actorId: 5) {cast {name}}}"}`,
+		status: 200, contains: []string{`"name":"Мария"`}},
+	{name: "graphql mutation without auth", method: "POST", route: "/graphql", target: "/graphql",
+		body:   `{"query":"mutation {deleteActor(id: 5) {id}}"}`,
+		status: 401},
+
+	// Кеш ответов
+	{name: "cache stats", method: "GET", route: "/cache/stats", target: "/cache/stats", admin: true,
+		status: 200, contains: []string{`"enabled":true`, `"hits":0`}},
+	{name: "cache stats without auth", method: "GET", route: "/cache/stats", target: "/cache/stats",
+		status: 401},
+
+	// Уровни журнала
+	{name: "log levels", method: "GET", route: "/log/levels", target: "/log/levels", admin: true,
+		status: 200, contains: []string{`{"component":"http","level":"info"}`}},
+	{name: "log levels without auth", method: "GET", route: "/log/levels", target: "/log/levels",
+		status: 401},
+	{name: "set log level", method: "PUT", route: "/log/levels", target: "/log/levels", admin: true,
+		body:   `{"component":"http","level":"DEBUG"}`,
+		status: 200, contains: []string{`{"component":"http","level":"debug"}`}},
+	{name: "set log level of unknown component", method: "PUT", route: "/log/levels", target: "/log/levels", admin: true,
+		body:   `{"component":"db","level":"debug"}`,
+		status: 404, contains: []string{`"code":"log_component_not_found"`}},
+	{name: "set unknown log level", method: "PUT", route: "/log/levels", target: "/log/levels", admin: true,
+		body:   `{"component":"http","level":"verbose"}`,
+		status: 400, contains: []string{`"code":"invalid_log_level"`}},
+
+	// Проверки состояния
+	{name: "live", method: "GET", route: "/healthz", target: "/healthz",
+		status: 200, contains: []string{`{"status":"ok"}`}},
+	{name: "ready", method: "GET", route: "/readyz", target: "/readyz",
+		status: 200, header: map[string]string{"Cache-Control": "no-store"}, contains: []string{`{"status":"ok"}`}},
+	{name: "ready verbose", method: "GET", route: "/readyz", target: "/readyz?verbose",
+		status: 200, contains: []string{`"dependencies":[{"name":"primary","status":"ok"`}},
+	{name: "startup", method: "GET", route: "/startupz", target: "/startupz?verbose=false",
+		status: 200, contains: []string{`{"status":"ok"}`}},
+}
+
+func TestHTTPRoutes(t *testing.T) {
+	for _, tc := range httpCases {
+		t.Run(tc.name, func(t *testing.T) {
+			router := newRouter(newEnv(t))
+
+			var body io.Reader
+			if tc.body != "" {
+				body = strings.NewReader(tc.body)
+			}
+
+			req := httptest.NewRequest(tc.method, tc.target, body)
+			if tc.body != "" {
+				req.Header.Set("Content-Type", "application/json")
+			}
+			if tc.admin {
+				req.SetBasicAuth(adminUser, adminPass)
+			}
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			got := rec.Body.String()
+
+			if rec.Code != tc.status {
+				t.Fatalf("status = %d, want %d\n%s", rec.Code, tc.status, got)
+			}
+
+			for name, want := range tc.header {
+				if v := rec.Header().Get(name); v != want {
+					t.Errorf("header %s = %q, want %q", name, v, want)
+				}
+			}
+
+			for _, want := range tc.contains {
+				if !strings.Contains(got, want) {
+					t.Errorf("response does NOT contain %s\n%s", want, got)
+				}
+			}
+		})
+	}
+}
+
+// Тест падает, если для маршрута, зарегистрированного в api.NewRouter, нет ни одного случая в httpCases
+func TestHTTPRoutesCovered(t *testing.T) {
+	covered := make(map[string]bool)
+	for _, tc := range httpCases {
+		covered[tc.method+" "+tc.route] = true
+	}
+
+	err := chi.Walk(newRouter(&env{}), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// chi регистрирует обработчик /docs/* для всех методов
+		if route == "/docs/*" && method != http.MethodGet {
+			return nil
+		}
+
+		// Вложенные маршруты chi могут оканчиваться на /*/
+		route = strings.ReplaceAll(route, "/*/", "/")
+
+		if !covered[method+" "+route] {
+			t.Errorf("route %s %s is NOT covered by httpCases", method, route)
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chi.Walk returned error: %v", err)
+	}
+}
+
+func newRouter(e *env) *chi.Mux {
+	cfg := &config.Config{}
+	cfg.HTTPServer.User = adminUser
+	cfg.HTTPServer.Pass = adminPass
+	cfg.GraphQL.MaxDepth = 7
+	cfg.GraphQL.MaxComplexity = 1000
+
+	l := nopLogger{}
+
+	// Запросы идут через кеш ответов, как в приложении
+	c := cache.New(cache.NewLRU(100, time.Minute), 0, l)
+
+	hc := health.New(time.Second)
+	hc.Add("primary", e.db.PingContext)
+
+	// Уровни журнала меняются у настоящего журнала, записи которого отбрасываются
+	lv := logger.New("prod", logger.Output(io.Discard)).Component("http").Levels()
+
+	router := chi.NewRouter()
+	api.NewRouter(cfg, router, l,
+		cache.NewActors(usecase.NewActors(repo.NewActorsRepo(e.db), l), c),
+		cache.NewMovies(usecase.NewMovies(repo.NewMoviesRepo(e.db), l), c),
+		cache.NewActorsMovies(usecase.NewActorsMovies(repo.NewActorsMoviesRepo(e.db), l), c),
+		c, hc, lv, nil,
+	)
+
+	return router
+}
+
+// nopLogger отбрасывает все записи
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+func (nopLogger) Info(string, ...any) {}
+
+func (nopLogger) Warn(string, ...any) {}
+
+func (nopLogger) Error(string, ...any) {}
+
+func (nopLogger) DebugContext(context.Context, string, ...any) {}
+
+func (nopLogger) InfoContext(context.Context, string, ...any) {}
+
+func (nopLogger) WarnContext(context.Context, string, ...any) {}
+
+func (nopLogger) ErrorContext(context.Context, string, ...any) {}
+
+func (nopLogger) Err(err error) slog.Attr {
+	return slog.String("error", err.Error())
+}
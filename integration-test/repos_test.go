@@ -0,0 +1,20 @@
+package integration_test
+
+import (
+	"testing"
+
+	"filmoteka/internal/usecase/repo"
+	"filmoteka/internal/usecase/repo/repotest"
+)
+
+func TestRepos(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		e := newEnv(t)
+
+		return repotest.Repos{
+			Actors:       repo.NewActorsRepo(e.db),
+			Movies:       repo.NewMoviesRepo(e.db),
+			ActorsMovies: repo.NewActorsMoviesRepo(e.db),
+		}
+	})
+}
@@ -0,0 +1,232 @@
+package integration_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+
+	"github.com/lib/pq"
+)
+
+// openTx открывает *sql.DB поверх одного соединения, на котором начата транзакция.
+// Все, что тест записал в БД, отменяется вызовом rollback, поэтому тесты не видят изменений друг друга.
+//
+// Транзакции репозиториев (BeginTx) становятся точками сохранения внутри общей транзакции.
+// Запрос вне транзакции тоже выполняется в своей точке сохранения: ошибка, которую ожидает тест,
+// не прерывает общую транзакцию.
+func openTx(ctx context.Context, dsn string) (*sql.DB, func() error, error) {
+	connector, err := pq.NewConnector(dsn)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse dsn: %w", err)
+	}
+
+	conn, err := connector.Connect(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect: %w", err)
+	}
+
+	c := &txConn{conn: conn}
+
+	if err = c.exec(ctx, "BEGIN"); err != nil {
+		conn.Close()
+		return nil, nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	db := sql.OpenDB(txConnector{conn: c})
+	db.SetMaxOpenConns(1)
+
+	rollback := func() error {
+		db.Close()
+		defer conn.Close()
+
+		return c.exec(context.Background(), "ROLLBACK")
+	}
+
+	return db, rollback, nil
+}
+
+// txConnector всегда отдает database/sql одно и то же соединение
+type txConnector struct {
+	conn *txConn
+}
+
+func (c txConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.conn, nil
+}
+
+func (c txConnector) Driver() driver.Driver {
+	return pq.Driver{}
+}
+
+// txConn - соединение lib/pq, которое нельзя закрыть и на котором нельзя завершить общую транзакцию
+type txConn struct {
+	conn driver.Conn
+	// Глубина вложенности транзакций, начатых через BeginTx
+	depth int
+	// Счетчик для имен точек сохранения
+	savepoints int
+}
+
+func (c *txConn) exec(ctx context.Context, query string) error {
+	_, err := c.conn.(driver.ExecerContext).ExecContext(ctx, query, nil)
+	return err
+}
+
+// savepoint создает точку сохранения и возвращает ее имя
+func (c *txConn) savepoint(ctx context.Context) (string, error) {
+	c.savepoints++
+	name := fmt.Sprintf("sp_%d", c.savepoints)
+
+	return name, c.exec(ctx, "SAVEPOINT "+name)
+}
+
+// release завершает точку сохранения, отменяя ее изменения, если запрос вернул ошибку.
+// Контекст запроса мог быть отменен, поэтому точка сохранения завершается без него.
+func (c *txConn) release(name string, failed bool) error {
+	query := "RELEASE SAVEPOINT " + name
+	if failed {
+		query = "ROLLBACK TO SAVEPOINT " + name + "; " + query
+	}
+
+	return c.exec(context.Background(), query)
+}
+
+func (c *txConn) Prepare(query string) (driver.Stmt, error) {
+	return c.conn.Prepare(query)
+}
+
+func (c *txConn) Close() error {
+	return nil
+}
+
+func (c *txConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+// BeginTx не учитывает уровень изоляции: он задается только для всей транзакции
+func (c *txConn) BeginTx(ctx context.Context, _ driver.TxOptions) (driver.Tx, error) {
+	name, err := c.savepoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.depth++
+
+	return &savepointTx{c: c, name: name}, nil
+}
+
+func (c *txConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	execer := c.conn.(driver.ExecerContext)
+
+	if c.depth > 0 {
+		return execer.ExecContext(ctx, query, args)
+	}
+
+	name, err := c.savepoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := execer.ExecContext(ctx, query, args)
+
+	if releaseErr := c.release(name, err != nil); err == nil {
+		err = releaseErr
+	}
+
+	return res, err
+}
+
+// QueryContext читает результат целиком: пока строки не прочитаны, соединение занято
+// и точку сохранения завершить нельзя
+func (c *txConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	if c.depth > 0 {
+		return c.query(ctx, query, args)
+	}
+
+	name, err := c.savepoint(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := c.query(ctx, query, args)
+
+	if releaseErr := c.release(name, err != nil); err == nil {
+		err = releaseErr
+	}
+
+	return rows, err
+}
+
+func (c *txConn) query(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	rows, err := c.conn.(driver.QueryerContext).QueryContext(ctx, query, args)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res := &bufferedRows{columns: rows.Columns()}
+
+	for {
+		values := make([]driver.Value, len(res.columns))
+
+		err = rows.Next(values)
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		// lib/pq переиспользует буфер, из которого возвращает []byte
+		for i, v := range values {
+			if b, ok := v.([]byte); ok {
+				values[i] = append([]byte(nil), b...)
+			}
+		}
+
+		res.rows = append(res.rows, values)
+	}
+}
+
+// savepointTx - транзакция репозитория внутри общей транзакции теста
+type savepointTx struct {
+	c    *txConn
+	name string
+}
+
+func (t *savepointTx) Commit() error {
+	t.c.depth--
+	return t.c.release(t.name, false)
+}
+
+func (t *savepointTx) Rollback() error {
+	t.c.depth--
+	return t.c.release(t.name, true)
+}
+
+// bufferedRows - результат запроса, прочитанный в память
+type bufferedRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *bufferedRows) Columns() []string {
+	return r.columns
+}
+
+func (r *bufferedRows) Close() error {
+	return nil
+}
+
+func (r *bufferedRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"golang.org/x/exp/slog"
+
+	"github.com/go-chi/chi/v5"
+
+	"filmoteka/config"
+	"filmoteka/internal/metrics"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/httpserver"
+)
+
+// newAdminServer запускает служебный сервер с метриками или возвращает nil, если его адрес не задан
+func newAdminServer(cfg *config.Config, m *metrics.Metrics, l usecase.Logger) *httpserver.Server {
+	if cfg.Admin.Address == "" {
+		l.Info("admin server is disabled")
+		return nil
+	}
+
+	r := chi.NewRouter()
+	r.Handle("/metrics", m.Handler())
+
+	l.Info("starting admin server", slog.String("address", cfg.Admin.Address))
+
+	return httpserver.New(r, cfg, httpserver.Address(cfg.Admin.Address))
+}
@@ -1,9 +1,11 @@
 package app
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"golang.org/x/exp/slog"
 
@@ -11,60 +13,156 @@ import (
 
 	"filmoteka/config"
 	"filmoteka/internal/controller/api"
+	"filmoteka/internal/controller/rpc"
+	"filmoteka/internal/metrics"
+	"filmoteka/internal/tracing"
 	"filmoteka/internal/usecase"
-	"filmoteka/internal/usecase/repo"
+	"filmoteka/internal/usecase/cache"
+	"filmoteka/pkg/grpcserver"
 	"filmoteka/pkg/httpserver"
 	"filmoteka/pkg/logger"
-	"filmoteka/pkg/postgres"
+	"filmoteka/pkg/tracesql"
 )
 
 // Run creates objects via constructors.
 func Run(cfg *config.Config) {
-	l := logger.New(cfg.Env)
+	l, closeLog, err := newLogger(cfg.Log)
+	if err != nil {
+		l = logger.New(cfg.Env)
+		l.Error("failed to init logger", l.Err(err))
+		os.Exit(1)
+	}
+	defer closeLog()
+
+	// Трассировка настраивается до открытия БД: драйвер БД берет глобальный провайдер спанов
+	t, target, err := tracing.New(context.Background(), cfg.Tracing)
+	if err != nil {
+		l.Error("failed to init tracing", l.Err(err))
+		os.Exit(1)
+	}
+	defer shutdownTracing(t, l)
+
+	l.Info("exporting traces", slog.String("target", target))
+
+	// Запросы к БД записываются в журнал с полями запроса клиента, в котором они выполнены
+	tracesql.SetLogger(l.Component(logRepo))
 
 	// Repository
-	db, err := postgres.New(cfg.StorageConfig)
+	repos, err := newRepos(context.Background(), cfg.StorageConfig, l)
 	if err != nil {
-		l.Debug("failed to init storage", l.Err(err))
+		l.Error("failed to init storage", l.Err(err))
 		os.Exit(1)
 	}
-	defer db.Close()
+	defer repos.close()
+
+	// Проверки готовности: доступность БД и версия схемы
+	hc, err := newHealth(cfg, repos)
+	if err != nil {
+		l.Error("failed to init health checks", l.Err(err))
+		os.Exit(1)
+	}
+
+	ul := l.Component(logUsecase)
 
 	// Creating usecase for actors
-	actorsUseCase := usecase.NewActors(
-		repo.NewActorsRepo(db),
-		l,
+	var actorsUseCase usecase.Actor = usecase.NewActors(
+		repos.actors,
+		ul,
 	)
 
 	// Creating usecase for movies
-	moviesUseCase := usecase.NewMovies(
-		repo.NewMoviesRepo(db),
-		l,
+	var moviesUseCase usecase.Movie = usecase.NewMovies(
+		repos.movies,
+		ul,
 	)
 
 	// Creating usecase for many-to-many relationship between actors and films
-	actorsMoviesUseCase := usecase.NewActorsMovies(
-		repo.NewActorsMoviesRepo(db),
-		l,
+	var actorsMoviesUseCase usecase.ActorMovie = usecase.NewActorsMovies(
+		repos.actorsMovies,
+		ul,
 	)
 
+	// Метрики Prometheus: время работы usecase без учета кеша, пулы соединений и размер каталога
+	m := metrics.New(l)
+	for name, db := range repos.pools {
+		m.DB(name, db)
+	}
+	m.Catalogue(repos.actors, repos.movies, repos.actorsMovies)
+
+	actorsUseCase = metrics.NewActors(actorsUseCase, m)
+	moviesUseCase = metrics.NewMovies(moviesUseCase, m)
+	actorsMoviesUseCase = metrics.NewActorsMovies(actorsMoviesUseCase, m)
+
+	// Кеш ответов на частые запросы чтения: HTTP, GraphQL и gRPC работают через него
+	c := newCache(cfg, l)
+	if c != nil {
+		actorsUseCase = cache.NewActors(actorsUseCase, c)
+		moviesUseCase = cache.NewMovies(moviesUseCase, c)
+		actorsMoviesUseCase = cache.NewActorsMovies(actorsMoviesUseCase, c)
+		m.Cache(c)
+	}
+
+	// Спаны методов usecase охватывают и кеш: попадание в кеш - спан без запросов к БД
+	actorsUseCase = tracing.NewActors(actorsUseCase, t)
+	moviesUseCase = tracing.NewMovies(moviesUseCase, t)
+	actorsMoviesUseCase = tracing.NewActorsMovies(actorsMoviesUseCase, t)
+
+	// Учетные данные администратора HTTP API и gRPC
+	creds := map[string]string{
+		cfg.HTTPServer.User: cfg.HTTPServer.Pass,
+	}
+
 	// HTTP Server
 	r := chi.NewRouter()
-	api.NewRouter(cfg, r, l, actorsUseCase, moviesUseCase, actorsMoviesUseCase)
+	r.Use(t.HTTP, m.HTTP)
+
+	// Ограничитель запросов общий для HTTP и gRPC: клиент не обойдет ограничения, сменив протокол
+	limiter := newRateLimiter(cfg.RateLimit, r, creds)
+
+	api.NewRouter(cfg, r, l.Component(logHTTP), actorsUseCase, moviesUseCase, actorsMoviesUseCase, c, hc, l.Levels(), limiter)
 
 	l.Info("starting server", slog.String("address", cfg.Address))
 
 	httpServer := httpserver.New(r, cfg)
 
+	// gRPC Server
+	l.Info("starting gRPC server", slog.String("address", cfg.GRPC.Address))
+
+	grpcServer := grpcserver.New(rpc.New(actorsUseCase, moviesUseCase, actorsMoviesUseCase, l.Component(logGRPC), creds, limiter), cfg)
+
+	// Служебный сервер с метриками
+	adminServer := newAdminServer(cfg, m, l)
+
+	var adminNotify <-chan error
+	if adminServer != nil {
+		adminNotify = adminServer.Notify()
+	}
+
 	// Waiting signal
 	interrupt := make(chan os.Signal, 1)
 	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
 
+	drain := false
+
 	select {
 	case s := <-interrupt:
 		l.Info("app - Run - signal: " + s.String())
+		drain = true
 	case err = <-httpServer.Notify():
 		l.Debug("Failed to start server", err)
+	case err = <-grpcServer.Notify():
+		l.Error("Failed to start gRPC server", l.Err(err))
+	case err = <-adminNotify:
+		l.Error("Failed to start admin server", l.Err(err))
+	}
+
+	// Приложение отвечает "не готов" до закрытия серверов, чтобы балансировщик успел
+	// перестать направлять ему запросы, пока они еще обслуживаются
+	hc.Shutdown()
+
+	if drain && cfg.Health.ShutdownDelay > 0 {
+		l.Info("not ready, draining before shutdown", slog.Duration("delay", cfg.Health.ShutdownDelay))
+		time.Sleep(cfg.Health.ShutdownDelay)
 	}
 
 	// Shutdown
@@ -72,4 +170,26 @@ func Run(cfg *config.Config) {
 	if err != nil {
 		l.Debug("Server shutdown", err)
 	}
+
+	err = grpcServer.Shutdown()
+	if err != nil {
+		l.Error("gRPC server shutdown", l.Err(err))
+	}
+
+	if adminServer != nil {
+		err = adminServer.Shutdown()
+		if err != nil {
+			l.Error("Admin server shutdown", l.Err(err))
+		}
+	}
+}
+
+// shutdownTracing отправляет спаны, накопленные к остановке приложения
+func shutdownTracing(t *tracing.Tracing, l usecase.Logger) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := t.Shutdown(ctx); err != nil {
+		l.Warn("failed to flush traces", l.Err(err))
+	}
 }
@@ -0,0 +1,27 @@
+package app
+
+import (
+	"time"
+
+	"filmoteka/config"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/cache"
+)
+
+// newCache создает кеш ответов в памяти процесса или возвращает nil, если кеш выключен
+func newCache(cfg *config.Config, l usecase.Logger) *cache.Cache {
+	if cfg.Cache.Size <= 0 {
+		l.Info("response cache is disabled")
+		return nil
+	}
+
+	// Пока реплики могут отставать от основной БД, прочитанные с них ответы не сохраняются
+	var lag time.Duration
+	if cfg.Storage == config.StoragePostgres && len(cfg.ReplicaURLs) > 0 {
+		lag = cfg.ReplicaStickiness
+	}
+
+	l.Info("caching responses", "size", cfg.Cache.Size, "ttl", cfg.Cache.TTL)
+
+	return cache.New(cache.NewLRU(cfg.Cache.Size, cfg.Cache.TTL), lag, l)
+}
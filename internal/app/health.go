@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net"
+	"net/http"
+	"sort"
+
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"filmoteka/config"
+	"filmoteka/internal/health"
+	"filmoteka/migrations"
+)
+
+// newHealth создает проверки готовности хранилища: доступность каждой БД по имени пула
+// и, для postgres, версию схемы. Миграции sqlite применяются при открытии БД, а у хранилища
+// в памяти нет зависимостей.
+func newHealth(cfg *config.Config, repos repos) (*health.Health, error) {
+	const op = "app.newHealth"
+
+	h := health.New(cfg.Health.Timeout)
+
+	names := make([]string, 0, len(repos.pools))
+	for name := range repos.pools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		h.Add(name, repos.pools[name].PingContext)
+	}
+
+	if cfg.Storage == config.StoragePostgres {
+		want, err := latestMigration()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		h.Add("migrations", migrationsCheck(repos.pools["primary"], want))
+	}
+
+	return h, nil
+}
+
+// latestMigration возвращает версию последней встроенной миграции postgres
+func latestMigration() (uint, error) {
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	defer src.Close()
+
+	v, err := src.First()
+	for err == nil {
+		var next uint
+		if next, err = src.Next(v); err == nil {
+			v = next
+		}
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return 0, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+
+	return v, nil
+}
+
+// migrationsCheck проверяет, что миграции применены хотя бы до версии want и ни одна
+// не завершилась ошибкой. Версия новее want допустима: при обновлении новые экземпляры
+// применяют миграции, пока старые еще обслуживают запросы.
+func migrationsCheck(db *sql.DB, want uint) health.Check {
+	return func(ctx context.Context) error {
+		var (
+			version uint
+			dirty   bool
+		)
+
+		err := db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return fmt.Errorf("no migrations applied, want version %d", want)
+		case err != nil:
+			return err
+		case dirty:
+			return fmt.Errorf("migration %d failed, fix the database and run migrate force %d", version, version)
+		case version < want:
+			return fmt.Errorf("schema version %d, want %d: run migrate up", version, want)
+		}
+
+		return nil
+	}
+}
+
+// Healthcheck запрашивает у запущенного сервера /readyz и возвращает ошибку, если он не готов.
+// Образ приложения собран из scratch и не содержит curl, поэтому проверка состояния контейнера
+// вызывает само приложение: app healthcheck.
+func Healthcheck(ctx context.Context, cfg *config.Config, out io.Writer) error {
+	const op = "app.Healthcheck"
+
+	host, port, err := net.SplitHostPort(cfg.HTTPServer.Address)
+	if err != nil {
+		return fmt.Errorf("%s: invalid HTTP_ADDRESS: %w", op, err)
+	}
+
+	// Сервер, который слушает все адреса, доступен по localhost
+	if ip := net.ParseIP(host); host == "" || ip != nil && ip.IsUnspecified() {
+		host = "localhost"
+	}
+
+	url := "http://" + net.JoinHostPort(host, port) + "/readyz?verbose"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer resp.Body.Close()
+
+	io.Copy(out, resp.Body)
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: server is not ready: %s", op, resp.Status)
+	}
+
+	return nil
+}
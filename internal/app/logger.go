@@ -0,0 +1,76 @@
+package app
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/exp/slog"
+
+	"filmoteka/config"
+	"filmoteka/pkg/logger"
+)
+
+// Компоненты журнала, уровни которых задаются отдельно
+const (
+	logHTTP    = "http"
+	logGRPC    = "grpc"
+	logUsecase = "usecase"
+	logRepo    = "repo"
+)
+
+var logComponents = map[string]bool{
+	logger.AppComponent: true,
+	logHTTP:             true,
+	logGRPC:             true,
+	logUsecase:          true,
+	logRepo:             true,
+}
+
+// newLogger создает журнал приложения по cfg. Возвращает функцию, которая закрывает файл журнала.
+func newLogger(cfg config.Log) (*logger.Logger, func() error, error) {
+	const op = "app.newLogger"
+
+	opts := []logger.Option{
+		logger.Sampling(cfg.Sampling.First, cfg.Sampling.Thereafter, cfg.Sampling.Tick),
+	}
+
+	if cfg.Level != "" {
+		level, err := logger.ParseLevel(cfg.Level)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: invalid level: %w", op, err)
+		}
+
+		opts = append(opts, logger.DefaultLevel(level))
+	}
+
+	levels := make(map[string]slog.Level, len(cfg.Components))
+	for name, s := range cfg.Components {
+		if !logComponents[name] {
+			return nil, nil, fmt.Errorf("%s: unknown component %q", op, name)
+		}
+
+		level, err := logger.ParseLevel(s)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: invalid level of component %s: %w", op, name, err)
+		}
+
+		levels[name] = level
+	}
+
+	opts = append(opts, logger.ComponentLevels(levels))
+
+	closeFile := func() error { return nil }
+
+	if cfg.File != "" {
+		f, err := logger.OpenFile(cfg.File, int64(cfg.FileMaxSize)<<20, cfg.FileMaxBackups)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: failed to open file: %w", op, err)
+		}
+
+		opts = append(opts, logger.Output(io.MultiWriter(os.Stdout, f)))
+		closeFile = f.Close
+	}
+
+	return logger.New(cfg.Env, opts...), closeFile, nil
+}
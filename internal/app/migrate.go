@@ -1,17 +1,24 @@
-//go:build migrate
-
 package app
 
 import (
+	"context"
+	"database/sql"
 	"errors"
+	"fmt"
+	"io"
+	"io/fs"
 	"log"
-	"os"
+	"strconv"
 	"time"
 
 	"github.com/golang-migrate/migrate/v4"
 	// migrate tools
 	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+
+	"filmoteka/config"
+	"filmoteka/migrations"
 )
 
 const (
@@ -19,43 +26,200 @@ const (
 	_defaultTimeout  = time.Second
 )
 
-func init() {
-	databaseURL, ok := os.LookupEnv("PG_URL")
-	if !ok || len(databaseURL) == 0 {
-		log.Fatalf("migrate: environment variable not declared: PG_URL")
+// Ключ advisory lock, который удерживается, пока выполняется команда migrate.
+// Экземпляры приложения, запущенные одновременно, применяют миграции по очереди.
+const migrateLockID = 20210221023242
+
+const migrateUsage = "migrate up [N] | down [N] | goto V | force V | status"
+
+// Migrate выполняет команду управления миграциями args:
+//
+//	up [N]   - применить все или N следующих миграций
+//	down [N] - откатить N последних миграций (по умолчанию одну)
+//	goto V   - перейти к версии V
+//	force V  - записать версию V без выполнения миграций (после исправления ошибки вручную)
+//	status   - показать текущую версию и список миграций
+func Migrate(ctx context.Context, sc config.StorageConfig, args []string, out io.Writer) error {
+	const op = "app.Migrate"
+
+	if len(args) == 0 || len(args) > 2 {
+		return fmt.Errorf("%s: usage: %s", op, migrateUsage)
+	}
+
+	db, err := connect(ctx, sc.URL)
+	if err != nil {
+		return fmt.Errorf("%s: postgres connect error: %w", op, err)
+	}
+	defer db.Close()
+
+	unlock, err := lockMigrations(ctx, db, sc.MigrateLockTimeout)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer unlock()
+
+	src, err := iofs.New(migrations.FS, ".")
+	if err != nil {
+		return fmt.Errorf("%s: failed to read embedded migrations: %w", op, err)
+	}
+
+	m, err := migrate.NewWithSourceInstance("iofs", src, sc.URL)
+	if err != nil {
+		return fmt.Errorf("%s: failed to init migrate: %w", op, err)
 	}
+	defer m.Close()
+
+	cmd := args[0]
 
-	var (
-		attempts = _defaultAttempts
-		err      error
-		m        *migrate.Migrate
-	)
+	if cmd == "status" {
+		return status(m, src, out)
+	}
 
-	for attempts > 0 {
-		m, err = migrate.New("file://migrations", databaseURL)
+	switch {
+	case cmd == "up" && len(args) == 1:
+		err = m.Up()
+	case cmd == "up":
+		var n int
+		if n, err = steps(args[1]); err == nil {
+			err = m.Steps(n)
+		}
+	case cmd == "down":
+		n := 1
+		if len(args) == 2 {
+			n, err = steps(args[1])
+		}
 		if err == nil {
-			break
+			err = m.Steps(-n)
 		}
+	case cmd == "goto" && len(args) == 2:
+		var v uint64
+		if v, err = strconv.ParseUint(args[1], 10, 64); err == nil {
+			err = m.Migrate(uint(v))
+		}
+	case cmd == "force" && len(args) == 2:
+		var v int
+		if v, err = strconv.Atoi(args[1]); err == nil {
+			err = m.Force(v)
+		}
+	default:
+		return fmt.Errorf("%s: usage: %s", op, migrateUsage)
+	}
+
+	if errors.Is(err, migrate.ErrNoChange) {
+		fmt.Fprintln(out, "migrate: no change")
+		return nil
+	}
+
+	if err != nil {
+		return fmt.Errorf("%s: %s error: %w", op, cmd, err)
+	}
+
+	fmt.Fprintf(out, "migrate: %s success\n", cmd)
+
+	return status(m, src, out)
+}
 
-		log.Printf("Migrate: postgres is trying to connect, attempts left: %d", attempts)
+// connect открывает БД, ожидая, пока postgres начнет принимать соединения
+func connect(ctx context.Context, url string) (*sql.DB, error) {
+	db, err := sql.Open("postgres", url)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempts := _defaultAttempts; ; attempts-- {
+		err = db.PingContext(ctx)
+		if err == nil {
+			return db, nil
+		}
+
+		if attempts == 1 || ctx.Err() != nil {
+			db.Close()
+			return nil, err
+		}
+
+		log.Printf("Migrate: postgres is trying to connect, attempts left: %d", attempts-1)
 		time.Sleep(_defaultTimeout)
-		attempts--
 	}
+}
 
+// lockMigrations занимает advisory lock на отдельном соединении и возвращает функцию, которая его отпускает.
+// Если соединение оборвется, postgres отпустит блокировку сам.
+func lockMigrations(ctx context.Context, db *sql.DB, timeout time.Duration) (func(), error) {
+	conn, err := db.Conn(ctx)
 	if err != nil {
-		log.Fatalf("Migrate: postgres connect error: %s", err)
+		return nil, fmt.Errorf("failed to get connection: %w", err)
 	}
 
-	err = m.Up()
-	defer m.Close()
-	if err != nil && !errors.Is(err, migrate.ErrNoChange) {
-		log.Fatalf("Migrate: up error: %s", err)
+	var locked bool
+	if err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", migrateLockID).Scan(&locked); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to take migration lock: %w", err)
 	}
 
-	if errors.Is(err, migrate.ErrNoChange) {
-		log.Printf("Migrate: no change")
-		return
+	if !locked {
+		log.Printf("Migrate: waiting for another instance to finish migrations")
+
+		lockCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+
+		if _, err = conn.ExecContext(lockCtx, "SELECT pg_advisory_lock($1)", migrateLockID); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("failed to take migration lock in %s: %w", timeout, err)
+		}
+	}
+
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", migrateLockID)
+		conn.Close()
+	}, nil
+}
+
+// status выводит текущую версию схемы и список миграций с отметкой о применении
+func status(m *migrate.Migrate, src source.Driver, out io.Writer) error {
+	current, dirty, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return fmt.Errorf("failed to get version: %w", err)
+	}
+
+	switch {
+	case errors.Is(err, migrate.ErrNilVersion):
+		fmt.Fprintln(out, "version: none")
+	case dirty:
+		fmt.Fprintf(out, "version: %d (dirty: fix the database and run migrate force %d)\n", current, current)
+	default:
+		fmt.Fprintf(out, "version: %d\n", current)
+	}
+
+	v, err := src.First()
+	for err == nil {
+		state := "pending"
+		if current != 0 && v <= current {
+			state = "applied"
+		}
+
+		var name string
+		if r, identifier, err := src.ReadUp(v); err == nil {
+			r.Close()
+			name = identifier
+		}
+
+		fmt.Fprintf(out, "%-8s %d %s\n", state, v, name)
+
+		v, err = src.Next(v)
+	}
+
+	if !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	return nil
+}
+
+func steps(arg string) (int, error) {
+	n, err := strconv.Atoi(arg)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("number of migrations should be a positive integer, got %q", arg)
 	}
 
-	log.Printf("Migrate: up success")
+	return n, nil
 }
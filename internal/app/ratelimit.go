@@ -0,0 +1,30 @@
+package app
+
+import (
+	"github.com/go-chi/chi/v5"
+
+	"filmoteka/config"
+	"filmoteka/internal/controller/middleware/ratelimit"
+)
+
+// newRateLimiter создает ограничитель запросов HTTP и gRPC к маршрутам router. Выключенный ограничитель
+// не ограничивает запросы, но по-прежнему определяет адрес клиента за доверенными прокси.
+func newRateLimiter(cfg config.RateLimit, router *chi.Mux, creds map[string]string) *ratelimit.Limiter {
+	if !cfg.Enabled {
+		return ratelimit.New(nil, ratelimit.Config{TrustedProxies: cfg.TrustedProxies})
+	}
+
+	routes := make(map[string]ratelimit.Limit, len(cfg.Routes))
+	for route, rule := range cfg.Routes {
+		routes[route] = ratelimit.Limit(rule)
+	}
+
+	return ratelimit.New(router, ratelimit.Config{
+		TrustedProxies: cfg.TrustedProxies,
+		APIKeys:        cfg.APIKeys,
+		Creds:          creds,
+		Default:        ratelimit.Limit(cfg.Default),
+		Routes:         routes,
+		AuthFailures:   ratelimit.Limit(cfg.AuthFailures),
+	})
+}
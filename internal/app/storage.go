@@ -0,0 +1,136 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+
+	"filmoteka/config"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/repo"
+	"filmoteka/internal/usecase/repo/memory"
+	"filmoteka/internal/usecase/repo/replica"
+	"filmoteka/internal/usecase/repo/sqlite"
+	"filmoteka/pkg/postgres"
+)
+
+// repos - репозитории хранилища, выбранного в конфигурации
+type repos struct {
+	actors       usecase.ActorsRepo
+	movies       usecase.MoviesRepo
+	actorsMovies usecase.ActorsMoviesRepo
+
+	// pools - пулы соединений с БД по именам для метрик. У хранилища в памяти их нет.
+	pools map[string]*sql.DB
+
+	// close освобождает ресурсы хранилища
+	close func() error
+}
+
+// newRepos создает репозитории хранилища sc.Storage. Для postgres при sc.AutoMigrate
+// сначала применяются миграции, а sqlite применяет свои миграции всегда.
+func newRepos(ctx context.Context, sc config.StorageConfig, l usecase.Logger) (repos, error) {
+	const op = "app.newRepos"
+
+	switch sc.Storage {
+	case config.StorageMemory:
+		l.Warn("using in-memory storage, data will be lost on shutdown")
+
+		s := memory.New()
+
+		return repos{
+			actors:       memory.NewActorsRepo(s),
+			movies:       memory.NewMoviesRepo(s),
+			actorsMovies: memory.NewActorsMoviesRepo(s),
+			close:        func() error { return nil },
+		}, nil
+	case config.StorageSQLite:
+		db, err := sqlite.Open(ctx, sc.SQLitePath)
+		if err != nil {
+			return repos{}, fmt.Errorf("%s: failed to init storage: %w", op, err)
+		}
+
+		l.Info("sqlite database is up to date", "path", sc.SQLitePath)
+
+		return repos{
+			actors:       sqlite.NewActorsRepo(db),
+			movies:       sqlite.NewMoviesRepo(db),
+			actorsMovies: sqlite.NewActorsMoviesRepo(db),
+			pools:        map[string]*sql.DB{"sqlite": db},
+			close:        db.Close,
+		}, nil
+	case config.StoragePostgres:
+		// Migrations
+		if sc.AutoMigrate {
+			if err := Migrate(ctx, sc, []string{"up"}, io.Discard); err != nil {
+				return repos{}, fmt.Errorf("%s: failed to migrate database: %w", op, err)
+			}
+
+			l.Info("database is up to date")
+		}
+
+		// Недоступная БД не мешает запуску: /readyz отвечает 503, пока она не станет доступна
+		db, err := postgres.Open(sc)
+		if err != nil {
+			return repos{}, fmt.Errorf("%s: failed to init storage: %w", op, err)
+		}
+
+		if len(sc.ReplicaURLs) == 0 {
+			return repos{
+				actors:       repo.NewActorsRepo(db),
+				movies:       repo.NewMoviesRepo(db),
+				actorsMovies: repo.NewActorsMoviesRepo(db),
+				pools:        map[string]*sql.DB{"primary": db},
+				close:        db.Close,
+			}, nil
+		}
+
+		replicas, err := postgres.OpenReplicas(sc)
+		if err != nil {
+			db.Close()
+			return repos{}, fmt.Errorf("%s: failed to init replicas: %w", op, err)
+		}
+
+		l.Info("reading from replicas", "replicas", len(replicas), "stickiness", sc.ReplicaStickiness)
+
+		return replicaRepos(sc, db, replicas), nil
+	}
+
+	return repos{}, fmt.Errorf("%s: unknown storage %q", op, sc.Storage)
+}
+
+// replicaRepos создает репозитории, которые читают с реплик, а пишут в основную БД primary
+func replicaRepos(sc config.StorageConfig, primary *sql.DB, replicas []*sql.DB) repos {
+	router := replica.NewRouter(sc.ReplicaStickiness)
+
+	actors := make([]usecase.ActorsRepo, 0, len(replicas))
+	movies := make([]usecase.MoviesRepo, 0, len(replicas))
+	actorsMovies := make([]usecase.ActorsMoviesRepo, 0, len(replicas))
+
+	pools := map[string]*sql.DB{"primary": primary}
+
+	for i, db := range replicas {
+		pools[fmt.Sprintf("replica%d", i+1)] = db
+
+		actors = append(actors, repo.NewActorsRepo(db))
+		movies = append(movies, repo.NewMoviesRepo(db))
+		actorsMovies = append(actorsMovies, repo.NewActorsMoviesRepo(db))
+	}
+
+	return repos{
+		actors:       replica.NewActorsRepo(router, repo.NewActorsRepo(primary), actors...),
+		movies:       replica.NewMoviesRepo(router, repo.NewMoviesRepo(primary), movies...),
+		actorsMovies: replica.NewActorsMoviesRepo(router, repo.NewActorsMoviesRepo(primary), actorsMovies...),
+		pools:        pools,
+		close: func() error {
+			errs := []error{primary.Close()}
+			for _, db := range replicas {
+				errs = append(errs, db.Close())
+			}
+
+			return errors.Join(errs...)
+		},
+	}
+}
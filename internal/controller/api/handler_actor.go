@@ -1,16 +1,15 @@
 package api
 
 import (
-	"database/sql"
-	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
-	"github.com/lib/pq"
 	"golang.org/x/exp/slog"
 
+	"filmoteka/internal/controller/problem"
 	"filmoteka/internal/entity"
 	"filmoteka/internal/usecase"
 	"filmoteka/pkg/logger"
@@ -28,40 +27,60 @@ func newActorHandler(t usecase.Actor, l logger.Interface) *actorHandler {
 func (h *actorHandler) find(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	id, err := urlID(r)
 
-	if id == 0 {
+	if err != nil {
 
-		h.l.Info("No data available for id = 0")
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
 
-		render.JSON(w, r,
-			ActorResponse{
-				Status: "Wrong id. Id should be > 0",
-			})
+		problem.Error(w, r, err)
 
 		return
 	}
 
+	res, err := h.t.Find(ctx, id)
+
 	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r,
+		ActorResponse{
+			Status: StatusOk,
+			Actor:  &res,
+		})
+}
+
+func (h *actorHandler) findByExternalID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-		h.l.Debug("id parameter in URL is not integer or empty", h.l.Err(err))
+	// Идентификатор передается в формате source:external_id, например imdb:nm0000001
+	source, externalID, ok := strings.Cut(chi.URLParam(r, "ref"), ":")
 
-		render.JSON(w, r, Error("Unable to retrieve id from URL"))
+	if !ok || source == "" || externalID == "" {
+
+		h.l.DebugContext(ctx, "external id in URL is not in source:external_id format")
+
+		problem.Error(w, r, errInvalidExternalID())
 
 		return
 	}
 
-	res, err := h.t.Find(ctx, id)
+	res, err := h.t.FindByExternalID(ctx, source, externalID)
 
 	if err != nil {
-		h.l.Debug("Failed to get data from DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
 
-		render.JSON(w, r, Error(fmt.Sprintf("Database has NO actor with id = %d", id)))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		ActorResponse{
 			Status: StatusOk,
 			Actor:  &res,
@@ -74,36 +93,58 @@ func (h *actorHandler) save(w http.ResponseWriter, r *http.Request) {
 	var data entity.ActorData
 	err := render.DecodeJSON(r.Body, &data)
 	if err != nil {
-		h.l.Debug("Failed to decode request body to entity.ActorData", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.ActorData", h.l.Err(err))
 
-		render.JSON(w, r, Error("failed to decode request body to entity.ActorData"))
+		problem.Error(w, r, errInvalidBody("entity.ActorData"))
 
 		return
 	}
 
-	h.l.Info("request body decoded to entity.ActorData successfully", slog.Any("request", data))
+	h.l.InfoContext(ctx, "request body decoded to entity.ActorData successfully", slog.Any("request", data))
 
 	res, err := h.t.Save(ctx, data)
 
 	if err != nil {
-		h.l.Debug("Failed to save data in DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to save data in DB", h.l.Err(err))
 
-		if err == sql.ErrNoRows {
-			render.JSON(w, r, Error("actor already exists"))
-			return
-		} else {
-			switch err := err.(type) {
-			case *pq.Error:
-				render.JSON(w, r, Error("provided data is invalid"))
-				return
-			default:
-				render.JSON(w, r, err.Error())
-				return
-			}
-		}
+		problem.Error(w, r, err)
+
+		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
+		ActorResponse{
+			Status: StatusOk,
+			Actor:  &res,
+		})
+}
+
+func (h *actorHandler) upsert(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data entity.ActorData
+	err := render.DecodeJSON(r.Body, &data)
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.ActorData", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("entity.ActorData"))
+
+		return
+	}
+
+	h.l.InfoContext(ctx, "request body decoded to entity.ActorData successfully", slog.Any("request", data))
+
+	res, err := h.t.Upsert(ctx, data)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to upsert data in DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r,
 		ActorResponse{
 			Status: StatusOk,
 			Actor:  &res,
@@ -116,26 +157,26 @@ func (h *actorHandler) update(w http.ResponseWriter, r *http.Request) {
 	var updates entity.Actor
 	err := render.DecodeJSON(r.Body, &updates)
 	if err != nil {
-		h.l.Debug("Failed to decode request body to entity.Actor", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.Actor", h.l.Err(err))
 
-		render.JSON(w, r, Error("failed to decode request body to entity.Actor"))
+		problem.Error(w, r, errInvalidBody("entity.Actor"))
 
 		return
 	}
 
-	h.l.Info("request body decoded to entity.Actor successfully", slog.Any("request", updates))
+	h.l.InfoContext(ctx, "request body decoded to entity.Actor successfully", slog.Any("request", updates))
 
 	res, err := h.t.Update(ctx, updates)
 
 	if err != nil {
-		h.l.Debug("Failed to update data in DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to update data in DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to update actor data in DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		ActorResponse{
 			Status: StatusOk,
 			Actor:  &res,
@@ -145,42 +186,110 @@ func (h *actorHandler) update(w http.ResponseWriter, r *http.Request) {
 func (h *actorHandler) delete(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	id, err := urlID(r)
 
 	if err != nil {
 
-		h.l.Debug("id parameter in URL is not integer or empty", h.l.Err(err))
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to retrieve id from URL"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	h.l.Info("request body decoded to int successfully", slog.Any("request", id))
+	h.l.InfoContext(ctx, "request body decoded to int successfully", slog.Any("request", id))
 
-	if id == 0 {
+	res, err := h.t.Delete(ctx, id)
 
-		h.l.Info("No data available for id = 0")
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to delete data from DB", h.l.Err(err))
 
-		render.JSON(w, r,
-			ActorResponse{
-				Status: "Wrong id. Id should be > 0",
-			})
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	res, err := h.t.Delete(ctx, id)
+	respond(w, r,
+		ActorResponse{
+			Status: StatusOk,
+			Actor:  &res,
+		})
+}
+
+// Минимальное сходство пары актеров по умолчанию
+const defaultDuplicateScore = 0.6
+
+func (h *actorHandler) duplicates(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	minScore := defaultDuplicateScore
+
+	if val := r.URL.Query().Get("min_score"); val != "" {
+		score, err := strconv.ParseFloat(val, 64)
+
+		if err != nil || score < 0 || score > 1 {
+			h.l.DebugContext(ctx, "min_score parameter in URL is not a number between 0 and 1")
+
+			problem.Error(w, r, usecase.Validation("invalid_min_score", "min_score should be a number between 0 and 1"))
+
+			return
+		}
+
+		minScore = score
+	}
+
+	res, err := h.t.Duplicates(ctx, minScore)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r,
+		ActorDuplicatesResponse{
+			Status:     StatusOk,
+			Duplicates: res,
+		})
+}
+
+func (h *actorHandler) merge(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data entity.ActorMerge
+	err := render.DecodeJSON(r.Body, &data)
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.ActorMerge", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("entity.ActorMerge"))
+
+		return
+	}
+
+	if data.SourceID == nil || data.TargetID == nil {
+
+		h.l.DebugContext(ctx, "source_id or target_id is not specified")
+
+		problem.Error(w, r, usecase.Validation("merge_ids_required", "source_id and target_id should be specified"))
+
+		return
+	}
+
+	h.l.InfoContext(ctx, "request body decoded to entity.ActorMerge successfully", slog.Any("request", data))
+
+	res, err := h.t.Merge(ctx, *data.SourceID, *data.TargetID)
 
 	if err != nil {
-		h.l.Debug("Failed to delete data from DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to merge actors in DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to delete data from DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		ActorResponse{
 			Status: StatusOk,
 			Actor:  &res,
@@ -4,9 +4,9 @@ import (
 	"net/http"
 
 	"github.com/go-chi/render"
-	"github.com/lib/pq"
 	"golang.org/x/exp/slog"
 
+	"filmoteka/internal/controller/problem"
 	"filmoteka/internal/entity"
 	"filmoteka/internal/usecase"
 	"filmoteka/pkg/logger"
@@ -27,31 +27,26 @@ func (h *actorMovieHandler) save(w http.ResponseWriter, r *http.Request) {
 	var data entity.ActorMovie
 	err := render.DecodeJSON(r.Body, &data)
 	if err != nil {
-		h.l.Debug("Failed to decode request body to entity.ActorMovie", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.ActorMovie", h.l.Err(err))
 
-		render.JSON(w, r, Error("failed to decode request body to entity.ActorMovie"))
+		problem.Error(w, r, errInvalidBody("entity.ActorMovie"))
 
 		return
 	}
 
-	h.l.Info("request body decoded to entity.ActorMovie successfully", slog.Any("request", data))
+	h.l.InfoContext(ctx, "request body decoded to entity.ActorMovie successfully", slog.Any("request", data))
 
 	err = h.t.Save(ctx, data)
 
 	if err != nil {
-		h.l.Debug("Failed to save data in DB", h.l.Err(err))
-
-		switch err := err.(type) {
-		case *pq.Error:
-			render.JSON(w, r, Error("provided data is invalid or actor_id to movie_id assignment already exists"))
-			return
-		default:
-			render.JSON(w, r, err.Error())
-			return
-		}
+		h.l.DebugContext(ctx, "Failed to save data in DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		ActorMovieResponse{
 			Status:     StatusOk,
 			ActorMovie: &data,
@@ -64,17 +59,17 @@ func (h *actorMovieHandler) list(w http.ResponseWriter, r *http.Request) {
 	res, err := h.t.List(ctx)
 
 	if err != nil {
-		h.l.Debug("Failed to get data from DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to get data from DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
 	if len(res) == 0 {
-		h.l.Info("No data")
+		h.l.InfoContext(ctx, "No data")
 
-		render.JSON(w, r, customError{
+		respond(w, r, customError{
 			Status: StatusOk,
 			Error:  "No data",
 		})
@@ -85,7 +80,7 @@ func (h *actorMovieHandler) list(w http.ResponseWriter, r *http.Request) {
 	//Представим данные из res в требуемый формат
 	newData := ConvertToMoviesOfActor(res)
 
-	render.JSON(w, r,
+	respond(w, r,
 		ActorsMoviesResponse{
 			Status: StatusOk,
 			Data:   newData,
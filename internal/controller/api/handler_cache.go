@@ -0,0 +1,25 @@
+package api
+
+import (
+	"net/http"
+
+	"filmoteka/internal/usecase/cache"
+)
+
+type cacheHandler struct {
+	c *cache.Cache
+}
+
+// newCacheHandler создает обработчик статистики кеша. c равен nil, если кеш выключен.
+func newCacheHandler(c *cache.Cache) *cacheHandler {
+	return &cacheHandler{c: c}
+}
+
+func (h *cacheHandler) stats(w http.ResponseWriter, r *http.Request) {
+	respond(w, r,
+		CacheStatsResponse{
+			Enabled: h.c != nil,
+			Stats:   h.c.Stats(),
+		},
+	)
+}
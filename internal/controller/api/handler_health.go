@@ -0,0 +1,64 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/render"
+
+	"filmoteka/internal/health"
+)
+
+// verboseParam - параметр запроса, с которым в ответе перечисляется состояние каждой зависимости
+const verboseParam = "verbose"
+
+type healthHandler struct {
+	h *health.Health
+}
+
+func newHealthHandler(h *health.Health) *healthHandler {
+	return &healthHandler{h: h}
+}
+
+func (hh *healthHandler) live(w http.ResponseWriter, r *http.Request) {
+	hh.respond(w, r, hh.h.Live())
+}
+
+func (hh *healthHandler) ready(w http.ResponseWriter, r *http.Request) {
+	hh.respond(w, r, hh.h.Ready(r.Context()))
+}
+
+func (hh *healthHandler) startup(w http.ResponseWriter, r *http.Request) {
+	hh.respond(w, r, hh.h.Started(r.Context()))
+}
+
+// respond отвечает 200, если приложение готово, и 503, если нет. Состояние зависимостей
+// отправляется только с параметром verbose (?verbose или ?verbose=true).
+func (hh *healthHandler) respond(w http.ResponseWriter, r *http.Request, report health.Report) {
+	if !verbose(r) {
+		report.Dependencies = nil
+	}
+
+	if !report.OK() {
+		render.Status(r, http.StatusServiceUnavailable)
+	}
+
+	// Результат проверки не должен кешироваться прокси
+	w.Header().Set("Cache-Control", "no-store")
+	respond(w, r, report)
+}
+
+func verbose(r *http.Request) bool {
+	query := r.URL.Query()
+	if !query.Has(verboseParam) {
+		return false
+	}
+
+	if query.Get(verboseParam) == "" {
+		return true
+	}
+
+	v, err := strconv.ParseBool(query.Get(verboseParam))
+
+	return err == nil && v
+}
@@ -0,0 +1,61 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"filmoteka/internal/controller/problem"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+)
+
+type logHandler struct {
+	lv *logger.Levels
+	l  logger.Interface
+}
+
+// newLogHandler создает обработчик уровней журнала. lv равен nil, если уровни менять нельзя.
+func newLogHandler(lv *logger.Levels, l logger.Interface) *logHandler {
+	return &logHandler{lv: lv, l: l}
+}
+
+func (h *logHandler) levels(w http.ResponseWriter, r *http.Request) {
+	respond(w, r, LogLevelsResponse{Levels: h.lv.Get()})
+}
+
+// setLevel меняет уровень компонента до перезапуска приложения
+func (h *logHandler) setLevel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data logger.ComponentLevel
+	if err := render.DecodeJSON(r.Body, &data); err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to logger.ComponentLevel", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("logger.ComponentLevel"))
+
+		return
+	}
+
+	level, err := logger.ParseLevel(data.Level)
+	if err != nil {
+		problem.Error(w, r, usecase.Validation("invalid_log_level",
+			fmt.Sprintf("unknown log level %q, want debug, info, warn or error", data.Level)))
+
+		return
+	}
+
+	if err = h.lv.Set(data.Component, level); errors.Is(err, logger.ErrUnknownComponent) {
+		problem.Error(w, r, usecase.NotFound("log_component_not_found",
+			fmt.Sprintf("log component %q was NOT found", data.Component)))
+
+		return
+	}
+
+	// Уровень влияет на объем журнала, поэтому его изменение записывается как предупреждение
+	h.l.WarnContext(ctx, "log level changed", "log_component", data.Component, "level", logger.LevelName(level))
+
+	respond(w, r, LogLevelsResponse{Levels: h.lv.Get()})
+}
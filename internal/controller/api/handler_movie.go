@@ -2,12 +2,13 @@ package api
 
 import (
 	"net/http"
-	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
 	"golang.org/x/exp/slog"
 
+	"filmoteka/internal/controller/problem"
 	"filmoteka/internal/entity"
 	"filmoteka/internal/usecase"
 	"filmoteka/pkg/logger"
@@ -25,40 +26,60 @@ func newMovieHandler(t usecase.Movie, l logger.Interface) *movieHandler {
 func (h *movieHandler) find(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id, err := strconv.Atoi(chi.URLParam(r, "id"))
+	id, err := urlID(r)
 
-	if id == 0 {
+	if err != nil {
 
-		h.l.Info("No data available for id = 0")
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
 
-		render.JSON(w, r,
-			MovieResponse{
-				Status: "Wrong id. Id should be > 0",
-			})
+		problem.Error(w, r, err)
 
 		return
 	}
 
+	res, err := h.t.Find(ctx, id)
+
 	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r,
+		MovieResponse{
+			Status: StatusOk,
+			Movie:  &res,
+		})
+}
+
+func (h *movieHandler) findByExternalID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
 
-		h.l.Debug("id parameter in URL is not integer or empty", h.l.Err(err))
+	// Идентификатор передается в формате source:external_id, например imdb:tt0111161
+	source, externalID, ok := strings.Cut(chi.URLParam(r, "ref"), ":")
 
-		render.JSON(w, r, Error("Unable to retrieve id from URL"))
+	if !ok || source == "" || externalID == "" {
+
+		h.l.DebugContext(ctx, "external id in URL is not in source:external_id format")
+
+		problem.Error(w, r, errInvalidExternalID())
 
 		return
 	}
 
-	res, err := h.t.Find(ctx, id)
+	res, err := h.t.FindByExternalID(ctx, source, externalID)
 
 	if err != nil {
-		h.l.Debug("Failed to get data from DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to get data from DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		MovieResponse{
 			Status: StatusOk,
 			Movie:  &res,
@@ -71,103 +92,123 @@ func (h *movieHandler) save(w http.ResponseWriter, r *http.Request) {
 	var data entity.MovieData
 	err := render.DecodeJSON(r.Body, &data)
 	if err != nil {
-		h.l.Debug("Failed to decode request body to entity.MovieData", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.MovieData", h.l.Err(err))
 
-		render.JSON(w, r, Error("failed to decode request body to entity.MovieData"))
+		problem.Error(w, r, errInvalidBody("entity.MovieData"))
 
 		return
 	}
 
-	h.l.Info("request body decoded to entity.MovieData successfully", slog.Any("request", data))
+	h.l.InfoContext(ctx, "request body decoded to entity.MovieData successfully", slog.Any("request", data))
 
 	res, err := h.t.Save(ctx, data)
 
 	if err != nil {
-		h.l.Debug("Failed to save data in DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to save data in DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to save movie data in DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		MovieResponse{
 			Status: StatusOk,
 			Movie:  &res,
 		})
 }
 
-func (h *movieHandler) update(w http.ResponseWriter, r *http.Request) {
+func (h *movieHandler) upsert(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	var updates entity.Movie
-	err := render.DecodeJSON(r.Body, &updates)
+	var data entity.MovieData
+	err := render.DecodeJSON(r.Body, &data)
 	if err != nil {
-		h.l.Debug("Failed to decode request body to entity.Movie", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.MovieData", h.l.Err(err))
 
-		render.JSON(w, r, Error("failed to decode request body to entity.Movie"))
+		problem.Error(w, r, errInvalidBody("entity.MovieData"))
 
 		return
 	}
 
-	h.l.Info("request body decoded to entity.Person successfully", slog.Any("request", updates))
+	h.l.InfoContext(ctx, "request body decoded to entity.MovieData successfully", slog.Any("request", data))
 
-	res, err := h.t.Update(ctx, updates)
+	res, err := h.t.Upsert(ctx, data)
 
 	if err != nil {
-		h.l.Debug("Failed to update data in DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to upsert data in DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to update person data in DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		MovieResponse{
 			Status: StatusOk,
 			Movie:  &res,
 		})
 }
 
-func (h *movieHandler) delete(w http.ResponseWriter, r *http.Request) {
+func (h *movieHandler) update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	id, err := strconv.Atoi(chi.URLParam(r, "id"))
-
+	var updates entity.Movie
+	err := render.DecodeJSON(r.Body, &updates)
 	if err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.Movie", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("entity.Movie"))
+
+		return
+	}
+
+	h.l.InfoContext(ctx, "request body decoded to entity.Movie successfully", slog.Any("request", updates))
+
+	res, err := h.t.Update(ctx, updates)
 
-		h.l.Debug("id parameter in URL is not integer or empty", h.l.Err(err))
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to update data in DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to retrieve id from URL"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	h.l.Info("request body decoded to int successfully", slog.Any("request", id))
+	respond(w, r,
+		MovieResponse{
+			Status: StatusOk,
+			Movie:  &res,
+		})
+}
+
+func (h *movieHandler) delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
 
-	if id == 0 {
+	if err != nil {
 
-		h.l.Info("No data available for id = 0")
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
 
-		render.JSON(w, r,
-			MovieResponse{
-				Status: "Wrong id. Id should be > 0",
-			})
+		problem.Error(w, r, err)
 
 		return
 	}
 
+	h.l.InfoContext(ctx, "request body decoded to int successfully", slog.Any("request", id))
+
 	res, err := h.t.Delete(ctx, id)
 
 	if err != nil {
-		h.l.Debug("Failed to delete data from DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to delete data from DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to delete data from DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		MovieResponse{
 			Status: StatusOk,
 			Movie:  &res,
@@ -180,22 +221,22 @@ func (h *movieHandler) findMovie(w http.ResponseWriter, r *http.Request) {
 	res, err := h.t.FindMovie(ctx)
 
 	if err != nil {
-		h.l.Debug("Failed to get data from DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to get data from DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
 	if len(res) == 0 {
-		h.l.Debug("User input targeted does't target movie title or actor name")
+		h.l.DebugContext(ctx, "User input targeted does't target movie title or actor name")
 
-		render.JSON(w, r, Error("No movies are in database with the speciefied input data"))
+		problem.Error(w, r, usecase.NotFound("movies_not_found", "No movies are in database with the specified input data"))
 
 		return
 	}
 
-	render.JSON(w, r,
+	respond(w, r,
 		MovieResponse{
 			Status: StatusOk,
 			Movies: res,
@@ -3,7 +3,7 @@ package api
 import (
 	"net/http"
 
-	"github.com/go-chi/render"
+	"filmoteka/internal/controller/problem"
 )
 
 func (h *movieHandler) list(w http.ResponseWriter, r *http.Request) {
@@ -12,17 +12,17 @@ func (h *movieHandler) list(w http.ResponseWriter, r *http.Request) {
 	res, err := h.t.List(ctx)
 
 	if err != nil {
-		h.l.Debug("Failed to get data from DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to get data from DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
 	if len(res) == 0 {
-		h.l.Info("No data")
+		h.l.InfoContext(ctx, "No data")
 
-		render.JSON(w, r, customError{
+		respond(w, r, customError{
 			Status: StatusOk,
 			Error:  "No data",
 		})
@@ -32,7 +32,7 @@ func (h *movieHandler) list(w http.ResponseWriter, r *http.Request) {
 
 	id := res[len(res)-1].Id
 
-	render.JSON(w, r,
+	respond(w, r,
 		MovieResponse{
 			Status:      StatusOk,
 			Movies:      res,
@@ -48,17 +48,17 @@ func (h *movieHandler) next(w http.ResponseWriter, r *http.Request) {
 	res, err := h.t.Next(ctx)
 
 	if err != nil {
-		h.l.Debug("Failed to get data from DB", h.l.Err(err))
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
 
-		render.JSON(w, r, Error("Unable to get data from DB"))
+		problem.Error(w, r, err)
 
 		return
 	}
 
 	if len(res) == 0 {
-		h.l.Info("No data")
+		h.l.InfoContext(ctx, "No data")
 
-		render.JSON(w, r, customError{
+		respond(w, r, customError{
 			Status: StatusOk,
 			Error:  "No data",
 		})
@@ -68,7 +68,7 @@ func (h *movieHandler) next(w http.ResponseWriter, r *http.Request) {
 
 	id := res[len(res)-1].Id
 
-	render.JSON(w, r,
+	respond(w, r,
 		MovieResponse{
 			Status:      StatusOk,
 			Movies:      res,
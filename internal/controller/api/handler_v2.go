@@ -0,0 +1,499 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"golang.org/x/exp/slog"
+
+	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/controller/problem"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+)
+
+const apiV2 = "/api/v2"
+
+// v2Handler обслуживает ресурсы /api/v2 теми же usecase, что и маршруты v1
+type v2Handler struct {
+	a  usecase.Actor
+	m  usecase.Movie
+	am usecase.ActorMovie
+	l  logger.Interface
+}
+
+func newV2Handler(a usecase.Actor, m usecase.Movie, am usecase.ActorMovie, l logger.Interface) *v2Handler {
+	return &v2Handler{a: a, m: m, am: am, l: l}
+}
+
+// sortedList сообщает, запрошен ли список в порядке sort_by. Страницы списка идут по возрастанию id:
+// next_person_id - id, с которого начинается страница, поэтому в другом порядке отдается только первая страница.
+func sortedList(r *http.Request) (bool, error) {
+	q := r.URL.Query()
+	if !q.Has("sort_by") {
+		return false, nil
+	}
+
+	if q.Has(string(pagination.NextPersonID)) {
+		return false, usecase.Validation("sort_with_pagination",
+			"sort_by can't be combined with next_person_id: pages are ordered by id")
+	}
+
+	return true, nil
+}
+
+func (h *v2Handler) listMovies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	sorted, err := sortedList(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "sort_by is combined with next_person_id", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	var res []entity.Movie
+
+	if sorted {
+		res, err = h.m.List(ctx)
+	} else {
+		res, err = h.m.Next(ctx)
+	}
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	// next_id продолжает только список по возрастанию id
+	list := MovieList{Movies: nonNil(res)}
+	if len(res) != 0 && !sorted {
+		list.NextID = *res[len(res)-1].Id + 1
+	}
+
+	respond(w, r, list)
+}
+
+func (h *v2Handler) createMovie(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data entity.MovieData
+	err := render.DecodeJSON(r.Body, &data)
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.MovieData", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("entity.MovieData"))
+
+		return
+	}
+
+	h.l.InfoContext(ctx, "request body decoded to entity.MovieData successfully", slog.Any("request", data))
+
+	res, err := h.m.Save(ctx, data)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to save data in DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	created(w, r, fmt.Sprintf("%s/movies/%d", apiV2, *res.Id), res)
+}
+
+func (h *v2Handler) getMovie(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	res, err := h.m.Find(ctx, id)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r, res)
+}
+
+func (h *v2Handler) patchMovie(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	var data entity.MovieData
+	err = render.DecodeJSON(r.Body, &data)
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.MovieData", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("entity.MovieData"))
+
+		return
+	}
+
+	h.l.InfoContext(ctx, "request body decoded to entity.MovieData successfully", slog.Any("request", data))
+
+	res, err := h.m.Update(ctx, entity.Movie{Id: &id, MovieData: data})
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to update data in DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r, res)
+}
+
+func (h *v2Handler) deleteMovie(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	if _, err = h.m.Delete(ctx, id); err != nil {
+		h.l.DebugContext(ctx, "Failed to delete data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *v2Handler) movieCast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	// Для несуществующего фильма возвращаем 404, а не пустой список
+	if _, err = h.m.Find(ctx, id); err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	res, err := h.am.Cast(ctx, []int{id})
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r, ActorList{Actors: nonNil(res[id])})
+}
+
+func (h *v2Handler) addCast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	var data entity.ActorMovie
+	err = render.DecodeJSON(r.Body, &data)
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.ActorMovie", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("entity.ActorMovie"))
+
+		return
+	}
+
+	data.Movie_id = &id
+
+	h.l.InfoContext(ctx, "request body decoded to entity.ActorMovie successfully", slog.Any("request", data))
+
+	if err = h.am.Save(ctx, data); err != nil {
+		h.l.DebugContext(ctx, "Failed to save data in DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *v2Handler) removeCast(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	actorID, err := urlParamID(r, "actor_id")
+
+	if err != nil {
+		h.l.DebugContext(ctx, "actor_id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	if err = h.am.Delete(ctx, entity.ActorMovie{Actor_id: &actorID, Movie_id: &id}); err != nil {
+		h.l.DebugContext(ctx, "Failed to delete data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *v2Handler) listActors(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Страницы идут по возрастанию id, первая начинается с next_person_id = 0
+	res, err := h.a.Next(ctx)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	list := ActorList{Actors: nonNil(res)}
+	if len(res) != 0 {
+		list.NextID = *res[len(res)-1].Id + 1
+	}
+
+	respond(w, r, list)
+}
+
+func (h *v2Handler) createActor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var data entity.ActorData
+	err := render.DecodeJSON(r.Body, &data)
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.ActorData", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("entity.ActorData"))
+
+		return
+	}
+
+	h.l.InfoContext(ctx, "request body decoded to entity.ActorData successfully", slog.Any("request", data))
+
+	res, err := h.a.Save(ctx, data)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to save data in DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	created(w, r, fmt.Sprintf("%s/actors/%d", apiV2, *res.Id), res)
+}
+
+func (h *v2Handler) getActor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	res, err := h.a.Find(ctx, id)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r, res)
+}
+
+func (h *v2Handler) patchActor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	var data entity.ActorData
+	err = render.DecodeJSON(r.Body, &data)
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to decode request body to entity.ActorData", h.l.Err(err))
+
+		problem.Error(w, r, errInvalidBody("entity.ActorData"))
+
+		return
+	}
+
+	h.l.InfoContext(ctx, "request body decoded to entity.ActorData successfully", slog.Any("request", data))
+
+	res, err := h.a.Update(ctx, entity.Actor{Id: &id, ActorData: data})
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to update data in DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r, res)
+}
+
+func (h *v2Handler) deleteActor(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	if _, err = h.a.Delete(ctx, id); err != nil {
+		h.l.DebugContext(ctx, "Failed to delete data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *v2Handler) actorMovies(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	id, err := urlID(r)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "id parameter in URL is not positive integer", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	// Для несуществующего актера возвращаем 404, а не пустой список.
+	// Объединенный актер находится по старому id, фильмы берем по новому.
+	actor, err := h.a.Find(ctx, id)
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	res, err := h.am.Filmography(ctx, []int{*actor.Id})
+
+	if err != nil {
+		h.l.DebugContext(ctx, "Failed to get data from DB", h.l.Err(err))
+
+		problem.Error(w, r, err)
+
+		return
+	}
+
+	respond(w, r, MovieList{Movies: nonNil(res[*actor.Id])})
+}
+
+// created отправляет созданный ресурс со статусом 201 и его адресом в заголовке Location
+func created(w http.ResponseWriter, r *http.Request, location string, v interface{}) {
+	w.Header().Set("Location", location)
+	render.Status(r, http.StatusCreated)
+
+	respond(w, r, v)
+}
+
+// nonNil заменяет nil на пустой список, чтобы в JSON был [], а не null
+func nonNil[T any](s []T) []T {
+	if s == nil {
+		return []T{}
+	}
+
+	return s
+}
@@ -0,0 +1,127 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"filmoteka/config"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/repo/memory"
+	"filmoteka/pkg/logger"
+)
+
+// newMemoryRouter возвращает маршрутизатор над usecase с хранилищем в памяти и n фильмами.
+// Рейтинг фильмов не возрастает вместе с id, поэтому порядок по рейтингу отличается от порядка по id.
+func newMemoryRouter(t *testing.T, n int) *chi.Mux {
+	t.Helper()
+
+	s := memory.New()
+	l := logger.New("prod")
+
+	movies := memory.NewMoviesRepo(s)
+	for i := 1; i <= n; i++ {
+		title, rating := "Movie "+strconv.Itoa(i), i*7%11
+		if _, err := movies.Save(context.Background(), entity.MovieData{Title: &title, Rating: &rating}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	router := chi.NewRouter()
+	NewRouter(&config.Config{}, router, l,
+		usecase.NewActors(memory.NewActorsRepo(s), l),
+		usecase.NewMovies(movies, l),
+		usecase.NewActorsMovies(memory.NewActorsMoviesRepo(s), l),
+		nil, nil, nil, nil)
+
+	return router
+}
+
+func getMovies(t *testing.T, router http.Handler, query string) (MovieList, int) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, apiV2+"/movies"+query, nil))
+
+	var res MovieList
+	if rec.Code == http.StatusOK {
+		if err := json.NewDecoder(rec.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return res, rec.Code
+}
+
+func TestListMoviesPages(t *testing.T) {
+	const n = 25
+	router := newMemoryRouter(t, n)
+
+	// Первая страница без next_person_id и следующие страницы идут в одном порядке
+	seen := make(map[int]int)
+	query := ""
+	for pages := 0; ; pages++ {
+		if pages > n {
+			t.Fatal("pagination does not stop")
+		}
+
+		page, code := getMovies(t, router, query)
+		if code != http.StatusOK {
+			t.Fatalf("GET %s: status %d", query, code)
+		}
+
+		if len(page.Movies) == 0 {
+			break
+		}
+
+		for _, m := range page.Movies {
+			seen[*m.Id]++
+		}
+
+		query = "?next_person_id=" + strconv.Itoa(page.NextID)
+	}
+
+	for id := 1; id <= n; id++ {
+		if seen[id] != 1 {
+			t.Errorf("movie %d returned %d times, want once", id, seen[id])
+		}
+	}
+
+	if len(seen) != n {
+		t.Errorf("got %d movies, want %d", len(seen), n)
+	}
+}
+
+func TestListMoviesSorted(t *testing.T) {
+	router := newMemoryRouter(t, 25)
+
+	// С sort_by возвращается только первая страница, продолжить ее по next_id нельзя
+	page, code := getMovies(t, router, "?sort_by=rating")
+	if code != http.StatusOK {
+		t.Fatalf("status %d", code)
+	}
+
+	if page.NextID != 0 {
+		t.Errorf("sorted list has next_id %d", page.NextID)
+	}
+
+	if len(page.Movies) == 0 || *page.Movies[0].Rating != 10 {
+		t.Errorf("first movie of sorted list = %+v, want rating 10", page.Movies)
+	}
+
+	for i := 1; i < len(page.Movies); i++ {
+		if *page.Movies[i-1].Rating < *page.Movies[i].Rating {
+			t.Fatalf("movies are not sorted by rating: %d before %d", *page.Movies[i-1].Rating, *page.Movies[i].Rating)
+		}
+	}
+
+	if _, code = getMovies(t, router, "?sort_by=rating&next_person_id=5"); code != http.StatusBadRequest {
+		t.Errorf("sort_by with next_person_id: status %d, want 400", code)
+	}
+}
@@ -0,0 +1,556 @@
+package api
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	"filmoteka/internal/controller/gql"
+	"filmoteka/internal/controller/middleware/dateformat"
+	"filmoteka/internal/controller/problem"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/health"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+	"filmoteka/pkg/openapi"
+)
+
+// docRoute описывает маршрут для документации OpenAPI.
+// Каждый маршрут, зарегистрированный в NewRouter, должен быть описан в docRoutes (см. openapi_test.go).
+type docRoute struct {
+	method  string
+	path    string
+	id      string
+	tag     string
+	summary string
+	// Маршрут доступен только администратору
+	admin  bool
+	params []openapi.Parameter
+	// Тип тела запроса
+	body any
+	// Статус и тип успешного ответа. По умолчанию 200.
+	status   int
+	response any
+	// Список, который при отсутствии данных возвращает customError
+	list bool
+	// HTTP статусы ошибок, кроме 401 для admin и 500/503 для всех маршрутов
+	errors []int
+	// Тип ответа 503, если маршрут отвечает им вместо problem+json
+	unavailable any
+	// Маршрут не ограничивается по частоте запросов и не отвечает 429
+	unlimited bool
+	// Путь ресурса v2, который заменяет устаревший маршрут v1. Маршрут отвечает с заголовками Deprecation и Link.
+	successor string
+}
+
+var docRoutes = []docRoute{
+	{
+		method: http.MethodGet, path: "/actor/find/{id}", id: "findActor", tag: "actors",
+		summary: "Найти актера по id", params: []openapi.Parameter{idParam},
+		response: ActorResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		successor: apiV2 + "/actors",
+	},
+	{
+		method: http.MethodGet, path: "/actor/find_by_external_id/{ref}", id: "findActorByExternalID", tag: "actors",
+		summary: "Найти актера по идентификатору во внешнем каталоге", params: []openapi.Parameter{refParam},
+		response: ActorResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodPost, path: "/actor/save", id: "saveActor", tag: "actors",
+		summary: "Добавить актера", admin: true, body: entity.ActorData{},
+		response: ActorResponse{}, errors: []int{http.StatusBadRequest, http.StatusConflict},
+		successor: apiV2 + "/actors",
+	},
+	{
+		method: http.MethodPut, path: "/actor/upsert", id: "upsertActor", tag: "actors",
+		summary: "Обновить актера, найденного по external_ids, или добавить нового", admin: true,
+		body: entity.ActorData{}, response: ActorResponse{}, errors: []int{http.StatusBadRequest, http.StatusConflict},
+	},
+	{
+		method: http.MethodPut, path: "/actor/update", id: "updateActor", tag: "actors",
+		summary: "Изменить данные актера", admin: true, body: entity.Actor{},
+		response: ActorResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		successor: apiV2 + "/actors",
+	},
+	{
+		method: http.MethodDelete, path: "/actor/delete/{id}", id: "deleteActor", tag: "actors",
+		summary: "Удалить актера", admin: true, params: []openapi.Parameter{idParam},
+		response: ActorResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		successor: apiV2 + "/actors",
+	},
+	{
+		method: http.MethodPost, path: "/actor/merge", id: "mergeActors", tag: "actors",
+		summary: "Объединить актера source_id с актером target_id", admin: true, body: entity.ActorMerge{},
+		response: ActorResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/actors/duplicates", id: "findActorDuplicates", tag: "actors",
+		summary: "Найти вероятные дубликаты актеров", admin: true,
+		params: []openapi.Parameter{{
+			Name: "min_score", In: openapi.InQuery, Description: "Минимальное сходство пары актеров",
+			Schema: &openapi.Schema{Type: "number", Minimum: openapi.Float(0), Maximum: openapi.Float(1), Example: defaultDuplicateScore},
+		}},
+		response: ActorDuplicatesResponse{}, errors: []int{http.StatusBadRequest},
+	},
+	{
+		method: http.MethodGet, path: "/actors/list/", id: "listActors", tag: "actors",
+		summary: "Первая страница списка актеров", params: actorFilterParams(),
+		response: ActorResponse{}, list: true,
+		successor: apiV2 + "/actors",
+	},
+	{
+		method: http.MethodGet, path: "/actors/list/next", id: "nextActors", tag: "actors",
+		summary: "Следующая страница списка актеров", params: append(actorFilterParams(), paginationParam),
+		response: ActorResponse{}, list: true, errors: []int{http.StatusBadRequest},
+		successor: apiV2 + "/actors",
+	},
+	{
+		method: http.MethodGet, path: "/movie/find_by_id/{id}", id: "findMovie", tag: "movies",
+		summary: "Найти фильм по id", params: []openapi.Parameter{idParam},
+		response: MovieResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		successor: apiV2 + "/movies",
+	},
+	{
+		method: http.MethodGet, path: "/movie/find_by_external_id/{ref}", id: "findMovieByExternalID", tag: "movies",
+		summary: "Найти фильм по идентификатору во внешнем каталоге", params: []openapi.Parameter{refParam},
+		response: MovieResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/movie/find/", id: "searchMovies", tag: "movies",
+		summary: "Найти фильмы по фрагменту названия и имени актера",
+		params: []openapi.Parameter{
+			queryParam("title", "Фрагмент названия фильма", &openapi.Schema{Type: "string"}),
+			queryParam("actor_name", "Фрагмент имени актера", &openapi.Schema{Type: "string"}),
+		},
+		response: MovieResponse{}, errors: []int{http.StatusNotFound},
+	},
+	{
+		method: http.MethodPost, path: "/movie/save", id: "saveMovie", tag: "movies",
+		summary: "Добавить фильм", admin: true, body: entity.MovieData{},
+		response: MovieResponse{}, errors: []int{http.StatusBadRequest, http.StatusConflict},
+		successor: apiV2 + "/movies",
+	},
+	{
+		method: http.MethodPut, path: "/movie/upsert", id: "upsertMovie", tag: "movies",
+		summary: "Обновить фильм, найденный по external_ids, или добавить новый", admin: true,
+		body: entity.MovieData{}, response: MovieResponse{}, errors: []int{http.StatusBadRequest, http.StatusConflict},
+	},
+	{
+		method: http.MethodPut, path: "/movie/update", id: "updateMovie", tag: "movies",
+		summary: "Изменить данные фильма", admin: true, body: entity.Movie{},
+		response: MovieResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		successor: apiV2 + "/movies",
+	},
+	{
+		method: http.MethodDelete, path: "/movie/delete/{id}", id: "deleteMovie", tag: "movies",
+		summary: "Удалить фильм", admin: true, params: []openapi.Parameter{idParam},
+		response: MovieResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+		successor: apiV2 + "/movies",
+	},
+	{
+		method: http.MethodGet, path: "/movies/list/", id: "listMovies", tag: "movies",
+		summary: "Первая страница списка фильмов", params: append(movieFilterParams(), sortParams()...),
+		response: MovieResponse{}, list: true, errors: []int{http.StatusBadRequest},
+		successor: apiV2 + "/movies",
+	},
+	{
+		method: http.MethodGet, path: "/movies/list/next", id: "nextMovies", tag: "movies",
+		summary: "Следующая страница списка фильмов", params: append(append(movieFilterParams(), sortParams()...), paginationParam),
+		response: MovieResponse{}, list: true, errors: []int{http.StatusBadRequest},
+		successor: apiV2 + "/movies",
+	},
+	{
+		method: http.MethodGet, path: "/actor_movie/list", id: "listActorsMovies", tag: "actors_movies",
+		summary:  "Список актеров с фильмами, в которых они снимались",
+		response: ActorsMoviesResponse{}, list: true,
+		successor: apiV2 + "/movies",
+	},
+	{
+		method: http.MethodPost, path: "/actor_movie/save", id: "saveActorMovie", tag: "actors_movies",
+		summary: "Связать актера с фильмом", admin: true, body: entity.ActorMovie{},
+		response: ActorMovieResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+		successor: apiV2 + "/movies",
+	},
+	{
+		method: http.MethodGet, path: apiV2 + "/movies", id: "listMoviesV2", tag: "movies",
+		summary: "Список фильмов по возрастанию id или его первая страница в порядке sort_by", params: append(append(movieFilterParams(), sortParams()...), paginationParam),
+		response: MovieList{}, errors: []int{http.StatusBadRequest},
+	},
+	{
+		method: http.MethodPost, path: apiV2 + "/movies", id: "createMovieV2", tag: "movies",
+		summary: "Добавить фильм", admin: true, body: entity.MovieData{},
+		status: http.StatusCreated, response: entity.Movie{}, errors: []int{http.StatusBadRequest, http.StatusConflict},
+	},
+	{
+		method: http.MethodGet, path: apiV2 + "/movies/{id}", id: "getMovieV2", tag: "movies",
+		summary: "Фильм", params: []openapi.Parameter{idParam},
+		response: entity.Movie{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodPatch, path: apiV2 + "/movies/{id}", id: "patchMovieV2", tag: "movies",
+		summary: "Изменить переданные поля фильма", admin: true, params: []openapi.Parameter{idParam},
+		body: entity.MovieData{}, response: entity.Movie{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodDelete, path: apiV2 + "/movies/{id}", id: "deleteMovieV2", tag: "movies",
+		summary: "Удалить фильм", admin: true, params: []openapi.Parameter{idParam},
+		status: http.StatusNoContent, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodGet, path: apiV2 + "/movies/{id}/cast", id: "getMovieCastV2", tag: "movies",
+		summary: "Актеры фильма", params: []openapi.Parameter{idParam},
+		response: ActorList{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodPost, path: apiV2 + "/movies/{id}/cast", id: "addMovieCastV2", tag: "movies",
+		summary: "Добавить актера actor_id в фильм", admin: true, params: []openapi.Parameter{idParam},
+		body: entity.ActorMovie{}, status: http.StatusNoContent,
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodDelete, path: apiV2 + "/movies/{id}/cast/{actor_id}", id: "removeMovieCastV2", tag: "movies",
+		summary: "Убрать актера actor_id из фильма", admin: true, params: []openapi.Parameter{idParam, actorIDParam},
+		status: http.StatusNoContent, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: apiV2 + "/actors", id: "listActorsV2", tag: "actors",
+		summary: "Список актеров", params: append(actorFilterParams(), paginationParam),
+		response: ActorList{}, errors: []int{http.StatusBadRequest},
+	},
+	{
+		method: http.MethodPost, path: apiV2 + "/actors", id: "createActorV2", tag: "actors",
+		summary: "Добавить актера", admin: true, body: entity.ActorData{},
+		status: http.StatusCreated, response: entity.Actor{}, errors: []int{http.StatusBadRequest, http.StatusConflict},
+	},
+	{
+		method: http.MethodGet, path: apiV2 + "/actors/{id}", id: "getActorV2", tag: "actors",
+		summary: "Актер", params: []openapi.Parameter{idParam},
+		response: entity.Actor{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodPatch, path: apiV2 + "/actors/{id}", id: "patchActorV2", tag: "actors",
+		summary: "Изменить переданные поля актера", admin: true, params: []openapi.Parameter{idParam},
+		body: entity.ActorData{}, response: entity.Actor{},
+		errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodDelete, path: apiV2 + "/actors/{id}", id: "deleteActorV2", tag: "actors",
+		summary: "Удалить актера", admin: true, params: []openapi.Parameter{idParam},
+		status: http.StatusNoContent, errors: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	},
+	{
+		method: http.MethodGet, path: apiV2 + "/actors/{id}/movies", id: "getActorMoviesV2", tag: "actors",
+		summary: "Фильмы актера", params: []openapi.Parameter{idParam},
+		response: MovieList{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/graphql", id: "graphqlQuery", tag: "graphql",
+		summary: "Запрос GraphQL в параметрах URL. Мутации принимаются только через POST.",
+		params: []openapi.Parameter{
+			queryParam("query", "Текст запроса", &openapi.Schema{Type: "string"}),
+			queryParam("operationName", "Выполняемая операция", &openapi.Schema{Type: "string"}),
+			queryParam("variables", "Переменные запроса (JSON)", &openapi.Schema{Type: "string"}),
+		},
+		response: gql.Response{}, errors: []int{http.StatusBadRequest, http.StatusMethodNotAllowed},
+	},
+	{
+		method: http.MethodPost, path: "/graphql", id: "graphqlMutation", tag: "graphql",
+		summary: "Запрос GraphQL. Мутации требуют учетных данных администратора.",
+		body:    gql.Request{}, response: gql.Response{}, errors: []int{http.StatusBadRequest, http.StatusUnauthorized},
+	},
+	{
+		method: http.MethodGet, path: "/cache/stats", id: "cacheStats", tag: "service",
+		summary: "Попадания в кеш ответов и промахи: всего и по методам", admin: true,
+		response: CacheStatsResponse{},
+	},
+	{
+		method: http.MethodGet, path: "/log/levels", id: "logLevels", tag: "service",
+		summary: "Уровни журналов компонентов", admin: true, response: LogLevelsResponse{},
+	},
+	{
+		method: http.MethodPut, path: "/log/levels", id: "setLogLevel", tag: "service",
+		summary: "Изменить уровень журнала компонента до перезапуска", admin: true, body: logger.ComponentLevel{},
+		response: LogLevelsResponse{}, errors: []int{http.StatusBadRequest, http.StatusNotFound},
+	},
+	{
+		method: http.MethodGet, path: "/healthz", id: "live", tag: "service",
+		summary: "Проверка живости: процесс отвечает, зависимости не проверяются", response: health.Report{},
+		unlimited: true,
+	},
+	{
+		method: http.MethodGet, path: "/readyz", id: "ready", tag: "service",
+		summary: "Проверка готовности: БД, миграции, остановка приложения", params: []openapi.Parameter{verboseDocParam},
+		response: health.Report{}, unavailable: health.Report{}, unlimited: true,
+	},
+	{
+		method: http.MethodGet, path: "/startupz", id: "startup", tag: "service",
+		summary: "Проверка запуска: первая успешная проверка готовности", params: []openapi.Parameter{verboseDocParam},
+		response: health.Report{}, unavailable: health.Report{}, unlimited: true,
+	},
+}
+
+// rateLimitHeaders - заголовки ответа 429 (см. пакет ratelimit). Заголовки RateLimit-* есть и в остальных ответах.
+var rateLimitHeaders = map[string]openapi.Header{
+	"Retry-After": {Description: "Через сколько секунд повторить запрос", Schema: &openapi.Schema{Type: "integer"}},
+	"RateLimit-Limit": {
+		Description: "Сколько запросов клиент может сделать подряд", Schema: &openapi.Schema{Type: "integer"},
+	},
+	"RateLimit-Remaining": {
+		Description: "Сколько запросов осталось", Schema: &openapi.Schema{Type: "integer"},
+	},
+	"RateLimit-Reset": {
+		Description: "Через сколько секунд ограничение полностью восстановится", Schema: &openapi.Schema{Type: "integer"},
+	},
+	"RateLimit-Policy": {
+		Description: "Ограничение: запросов подряд и окно в секундах, например 10;w=5", Schema: &openapi.Schema{Type: "string"},
+	},
+}
+
+var (
+	idParam = openapi.Parameter{
+		Name: "id", In: openapi.InPath, Required: true, Description: "Положительный id",
+		Schema: &openapi.Schema{Type: "integer", Minimum: openapi.Float(1)},
+	}
+
+	actorIDParam = openapi.Parameter{
+		Name: "actor_id", In: openapi.InPath, Required: true, Description: "Положительный id актера",
+		Schema: &openapi.Schema{Type: "integer", Minimum: openapi.Float(1)},
+	}
+
+	refParam = openapi.Parameter{
+		Name: "ref", In: openapi.InPath, Required: true,
+		Description: "Идентификатор во внешнем каталоге в формате source:external_id",
+		Schema:      &openapi.Schema{Type: "string", Example: "imdb:tt0111161"},
+	}
+
+	paginationParam = openapi.Parameter{
+		Name: "next_person_id", In: openapi.InQuery,
+		Description: "id, с которого начинается страница (next_actor_id или next_movie_id из предыдущего ответа)",
+		Schema:      &openapi.Schema{Type: "integer"},
+	}
+)
+
+// Параметры выбора формата дат (middleware dateformat) добавляются ко всем маршрутам
+var dateFormatParams = []openapi.Parameter{
+	{
+		Name: dateformat.QueryParam, In: openapi.InQuery, Description: "Формат дат в ответе",
+		Schema: &openapi.Schema{Type: "string", Enum: []any{"iso", "ru"}},
+	},
+	{
+		Name: dateformat.Header, In: openapi.InHeader, Description: "Формат дат в ответе, если не указан параметр date_format",
+		Schema: &openapi.Schema{Type: "string", Enum: []any{"iso", "ru"}},
+	},
+}
+
+var verboseDocParam = queryParam(verboseParam, "Перечислить состояние и время проверки каждой зависимости",
+	&openapi.Schema{Type: "boolean"})
+
+func queryParam(name, description string, schema *openapi.Schema) openapi.Parameter {
+	return openapi.Parameter{Name: name, In: openapi.InQuery, Description: description, Schema: schema}
+}
+
+// Фильтры (middleware filter): любой параметр запроса сравнивается со столбцом с тем же названием
+func actorFilterParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		queryParam("name", "Фильтр по имени", &openapi.Schema{Type: "string"}),
+		queryParam("surname", "Фильтр по фамилии", &openapi.Schema{Type: "string"}),
+		queryParam("patronymic", "Фильтр по отчеству", &openapi.Schema{Type: "string"}),
+		queryParam("gender", "Фильтр по полу", &openapi.Schema{Type: "string", Enum: []any{"male", "female"}}),
+		queryParam("date_of_birth", "Фильтр по дате рождения", &openapi.Schema{Type: "string"}),
+	}
+}
+
+func movieFilterParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		queryParam("title", "Фильтр по названию", &openapi.Schema{Type: "string"}),
+		queryParam("description", "Фильтр по описанию", &openapi.Schema{Type: "string"}),
+		queryParam("release_date", "Фильтр по дате выпуска", &openapi.Schema{Type: "string"}),
+		queryParam("rating", "Фильтр по рейтингу", &openapi.Schema{Type: "integer"}),
+	}
+}
+
+// Параметры сортировки (middleware sort). Параметры можно повторять, по умолчанию - rating DESC.
+func sortParams() []openapi.Parameter {
+	return []openapi.Parameter{
+		queryParam("sort_by", "Поле сортировки",
+			&openapi.Schema{Type: "string", Enum: []any{"title", "rating", "release_date"}}),
+		queryParam("sort_order", "Направление сортировки для поля sort_by с тем же номером",
+			&openapi.Schema{Type: "string", Enum: []any{"asc", "desc"}}),
+	}
+}
+
+const (
+	contentJSON = "application/json"
+	basicAuth   = "basicAuth"
+)
+
+// newSpec строит документ OpenAPI по описаниям маршрутов
+func newSpec() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:       "Filmoteka API",
+		Description: "REST API фильмотеки: фильмы, актеры и связи между ними",
+		Version:     "1.0.0",
+	})
+
+	doc.Tags = []openapi.Tag{
+		{Name: "actors", Description: "Актеры"},
+		{Name: "movies", Description: "Фильмы"},
+		{Name: "actors_movies", Description: "Связи актеров с фильмами"},
+		{Name: "graphql", Description: "Схема GraphQL над фильмами, актерами и связями между ними"},
+		{Name: "service", Description: "Служебные маршруты"},
+	}
+
+	doc.Components.SecuritySchemes[basicAuth] = openapi.SecurityScheme{
+		Type: "http", Scheme: "basic", Description: "Учетная запись администратора (HTTP_USER, HTTP_PASS)",
+	}
+
+	reflector := openapi.NewReflector(doc.Components.Schemas)
+	reflector.Define(entity.Date{}, &openapi.Schema{
+		Type:        "string",
+		Description: "Дата ISO 8601 (YYYY-MM-DD, YYYY-MM, YYYY) или DD.MM.YYYY. Ответ - в формате, выбранном date_format.",
+		Example:     "1994-09-23",
+	})
+
+	problemSchema := reflector.Schema(problem.Problem{})
+	noData := reflector.Schema(customError{})
+
+	for _, route := range docRoutes {
+		op := &openapi.Operation{
+			Tags:        []string{route.tag},
+			Summary:     route.summary,
+			OperationID: route.id,
+			Parameters:  append(append([]openapi.Parameter{}, route.params...), dateFormatParams...),
+			Responses:   make(map[string]openapi.Response),
+			Deprecated:  route.successor != "",
+		}
+
+		if route.body != nil {
+			op.RequestBody = &openapi.RequestBody{
+				Required: true,
+				Content:  map[string]openapi.MediaType{contentJSON: {Schema: reflector.Schema(route.body)}},
+			}
+		}
+
+		status := route.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		success := openapi.Response{Description: http.StatusText(status)}
+
+		if route.response != nil {
+			schema := reflector.Schema(route.response)
+			if route.list {
+				schema = &openapi.Schema{OneOf: []*openapi.Schema{schema, noData}}
+			}
+
+			success.Content = map[string]openapi.MediaType{contentJSON: {Schema: schema}}
+		}
+
+		if status == http.StatusCreated {
+			success.Headers = map[string]openapi.Header{
+				"Location": {Description: "Адрес созданного ресурса", Schema: &openapi.Schema{Type: "string"}},
+			}
+		}
+
+		op.Responses[strconv.Itoa(status)] = success
+
+		statuses := append([]int{}, route.errors...)
+		if route.admin {
+			op.Security = []openapi.SecurityRequirement{{basicAuth: {}}}
+			statuses = append(statuses, http.StatusUnauthorized)
+		}
+
+		for _, status := range append(statuses, http.StatusInternalServerError, http.StatusServiceUnavailable) {
+			op.Responses[strconv.Itoa(status)] = openapi.Response{
+				Description: http.StatusText(status),
+				Content:     map[string]openapi.MediaType{problem.ContentType: {Schema: problemSchema}},
+			}
+		}
+
+		if !route.unlimited {
+			op.Responses[strconv.Itoa(http.StatusTooManyRequests)] = openapi.Response{
+				Description: http.StatusText(http.StatusTooManyRequests),
+				Headers:     rateLimitHeaders,
+				Content:     map[string]openapi.MediaType{problem.ContentType: {Schema: problemSchema}},
+			}
+		}
+
+		if route.unavailable != nil {
+			op.Responses[strconv.Itoa(http.StatusServiceUnavailable)] = openapi.Response{
+				Description: http.StatusText(http.StatusServiceUnavailable),
+				Content:     map[string]openapi.MediaType{contentJSON: {Schema: reflector.Schema(route.unavailable)}},
+			}
+		}
+
+		doc.AddOperation(strings.ToLower(route.method), route.path, op)
+	}
+
+	constrainEntities(doc.Components.Schemas)
+
+	return doc
+}
+
+// constrainEntities добавляет в схемы актеров и фильмов ограничения, которые проверяет usecase
+func constrainEntities(schemas map[string]*openapi.Schema) {
+	for _, name := range []string{"Actor", "ActorData"} {
+		if s, ok := schemas[name]; ok {
+			for _, field := range []string{"name", "surname", "patronymic"} {
+				s.Properties[field].MaxLength = openapi.Int(usecase.MaxNameLen)
+			}
+			s.Properties["gender"].Enum = []any{"male", "female"}
+		}
+	}
+
+	for _, name := range []string{"Movie", "MovieData"} {
+		if s, ok := schemas[name]; ok {
+			s.Properties["title"].MinLength = openapi.Int(1)
+			s.Properties["title"].MaxLength = openapi.Int(usecase.MaxTitleLen)
+			s.Properties["description"].MaxLength = openapi.Int(usecase.MaxDescriptionLen)
+			s.Properties["rating"].Minimum = openapi.Float(usecase.MinRating)
+			s.Properties["rating"].Maximum = openapi.Float(usecase.MaxRating)
+		}
+	}
+}
+
+// openAPIHandler отдает документ OpenAPI, построенный один раз при запуске
+func openAPIHandler() http.HandlerFunc {
+	spec := newSpec()
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.JSON(w, r, spec)
+	}
+}
+
+// Swagger UI загружает документ /openapi.json вместо примера из поставки
+const swaggerInitializer = `window.onload = function() {
+  window.ui = SwaggerUIBundle({
+    url: "/openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout"
+  });
+};
+`
+
+// swaggerUIHandler отдает встроенные в бинарник файлы Swagger UI по адресу /docs/
+func swaggerUIHandler() http.Handler {
+	files := http.FileServer(http.FS(swaggerFiles.FS))
+
+	return http.StripPrefix("/docs", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/swagger-initializer.js" {
+			w.Header().Set("Content-Type", "application/javascript")
+			_, _ = io.WriteString(w, swaggerInitializer)
+			return
+		}
+
+		files.ServeHTTP(w, r)
+	}))
+}
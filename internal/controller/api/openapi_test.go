@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"filmoteka/config"
+	"filmoteka/pkg/logger"
+	"filmoteka/pkg/openapi"
+)
+
+// Маршруты документации не описываются в самой документации
+var undocumentedRoutes = map[string]bool{
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+	"GET /docs/*":       true,
+}
+
+func newTestRouter(t *testing.T) *chi.Mux {
+	t.Helper()
+
+	router := chi.NewRouter()
+	NewRouter(&config.Config{}, router, logger.New("local"), nil, nil, nil, nil, nil, nil, nil)
+
+	return router
+}
+
+// Тест падает, если маршрут зарегистрирован в NewRouter, но не описан в docRoutes, и наоборот
+func TestOpenAPIMatchesRouter(t *testing.T) {
+	registered := make(map[string]bool)
+
+	err := chi.Walk(newTestRouter(t), func(method, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// chi регистрирует обработчик /docs/* для всех методов
+		key := method + " " + route
+		if strings.HasPrefix(route, "/docs/") && method != http.MethodGet {
+			return nil
+		}
+
+		if !undocumentedRoutes[key] {
+			registered[trimSlash(method, route)] = true
+		}
+
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("chi.Walk returned error: %v", err)
+	}
+
+	documented := make(map[string]bool)
+	for path, item := range newSpec().Paths {
+		for method := range *item {
+			documented[trimSlash(strings.ToUpper(method), path)] = true
+		}
+	}
+
+	for route := range registered {
+		if !documented[route] {
+			t.Errorf("route %s is registered in NewRouter but NOT described in docRoutes", route)
+		}
+	}
+
+	for route := range documented {
+		if !registered[route] {
+			t.Errorf("route %s is described in docRoutes but NOT registered in NewRouter", route)
+		}
+	}
+}
+
+var pathParamRe = regexp.MustCompile(`\{(\w+)\}`)
+
+func TestOpenAPISpecIsConsistent(t *testing.T) {
+	spec := newSpec()
+
+	ids := make(map[string]bool)
+
+	for path, item := range spec.Paths {
+		for method, op := range *item {
+			if ids[op.OperationID] {
+				t.Errorf("%s %s: duplicate operationId %q", method, path, op.OperationID)
+			}
+			ids[op.OperationID] = true
+
+			for _, m := range pathParamRe.FindAllStringSubmatch(path, -1) {
+				if !hasParam(op.Parameters, m[1], openapi.InPath) {
+					t.Errorf("%s %s: path parameter %q is NOT described", method, path, m[1])
+				}
+			}
+		}
+	}
+
+	// Все ссылки на схемы должны вести к схемам из components
+	b, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatalf("failed to marshal spec: %v", err)
+	}
+
+	for _, m := range regexp.MustCompile(`"#/components/schemas/(\w+)"`).FindAllStringSubmatch(string(b), -1) {
+		if _, ok := spec.Components.Schemas[m[1]]; !ok {
+			t.Errorf("schema %q is referenced but NOT defined", m[1])
+		}
+	}
+}
+
+func TestOpenAPIServed(t *testing.T) {
+	router := newTestRouter(t)
+
+	for _, path := range []string{"/openapi.json", "/docs/", "/docs/swagger-initializer.js"} {
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+
+		if rec.Code != http.StatusOK {
+			t.Errorf("GET %s: expected status 200, got %d", path, rec.Code)
+		}
+	}
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+
+	var doc openapi.Document
+	if err := json.Unmarshal(rec.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("/openapi.json is NOT valid JSON: %v", err)
+	}
+
+	if doc.OpenAPI != openapi.Version || len(doc.Paths) == 0 {
+		t.Errorf("/openapi.json returned unexpected document: openapi=%q, paths=%d", doc.OpenAPI, len(doc.Paths))
+	}
+}
+
+// trimSlash убирает завершающий слеш: chi регистрирует r.Route("/movies") + r.Get("/") как /movies/,
+// но отвечает и на /movies
+func trimSlash(method, route string) string {
+	if len(route) > 1 {
+		route = strings.TrimSuffix(route, "/")
+	}
+
+	return method + " " + route
+}
+
+func hasParam(params []openapi.Parameter, name, in string) bool {
+	for _, p := range params {
+		if p.Name == name && p.In == in {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"filmoteka/internal/usecase"
+)
+
+func errInvalidExternalID() error {
+	return usecase.Validation("invalid_external_id", "external id should be in source:external_id format")
+}
+
+func errInvalidBody(target string) error {
+	return usecase.Validation("invalid_body", "failed to decode request body to "+target)
+}
+
+// urlID возвращает положительный id из URL запроса
+func urlID(r *http.Request) (int, error) {
+	return urlParamID(r, "id")
+}
+
+// urlParamID возвращает положительный id из параметра name в URL запроса
+func urlParamID(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(chi.URLParam(r, name))
+
+	if err != nil || id <= 0 {
+		return 0, usecase.Validation("invalid_"+name, name+" should be a positive integer").Wrap(err)
+	}
+
+	return id, nil
+}
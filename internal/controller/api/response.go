@@ -1,6 +1,15 @@
 package api
 
-import "filmoteka/internal/entity"
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"filmoteka/internal/controller/middleware/dateformat"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase/cache"
+	"filmoteka/pkg/logger"
+)
 
 type ActorResponse struct {
 	Status      string         `json:"status,omitempty"`
@@ -9,6 +18,11 @@ type ActorResponse struct {
 	NextActorID int            `json:"next_actor_id,omitempty"`
 }
 
+type ActorDuplicatesResponse struct {
+	Status     string                   `json:"status,omitempty"`
+	Duplicates []entity.DuplicateActors `json:"duplicates"`
+}
+
 type MovieResponse struct {
 	Status      string         `json:"status,omitempty"`
 	Movie       *entity.Movie  `json:"movie,omitempty"`
@@ -26,19 +40,40 @@ type ActorsMoviesResponse struct {
 	Data   []entity.MoviesOfActor `json:"movies_of_actor,omitempty"`
 }
 
+// CacheStatsResponse - попадания в кеш ответов и промахи с момента запуска сервера
+type CacheStatsResponse struct {
+	Enabled bool `json:"enabled"`
+	cache.Stats
+}
+
+// LogLevelsResponse - уровни журналов компонентов
+type LogLevelsResponse struct {
+	Levels []logger.ComponentLevel `json:"levels"`
+}
+
+// Списки ресурсов /api/v2. NextID - значение next_person_id для запроса следующей страницы.
+type MovieList struct {
+	Movies []entity.Movie `json:"movies"`
+	NextID int            `json:"next_id,omitempty"`
+}
+
+type ActorList struct {
+	Actors []entity.Actor `json:"actors"`
+	NextID int            `json:"next_id,omitempty"`
+}
+
 const (
-	StatusOk    = "OK"
-	StatusError = "Error"
+	StatusOk = "OK"
 )
 
+// Ответ на запрос списка, когда данных нет.
+// Ошибки отправляются в формате application/problem+json (см. internal/controller/problem).
 type customError struct {
 	Status string `json:"status,omitempty"`
 	Error  string `json:"error,omitempty"`
 }
 
-func Error(msg string) customError {
-	return customError{
-		Status: StatusError,
-		Error:  msg,
-	}
+// respond отправляет ответ в JSON, записывая даты в формате, выбранном клиентом
+func respond(w http.ResponseWriter, r *http.Request, v interface{}) {
+	render.JSON(w, r, dateformat.Apply(v, dateformat.FromContext(r.Context())))
 }
@@ -1,46 +1,91 @@
 package api
 
 import (
+	"net/http"
+
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
 	"filmoteka/config"
+	"filmoteka/internal/controller/gql"
+	"filmoteka/internal/controller/middleware/auth"
+	"filmoteka/internal/controller/middleware/client"
+	"filmoteka/internal/controller/middleware/dateformat"
+	"filmoteka/internal/controller/middleware/deprecation"
 	"filmoteka/internal/controller/middleware/filter"
 	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/controller/middleware/ratelimit"
+	"filmoteka/internal/controller/middleware/requestlog"
 	"filmoteka/internal/controller/middleware/sort"
+	"filmoteka/internal/health"
 	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/cache"
 	"filmoteka/pkg/logger"
 )
 
-func NewRouter(cfg *config.Config, router *chi.Mux, l logger.Interface, a usecase.Actor, m usecase.Movie, am usecase.ActorMovie) {
+// c - кеш ответов, статистика которого отдается по /cache/stats, или nil, если кеш выключен.
+// hc - проверки живости, готовности и запуска (/healthz, /readyz, /startupz).
+// lv - уровни журналов компонентов, которые меняются через /log/levels.
+// limiter - ограничитель запросов, общий с gRPC, или nil, если запросы не ограничиваются.
+func NewRouter(cfg *config.Config, router *chi.Mux, l logger.Interface, a usecase.Actor, m usecase.Movie, am usecase.ActorMovie, c *cache.Cache, hc *health.Health, lv *logger.Levels, limiter *ratelimit.Limiter) {
 	// Middleware для общего использования
 	commonMiddleware := chi.Chain(
 		middleware.RequestID,
-		middleware.Logger,
+		requestlog.Middleware(l),
 		middleware.Recoverer,
+		limiter.Middleware,
 		middleware.URLFormat,
+		dateformat.Middleware,
+		client.Middleware(limiter.ClientIP),
 	)
 
-	// Middleware для авторизации администратора
-	adminAuthMiddleware := middleware.BasicAuth("filmoteka", map[string]string{
+	// Middleware для авторизации администратора. Неудачные попытки входа ограничиваются отдельно.
+	adminAuth := auth.Basic("filmoteka", map[string]string{
 		cfg.HTTPServer.User: cfg.HTTPServer.Pass,
 	})
+	adminAuthMiddleware := func(next http.Handler) http.Handler {
+		return limiter.AuthFailures(adminAuth(next))
+	}
 
 	actor := newActorHandler(a, l)
 	movie := newMovieHandler(m, l)
 	actor_movie := newActorMovieHandler(am, l)
+	cacheStats := newCacheHandler(c)
+	healthCheck := newHealthHandler(hc)
+	logLevels := newLogHandler(lv, l)
+
+	// Документация API. Маршруты ниже описываются в docRoutes (openapi.go).
+	router.Get("/openapi.json", openAPIHandler())
+	router.Get("/docs", func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "/docs/", http.StatusMovedPermanently)
+	})
+	router.Handle("/docs/*", swaggerUIHandler())
+
+	// Проверки состояния без авторизации и журнала запросов: их часто вызывают оркестратор и балансировщик
+	router.Get("/healthz", healthCheck.live)
+	router.Get("/readyz", healthCheck.ready)
+	router.Get("/startupz", healthCheck.startup)
+
+	// Маршруты v1, у которых есть замена в /api/v2, устарели. Поиск, upsert, объединение актеров
+	// и поиск по внешнему идентификатору в v2 не перенесены и устаревшими не считаются.
+	actorsV1 := deprecation.Middleware(apiV2 + "/actors")
+	moviesV1 := deprecation.Middleware(apiV2 + "/movies")
 
 	router.Route("/actor", func(r chi.Router) {
 		r.Use(commonMiddleware.Handler)
-		r.Get("/find/{id}", actor.find)
-		r.With(adminAuthMiddleware).Post("/save", actor.save)
-		r.With(adminAuthMiddleware).Put("/update", actor.update)
-		r.With(adminAuthMiddleware).Delete("/delete/{id}", actor.delete)
+		r.With(actorsV1).Get("/find/{id}", actor.find)
+		r.Get("/find_by_external_id/{ref}", actor.findByExternalID)
+		r.With(actorsV1, adminAuthMiddleware).Post("/save", actor.save)
+		r.With(adminAuthMiddleware).Put("/upsert", actor.upsert)
+		r.With(actorsV1, adminAuthMiddleware).Put("/update", actor.update)
+		r.With(actorsV1, adminAuthMiddleware).Delete("/delete/{id}", actor.delete)
+		r.With(adminAuthMiddleware).Post("/merge", actor.merge)
 	})
 
 	router.Route("/actors", func(r chi.Router) {
+		r.With(commonMiddleware.Handler, adminAuthMiddleware).Get("/duplicates", actor.duplicates)
 		r.Route("/list", func(r chi.Router) {
-			r.Use(commonMiddleware.Handler)
+			r.Use(commonMiddleware.Handler, actorsV1)
 			r.With(filter.Middleware).Get("/", actor.list)
 			r.With(filter.Middleware, pagination.Middleware).Get("/next", actor.next)
 		})
@@ -48,25 +93,69 @@ func NewRouter(cfg *config.Config, router *chi.Mux, l logger.Interface, a usecas
 
 	router.Route("/movie", func(r chi.Router) {
 		r.Use(commonMiddleware.Handler)
-		r.Get("/find_by_id/{id}", movie.find)
+		r.With(moviesV1).Get("/find_by_id/{id}", movie.find)
+		r.Get("/find_by_external_id/{ref}", movie.findByExternalID)
 		r.With(filter.Middleware).Get("/find/", movie.findMovie)
-		r.With(adminAuthMiddleware).Post("/save", movie.save)
-		r.With(adminAuthMiddleware).Put("/update", movie.update)
-		r.With(adminAuthMiddleware).Delete("/delete/{id}", movie.delete)
+		r.With(moviesV1, adminAuthMiddleware).Post("/save", movie.save)
+		r.With(adminAuthMiddleware).Put("/upsert", movie.upsert)
+		r.With(moviesV1, adminAuthMiddleware).Put("/update", movie.update)
+		r.With(moviesV1, adminAuthMiddleware).Delete("/delete/{id}", movie.delete)
 
 	})
 
 	router.Route("/movies", func(r chi.Router) {
 		r.Route("/list", func(r chi.Router) {
-			r.Use(commonMiddleware.Handler)
+			r.Use(commonMiddleware.Handler, moviesV1)
 			r.With(filter.Middleware, sort.Middleware).Get("/", movie.list)
 			r.With(filter.Middleware, sort.Middleware, pagination.Middleware).Get("/next", movie.next)
 		})
 	})
 
+	// Связи актеров с фильмами в v2 - состав фильма /api/v2/movies/{id}/cast
 	router.Route("/actor_movie", func(r chi.Router) {
-		r.Use(commonMiddleware.Handler)
+		r.Use(commonMiddleware.Handler, moviesV1)
 		r.Get("/list", actor_movie.list)
 		r.With(adminAuthMiddleware).Post("/save", actor_movie.save)
 	})
+
+	router.With(commonMiddleware.Handler, adminAuthMiddleware).Get("/cache/stats", cacheStats.stats)
+
+	router.With(commonMiddleware.Handler, adminAuthMiddleware).Get("/log/levels", logLevels.levels)
+	router.With(commonMiddleware.Handler, adminAuthMiddleware).Put("/log/levels", logLevels.setLevel)
+
+	// Ресурсы API v2
+	v2 := newV2Handler(a, m, am, l)
+
+	router.Route(apiV2, func(r chi.Router) {
+		r.Use(commonMiddleware.Handler)
+
+		r.Route("/movies", func(r chi.Router) {
+			r.With(filter.Middleware, sort.Middleware, pagination.Middleware).Get("/", v2.listMovies)
+			r.With(adminAuthMiddleware).Post("/", v2.createMovie)
+			r.Get("/{id}", v2.getMovie)
+			r.With(adminAuthMiddleware).Patch("/{id}", v2.patchMovie)
+			r.With(adminAuthMiddleware).Delete("/{id}", v2.deleteMovie)
+			r.Get("/{id}/cast", v2.movieCast)
+			r.With(adminAuthMiddleware).Post("/{id}/cast", v2.addCast)
+			r.With(adminAuthMiddleware).Delete("/{id}/cast/{actor_id}", v2.removeCast)
+		})
+
+		r.Route("/actors", func(r chi.Router) {
+			r.With(filter.Middleware, pagination.Middleware).Get("/", v2.listActors)
+			r.With(adminAuthMiddleware).Post("/", v2.createActor)
+			r.Get("/{id}", v2.getActor)
+			r.With(adminAuthMiddleware).Patch("/{id}", v2.patchActor)
+			r.With(adminAuthMiddleware).Delete("/{id}", v2.deleteActor)
+			r.Get("/{id}/movies", v2.actorMovies)
+		})
+	})
+
+	// GraphQL: запросы доступны всем, мутации - только администратору
+	graphql := gql.NewHandler(a, m, am, gql.Limits{
+		MaxDepth:      cfg.GraphQL.MaxDepth,
+		MaxComplexity: cfg.GraphQL.MaxComplexity,
+	}, adminAuthMiddleware, l)
+
+	router.With(commonMiddleware.Handler).Get("/graphql", graphql.ServeHTTP)
+	router.With(commonMiddleware.Handler).Post("/graphql", graphql.ServeHTTP)
 }
@@ -0,0 +1,43 @@
+package api
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Маршрут v1 отвечает с заголовками Deprecation и Link, только если в docRoutes указана его замена в v2
+func TestDeprecatedRoutesLinkSuccessor(t *testing.T) {
+	router := newMemoryRouter(t, 0)
+
+	paths := strings.NewReplacer("{id}", "1", "{ref}", "imdb:tt0118767")
+
+	for _, route := range docRoutes {
+		if !strings.HasPrefix(route.path, "/actor") && !strings.HasPrefix(route.path, "/movie") {
+			continue
+		}
+
+		// Запросы без учетных данных отклоняются до обращения к usecase, но заголовки уже выставлены
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, httptest.NewRequest(route.method, paths.Replace(route.path), nil))
+
+		deprecated := rec.Header().Get("Deprecation") != ""
+		if deprecated != (route.successor != "") {
+			t.Errorf("%s %s: deprecated = %t, successor %q", route.method, route.path, deprecated, route.successor)
+			continue
+		}
+
+		if route.successor == "" {
+			continue
+		}
+
+		if strings.Contains(route.successor, "{") {
+			t.Errorf("%s %s: successor %q is not a URI reference", route.method, route.path, route.successor)
+		}
+
+		want := "<" + route.successor + `>; rel="successor-version"`
+		if got := rec.Header().Get("Link"); got != want {
+			t.Errorf("%s %s: Link = %q, want %q", route.method, route.path, got, want)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Package gql обслуживает запросы GraphQL к фильмам, актерам и связям между ними.
+package gql
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/render"
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+
+	"filmoteka/internal/controller/problem"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+)
+
+// Request - тело запроса GraphQL
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName,omitempty"`
+	Variables     map[string]any `json:"variables,omitempty"`
+}
+
+// Response - ответ GraphQL
+type Response struct {
+	Data   any                        `json:"data,omitempty"`
+	Errors []gqlerrors.FormattedError `json:"errors,omitempty"`
+}
+
+type Handler struct {
+	schema graphql.Schema
+	am     usecase.ActorMovie
+	limits Limits
+	// Мутации выполняются только после проверки учетных данных администратора
+	adminAuth func(http.Handler) http.Handler
+	l         logger.Interface
+}
+
+// NewHandler создает обработчик запросов GraphQL.
+// Схема не зависит от данных, поэтому ошибка ее построения - ошибка программиста.
+func NewHandler(a usecase.Actor, m usecase.Movie, am usecase.ActorMovie, limits Limits,
+	adminAuth func(http.Handler) http.Handler, l logger.Interface) *Handler {
+	schema, err := NewSchema(a, m, am)
+	if err != nil {
+		panic(fmt.Sprintf("gql - NewHandler - NewSchema: %s", err))
+	}
+
+	return &Handler{schema: schema, am: am, limits: limits, adminAuth: adminAuth, l: l}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	req, err := decodeRequest(r)
+	if err != nil {
+		h.l.DebugContext(r.Context(), "Failed to decode GraphQL request", h.l.Err(err))
+
+		problem.Render(w, r, problem.New(http.StatusBadRequest, "invalid_body", err.Error()))
+
+		return
+	}
+
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(req.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		render.JSON(w, r, Response{Errors: gqlerrors.FormatErrors(err)})
+		return
+	}
+
+	if res := graphql.ValidateDocument(&h.schema, doc, nil); !res.IsValid {
+		render.JSON(w, r, Response{Errors: res.Errors})
+		return
+	}
+
+	op, err := operation(doc, req.OperationName)
+	if err != nil {
+		render.JSON(w, r, Response{Errors: []gqlerrors.FormattedError{queryError("invalid_operation", err)}})
+		return
+	}
+
+	if err = h.limits.check(h.schema, doc, op, req.Variables); err != nil {
+		h.l.DebugContext(r.Context(), "GraphQL query rejected by limits", h.l.Err(err))
+
+		render.JSON(w, r, Response{Errors: []gqlerrors.FormattedError{queryError("query_limit_exceeded", err)}})
+
+		return
+	}
+
+	execute := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := graphql.Execute(graphql.ExecuteParams{
+			Schema:        h.schema,
+			AST:           doc,
+			OperationName: req.OperationName,
+			Args:          req.Variables,
+			Context:       withLoaders(r.Context(), h.am),
+		})
+
+		render.JSON(w, r, Response{Data: res.Data, Errors: res.Errors})
+	})
+
+	if op.Operation != ast.OperationTypeMutation {
+		execute(w, r)
+		return
+	}
+
+	// Изменять данные через GET нельзя: такой запрос можно подделать ссылкой
+	if r.Method != http.MethodPost {
+		problem.Render(w, r, problem.New(http.StatusMethodNotAllowed, "mutation_requires_post", "mutations are only accepted via POST"))
+		return
+	}
+
+	h.adminAuth(execute).ServeHTTP(w, r)
+}
+
+// decodeRequest читает запрос из тела POST или из параметров GET
+func decodeRequest(r *http.Request) (Request, error) {
+	var req Request
+
+	if r.Method == http.MethodGet {
+		q := r.URL.Query()
+		req.Query = q.Get("query")
+		req.OperationName = q.Get("operationName")
+
+		if vars := q.Get("variables"); vars != "" {
+			if err := json.Unmarshal([]byte(vars), &req.Variables); err != nil {
+				return req, fmt.Errorf("variables should be a JSON object")
+			}
+		}
+	} else if err := render.DecodeJSON(r.Body, &req); err != nil {
+		return req, fmt.Errorf("request body should be a JSON object with query, operationName and variables")
+	}
+
+	if req.Query == "" {
+		return req, fmt.Errorf("query is required")
+	}
+
+	return req, nil
+}
+
+// operation находит выполняемую операцию документа
+func operation(doc *ast.Document, name string) (*ast.OperationDefinition, error) {
+	var found *ast.OperationDefinition
+
+	for _, def := range doc.Definitions {
+		op, ok := def.(*ast.OperationDefinition)
+		if !ok {
+			continue
+		}
+
+		if name == "" {
+			if found != nil {
+				return nil, fmt.Errorf("operationName is required for a document with several operations")
+			}
+			found = op
+		} else if op.Name != nil && op.Name.Value == name {
+			return op, nil
+		}
+	}
+
+	if found == nil {
+		return nil, fmt.Errorf("unknown operation %q", name)
+	}
+
+	return found, nil
+}
+
+func queryError(code string, err error) gqlerrors.FormattedError {
+	return gqlerrors.FormattedError{
+		Message:    err.Error(),
+		Extensions: map[string]any{"code": code},
+	}
+}
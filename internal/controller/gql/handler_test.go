@@ -0,0 +1,211 @@
+package gql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"filmoteka/internal/controller/middleware/auth"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+)
+
+const (
+	adminUser = "admin"
+	adminPass = "secret"
+)
+
+func intPtr(v int) *int { return &v }
+
+// movies - фильмы 1, 2, 3. Остальные методы usecase.Movie тестам не нужны.
+type movies struct {
+	usecase.Movie
+
+	mu    sync.Mutex
+	saved int
+}
+
+func (m *movies) List(context.Context) ([]entity.Movie, error) {
+	return []entity.Movie{{Id: intPtr(1)}, {Id: intPtr(2)}, {Id: intPtr(3)}}, nil
+}
+
+func (m *movies) Save(_ context.Context, data entity.MovieData) (entity.Movie, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.saved++
+
+	return entity.Movie{Id: intPtr(100), MovieData: data}, nil
+}
+
+// actorMovies считает обращения за актерами фильмов и фильмами актеров
+type actorMovies struct {
+	usecase.ActorMovie
+
+	mu          sync.Mutex
+	cast        [][]int
+	filmography [][]int
+}
+
+func (am *actorMovies) Cast(_ context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	am.cast = append(am.cast, movieIDs)
+
+	cast := map[int][]int{1: {10, 11}, 2: {10}, 3: {11}, 4: {11}}
+
+	res := make(map[int][]entity.Actor, len(movieIDs))
+	for _, id := range movieIDs {
+		for _, actorID := range cast[id] {
+			res[id] = append(res[id], entity.Actor{Id: intPtr(actorID)})
+		}
+	}
+
+	return res, nil
+}
+
+func (am *actorMovies) Filmography(_ context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+	am.mu.Lock()
+	defer am.mu.Unlock()
+
+	am.filmography = append(am.filmography, actorIDs)
+
+	filmography := map[int][]int{10: {1, 2}, 11: {1, 3, 4}}
+
+	res := make(map[int][]entity.Movie, len(actorIDs))
+	for _, id := range actorIDs {
+		for _, movieID := range filmography[id] {
+			res[id] = append(res[id], entity.Movie{Id: intPtr(movieID)})
+		}
+	}
+
+	return res, nil
+}
+
+func newTestHandler(limits Limits) (*Handler, *movies, *actorMovies) {
+	m := &movies{}
+	am := &actorMovies{}
+	adminAuth := auth.Basic("test", map[string]string{adminUser: adminPass})
+
+	return NewHandler(nil, m, am, limits, adminAuth, logger.New("prod", logger.Output(io.Discard))), m, am
+}
+
+func post(h http.Handler, query string, admin bool) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(Request{Query: query})
+
+	r := httptest.NewRequest(http.MethodPost, "/graphql", bytes.NewReader(body))
+	r.Header.Set("Content-Type", "application/json")
+	if admin {
+		r.SetBasicAuth(adminUser, adminPass)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	return w
+}
+
+func decode(t *testing.T, w *httptest.ResponseRecorder) Response {
+	t.Helper()
+
+	var res Response
+	if err := json.NewDecoder(w.Body).Decode(&res); err != nil {
+		t.Fatalf("failed to decode response: %s", err)
+	}
+
+	return res
+}
+
+func TestBatching(t *testing.T) {
+	h, _, am := newTestHandler(Limits{})
+
+	w := post(h, `{ movies { id cast { id movies { id cast { id } } } } }`, false)
+
+	res := decode(t, w)
+	if w.Code != http.StatusOK || len(res.Errors) != 0 {
+		t.Fatalf("status = %d, errors = %v, want 200 without errors", w.Code, res.Errors)
+	}
+
+	// Один запрос на уровень, сколько бы ни было родителей. Фильмы 1-3 уже загружены на первом уровне,
+	// поэтому на третьем запрашивается только фильм 4.
+	if fmt.Sprint(am.cast) != "[[1 2 3] [4]]" || fmt.Sprint(am.filmography) != "[[10 11]]" {
+		t.Fatalf("Cast calls = %v, Filmography calls = %v, want [[1 2 3] [4]] and [[10 11]]", am.cast, am.filmography)
+	}
+
+	data, _ := json.Marshal(res.Data)
+	actor10 := `{"id":10,"movies":[{"cast":[{"id":10},{"id":11}],"id":1},{"cast":[{"id":10}],"id":2}]}`
+	actor11 := `{"id":11,"movies":[{"cast":[{"id":10},{"id":11}],"id":1},{"cast":[{"id":11}],"id":3},{"cast":[{"id":11}],"id":4}]}`
+	want := `{"movies":[{"cast":[` + actor10 + `,` + actor11 + `],"id":1},{"cast":[` + actor10 + `],"id":2},` +
+		`{"cast":[` + actor11 + `],"id":3}]}`
+	if string(data) != want {
+		t.Errorf("data = %s, want %s", data, want)
+	}
+}
+
+func TestLimits(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits Limits
+		query  string
+		reject bool
+	}{
+		{"within limits", Limits{MaxDepth: 3, MaxComplexity: 200}, `{ movies { id cast { id } } }`, false},
+		{"too deep", Limits{MaxDepth: 3}, `{ movies { cast { movies { id } } } }`, true},
+		{"too complex", Limits{MaxComplexity: 200}, `{ movies { id cast(first: 50) { id name } } }`, true},
+		{"first reduces complexity", Limits{MaxComplexity: 200}, `{ movies { id cast(first: 2) { id name } } }`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, am := newTestHandler(tt.limits)
+
+			res := decode(t, post(h, tt.query, false))
+
+			rejected := len(res.Errors) == 1 && res.Errors[0].Extensions["code"] == "query_limit_exceeded"
+			if rejected != tt.reject {
+				t.Fatalf("errors = %v, want rejected %v", res.Errors, tt.reject)
+			}
+
+			if tt.reject && (res.Data != nil || len(am.cast) != 0) {
+				t.Errorf("rejected query was executed: data = %v", res.Data)
+			}
+		})
+	}
+}
+
+func TestMutationAuth(t *testing.T) {
+	const mutation = `mutation { createMovie(input: {title: "Alien", rating: 8}) { id } }`
+
+	h, m, _ := newTestHandler(Limits{})
+
+	r := httptest.NewRequest(http.MethodGet, "/graphql?query="+url.QueryEscape(mutation), nil)
+	r.SetBasicAuth(adminUser, adminPass)
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("mutation via GET: status = %d, want 405", w.Code)
+	}
+
+	if w := post(h, mutation, false); w.Code != http.StatusUnauthorized {
+		t.Errorf("mutation without credentials: status = %d, want 401", w.Code)
+	}
+
+	if m.saved != 0 {
+		t.Fatalf("refused mutations saved %d movies", m.saved)
+	}
+
+	w = post(h, mutation, true)
+	if res := decode(t, w); w.Code != http.StatusOK || len(res.Errors) != 0 || m.saved != 1 {
+		t.Errorf("admin mutation: status = %d, errors = %v, saved = %d, want 200 and one movie", w.Code, res.Errors, m.saved)
+	}
+}
@@ -0,0 +1,162 @@
+package gql
+
+import (
+	"fmt"
+
+	"github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/kinds"
+)
+
+// defaultListSize - сколько элементов списка предполагается при оценке сложности,
+// если размер не ограничен аргументом first. Совпадает с размером страницы списков.
+const defaultListSize = 10
+
+// Limits ограничивает запросы, которые сервер согласен выполнить
+type Limits struct {
+	// Максимальная вложенность полей
+	MaxDepth int
+	// Максимальная сложность: каждое поле стоит 1, поля-списки умножают стоимость вложенных полей
+	// на first или defaultListSize
+	MaxComplexity int
+}
+
+// cost - глубина и сложность набора полей
+type cost struct {
+	depth      int
+	complexity int
+}
+
+// check проверяет операцию документа, уже прошедшего валидацию схемы
+func (lim Limits) check(schema graphql.Schema, doc *ast.Document, op *ast.OperationDefinition, vars map[string]interface{}) error {
+	root := schema.QueryType()
+	if op.Operation == ast.OperationTypeMutation {
+		root = schema.MutationType()
+	}
+
+	w := walker{vars: vars, fragments: make(map[string]*ast.FragmentDefinition)}
+	for _, def := range doc.Definitions {
+		if f, ok := def.(*ast.FragmentDefinition); ok {
+			w.fragments[f.Name.Value] = f
+		}
+	}
+
+	c := w.selectionSet(schema, root, op.SelectionSet)
+
+	if lim.MaxDepth > 0 && c.depth > lim.MaxDepth {
+		return fmt.Errorf("query depth %d exceeds the limit of %d", c.depth, lim.MaxDepth)
+	}
+
+	if lim.MaxComplexity > 0 && c.complexity > lim.MaxComplexity {
+		return fmt.Errorf("query complexity %d exceeds the limit of %d", c.complexity, lim.MaxComplexity)
+	}
+
+	return nil
+}
+
+type walker struct {
+	vars      map[string]interface{}
+	fragments map[string]*ast.FragmentDefinition
+}
+
+func (w walker) selectionSet(schema graphql.Schema, parent *graphql.Object, set *ast.SelectionSet) cost {
+	var total cost
+	if set == nil || parent == nil {
+		return total
+	}
+
+	for _, sel := range set.Selections {
+		var c cost
+
+		switch sel := sel.(type) {
+		case *ast.Field:
+			c = w.field(schema, parent, sel)
+		case *ast.InlineFragment:
+			c = w.selectionSet(schema, fragmentType(schema, parent, sel.TypeCondition), sel.SelectionSet)
+		case *ast.FragmentSpread:
+			if f, ok := w.fragments[sel.Name.Value]; ok {
+				c = w.selectionSet(schema, fragmentType(schema, parent, f.TypeCondition), f.SelectionSet)
+			}
+		}
+
+		total.complexity += c.complexity
+		if c.depth > total.depth {
+			total.depth = c.depth
+		}
+	}
+
+	return total
+}
+
+func (w walker) field(schema graphql.Schema, parent *graphql.Object, f *ast.Field) cost {
+	def, ok := parent.Fields()[f.Name.Value]
+	if !ok {
+		// __typename и поля интроспекции
+		return cost{depth: 1, complexity: 1}
+	}
+
+	typ := def.Type
+	if nn, ok := typ.(*graphql.NonNull); ok {
+		typ = nn.OfType
+	}
+
+	multiplier := 1
+	if list, ok := typ.(*graphql.List); ok {
+		multiplier = w.first(f)
+		typ = list.OfType
+		if nn, ok := typ.(*graphql.NonNull); ok {
+			typ = nn.OfType
+		}
+	}
+
+	obj, _ := typ.(*graphql.Object)
+	child := w.selectionSet(schema, obj, f.SelectionSet)
+
+	return cost{
+		depth:      child.depth + 1,
+		complexity: 1 + multiplier*child.complexity,
+	}
+}
+
+// first возвращает значение аргумента first, заданное в запросе или переменной
+func (w walker) first(f *ast.Field) int {
+	for _, arg := range f.Arguments {
+		if arg.Name.Value != "first" {
+			continue
+		}
+
+		var v interface{}
+		switch val := arg.Value.(type) {
+		case *ast.IntValue:
+			v = val.Value
+		case *ast.Variable:
+			v = w.vars[val.Name.Value]
+		}
+
+		var n int
+		switch v := v.(type) {
+		case string:
+			_, _ = fmt.Sscan(v, &n)
+		case float64:
+			n = int(v)
+		case int:
+			n = v
+		}
+
+		if n > 0 {
+			return n
+		}
+	}
+
+	return defaultListSize
+}
+
+func fragmentType(schema graphql.Schema, parent *graphql.Object, cond *ast.Named) *graphql.Object {
+	if cond == nil || cond.Kind != kinds.Named {
+		return parent
+	}
+
+	obj, _ := schema.Type(cond.Name.Value).(*graphql.Object)
+
+	return obj
+}
@@ -0,0 +1,78 @@
+package gql
+
+import (
+	"context"
+	"sync"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// loader откладывает загрузку связанных записей и собирает ключи в пакет.
+// Резолверы возвращают thunk, а graphql-go вызывает thunk уровня запроса только после того,
+// как отработали резолверы всех родителей этого уровня, поэтому один пакет - один запрос к БД.
+type loader[V any] struct {
+	fetch func(ctx context.Context, keys []int) (map[int]V, error)
+
+	mu      sync.Mutex
+	pending []int
+	queued  map[int]bool
+	values  map[int]V
+	errs    map[int]error
+}
+
+func newLoader[V any](fetch func(ctx context.Context, keys []int) (map[int]V, error)) *loader[V] {
+	return &loader[V]{
+		fetch:  fetch,
+		queued: make(map[int]bool),
+		values: make(map[int]V),
+		errs:   make(map[int]error),
+	}
+}
+
+// Load добавляет ключ в текущий пакет и возвращает thunk, который загрузит пакет при первом вызове
+func (l *loader[V]) Load(ctx context.Context, key int) func() (V, error) {
+	l.mu.Lock()
+	if !l.queued[key] {
+		l.queued[key] = true
+		l.pending = append(l.pending, key)
+	}
+	l.mu.Unlock()
+
+	return func() (V, error) {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+
+		if len(l.pending) != 0 {
+			keys := l.pending
+			l.pending = nil
+
+			res, err := l.fetch(ctx, keys)
+			for _, k := range keys {
+				l.values[k] = res[k]
+				l.errs[k] = err
+			}
+		}
+
+		return l.values[key], l.errs[key]
+	}
+}
+
+// loaders - загрузчики одного запроса. Кэш не переживает запрос, чтобы не отдавать устаревшие данные.
+type loaders struct {
+	cast        *loader[[]entity.Actor]
+	filmography *loader[[]entity.Movie]
+}
+
+type loadersKey struct{}
+
+func withLoaders(ctx context.Context, am usecase.ActorMovie) context.Context {
+	return context.WithValue(ctx, loadersKey{}, &loaders{
+		cast:        newLoader(am.Cast),
+		filmography: newLoader(am.Filmography),
+	})
+}
+
+func loadersFrom(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
@@ -0,0 +1,396 @@
+package gql
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/graphql-go/graphql"
+
+	"filmoteka/internal/controller/middleware/dateformat"
+	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/controller/problem"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// resolver - резолверы схемы поверх usecase
+type resolver struct {
+	a  usecase.Actor
+	m  usecase.Movie
+	am usecase.ActorMovie
+}
+
+// NewSchema строит схему GraphQL: фильмы, актеры и связи между ними
+func NewSchema(a usecase.Actor, m usecase.Movie, am usecase.ActorMovie) (graphql.Schema, error) {
+	r := &resolver{a: a, m: m, am: am}
+
+	// Связанные списки: актеры фильма и фильмы актера
+	related := graphql.FieldConfigArgument{
+		"first":   {Type: graphql.Int, Description: "Сколько записей вернуть"},
+		"exclude": {Type: graphql.Int, Description: "id записи, которую не нужно включать в список"},
+	}
+
+	var actorType *graphql.Object
+
+	movieType := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Movie",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":          {Type: graphql.NewNonNull(graphql.Int), Resolve: movieField(func(m entity.Movie) any { return m.Id })},
+				"title":       {Type: graphql.String, Resolve: movieField(func(m entity.Movie) any { return m.Title })},
+				"description": {Type: graphql.String, Resolve: movieField(func(m entity.Movie) any { return m.Description })},
+				"releaseDate": {Type: graphql.String, Resolve: dateField(func(p graphql.ResolveParams) *entity.Date {
+					return p.Source.(entity.Movie).ReleaseDate
+				})},
+				"rating": {Type: graphql.Int, Resolve: movieField(func(m entity.Movie) any { return m.Rating })},
+				"cast": {
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(actorType))),
+					Args:    related,
+					Resolve: r.cast,
+				},
+			}
+		}),
+	})
+
+	actorType = graphql.NewObject(graphql.ObjectConfig{
+		Name: "Actor",
+		Fields: graphql.FieldsThunk(func() graphql.Fields {
+			return graphql.Fields{
+				"id":         {Type: graphql.NewNonNull(graphql.Int), Resolve: actorField(func(a entity.Actor) any { return a.Id })},
+				"name":       {Type: graphql.String, Resolve: actorField(func(a entity.Actor) any { return a.Name })},
+				"surname":    {Type: graphql.String, Resolve: actorField(func(a entity.Actor) any { return a.Surname })},
+				"patronymic": {Type: graphql.String, Resolve: actorField(func(a entity.Actor) any { return a.Patronymic })},
+				"gender":     {Type: graphql.String, Resolve: actorField(func(a entity.Actor) any { return a.Gender })},
+				"dateOfBirth": {Type: graphql.String, Resolve: dateField(func(p graphql.ResolveParams) *entity.Date {
+					return p.Source.(entity.Actor).DateOfBirth
+				})},
+				"movies": {
+					Type:    graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(movieType))),
+					Args:    related,
+					Resolve: r.filmography,
+				},
+			}
+		}),
+	})
+
+	movieInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "MovieInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"title":       {Type: graphql.String},
+			"description": {Type: graphql.String},
+			"releaseDate": {Type: graphql.String},
+			"rating":      {Type: graphql.Int},
+		},
+	})
+
+	actorInput := graphql.NewInputObject(graphql.InputObjectConfig{
+		Name: "ActorInput",
+		Fields: graphql.InputObjectConfigFieldMap{
+			"name":        {Type: graphql.String},
+			"surname":     {Type: graphql.String},
+			"patronymic":  {Type: graphql.String},
+			"gender":      {Type: graphql.String},
+			"dateOfBirth": {Type: graphql.String},
+		},
+	})
+
+	id := graphql.FieldConfigArgument{"id": {Type: graphql.NewNonNull(graphql.Int)}}
+	page := graphql.FieldConfigArgument{"after": {Type: graphql.Int, Description: "id, с которого начинается страница"}}
+
+	query := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Query",
+		Fields: graphql.Fields{
+			"movie":  {Type: movieType, Args: id, Resolve: r.movie},
+			"movies": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(movieType))), Args: page, Resolve: r.movies},
+			"actor":  {Type: actorType, Args: id, Resolve: r.actor},
+			"actors": {Type: graphql.NewNonNull(graphql.NewList(graphql.NewNonNull(actorType))), Args: page, Resolve: r.actors},
+		},
+	})
+
+	mutation := graphql.NewObject(graphql.ObjectConfig{
+		Name: "Mutation",
+		Fields: graphql.Fields{
+			"createMovie": {
+				Type:    graphql.NewNonNull(movieType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(movieInput)}},
+				Resolve: r.createMovie,
+			},
+			"updateMovie": {
+				Type: graphql.NewNonNull(movieType),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.Int)},
+					"input": {Type: graphql.NewNonNull(movieInput)},
+				},
+				Resolve: r.updateMovie,
+			},
+			"deleteMovie": {Type: graphql.NewNonNull(movieType), Args: id, Resolve: r.deleteMovie},
+			"createActor": {
+				Type:    graphql.NewNonNull(actorType),
+				Args:    graphql.FieldConfigArgument{"input": {Type: graphql.NewNonNull(actorInput)}},
+				Resolve: r.createActor,
+			},
+			"updateActor": {
+				Type: graphql.NewNonNull(actorType),
+				Args: graphql.FieldConfigArgument{
+					"id":    {Type: graphql.NewNonNull(graphql.Int)},
+					"input": {Type: graphql.NewNonNull(actorInput)},
+				},
+				Resolve: r.updateActor,
+			},
+			"deleteActor": {Type: graphql.NewNonNull(actorType), Args: id, Resolve: r.deleteActor},
+			"addCast": {
+				Type: graphql.NewNonNull(movieType),
+				Args: graphql.FieldConfigArgument{
+					"movieId": {Type: graphql.NewNonNull(graphql.Int)},
+					"actorId": {Type: graphql.NewNonNull(graphql.Int)},
+				},
+				Resolve: r.addCast,
+			},
+		},
+	})
+
+	return graphql.NewSchema(graphql.SchemaConfig{Query: query, Mutation: mutation})
+}
+
+func (r *resolver) movie(p graphql.ResolveParams) (any, error) {
+	return result(r.m.Find(p.Context, p.Args["id"].(int)))
+}
+
+func (r *resolver) movies(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+
+	var res []entity.Movie
+	var err error
+
+	if after, ok := p.Args["after"].(int); ok {
+		res, err = r.m.Next(context.WithValue(ctx, pagination.NextPersonID, after))
+	} else {
+		res, err = r.m.List(ctx)
+	}
+
+	return result(res, err)
+}
+
+func (r *resolver) actor(p graphql.ResolveParams) (any, error) {
+	return result(r.a.Find(p.Context, p.Args["id"].(int)))
+}
+
+func (r *resolver) actors(p graphql.ResolveParams) (any, error) {
+	ctx := p.Context
+
+	var res []entity.Actor
+	var err error
+
+	if after, ok := p.Args["after"].(int); ok {
+		res, err = r.a.Next(context.WithValue(ctx, pagination.NextPersonID, after))
+	} else {
+		res, err = r.a.List(ctx)
+	}
+
+	return result(res, err)
+}
+
+// cast и filmography не обращаются к БД сами: id родителя попадает в пакет загрузчика
+func (r *resolver) cast(p graphql.ResolveParams) (any, error) {
+	movie := p.Source.(entity.Movie)
+	load := loadersFrom(p.Context).cast.Load(p.Context, *movie.Id)
+
+	return func() (any, error) {
+		actors, err := load()
+		if err != nil {
+			return nil, wrap(err)
+		}
+
+		return window(actors, p.Args, func(a entity.Actor) int { return *a.Id }), nil
+	}, nil
+}
+
+func (r *resolver) filmography(p graphql.ResolveParams) (any, error) {
+	actor := p.Source.(entity.Actor)
+	load := loadersFrom(p.Context).filmography.Load(p.Context, *actor.Id)
+
+	return func() (any, error) {
+		movies, err := load()
+		if err != nil {
+			return nil, wrap(err)
+		}
+
+		return window(movies, p.Args, func(m entity.Movie) int { return *m.Id }), nil
+	}, nil
+}
+
+func (r *resolver) createMovie(p graphql.ResolveParams) (any, error) {
+	return result(r.m.Save(p.Context, movieData(p.Args["input"])))
+}
+
+func (r *resolver) updateMovie(p graphql.ResolveParams) (any, error) {
+	id := p.Args["id"].(int)
+
+	return result(r.m.Update(p.Context, entity.Movie{Id: &id, MovieData: movieData(p.Args["input"])}))
+}
+
+func (r *resolver) deleteMovie(p graphql.ResolveParams) (any, error) {
+	return result(r.m.Delete(p.Context, p.Args["id"].(int)))
+}
+
+func (r *resolver) createActor(p graphql.ResolveParams) (any, error) {
+	return result(r.a.Save(p.Context, actorData(p.Args["input"])))
+}
+
+func (r *resolver) updateActor(p graphql.ResolveParams) (any, error) {
+	id := p.Args["id"].(int)
+
+	return result(r.a.Update(p.Context, entity.Actor{Id: &id, ActorData: actorData(p.Args["input"])}))
+}
+
+func (r *resolver) deleteActor(p graphql.ResolveParams) (any, error) {
+	return result(r.a.Delete(p.Context, p.Args["id"].(int)))
+}
+
+func (r *resolver) addCast(p graphql.ResolveParams) (any, error) {
+	movieID, actorID := p.Args["movieId"].(int), p.Args["actorId"].(int)
+
+	err := r.am.Save(p.Context, entity.ActorMovie{Actor_id: &actorID, Movie_id: &movieID})
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	return result(r.m.Find(p.Context, movieID))
+}
+
+func movieField(get func(entity.Movie) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(entity.Movie)), nil
+	}
+}
+
+func actorField(get func(entity.Actor) any) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		return get(p.Source.(entity.Actor)), nil
+	}
+}
+
+// dateField отдает дату в формате, выбранном параметром date_format или заголовком X-Date-Format
+func dateField(get func(graphql.ResolveParams) *entity.Date) graphql.FieldResolveFn {
+	return func(p graphql.ResolveParams) (any, error) {
+		d := get(p)
+		if d == nil {
+			return nil, nil
+		}
+
+		return d.Format(dateformat.FromContext(p.Context)), nil
+	}
+}
+
+// window применяет к связанному списку аргументы exclude и first
+func window[T any](items []T, args map[string]any, id func(T) int) []T {
+	exclude, hasExclude := args["exclude"].(int)
+	first, hasFirst := args["first"].(int)
+
+	res := make([]T, 0, len(items))
+	for _, item := range items {
+		if hasExclude && id(item) == exclude {
+			continue
+		}
+
+		if hasFirst && len(res) >= first {
+			break
+		}
+
+		res = append(res, item)
+	}
+
+	return res
+}
+
+func movieData(input any) entity.MovieData {
+	in, _ := input.(map[string]any)
+
+	return entity.MovieData{
+		Title:       optString(in, "title"),
+		Description: optString(in, "description"),
+		ReleaseDate: optDate(in, "releaseDate"),
+		Rating:      optInt(in, "rating"),
+	}
+}
+
+func actorData(input any) entity.ActorData {
+	in, _ := input.(map[string]any)
+
+	return entity.ActorData{
+		Name:        optString(in, "name"),
+		Surname:     optString(in, "surname"),
+		Patronymic:  optString(in, "patronymic"),
+		Gender:      optString(in, "gender"),
+		DateOfBirth: optDate(in, "dateOfBirth"),
+	}
+}
+
+func optString(in map[string]any, key string) *string {
+	if v, ok := in[key].(string); ok {
+		return &v
+	}
+
+	return nil
+}
+
+func optInt(in map[string]any, key string) *int {
+	if v, ok := in[key].(int); ok {
+		return &v
+	}
+
+	return nil
+}
+
+// optDate разбирает дату так же, как тело запроса REST API: о неверной дате сообщит проверка данных
+func optDate(in map[string]any, key string) *entity.Date {
+	v, ok := in[key].(string)
+	if !ok {
+		return nil
+	}
+
+	var d entity.Date
+	_ = d.UnmarshalJSON([]byte(strconv.Quote(v)))
+
+	return &d
+}
+
+// result возвращает null вместо пустого значения, если usecase вернул ошибку
+func result[T any](res T, err error) (any, error) {
+	if err != nil {
+		return nil, wrap(err)
+	}
+
+	return res, nil
+}
+
+// fieldError - ошибка предметной области в ответе GraphQL.
+// Код, статус и ошибки полей передаются в extensions, текст исходной ошибки клиенту не показывается.
+type fieldError struct {
+	problem problem.Problem
+}
+
+func wrap(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return fieldError{problem: problem.FromError(err)}
+}
+
+func (e fieldError) Error() string {
+	return e.problem.Detail
+}
+
+func (e fieldError) Extensions() map[string]any {
+	ext := map[string]any{
+		"code":   e.problem.Code,
+		"status": e.problem.Status,
+	}
+
+	if len(e.problem.Errors) != 0 {
+		ext["errors"] = e.problem.Errors
+	}
+
+	return ext
+}
@@ -0,0 +1,60 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+
+	"filmoteka/internal/controller/middleware/requestlog"
+	"filmoteka/internal/controller/problem"
+)
+
+type (
+	CustomKey string
+)
+
+const (
+	PrincipalContextKey CustomKey = "principal"
+)
+
+// Basic проверяет учетные данные администратора (HTTP Basic Auth).
+// При успешной проверке имя пользователя сохраняется в контексте запроса и в журнале запросов.
+func Basic(realm string, creds map[string]string) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := Check(r, creds)
+			if !ok {
+				w.Header().Add("WWW-Authenticate", fmt.Sprintf(`Basic realm="%s"`, realm))
+				problem.Render(w, r, problem.New(http.StatusUnauthorized, "unauthorized", "valid admin credentials are required"))
+				return
+			}
+
+			requestlog.SetPrincipal(r.Context(), user)
+
+			ctx := context.WithValue(r.Context(), PrincipalContextKey, user)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Check проверяет учетные данные запроса и возвращает имя пользователя
+func Check(r *http.Request, creds map[string]string) (string, bool) {
+	user, pass, ok := r.BasicAuth()
+	if !ok {
+		return "", false
+	}
+
+	credPass, credUserOk := creds[user]
+	if !credUserOk || subtle.ConstantTimeCompare([]byte(pass), []byte(credPass)) != 1 {
+		return "", false
+	}
+
+	return user, true
+}
+
+// Principal возвращает имя аутентифицированного пользователя
+func Principal(ctx context.Context) (string, bool) {
+	user, ok := ctx.Value(PrincipalContextKey).(string)
+	return user, ok
+}
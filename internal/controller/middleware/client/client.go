@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/netip"
+)
+
+type (
+	CustomKey string
+)
+
+const (
+	ClientContextKey CustomKey = "client"
+)
+
+// Middleware сохраняет в контексте запроса адрес клиента без порта. По нему хранилище узнает клиента,
+// который только что изменил данные, и читает для него из основной БД, а не из реплики.
+// ip определяет адрес клиента за доверенными прокси (ratelimit.Limiter.ClientIP): за балансировщиком
+// адрес соединения у всех клиентов один. Если адрес определить не удалось, используется адрес соединения.
+func Middleware(ip func(r *http.Request) netip.Addr) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := WithAddr(r.Context(), Addr(r, ip))
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Addr возвращает адрес клиента, определенный ip, или адрес соединения
+func Addr(r *http.Request, ip func(r *http.Request) netip.Addr) string {
+	if addr := ip(r); addr.IsValid() {
+		return addr.String()
+	}
+
+	return r.RemoteAddr
+}
+
+// WithAddr сохраняет в контексте адрес клиента host:port
+func WithAddr(ctx context.Context, addr string) context.Context {
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+
+	if addr == "" {
+		return ctx
+	}
+
+	return context.WithValue(ctx, ClientContextKey, addr)
+}
+
+// ID возвращает адрес клиента из контекста
+func ID(ctx context.Context) (string, bool) {
+	id, ok := ctx.Value(ClientContextKey).(string)
+	return id, ok
+}
@@ -0,0 +1,47 @@
+package client_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"filmoteka/internal/controller/middleware/client"
+	"filmoteka/internal/controller/middleware/ratelimit"
+)
+
+func TestMiddleware(t *testing.T) {
+	lim := ratelimit.New(nil, ratelimit.Config{TrustedProxies: []string{"10.0.0.0/8"}})
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    string
+		want   string
+	}{
+		{"direct", "192.0.2.1:1234", "", "192.0.2.1"},
+		{"behind balancer", "10.0.0.2:1234", "198.51.100.7", "198.51.100.7"},
+		{"another client behind balancer", "10.0.0.2:1234", "198.51.100.8", "198.51.100.8"},
+		{"untrusted proxy", "192.0.2.1:1234", "198.51.100.7", "192.0.2.1"},
+		{"invalid address", "pipe", "", "pipe"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+			h := client.Middleware(lim.ClientIP)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got, _ = client.ID(r.Context())
+			}))
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			if tt.xff != "" {
+				r.Header.Set("X-Forwarded-For", tt.xff)
+			}
+			h.ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("client = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package dateformat
+
+import (
+	"context"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"filmoteka/internal/controller/problem"
+	"filmoteka/internal/entity"
+)
+
+type (
+	CustomKey string
+)
+
+const (
+	DateFormatContextKey CustomKey = "date_format"
+
+	// Формат дат можно выбрать параметром ?date_format=ru или заголовком X-Date-Format: ru
+	QueryParam = "date_format"
+	Header     = "X-Date-Format"
+)
+
+// Middleware сохраняет в контексте запроса формат, в котором клиенту отправляются даты.
+// По умолчанию используется ISO 8601.
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		val := r.URL.Query().Get(QueryParam)
+		if val == "" {
+			val = r.Header.Get(Header)
+		}
+
+		var format entity.DateFormat
+
+		switch strings.ToLower(val) {
+		case "", "iso":
+			format = entity.DateFormatISO
+		case "ru":
+			format = entity.DateFormatRU
+		default:
+			problem.Render(w, r, problem.New(http.StatusBadRequest, "invalid_date_format",
+				"date_format should be one of: iso, ru"))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), DateFormatContextKey, format)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// FromContext возвращает формат дат, выбранный клиентом
+func FromContext(ctx context.Context) entity.DateFormat {
+	format, _ := ctx.Value(DateFormatContextKey).(entity.DateFormat)
+
+	return format
+}
+
+var dateType = reflect.TypeOf(entity.Date{})
+
+// Apply возвращает копию ответа v, в которой всем датам задан формат format.
+// Сам ответ не изменяется: его данные могут разделяться с другими запросами.
+func Apply(v any, format entity.DateFormat) any {
+	if v == nil || format == entity.DateFormatISO {
+		return v
+	}
+
+	return withFormat(reflect.ValueOf(v), format).Interface()
+}
+
+func withFormat(v reflect.Value, format entity.DateFormat) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.New(v.Type().Elem())
+		out.Elem().Set(withFormat(v.Elem(), format))
+
+		return out
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.New(v.Type()).Elem()
+		out.Set(withFormat(v.Elem(), format))
+
+		return out
+	case reflect.Struct:
+		out := reflect.New(v.Type()).Elem()
+		out.Set(v)
+
+		if v.Type() == dateType {
+			out.Addr().Interface().(*entity.Date).SetFormat(format)
+			return out
+		}
+
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				out.Field(i).Set(withFormat(v.Field(i), format))
+			}
+		}
+
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(withFormat(v.Index(i), format))
+		}
+
+		return out
+	case reflect.Array:
+		out := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(withFormat(v.Index(i), format))
+		}
+
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), withFormat(iter.Value(), format))
+		}
+
+		return out
+	}
+
+	return v
+}
@@ -0,0 +1,26 @@
+package deprecation
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Since - дата, с которой маршруты v1 считаются устаревшими (выход /api/v2)
+var Since = time.Date(2023, time.November, 5, 0, 0, 0, 0, time.UTC)
+
+// Middleware помечает ответы устаревших маршрутов заголовками Deprecation (RFC 9745)
+// и Link со ссылкой на маршрут, который следует использовать вместо них.
+func Middleware(successor string) func(http.Handler) http.Handler {
+	deprecation := fmt.Sprintf("@%d", Since.Unix())
+	link := fmt.Sprintf(`<%s>; rel="successor-version"`, successor)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", deprecation)
+			w.Header().Add("Link", link)
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -19,7 +19,7 @@ func Middleware(next http.Handler) http.Handler {
 		fields := make(map[string][]string)
 		for k, v := range r.URL.Query() {
 
-			if k != "sort_by" && k != "sort_order" && k != "next_person_id" {
+			if k != "sort_by" && k != "sort_order" && k != "next_person_id" && k != "date_format" {
 				fields[k] = v
 			}
 		}
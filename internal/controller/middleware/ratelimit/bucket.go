@@ -0,0 +1,116 @@
+package ratelimit
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepInterval - как часто удаляются полные корзины: клиент с полной корзиной
+// ничем не отличается от нового
+const sweepInterval = time.Minute
+
+// bucket - корзина токенов клиента
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// state - корзина после запроса
+type state struct {
+	allowed bool
+	// remaining - сколько запросов можно сделать сразу
+	remaining int
+	// retryAfter - через сколько появится токен, если его нет
+	retryAfter time.Duration
+	// reset - через сколько корзина снова заполнится
+	reset time.Duration
+}
+
+// buckets - корзины клиентов с одним лимитом
+type buckets struct {
+	limit Limit
+
+	mu    sync.Mutex
+	m     map[string]*bucket
+	swept time.Time
+}
+
+func newBuckets(limit Limit) *buckets {
+	return &buckets{limit: limit, m: map[string]*bucket{}}
+}
+
+// take берет токен из корзины key
+func (b *buckets) take(key string, now time.Time) state {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.sweep(now)
+
+	bk := b.refill(key, now)
+
+	allowed := bk.tokens >= 1
+	if allowed {
+		bk.tokens--
+	}
+
+	res := state{
+		allowed:   allowed,
+		remaining: int(bk.tokens),
+		reset:     b.after(float64(b.limit.Burst) - bk.tokens),
+	}
+
+	if !allowed {
+		res.retryAfter = b.after(1 - bk.tokens)
+	}
+
+	return res
+}
+
+// put возвращает токен в корзину key
+func (b *buckets) put(key string, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	bk := b.refill(key, now)
+	bk.tokens = math.Min(float64(b.limit.Burst), bk.tokens+1)
+}
+
+// refill пополняет корзину key за время с прошлого запроса
+func (b *buckets) refill(key string, now time.Time) *bucket {
+	bk, ok := b.m[key]
+	if !ok {
+		bk = &bucket{tokens: float64(b.limit.Burst), updated: now}
+		b.m[key] = bk
+
+		return bk
+	}
+
+	bk.tokens = math.Min(float64(b.limit.Burst), bk.tokens+now.Sub(bk.updated).Seconds()*b.limit.Rate)
+	bk.updated = now
+
+	return bk
+}
+
+// after возвращает, за сколько накопится tokens токенов
+func (b *buckets) after(tokens float64) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+
+	return time.Duration(tokens / b.limit.Rate * float64(time.Second))
+}
+
+func (b *buckets) sweep(now time.Time) {
+	if now.Sub(b.swept) < sweepInterval {
+		return
+	}
+
+	b.swept = now
+
+	for key, bk := range b.m {
+		if bk.tokens+now.Sub(bk.updated).Seconds()*b.limit.Rate >= float64(b.limit.Burst) {
+			delete(b.m, key)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package ratelimit
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+)
+
+// proxies - подсети доверенных прокси
+type proxies []netip.Prefix
+
+// ParseProxy разбирает адрес или подсеть прокси: 10.0.0.1, 10.0.0.0/8, ::1
+func ParseProxy(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		p, err := netip.ParsePrefix(s)
+		return p.Masked(), err
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+
+	addr = addr.Unmap()
+
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// parseProxies разбирает адреса прокси, пропуская неверные: их отклоняет проверка конфигурации
+func parseProxies(ss []string) proxies {
+	res := make(proxies, 0, len(ss))
+
+	for _, s := range ss {
+		if p, err := ParseProxy(s); err == nil {
+			res = append(res, p)
+		}
+	}
+
+	return res
+}
+
+func (ps proxies) trusted(addr netip.Addr) bool {
+	for _, p := range ps {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ClientIP возвращает адрес клиента. Если запрос пришел от доверенного прокси, адрес берется
+// из X-Forwarded-For: справа налево пропускаются доверенные прокси, первый недоверенный адрес
+// принадлежит клиенту. Адресам левее него верить нельзя - их мог подставить сам клиент.
+func (lim *Limiter) ClientIP(r *http.Request) netip.Addr {
+	addr := parseAddr(r.RemoteAddr)
+	if lim == nil || !addr.IsValid() || !lim.proxies.trusted(addr) {
+		return addr
+	}
+
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop := parseAddr(strings.TrimSpace(hops[i]))
+		if !hop.IsValid() {
+			// Прокси не стал бы записывать неверный адрес: дальше цепочке верить нельзя
+			break
+		}
+
+		addr = hop
+		if !lim.proxies.trusted(hop) {
+			break
+		}
+	}
+
+	return addr
+}
+
+// parseAddr разбирает адрес с портом или без него
+func parseAddr(s string) netip.Addr {
+	if host, _, err := net.SplitHostPort(s); err == nil {
+		s = host
+	}
+
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Addr{}
+	}
+
+	return addr.Unmap()
+}
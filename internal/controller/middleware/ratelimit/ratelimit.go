@@ -0,0 +1,293 @@
+package ratelimit
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"filmoteka/internal/controller/middleware/auth"
+	"filmoteka/internal/controller/problem"
+)
+
+// Limit - ограничение корзины токенов: Rate токенов в секунду, не больше Burst в корзине.
+// Rate = 0 снимает ограничение.
+type Limit struct {
+	Rate  float64
+	Burst int
+}
+
+func (l Limit) unlimited() bool {
+	return l.Rate <= 0
+}
+
+// Config - ограничения запросов
+type Config struct {
+	// TrustedProxies - адреса и подсети прокси, которым можно верить в X-Forwarded-For
+	TrustedProxies []string
+	// APIKeys - ключи клиентов по их названиям. Ключ передается в X-API-Key и только отличает клиента
+	// от других, доступа администратора он не дает.
+	APIKeys map[string]string
+	// Creds - учетные данные администратора: прошедший проверку пользователь получает свои корзины
+	Creds map[string]string
+	// Default - ограничение маршрутов, которых нет в Routes
+	Default Limit
+	// Routes - ограничения маршрутов по ключу "METHOD /pattern", как поле route журнала, например "GET /movie/find"
+	Routes map[string]Limit
+	// AuthFailures - ограничение неудачных попыток входа администратора с одного адреса
+	AuthFailures Limit
+}
+
+// Limiter ограничивает частоту запросов клиентов. Клиент определяется по пользователю,
+// ключу API или адресу, по которому пришел запрос.
+type Limiter struct {
+	mux     *chi.Mux
+	proxies proxies
+	apiKeys map[string]string
+	creds   map[string]string
+
+	def    *buckets
+	routes map[string]*buckets
+	auth   *buckets
+
+	now func() time.Time
+}
+
+// New создает ограничитель запросов к маршрутам mux. Если mux = nil, все маршруты ограничиваются cfg.Default,
+// кроме тех, что переданы в Allow явно.
+func New(mux *chi.Mux, cfg Config) *Limiter {
+	lim := &Limiter{
+		mux:     mux,
+		proxies: parseProxies(cfg.TrustedProxies),
+		apiKeys: cfg.APIKeys,
+		creds:   cfg.Creds,
+		routes:  make(map[string]*buckets, len(cfg.Routes)),
+		now:     time.Now,
+	}
+
+	if !cfg.Default.unlimited() {
+		lim.def = newBuckets(cfg.Default)
+	}
+
+	for route, limit := range cfg.Routes {
+		// Маршрут без ограничения тоже запоминается, чтобы на него не распространялось Default
+		var b *buckets
+		if !limit.unlimited() {
+			b = newBuckets(limit)
+		}
+		lim.routes[route] = b
+	}
+
+	if !cfg.AuthFailures.unlimited() {
+		lim.auth = newBuckets(cfg.AuthFailures)
+	}
+
+	return lim
+}
+
+// Middleware ограничивает запросы клиента к маршруту. Превысивший ограничение клиент получает 429
+// с заголовком Retry-After. Заголовки RateLimit-* сообщают об ограничении в каждом ответе.
+// Ограничитель nil пропускает все запросы.
+func (lim *Limiter) Middleware(next http.Handler) http.Handler {
+	if lim == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, route := lim.bucketsOf(lim.route(r))
+		if b == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		client, retryAfter, ok := lim.client(r)
+		if !ok {
+			tooManyRequests(w, r, retryAfter, "too many failed authentication attempts, retry later")
+			return
+		}
+
+		// Попытка входа учтена в client: AuthFailures не учитывает ее второй раз
+		if _, _, basic := r.BasicAuth(); basic {
+			r = r.WithContext(context.WithValue(r.Context(), attemptKey{}, true))
+		}
+
+		s := b.take(client+" "+route, lim.now())
+		setHeaders(w, b.limit, s)
+
+		if !s.allowed {
+			tooManyRequests(w, r, s.retryAfter, "too many requests, retry later")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// Allow забирает токен клиента запроса r для маршрута route ("METHOD /pattern"). Если токена нет,
+// возвращает false и через сколько повторить запрос. Так ограничиваются вызовы gRPC.
+func (lim *Limiter) Allow(r *http.Request, route string) (time.Duration, bool) {
+	if lim == nil {
+		return 0, true
+	}
+
+	b, route := lim.bucketsOf(route)
+	if b == nil {
+		return 0, true
+	}
+
+	client, retryAfter, ok := lim.client(r)
+	if !ok {
+		return retryAfter, false
+	}
+
+	s := b.take(client+" "+route, lim.now())
+
+	return s.retryAfter, s.allowed
+}
+
+// bucketsOf возвращает корзины маршрута и ключ маршрута в них или nil, если маршрут не ограничен.
+// Корзины маршрутов из Routes у каждого клиента свои, остальные маршруты делят одну.
+func (lim *Limiter) bucketsOf(route string) (*buckets, string) {
+	if b, ok := lim.routes[route]; ok {
+		return b, route
+	}
+
+	return lim.def, ""
+}
+
+// AuthFailures ограничивает неудачные попытки входа администратора: next должен проверять учетные
+// данные и отвечать 401, если они неверны. Каждая попытка забирает токен адреса клиента, удачная
+// возвращает его. Когда токены кончаются, адрес получает 429 даже с верными учетными данными.
+// Запрос, учетные данные которого уже проверил Middleware, второй раз не учитывается.
+func (lim *Limiter) AuthFailures(next http.Handler) http.Handler {
+	if lim == nil || lim.auth == nil {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if counted, _ := r.Context().Value(attemptKey{}).(bool); counted {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		retryAfter, ok := lim.AuthAttempt(r)
+		if !ok {
+			tooManyRequests(w, r, retryAfter, "too many failed authentication attempts, retry later")
+			return
+		}
+
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+
+		if ww.Status() != http.StatusUnauthorized {
+			lim.AuthSucceeded(r)
+		}
+	})
+}
+
+// AuthAttempt забирает попытку входа у адреса клиента запроса r. Если попыток нет, возвращает false
+// и через сколько повторить. Удачную попытку следует вернуть вызовом AuthSucceeded.
+func (lim *Limiter) AuthAttempt(r *http.Request) (time.Duration, bool) {
+	if lim == nil || lim.auth == nil {
+		return 0, true
+	}
+
+	s := lim.auth.take(lim.ClientIP(r).String(), lim.now())
+
+	return s.retryAfter, s.allowed
+}
+
+// AuthSucceeded возвращает попытку входа, забранную AuthAttempt, если учетные данные оказались верными
+func (lim *Limiter) AuthSucceeded(r *http.Request) {
+	if lim == nil || lim.auth == nil {
+		return
+	}
+
+	lim.auth.put(lim.ClientIP(r).String(), lim.now())
+}
+
+// route возвращает ключ маршрута запроса "METHOD /pattern" или пустую строку, если маршрута нет.
+// Маршрут ищется от корня: внутри вложенных маршрутизаторов шаблон еще не собран полностью.
+func (lim *Limiter) route(r *http.Request) string {
+	if lim.mux == nil {
+		return ""
+	}
+
+	rctx := chi.NewRouteContext()
+	if !lim.mux.Match(rctx, r.Method, r.URL.Path) {
+		return ""
+	}
+
+	return r.Method + " " + rctx.RoutePattern()
+}
+
+// attemptKey - ключ контекста запроса, учетные данные которого Middleware уже проверил
+type attemptKey struct{}
+
+// client возвращает ключ клиента: пользователь, прошедший проверку, название ключа API или адрес.
+// Учетные данные проверяются на любом ограниченном маршруте, поэтому проверка забирает попытку входа
+// адреса, как AuthFailures, а верные учетные данные ее возвращают. Иначе пароль можно было бы подбирать
+// на открытых маршрутах по смене корзины в заголовках RateLimit-*. Если попыток не осталось,
+// возвращает false и через сколько повторить.
+func (lim *Limiter) client(r *http.Request) (string, time.Duration, bool) {
+	// Вызовы gRPC ограничиваются после проверки учетных данных
+	if user, ok := auth.Principal(r.Context()); ok {
+		return "user:" + user, 0, true
+	}
+
+	if _, _, ok := r.BasicAuth(); ok {
+		if retryAfter, ok := lim.AuthAttempt(r); !ok {
+			return "", retryAfter, false
+		}
+
+		if user, ok := auth.Check(r, lim.creds); ok {
+			lim.AuthSucceeded(r)
+			return "user:" + user, 0, true
+		}
+	}
+
+	if key := r.Header.Get("X-API-Key"); key != "" {
+		for name, k := range lim.apiKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+				return "key:" + name, 0, true
+			}
+		}
+	}
+
+	return "ip:" + lim.ClientIP(r).String(), 0, true
+}
+
+// setHeaders сообщает клиенту ограничение и остаток запросов (draft-ietf-httpapi-ratelimit-headers)
+func setHeaders(w http.ResponseWriter, limit Limit, s state) {
+	h := w.Header()
+	h.Set("RateLimit-Limit", strconv.Itoa(limit.Burst))
+	h.Set("RateLimit-Remaining", strconv.Itoa(s.remaining))
+	h.Set("RateLimit-Reset", strconv.Itoa(seconds(s.reset)))
+	h.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Burst, int(math.Ceil(float64(limit.Burst)/limit.Rate))))
+}
+
+func tooManyRequests(w http.ResponseWriter, r *http.Request, retryAfter time.Duration, detail string) {
+	w.Header().Set("Retry-After", RetryAfter(retryAfter))
+	problem.Render(w, r, problem.New(http.StatusTooManyRequests, "rate_limited", detail))
+}
+
+// RetryAfter возвращает значение заголовка Retry-After: секунды, округленные вверх, но не меньше одной
+func RetryAfter(d time.Duration) string {
+	sec := seconds(d)
+	if sec < 1 {
+		sec = 1
+	}
+
+	return strconv.Itoa(sec)
+}
+
+// seconds округляет d вверх до секунд
+func seconds(d time.Duration) int {
+	return int(math.Ceil(d.Seconds()))
+}
@@ -0,0 +1,280 @@
+package ratelimit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"filmoteka/internal/controller/middleware/auth"
+)
+
+func TestBuckets(t *testing.T) {
+	b := newBuckets(Limit{Rate: 2, Burst: 3})
+	now := time.Unix(1000, 0)
+
+	for i := 0; i < 3; i++ {
+		if s := b.take("a", now); !s.allowed || s.remaining != 2-i {
+			t.Fatalf("take %d = %+v, want allowed with %d remaining", i, s, 2-i)
+		}
+	}
+
+	s := b.take("a", now)
+	if s.allowed || s.retryAfter != 500*time.Millisecond || s.reset != 1500*time.Millisecond {
+		t.Fatalf("take on empty bucket = %+v, want denied, retry after 500ms, reset after 1.5s", s)
+	}
+
+	if s := b.take("b", now); !s.allowed {
+		t.Fatalf("other key is limited: %+v", s)
+	}
+
+	if s := b.take("a", now.Add(time.Second)); !s.allowed || s.remaining != 1 {
+		t.Fatalf("take after refill = %+v, want allowed with 1 remaining", s)
+	}
+
+	// Возвращенный токен не переполняет корзину
+	b.put("a", now.Add(time.Second))
+	b.put("a", now.Add(time.Second))
+	b.put("a", now.Add(time.Second))
+	if s := b.take("a", now.Add(time.Second)); s.remaining != 2 {
+		t.Fatalf("take after put = %+v, want 2 remaining", s)
+	}
+
+	// Полные корзины удаляются
+	b.take("c", now.Add(2*sweepInterval))
+	if _, ok := b.m["a"]; ok {
+		t.Errorf("full bucket was not swept")
+	}
+}
+
+func TestClientIP(t *testing.T) {
+	lim := New(nil, Config{TrustedProxies: []string{"10.0.0.0/8", "::1", "bad"}})
+
+	tests := []struct {
+		name   string
+		remote string
+		xff    []string
+		want   string
+	}{
+		{"direct", "192.0.2.1:1234", nil, "192.0.2.1"},
+		{"untrusted proxy", "192.0.2.1:1234", []string{"198.51.100.7"}, "192.0.2.1"},
+		{"trusted proxy", "10.1.2.3:1234", []string{"198.51.100.7"}, "198.51.100.7"},
+		{"spoofed hop", "10.1.2.3:1234", []string{"203.0.113.9, 198.51.100.7, 10.0.0.2"}, "198.51.100.7"},
+		{"several headers", "[::1]:1234", []string{"203.0.113.9", "198.51.100.7"}, "198.51.100.7"},
+		{"only proxies", "10.1.2.3:1234", []string{"10.0.0.5, 10.0.0.2"}, "10.0.0.5"},
+		{"invalid hop", "10.1.2.3:1234", []string{"198.51.100.7, unknown"}, "10.1.2.3"},
+		{"mapped address", "[::ffff:192.0.2.1]:1234", nil, "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remote
+			for _, v := range tt.xff {
+				r.Header.Add("X-Forwarded-For", v)
+			}
+
+			if got := lim.ClientIP(r).String(); got != tt.want {
+				t.Errorf("ClientIP() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTestRouter(cfg Config) (*chi.Mux, *Limiter) {
+	router := chi.NewRouter()
+	lim := New(router, cfg)
+
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+
+	router.Route("/movies", func(r chi.Router) {
+		r.Use(lim.Middleware)
+		r.Get("/list/", ok)
+		r.Get("/{id}", ok)
+		r.With(lim.AuthFailures, auth.Basic("test", cfg.Creds)).Post("/", ok)
+	})
+	router.With(lim.AuthFailures, auth.Basic("test", cfg.Creds)).Post("/admin", ok)
+
+	return router, lim
+}
+
+func serve(router http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestMiddleware(t *testing.T) {
+	router, lim := newTestRouter(Config{
+		APIKeys: map[string]string{"mobile": "secret"},
+		Creds:   map[string]string{"admin": "pass"},
+		Default: Limit{Rate: 1, Burst: 5},
+		Routes:  map[string]Limit{"GET /movies/list": {Rate: 0.5, Burst: 2}},
+	})
+
+	now := time.Unix(1000, 0)
+	lim.now = func() time.Time { return now }
+
+	get := func(path string, prepare func(r *http.Request)) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, path, nil)
+		if prepare != nil {
+			prepare(r)
+		}
+
+		return serve(router, r)
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := get("/movies/list/", nil); w.Code != http.StatusOK {
+			t.Fatalf("request %d: status = %d, want 200", i, w.Code)
+		}
+	}
+
+	w := get("/movies/list/", nil)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want 429", w.Code)
+	}
+
+	want := map[string]string{
+		"Retry-After":         "2",
+		"RateLimit-Limit":     "2",
+		"RateLimit-Remaining": "0",
+		"RateLimit-Reset":     "4",
+		"RateLimit-Policy":    "2;w=4",
+	}
+	for name, v := range want {
+		if got := w.Header().Get(name); got != v {
+			t.Errorf("%s = %q, want %q", name, got, v)
+		}
+	}
+
+	// Остальные маршруты ограничиваются своей корзиной
+	if w := get("/movies/1", nil); w.Code != http.StatusOK || w.Header().Get("RateLimit-Limit") != "5" {
+		t.Errorf("default route: status = %d, limit = %q, want 200 and 5", w.Code, w.Header().Get("RateLimit-Limit"))
+	}
+
+	// Ключ API и администратор - отдельные клиенты, неверный ключ - нет
+	if w := get("/movies/list/", func(r *http.Request) { r.Header.Set("X-API-Key", "secret") }); w.Code != http.StatusOK {
+		t.Errorf("api key: status = %d, want 200", w.Code)
+	}
+	if w := get("/movies/list/", func(r *http.Request) { r.SetBasicAuth("admin", "pass") }); w.Code != http.StatusOK {
+		t.Errorf("admin: status = %d, want 200", w.Code)
+	}
+	if w := get("/movies/list/", func(r *http.Request) { r.Header.Set("X-API-Key", "wrong") }); w.Code != http.StatusTooManyRequests {
+		t.Errorf("wrong api key: status = %d, want 429", w.Code)
+	}
+
+	now = now.Add(2 * time.Second)
+	if w := get("/movies/list/", nil); w.Code != http.StatusOK {
+		t.Errorf("after retry: status = %d, want 200", w.Code)
+	}
+}
+
+func TestAuthFailures(t *testing.T) {
+	router, lim := newTestRouter(Config{
+		Creds:        map[string]string{"admin": "pass"},
+		AuthFailures: Limit{Rate: 0.1, Burst: 2},
+	})
+
+	now := time.Unix(1000, 0)
+	lim.now = func() time.Time { return now }
+
+	post := func(pass string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodPost, "/admin", nil)
+		r.SetBasicAuth("admin", pass)
+
+		return serve(router, r)
+	}
+
+	// Удачные попытки не расходуют токены
+	for i := 0; i < 5; i++ {
+		if w := post("pass"); w.Code != http.StatusOK {
+			t.Fatalf("valid credentials %d: status = %d, want 200", i, w.Code)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		if w := post("wrong"); w.Code != http.StatusUnauthorized {
+			t.Fatalf("invalid credentials %d: status = %d, want 401", i, w.Code)
+		}
+	}
+
+	w := post("pass")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "10" {
+		t.Fatalf("after failures: status = %d, Retry-After = %q, want 429 and 10", w.Code, w.Header().Get("Retry-After"))
+	}
+
+	now = now.Add(10 * time.Second)
+	if w := post("pass"); w.Code != http.StatusOK {
+		t.Errorf("after retry: status = %d, want 200", w.Code)
+	}
+}
+
+func TestCredentialsOnPublicRoutes(t *testing.T) {
+	router, lim := newTestRouter(Config{
+		Creds:        map[string]string{"admin": "pass"},
+		Default:      Limit{Rate: 1, Burst: 5},
+		AuthFailures: Limit{Rate: 0.1, Burst: 2},
+	})
+
+	now := time.Unix(1000, 0)
+	lim.now = func() time.Time { return now }
+
+	get := func(pass string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(http.MethodGet, "/movies/1", nil)
+		r.SetBasicAuth("admin", pass)
+
+		return serve(router, r)
+	}
+
+	// Верные учетные данные не расходуют попытки входа
+	for i := 0; i < 3; i++ {
+		if w := get("pass"); w.Code != http.StatusOK {
+			t.Fatalf("valid credentials %d: status = %d, want 200", i, w.Code)
+		}
+	}
+
+	// Неверные учетные данные на открытом маршруте расходуют попытки входа, как на маршрутах администратора
+	for i := 0; i < 2; i++ {
+		if w := get("wrong"); w.Code != http.StatusOK {
+			t.Fatalf("invalid credentials %d: status = %d, want 200", i, w.Code)
+		}
+	}
+
+	for _, pass := range []string{"wrong", "pass"} {
+		w := get(pass)
+		if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "10" {
+			t.Fatalf("password %q after failures: status = %d, Retry-After = %q, want 429 and 10",
+				pass, w.Code, w.Header().Get("Retry-After"))
+		}
+	}
+
+	// Без учетных данных запросы ограничиваются только корзиной адреса
+	if w := serve(router, httptest.NewRequest(http.MethodGet, "/movies/1", nil)); w.Code != http.StatusOK {
+		t.Errorf("without credentials: status = %d, want 200", w.Code)
+	}
+}
+
+func TestAuthFailuresCountedOnce(t *testing.T) {
+	router, lim := newTestRouter(Config{
+		Creds:        map[string]string{"admin": "pass"},
+		Default:      Limit{Rate: 1, Burst: 5},
+		AuthFailures: Limit{Rate: 0.1, Burst: 2},
+	})
+
+	now := time.Unix(1000, 0)
+	lim.now = func() time.Time { return now }
+
+	// Middleware и AuthFailures проверяют один запрос, но попытка учитывается один раз
+	for i, want := range []int{http.StatusUnauthorized, http.StatusUnauthorized, http.StatusTooManyRequests} {
+		r := httptest.NewRequest(http.MethodPost, "/movies/", nil)
+		r.SetBasicAuth("admin", "wrong")
+
+		if w := serve(router, r); w.Code != want {
+			t.Fatalf("invalid credentials %d: status = %d, want %d", i, w.Code, want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+// Package requestlog записывает запросы в журнал приложения и связывает с запросом все записи,
+// сделанные с его контекстом: обработчиков, usecase и запросов к БД.
+//
+// К записям добавляются идентификатор запроса, шаблон маршрута, пользователь и время
+// с начала запроса.
+package requestlog
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"golang.org/x/exp/slog"
+
+	"filmoteka/pkg/logger"
+)
+
+type entryKey struct{}
+
+// entry - запрос, к записям которого добавляются поля
+type entry struct {
+	start     time.Time
+	id        string
+	route     func() string
+	principal atomic.Pointer[string]
+}
+
+// Start связывает ctx с запросом id. route возвращает маршрут запроса: он может стать
+// известен позже, например после выбора маршрута в chi.
+func Start(ctx context.Context, id string, route func() string) context.Context {
+	e := &entry{start: time.Now(), id: id, route: route}
+
+	ctx = context.WithValue(ctx, entryKey{}, e)
+
+	return logger.WithFields(ctx, e.fields)
+}
+
+// SetPrincipal запоминает пользователя, выполняющего запрос. Вызывается после проверки
+// учетных данных, поэтому пользователь попадает и в записи, сделанные до нее, например
+// в итоговую запись о запросе.
+func SetPrincipal(ctx context.Context, user string) {
+	if e, ok := ctx.Value(entryKey{}).(*entry); ok {
+		e.principal.Store(&user)
+	}
+}
+
+func (e *entry) fields() []slog.Attr {
+	res := make([]slog.Attr, 0, 4)
+
+	if e.id != "" {
+		res = append(res, slog.String("request_id", e.id))
+	}
+
+	if route := e.route(); route != "" {
+		res = append(res, slog.String("route", route))
+	}
+
+	if user := e.principal.Load(); user != nil {
+		res = append(res, slog.String("principal", *user))
+	}
+
+	return append(res, latency(time.Since(e.start)))
+}
+
+// latency - время выполнения в миллисекундах, как в ответах проверок готовности
+func latency(d time.Duration) slog.Attr {
+	return slog.Float64("latency_ms", float64(d.Microseconds())/1000)
+}
+
+// Middleware записывает в журнал каждый запрос после ответа на него. Должен стоять после
+// middleware.RequestID и перед middleware.Recoverer, чтобы запрос с паникой попал в журнал
+// со статусом 500.
+func Middleware(l logger.Interface) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			rctx := chi.RouteContext(r.Context())
+
+			ctx := Start(r.Context(), middleware.GetReqID(r.Context()), func() string {
+				if rctx == nil {
+					return ""
+				}
+
+				return rctx.RoutePattern()
+			})
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			defer func() {
+				status := ww.Status()
+				if status == 0 {
+					status = http.StatusOK
+				}
+
+				log := l.InfoContext
+				if status >= http.StatusInternalServerError {
+					log = l.ErrorContext
+				}
+
+				log(ctx, "request completed",
+					slog.String("method", r.Method),
+					slog.String("path", r.URL.Path),
+					slog.Int("status", status),
+					slog.Int("bytes", ww.BytesWritten()),
+					slog.String("remote", r.RemoteAddr),
+				)
+			}()
+
+			next.ServeHTTP(ww, r.WithContext(ctx))
+		})
+	}
+}
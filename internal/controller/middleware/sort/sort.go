@@ -4,6 +4,8 @@ import (
 	"context"
 	"net/http"
 	"strings"
+
+	"filmoteka/internal/controller/problem"
 )
 
 const (
@@ -58,10 +60,9 @@ func Middleware(next http.Handler) http.Handler {
 			for _, val := range sortOrder {
 
 				if val != ASC && val != DESC {
-					w.WriteHeader(http.StatusBadRequest)
-					w.Write([]byte("incorrect sort order"))
+					problem.Render(w, r, problem.New(http.StatusBadRequest, "invalid_sort_order", "incorrect sort order"))
 
-					next.ServeHTTP(w, r)
+					return
 				}
 
 			}
@@ -0,0 +1,80 @@
+package problem
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/validator"
+)
+
+// ContentType ответа с описанием ошибки (RFC 7807)
+const ContentType = "application/problem+json"
+
+// Problem - описание ошибки в формате RFC 7807.
+// Code - стабильный машиночитаемый код ошибки, по которому клиенты различают ошибки.
+// Errors - ошибки отдельных полей запроса, все сразу.
+type Problem struct {
+	Type     string                 `json:"type"`
+	Title    string                 `json:"title"`
+	Status   int                    `json:"status"`
+	Detail   string                 `json:"detail,omitempty"`
+	Instance string                 `json:"instance,omitempty"`
+	Code     string                 `json:"code"`
+	Errors   []validator.FieldError `json:"errors,omitempty"`
+}
+
+// New создает описание ошибки с указанным статусом и кодом
+func New(status int, code, detail string) Problem {
+	return Problem{
+		Type:   "urn:filmoteka:problem:" + code,
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// FromError создает описание ошибки предметной области.
+// Текст исходной ошибки клиенту не передается.
+func FromError(err error) Problem {
+	e := usecase.AsError(err)
+
+	p := New(Status(e.Kind), e.Code, e.Message)
+	p.Errors = e.Fields
+
+	return p
+}
+
+// Status возвращает HTTP статус для вида ошибки предметной области
+func Status(kind usecase.ErrorKind) int {
+	switch kind {
+	case usecase.KindNotFound:
+		return http.StatusNotFound
+	case usecase.KindConflict:
+		return http.StatusConflict
+	case usecase.KindValidation:
+		return http.StatusBadRequest
+	case usecase.KindUnauthorized:
+		return http.StatusUnauthorized
+	case usecase.KindUnavailable:
+		return http.StatusServiceUnavailable
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// Render отправляет описание ошибки клиенту
+func Render(w http.ResponseWriter, r *http.Request, p Problem) {
+	p.Instance = r.URL.Path
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// Error отправляет клиенту описание ошибки предметной области
+func Error(w http.ResponseWriter, r *http.Request, err error) {
+	Render(w, r, FromError(err))
+}
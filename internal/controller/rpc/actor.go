@@ -0,0 +1,155 @@
+package rpc
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+	pb "filmoteka/pkg/pb/filmoteka/v1"
+)
+
+// Минимальное сходство пары актеров по умолчанию
+const defaultDuplicateScore = 0.6
+
+type actorServer struct {
+	pb.UnimplementedActorServiceServer
+
+	a usecase.Actor
+	l logger.Interface
+}
+
+func (s *actorServer) GetActor(ctx context.Context, req *pb.IDRequest) (*pb.Actor, error) {
+	res, err := s.a.Find(ctx, int(req.Id))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return actorToPB(res), nil
+}
+
+func (s *actorServer) GetActorByExternalID(ctx context.Context, req *pb.ExternalIDRequest) (*pb.Actor, error) {
+	res, err := s.a.FindByExternalID(ctx, req.Source, req.ExternalId)
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return actorToPB(res), nil
+}
+
+func (s *actorServer) ListActors(req *pb.ListRequest, stream pb.ActorService_ListActorsServer) error {
+	err := sendPages(stream.Context(), req, s.a.Next,
+		func(a entity.Actor) int { return *a.Id },
+		func(a entity.Actor) error { return stream.Send(actorToPB(a)) },
+	)
+
+	if err != nil {
+		s.l.DebugContext(stream.Context(), "Failed to stream actors", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	return nil
+}
+
+func (s *actorServer) CreateActor(ctx context.Context, req *pb.ActorData) (*pb.Actor, error) {
+	res, err := s.a.Save(ctx, actorDataFromPB(req))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to save data in DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return actorToPB(res), nil
+}
+
+func (s *actorServer) UpsertActor(ctx context.Context, req *pb.ActorData) (*pb.Actor, error) {
+	res, err := s.a.Upsert(ctx, actorDataFromPB(req))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to upsert data in DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return actorToPB(res), nil
+}
+
+func (s *actorServer) UpdateActor(ctx context.Context, req *pb.UpdateActorRequest) (*pb.Actor, error) {
+	id := int(req.Id)
+
+	res, err := s.a.Update(ctx, entity.Actor{Id: &id, ActorData: actorDataFromPB(req.Data)})
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to update data in DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return actorToPB(res), nil
+}
+
+func (s *actorServer) DeleteActor(ctx context.Context, req *pb.IDRequest) (*pb.Actor, error) {
+	res, err := s.a.Delete(ctx, int(req.Id))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to delete data from DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return actorToPB(res), nil
+}
+
+func (s *actorServer) FindDuplicates(req *pb.DuplicatesRequest, stream pb.ActorService_FindDuplicatesServer) error {
+	minScore := req.MinScore
+	if minScore == 0 {
+		minScore = defaultDuplicateScore
+	}
+
+	if minScore < 0 || minScore > 1 {
+		return toStatus(usecase.Validation("invalid_min_score", "min_score should be a number between 0 and 1"))
+	}
+
+	res, err := s.a.Duplicates(stream.Context(), minScore)
+
+	if err != nil {
+		s.l.DebugContext(stream.Context(), "Failed to get data from DB", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	for _, d := range res {
+		err = stream.Send(&pb.DuplicateActors{
+			Actor:        actorToPB(d.Actor),
+			Duplicate:    actorToPB(d.Duplicate),
+			Score:        d.Score,
+			SharedMovies: int32(d.SharedMovies),
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *actorServer) MergeActors(ctx context.Context, req *pb.MergeActorsRequest) (*pb.Actor, error) {
+	res, err := s.a.Merge(ctx, int(req.SourceId), int(req.TargetId))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to merge actors in DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return actorToPB(res), nil
+}
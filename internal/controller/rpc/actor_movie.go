@@ -0,0 +1,118 @@
+package rpc
+
+import (
+	"context"
+
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+	pb "filmoteka/pkg/pb/filmoteka/v1"
+)
+
+type actorMovieServer struct {
+	pb.UnimplementedActorMovieServiceServer
+
+	a  usecase.Actor
+	m  usecase.Movie
+	am usecase.ActorMovie
+	l  logger.Interface
+}
+
+func (s *actorMovieServer) AddCast(ctx context.Context, req *pb.CastLink) (*emptypb.Empty, error) {
+	actorID, movieID := int(req.ActorId), int(req.MovieId)
+
+	err := s.am.Save(ctx, entity.ActorMovie{Actor_id: &actorID, Movie_id: &movieID})
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to save data in DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+func (s *actorMovieServer) ListActorsMovies(_ *emptypb.Empty, stream pb.ActorMovieService_ListActorsMoviesServer) error {
+	res, err := s.am.List(stream.Context())
+
+	if err != nil {
+		s.l.DebugContext(stream.Context(), "Failed to get data from DB", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	for _, d := range res {
+		err = stream.Send(&pb.ActorMovieData{
+			ActorId:      int64(d.ActorID),
+			ActorName:    d.ActorName,
+			ActorSurname: d.ActorSurname,
+			MovieId:      int64(d.MovieID),
+			MovieTitle:   d.MovieTitle,
+		})
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *actorMovieServer) GetCast(req *pb.IDRequest, stream pb.ActorMovieService_GetCastServer) error {
+	ctx := stream.Context()
+	id := int(req.Id)
+
+	// Для несуществующего фильма возвращаем NotFound, а не пустой поток
+	if _, err := s.m.Find(ctx, id); err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	res, err := s.am.Cast(ctx, []int{id})
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	for _, a := range res[id] {
+		if err = stream.Send(actorToPB(a)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *actorMovieServer) GetFilmography(req *pb.IDRequest, stream pb.ActorMovieService_GetFilmographyServer) error {
+	ctx := stream.Context()
+
+	// Объединенный актер находится по старому id, фильмы берем по новому
+	actor, err := s.a.Find(ctx, int(req.Id))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	res, err := s.am.Filmography(ctx, []int{*actor.Id})
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	for _, m := range res[*actor.Id] {
+		if err = stream.Send(movieToPB(m)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,103 @@
+package rpc
+
+import (
+	"filmoteka/internal/entity"
+	pb "filmoteka/pkg/pb/filmoteka/v1"
+)
+
+// Преобразование сущностей в сообщения protobuf и обратно
+
+func actorToPB(a entity.Actor) *pb.Actor {
+	return &pb.Actor{Id: id64(a.Id), Data: actorDataToPB(a.ActorData)}
+}
+
+func actorDataToPB(d entity.ActorData) *pb.ActorData {
+	return &pb.ActorData{
+		Name:        d.Name,
+		Surname:     d.Surname,
+		Patronymic:  d.Patronymic,
+		Gender:      d.Gender,
+		DateOfBirth: dateToPB(d.DateOfBirth),
+		ExternalIds: d.ExternalIDs,
+	}
+}
+
+func actorDataFromPB(d *pb.ActorData) entity.ActorData {
+	if d == nil {
+		return entity.ActorData{}
+	}
+
+	return entity.ActorData{
+		Name:        d.Name,
+		Surname:     d.Surname,
+		Patronymic:  d.Patronymic,
+		Gender:      d.Gender,
+		DateOfBirth: dateFromPB(d.DateOfBirth),
+		ExternalIDs: d.ExternalIds,
+	}
+}
+
+func movieToPB(m entity.Movie) *pb.Movie {
+	return &pb.Movie{Id: id64(m.Id), Data: movieDataToPB(m.MovieData)}
+}
+
+func movieDataToPB(d entity.MovieData) *pb.MovieData {
+	var rating *int32
+	if d.Rating != nil {
+		r := int32(*d.Rating)
+		rating = &r
+	}
+
+	return &pb.MovieData{
+		Title:       d.Title,
+		Description: d.Description,
+		ReleaseDate: dateToPB(d.ReleaseDate),
+		Rating:      rating,
+		ExternalIds: d.ExternalIDs,
+	}
+}
+
+func movieDataFromPB(d *pb.MovieData) entity.MovieData {
+	if d == nil {
+		return entity.MovieData{}
+	}
+
+	var rating *int
+	if d.Rating != nil {
+		r := int(*d.Rating)
+		rating = &r
+	}
+
+	return entity.MovieData{
+		Title:       d.Title,
+		Description: d.Description,
+		ReleaseDate: dateFromPB(d.ReleaseDate),
+		Rating:      rating,
+		ExternalIDs: d.ExternalIds,
+	}
+}
+
+func dateToPB(d *entity.Date) *pb.Date {
+	if d == nil {
+		return nil
+	}
+
+	return &pb.Date{Year: int32(d.Year), Month: int32(d.Month), Day: int32(d.Day)}
+}
+
+// dateFromPB не проверяет дату: о несуществующей дате сообщит проверка данных в usecase
+func dateFromPB(d *pb.Date) *entity.Date {
+	if d == nil {
+		return nil
+	}
+
+	return &entity.Date{Year: int(d.Year), Month: int(d.Month), Day: int(d.Day)}
+}
+
+func id64(id *int) int64 {
+	if id == nil {
+		return 0
+	}
+
+	return int64(*id)
+}
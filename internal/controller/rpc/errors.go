@@ -0,0 +1,67 @@
+package rpc
+
+import (
+	"strings"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"filmoteka/internal/usecase"
+)
+
+// errorDomain - домен кодов ошибок в ErrorInfo
+const errorDomain = "filmoteka"
+
+// toStatus преобразует ошибку предметной области в статус gRPC.
+// Код ошибки передается в ErrorInfo.Reason, ошибки полей - в BadRequest.
+// Текст исходной ошибки клиенту не передается.
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	e := usecase.AsError(err)
+
+	st := status.New(code(e), e.Message)
+
+	details := []*errdetails.BadRequest_FieldViolation{}
+	for _, f := range e.Fields {
+		details = append(details, &errdetails.BadRequest_FieldViolation{Field: f.Field, Description: f.Message})
+	}
+
+	info := &errdetails.ErrorInfo{Reason: e.Code, Domain: errorDomain}
+
+	var withDetails *status.Status
+	if len(details) != 0 {
+		withDetails, err = st.WithDetails(info, &errdetails.BadRequest{FieldViolations: details})
+	} else {
+		withDetails, err = st.WithDetails(info)
+	}
+
+	if err != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+func code(e *usecase.Error) codes.Code {
+	switch e.Kind {
+	case usecase.KindNotFound:
+		return codes.NotFound
+	case usecase.KindConflict:
+		if strings.HasSuffix(e.Code, "_already_exists") {
+			return codes.AlreadyExists
+		}
+		return codes.FailedPrecondition
+	case usecase.KindValidation:
+		return codes.InvalidArgument
+	case usecase.KindUnauthorized:
+		return codes.Unauthenticated
+	case usecase.KindUnavailable:
+		return codes.Unavailable
+	default:
+		return codes.Internal
+	}
+}
@@ -0,0 +1,76 @@
+package rpc
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/validator"
+)
+
+func TestToStatus(t *testing.T) {
+	invalid := usecase.Validation("invalid_actor", "invalid actor")
+	invalid.Fields = []validator.FieldError{{Field: "name", Code: "required", Message: "name is required"}}
+
+	tests := []struct {
+		name   string
+		err    error
+		code   codes.Code
+		reason string
+	}{
+		{"not found", usecase.NotFound("actor_not_found", "actor not found"), codes.NotFound, "actor_not_found"},
+		{"already exists", usecase.Conflict("actor_already_exists", "actor already exists"), codes.AlreadyExists, "actor_already_exists"},
+		{"other conflict", usecase.Conflict("actor_has_movies", "actor has movies"), codes.FailedPrecondition, "actor_has_movies"},
+		{"validation", invalid, codes.InvalidArgument, "invalid_actor"},
+		{"unauthorized", usecase.Unauthorized("unauthorized", "unauthorized"), codes.Unauthenticated, "unauthorized"},
+		{"unavailable", usecase.Unavailable("database_unavailable", "database unavailable"), codes.Unavailable, "database_unavailable"},
+		{"internal", usecase.Internal(errors.New("connection secret")), codes.Internal, "internal_error"},
+		{"plain error", errors.New("connection secret"), codes.Internal, "internal_error"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			st := status.Convert(toStatus(tt.err))
+
+			if st.Code() != tt.code {
+				t.Errorf("code = %s, want %s", st.Code(), tt.code)
+			}
+
+			// Текст исходной ошибки клиенту не передается
+			if strings.Contains(st.Message(), "secret") {
+				t.Errorf("message %q leaks the wrapped error", st.Message())
+			}
+
+			var reason string
+			var fields []*errdetails.BadRequest_FieldViolation
+			for _, d := range st.Details() {
+				switch d := d.(type) {
+				case *errdetails.ErrorInfo:
+					if d.Domain != errorDomain {
+						t.Errorf("domain = %q, want %q", d.Domain, errorDomain)
+					}
+					reason = d.Reason
+				case *errdetails.BadRequest:
+					fields = d.FieldViolations
+				}
+			}
+
+			if reason != tt.reason {
+				t.Errorf("reason = %q, want %q", reason, tt.reason)
+			}
+
+			if len(fields) != len(usecase.AsError(tt.err).Fields) {
+				t.Errorf("field violations = %v, want %d", fields, len(usecase.AsError(tt.err).Fields))
+			}
+		})
+	}
+
+	if toStatus(nil) != nil {
+		t.Error("toStatus(nil) != nil")
+	}
+}
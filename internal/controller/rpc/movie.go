@@ -0,0 +1,131 @@
+package rpc
+
+import (
+	"context"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+	pb "filmoteka/pkg/pb/filmoteka/v1"
+)
+
+type movieServer struct {
+	pb.UnimplementedMovieServiceServer
+
+	m usecase.Movie
+	l logger.Interface
+}
+
+func (s *movieServer) GetMovie(ctx context.Context, req *pb.IDRequest) (*pb.Movie, error) {
+	res, err := s.m.Find(ctx, int(req.Id))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return movieToPB(res), nil
+}
+
+func (s *movieServer) GetMovieByExternalID(ctx context.Context, req *pb.ExternalIDRequest) (*pb.Movie, error) {
+	res, err := s.m.FindByExternalID(ctx, req.Source, req.ExternalId)
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return movieToPB(res), nil
+}
+
+func (s *movieServer) ListMovies(req *pb.ListRequest, stream pb.MovieService_ListMoviesServer) error {
+	err := sendPages(stream.Context(), req, s.m.Next,
+		func(m entity.Movie) int { return *m.Id },
+		func(m entity.Movie) error { return stream.Send(movieToPB(m)) },
+	)
+
+	if err != nil {
+		s.l.DebugContext(stream.Context(), "Failed to stream movies", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	return nil
+}
+
+func (s *movieServer) SearchMovies(req *pb.SearchMoviesRequest, stream pb.MovieService_SearchMoviesServer) error {
+	// Поиск читает фрагменты названия и имени актера из параметров фильтрации, как и маршрут /movie/find/
+	ctx := context.WithValue(stream.Context(), filter.FilterOptionsContextKey, map[string][]string{
+		"title":      {req.Title},
+		"actor_name": {req.ActorName},
+	})
+
+	res, err := s.m.FindMovie(ctx)
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to get data from DB", s.l.Err(err))
+
+		return toStatus(err)
+	}
+
+	for _, m := range res {
+		if err = stream.Send(movieToPB(m)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *movieServer) CreateMovie(ctx context.Context, req *pb.MovieData) (*pb.Movie, error) {
+	res, err := s.m.Save(ctx, movieDataFromPB(req))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to save data in DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return movieToPB(res), nil
+}
+
+func (s *movieServer) UpsertMovie(ctx context.Context, req *pb.MovieData) (*pb.Movie, error) {
+	res, err := s.m.Upsert(ctx, movieDataFromPB(req))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to upsert data in DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return movieToPB(res), nil
+}
+
+func (s *movieServer) UpdateMovie(ctx context.Context, req *pb.UpdateMovieRequest) (*pb.Movie, error) {
+	id := int(req.Id)
+
+	res, err := s.m.Update(ctx, entity.Movie{Id: &id, MovieData: movieDataFromPB(req.Data)})
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to update data in DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return movieToPB(res), nil
+}
+
+func (s *movieServer) DeleteMovie(ctx context.Context, req *pb.IDRequest) (*pb.Movie, error) {
+	res, err := s.m.Delete(ctx, int(req.Id))
+
+	if err != nil {
+		s.l.DebugContext(ctx, "Failed to delete data from DB", s.l.Err(err))
+
+		return nil, toStatus(err)
+	}
+
+	return movieToPB(res), nil
+}
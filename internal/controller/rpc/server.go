@@ -0,0 +1,245 @@
+// Package rpc обслуживает сервисы gRPC из api/proto поверх тех же usecase, что и HTTP API.
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"golang.org/x/exp/slog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+
+	"filmoteka/internal/controller/middleware/auth"
+	"filmoteka/internal/controller/middleware/client"
+	"filmoteka/internal/controller/middleware/ratelimit"
+	"filmoteka/internal/controller/middleware/requestlog"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+	pb "filmoteka/pkg/pb/filmoteka/v1"
+)
+
+// adminMethods - методы, изменяющие данные. Для них нужны учетные данные администратора.
+var adminMethods = map[string]bool{
+	pb.ActorService_CreateActor_FullMethodName:  true,
+	pb.ActorService_UpsertActor_FullMethodName:  true,
+	pb.ActorService_UpdateActor_FullMethodName:  true,
+	pb.ActorService_DeleteActor_FullMethodName:  true,
+	pb.ActorService_MergeActors_FullMethodName:  true,
+	pb.MovieService_CreateMovie_FullMethodName:  true,
+	pb.MovieService_UpsertMovie_FullMethodName:  true,
+	pb.MovieService_UpdateMovie_FullMethodName:  true,
+	pb.MovieService_DeleteMovie_FullMethodName:  true,
+	pb.ActorMovieService_AddCast_FullMethodName: true,
+	// Поиск дубликатов доступен только администратору, как и маршрут /actors/duplicates
+	pb.ActorService_FindDuplicates_FullMethodName: true,
+}
+
+// New создает сервер gRPC с сервисами актеров, фильмов и связей между ними.
+// creds - учетные данные администратора, как для HTTP API. lim - ограничитель запросов, общий с HTTP API,
+// или nil, если запросы не ограничиваются.
+func New(a usecase.Actor, m usecase.Movie, am usecase.ActorMovie, l logger.Interface, creds map[string]string, lim *ratelimit.Limiter) *grpc.Server {
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryLog(l), unaryClient(lim), unaryAuth(creds, lim), unaryLimit(lim)),
+		grpc.ChainStreamInterceptor(streamLog(l), streamClient(lim), streamAuth(creds, lim), streamLimit(lim)),
+	)
+
+	pb.RegisterActorServiceServer(srv, &actorServer{a: a, l: l})
+	pb.RegisterMovieServiceServer(srv, &movieServer{m: m, l: l})
+	pb.RegisterActorMovieServiceServer(srv, &actorMovieServer{a: a, m: m, am: am, l: l})
+
+	return srv
+}
+
+// unaryLog записывает вызов в журнал так же, как requestlog.Middleware запрос HTTP
+func unaryLog(l logger.Interface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx = logContext(ctx, info.FullMethod)
+
+		res, err := handler(ctx, req)
+		logCall(ctx, l, err)
+
+		return res, err
+	}
+}
+
+func streamLog(l logger.Interface) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx := logContext(ss.Context(), info.FullMethod)
+
+		err := handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+		logCall(ctx, l, err)
+
+		return err
+	}
+}
+
+// logContext связывает вызов method с журналом. Идентификатор запроса берется из метаданных
+// x-request-id, как заголовок X-Request-Id в HTTP, или создается новый из общего с HTTP счетчика.
+func logContext(ctx context.Context, method string) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	id := fmt.Sprintf("grpc-%06d", middleware.NextRequestID())
+	if ids := md.Get("x-request-id"); len(ids) > 0 && ids[0] != "" {
+		id = ids[0]
+	}
+
+	return requestlog.Start(ctx, id, func() string { return method })
+}
+
+// logCall записывает итог вызова. Ошибки сервера (Internal, Unavailable и т.п.) записываются
+// как ошибки, остальные коды - как обычные ответы.
+func logCall(ctx context.Context, l logger.Interface, err error) {
+	code := status.Code(err)
+
+	log := l.InfoContext
+	switch code {
+	case codes.Internal, codes.Unknown, codes.Unavailable, codes.DataLoss, codes.Unimplemented:
+		log = l.ErrorContext
+	}
+
+	log(ctx, "request completed", slog.String("code", code.String()))
+}
+
+// unaryClient сохраняет в контексте адрес клиента, как middleware client.Middleware.
+// Адрес за доверенными прокси берется из метаданных x-forwarded-for.
+func unaryClient(lim *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(clientContext(ctx, lim), req)
+	}
+}
+
+func streamClient(lim *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, &serverStream{ServerStream: ss, ctx: clientContext(ss.Context(), lim)})
+	}
+}
+
+func clientContext(ctx context.Context, lim *ratelimit.Limiter) context.Context {
+	r := httpRequest(ctx)
+	if r.RemoteAddr == "" {
+		return ctx
+	}
+
+	return client.WithAddr(ctx, client.Addr(r, lim.ClientIP))
+}
+
+// serverStream - поток с измененным контекстом
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// unaryLimit ограничивает частоту вызовов, как ratelimit.Middleware запросы HTTP. Метод ограничивается
+// по ключу "POST /пакет.Сервис/Метод": так вызов gRPC выглядит в HTTP/2. Вызовы методов администратора
+// проверяются раньше и ограничиваются по пользователю из контекста.
+func unaryLimit(lim *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if retryAfter, ok := lim.Allow(httpRequest(ctx), routeKey(info.FullMethod)); !ok {
+			_ = grpc.SetHeader(ctx, retryAfterHeader(retryAfter))
+			return nil, status.Error(codes.ResourceExhausted, "too many requests, retry later")
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamLimit(lim *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if retryAfter, ok := lim.Allow(httpRequest(ss.Context()), routeKey(info.FullMethod)); !ok {
+			_ = ss.SetHeader(retryAfterHeader(retryAfter))
+			return status.Error(codes.ResourceExhausted, "too many requests, retry later")
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func routeKey(method string) string {
+	return http.MethodPost + " " + method
+}
+
+// retryAfterHeader сообщает клиенту в метаданных retry-after, через сколько секунд повторить вызов
+func retryAfterHeader(d time.Duration) metadata.MD {
+	return metadata.Pairs("retry-after", ratelimit.RetryAfter(d))
+}
+
+func unaryAuth(creds map[string]string, lim *ratelimit.Limiter) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, retryAfter, err := authenticate(ctx, info.FullMethod, creds, lim)
+		if err != nil {
+			if retryAfter > 0 {
+				_ = grpc.SetHeader(ctx, retryAfterHeader(retryAfter))
+			}
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+func streamAuth(creds map[string]string, lim *ratelimit.Limiter) grpc.StreamServerInterceptor {
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, retryAfter, err := authenticate(ss.Context(), info.FullMethod, creds, lim)
+		if err != nil {
+			if retryAfter > 0 {
+				_ = ss.SetHeader(retryAfterHeader(retryAfter))
+			}
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authenticate проверяет учетные данные из метаданных authorization так же, как middleware auth.Basic.
+// Неудачные попытки ограничиваются общими с HTTP API корзинами адресов (ratelimit.Limiter.AuthFailures).
+// Если попытки кончились, возвращает ResourceExhausted и через сколько повторить.
+func authenticate(ctx context.Context, method string, creds map[string]string, lim *ratelimit.Limiter) (context.Context, time.Duration, error) {
+	if !adminMethods[method] {
+		return ctx, 0, nil
+	}
+
+	r := httpRequest(ctx)
+
+	if retryAfter, ok := lim.AuthAttempt(r); !ok {
+		return ctx, retryAfter, status.Error(codes.ResourceExhausted, "too many failed authentication attempts, retry later")
+	}
+
+	user, ok := auth.Check(r, creds)
+	if !ok {
+		return ctx, 0, status.Error(codes.Unauthenticated, "valid admin credentials are required")
+	}
+
+	lim.AuthSucceeded(r)
+	requestlog.SetPrincipal(ctx, user)
+
+	return context.WithValue(ctx, auth.PrincipalContextKey, user), 0, nil
+}
+
+// httpRequest переносит адрес клиента, метаданные и контекст вызова в запрос HTTP, чтобы проверить их
+// теми же функциями, что и запросы HTTP API
+func httpRequest(ctx context.Context) *http.Request {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	r := &http.Request{Header: http.Header{
+		"Authorization":   md.Get("authorization"),
+		"X-Api-Key":       md.Get("x-api-key"),
+		"X-Forwarded-For": md.Get("x-forwarded-for"),
+	}}
+
+	if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		r.RemoteAddr = p.Addr.String()
+	}
+
+	return r.WithContext(ctx)
+}
@@ -0,0 +1,283 @@
+package rpc
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"net"
+	"reflect"
+	"testing"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/controller/middleware/ratelimit"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/logger"
+	pb "filmoteka/pkg/pb/filmoteka/v1"
+)
+
+const (
+	adminUser = "admin"
+	adminPass = "secret"
+)
+
+// movieIDs - фильмы, которые отдает movies.Next. Пропуски в id, как после удалений.
+var movieIDs = []int{1, 2, 4, 5, 7, 8, 9}
+
+// pageSize - размер страницы movies.Next
+const pageSize = 3
+
+// movies - фильм 1 и постраничный список movieIDs. Остальные методы usecase.Movie тестам не нужны.
+type movies struct {
+	usecase.Movie
+}
+
+func (movies) Find(_ context.Context, id int) (entity.Movie, error) {
+	if id != 1 {
+		return entity.Movie{}, usecase.NotFound("movie_not_found", "movie not found")
+	}
+
+	return entity.Movie{Id: &id}, nil
+}
+
+func (movies) Delete(ctx context.Context, id int) (entity.Movie, error) {
+	return movies{}.Find(ctx, id)
+}
+
+// Next возвращает до pageSize фильмов с id не меньше next_person_id, как репозитории
+func (movies) Next(ctx context.Context) ([]entity.Movie, error) {
+	from, _ := ctx.Value(pagination.NextPersonID).(int)
+
+	var page []entity.Movie
+	for _, id := range movieIDs {
+		if id >= from && len(page) < pageSize {
+			id := id
+			page = append(page, entity.Movie{Id: &id})
+		}
+	}
+
+	return page, nil
+}
+
+// newTestClient запускает сервер в памяти и возвращает соединение с ним
+func newTestClient(t *testing.T, a usecase.Actor, m usecase.Movie, am usecase.ActorMovie, lim *ratelimit.Limiter) *grpc.ClientConn {
+	t.Helper()
+
+	lis := bufconn.Listen(1 << 20)
+	srv := New(a, m, am, logger.New("prod", logger.Output(io.Discard)), map[string]string{adminUser: adminPass}, lim)
+
+	go func() { _ = srv.Serve(lis) }()
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.Dial("bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("failed to dial: %s", err)
+	}
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn
+}
+
+func withCreds(ctx context.Context, user, pass string) context.Context {
+	token := base64.StdEncoding.EncodeToString([]byte(user + ":" + pass))
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Basic "+token)
+}
+
+func TestRateLimit(t *testing.T) {
+	lim := ratelimit.New(nil, ratelimit.Config{
+		Routes: map[string]ratelimit.Limit{
+			"POST " + pb.MovieService_GetMovie_FullMethodName: {Rate: 0.01, Burst: 2},
+		},
+	})
+	movieClient := pb.NewMovieServiceClient(newTestClient(t, nil, movies{}, nil, lim))
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := movieClient.GetMovie(ctx, &pb.IDRequest{Id: 1}); err != nil {
+			t.Fatalf("call %d: %s", i, err)
+		}
+	}
+
+	var header metadata.MD
+
+	_, err := movieClient.GetMovie(ctx, &pb.IDRequest{Id: 1}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("code = %s, want ResourceExhausted", status.Code(err))
+	}
+
+	if got := header.Get("retry-after"); len(got) != 1 || got[0] != "100" {
+		t.Errorf("retry-after = %v, want 100", got)
+	}
+
+	// Методы без ограничения в Routes не ограничиваются: Default не задан
+	if _, err := movieClient.DeleteMovie(withCreds(ctx, adminUser, adminPass), &pb.IDRequest{Id: 1}); err != nil {
+		t.Errorf("unlimited method: %s", err)
+	}
+}
+
+func TestAuthFailures(t *testing.T) {
+	lim := ratelimit.New(nil, ratelimit.Config{AuthFailures: ratelimit.Limit{Rate: 0.01, Burst: 2}})
+	movieClient := pb.NewMovieServiceClient(newTestClient(t, nil, movies{}, nil, lim))
+	ctx := context.Background()
+
+	// Удачные попытки и методы без авторизации попыток не расходуют
+	for i := 0; i < 3; i++ {
+		if _, err := movieClient.DeleteMovie(withCreds(ctx, adminUser, adminPass), &pb.IDRequest{Id: 1}); err != nil {
+			t.Fatalf("valid credentials %d: %s", i, err)
+		}
+		if _, err := movieClient.GetMovie(ctx, &pb.IDRequest{Id: 1}); err != nil {
+			t.Fatalf("public method %d: %s", i, err)
+		}
+	}
+
+	for i := 0; i < 2; i++ {
+		_, err := movieClient.DeleteMovie(withCreds(ctx, adminUser, "wrong"), &pb.IDRequest{Id: 1})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("invalid credentials %d: code = %s, want Unauthenticated", i, status.Code(err))
+		}
+	}
+
+	var header metadata.MD
+
+	_, err := movieClient.DeleteMovie(withCreds(ctx, adminUser, adminPass), &pb.IDRequest{Id: 1}, grpc.Header(&header))
+	if status.Code(err) != codes.ResourceExhausted || len(header.Get("retry-after")) != 1 {
+		t.Fatalf("after failures: code = %s, retry-after = %v, want ResourceExhausted with retry-after",
+			status.Code(err), header.Get("retry-after"))
+	}
+}
+
+func TestAuthFailuresOnLimitedMethods(t *testing.T) {
+	lim := ratelimit.New(nil, ratelimit.Config{
+		Default:      ratelimit.Limit{Rate: 1, Burst: 10},
+		AuthFailures: ratelimit.Limit{Rate: 0.01, Burst: 3},
+	})
+	movieClient := pb.NewMovieServiceClient(newTestClient(t, nil, movies{}, nil, lim))
+	ctx := withCreds(context.Background(), adminUser, "wrong")
+
+	// Неудачный вход в метод администратора учитывается один раз, хотя метод еще и ограничен по частоте
+	for i := 0; i < 2; i++ {
+		_, err := movieClient.DeleteMovie(ctx, &pb.IDRequest{Id: 1})
+		if status.Code(err) != codes.Unauthenticated {
+			t.Fatalf("invalid credentials %d: code = %s, want Unauthenticated", i, status.Code(err))
+		}
+	}
+
+	// Неверные учетные данные в открытом методе тоже расходуют попытку
+	if _, err := movieClient.GetMovie(ctx, &pb.IDRequest{Id: 1}); err != nil {
+		t.Fatalf("public method: %s", err)
+	}
+
+	_, err := movieClient.GetMovie(withCreds(context.Background(), adminUser, adminPass), &pb.IDRequest{Id: 1})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("after failures: code = %s, want ResourceExhausted", status.Code(err))
+	}
+}
+
+func TestListMovies(t *testing.T) {
+	movieClient := pb.NewMovieServiceClient(newTestClient(t, nil, movies{}, nil, nil))
+
+	tests := []struct {
+		name  string
+		after int64
+		limit int64
+		want  []int64
+	}{
+		{"all pages", 0, 0, []int64{1, 2, 4, 5, 7, 8, 9}},
+		{"after inside page", 2, 0, []int64{4, 5, 7, 8, 9}},
+		{"after missing id", 3, 0, []int64{4, 5, 7, 8, 9}},
+		{"limit within page", 0, 2, []int64{1, 2}},
+		{"limit on page boundary", 0, pageSize, []int64{1, 2, 4}},
+		{"limit across pages", 1, 4, []int64{2, 4, 5, 7}},
+		{"limit beyond end", 5, 10, []int64{7, 8, 9}},
+		{"after last", 9, 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream, err := movieClient.ListMovies(context.Background(), &pb.ListRequest{After: tt.after, Limit: tt.limit})
+			if err != nil {
+				t.Fatalf("ListMovies: %s", err)
+			}
+
+			var got []int64
+			for {
+				m, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatalf("Recv: %s", err)
+				}
+
+				got = append(got, m.Id)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ids = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAdminMethodsRequireCredentials(t *testing.T) {
+	conn := newTestClient(t, nil, movies{}, nil, nil)
+	descs := []grpc.ServiceDesc{pb.ActorService_ServiceDesc, pb.MovieService_ServiceDesc, pb.ActorMovieService_ServiceDesc}
+
+	tested := 0
+
+	for _, desc := range descs {
+		for _, m := range desc.Methods {
+			method := "/" + desc.ServiceName + "/" + m.MethodName
+			if !adminMethods[method] {
+				continue
+			}
+			tested++
+
+			for name, ctx := range map[string]context.Context{
+				"no credentials":    context.Background(),
+				"wrong credentials": withCreds(context.Background(), adminUser, "wrong"),
+			} {
+				// Пустое сообщение разбирается как запрос любого метода: до обработчика вызов не доходит
+				err := conn.Invoke(ctx, method, &emptypb.Empty{}, &emptypb.Empty{})
+				if status.Code(err) != codes.Unauthenticated {
+					t.Errorf("%s with %s: code = %s, want Unauthenticated", method, name, status.Code(err))
+				}
+			}
+		}
+
+		for _, s := range desc.Streams {
+			method := "/" + desc.ServiceName + "/" + s.StreamName
+			if !adminMethods[method] {
+				continue
+			}
+			tested++
+
+			stream, err := conn.NewStream(context.Background(), &grpc.StreamDesc{ServerStreams: true}, method)
+			if err == nil {
+				if err = stream.SendMsg(&emptypb.Empty{}); err == nil {
+					_ = stream.CloseSend()
+					err = stream.RecvMsg(&emptypb.Empty{})
+				}
+			}
+
+			if status.Code(err) != codes.Unauthenticated {
+				t.Errorf("%s: code = %s, want Unauthenticated", method, status.Code(err))
+			}
+		}
+	}
+
+	if tested != len(adminMethods) {
+		t.Errorf("tested %d admin methods, want %d", tested, len(adminMethods))
+	}
+}
@@ -0,0 +1,41 @@
+package rpc
+
+import (
+	"context"
+
+	"filmoteka/internal/controller/middleware/pagination"
+	pb "filmoteka/pkg/pb/filmoteka/v1"
+)
+
+// sendPages отправляет в поток записи с id больше req.After, запрашивая у usecase страницу за страницей,
+// пока записи не закончатся или не будет отправлено req.Limit записей
+func sendPages[T any](ctx context.Context, req *pb.ListRequest,
+	next func(ctx context.Context) ([]T, error), id func(T) int, send func(T) error) error {
+	after := int(req.After)
+	var sent int64
+
+	for {
+		// Next возвращает записи с id не меньше next_person_id
+		page, err := next(context.WithValue(ctx, pagination.NextPersonID, after+1))
+		if err != nil {
+			return err
+		}
+
+		if len(page) == 0 {
+			return nil
+		}
+
+		for _, item := range page {
+			if req.Limit > 0 && sent >= req.Limit {
+				return nil
+			}
+
+			if err = send(item); err != nil {
+				return err
+			}
+
+			sent++
+			after = id(item)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+// Package ctl - команды утилиты filmotekactl для обслуживания каталога.
+//
+// Команды работают с каталогом через интерфейс Catalogue: либо напрямую с usecase поверх БД,
+// либо с запущенным сервером через HTTP API.
+package ctl
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+)
+
+// Catalogue - операции с каталогом, которые нужны командам
+type Catalogue interface {
+	Movie(ctx context.Context, id int) (entity.Movie, error)
+	Movies(ctx context.Context) ([]entity.Movie, error)
+	CreateMovie(ctx context.Context, data entity.MovieData) (entity.Movie, error)
+	UpdateMovie(ctx context.Context, id int, data entity.MovieData) (entity.Movie, error)
+	UpsertMovie(ctx context.Context, data entity.MovieData) (entity.Movie, error)
+	DeleteMovie(ctx context.Context, id int) error
+
+	Actor(ctx context.Context, id int) (entity.Actor, error)
+	Actors(ctx context.Context) ([]entity.Actor, error)
+	CreateActor(ctx context.Context, data entity.ActorData) (entity.Actor, error)
+	UpdateActor(ctx context.Context, id int, data entity.ActorData) (entity.Actor, error)
+	UpsertActor(ctx context.Context, data entity.ActorData) (entity.Actor, error)
+	DeleteActor(ctx context.Context, id int) error
+
+	Link(ctx context.Context, movieID, actorID int) error
+	Unlink(ctx context.Context, movieID, actorID int) error
+}
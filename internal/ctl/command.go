@@ -0,0 +1,298 @@
+package ctl
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"filmoteka/internal/entity"
+)
+
+// ErrUsage - команда вызвана с неверными аргументами
+var ErrUsage = errors.New("usage")
+
+// Usage - справка по командам filmotekactl
+const Usage = `Usage: filmotekactl [global flags] <command> [flags] [args]
+
+Commands:
+  movie add [-title T] [-description D] [-release-date D] [-rating N] [-external source=id ...]
+  movie update ID [movie flags]     change only the fields passed as flags
+  movie rm ID
+  movie show ID
+  movie list
+  actor add [-name N] [-surname S] [-patronymic P] [-gender G] [-birth-date D] [-external source=id ...]
+  actor update ID [actor flags]     change only the fields passed as flags
+  actor rm ID
+  actor show ID
+  actor list
+  cast link MOVIE_ID ACTOR_ID
+  cast unlink MOVIE_ID ACTOR_ID
+  import movies|actors [-format json|csv] FILE|-
+  export movies|actors
+
+Global flags:
+`
+
+// Command выполняет команды filmotekactl над каталогом
+type Command struct {
+	cat    Catalogue
+	in     io.Reader
+	out    io.Writer
+	format string
+}
+
+// New создает исполнителя команд. format - формат вывода (table, json или csv);
+// пустой format означает формат по умолчанию: json для export и table для остальных команд.
+func New(cat Catalogue, in io.Reader, out io.Writer, format string) *Command {
+	return &Command{cat: cat, in: in, out: out, format: format}
+}
+
+// Run выполняет команду args (без имени программы и глобальных флагов)
+func (c *Command) Run(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: command is required", ErrUsage)
+	}
+
+	// Формат проверяем до выполнения команды, чтобы не изменить каталог и не потерять результат
+	switch c.format {
+	case "", FormatTable, FormatJSON, FormatCSV:
+	default:
+		return fmt.Errorf("%w: unknown output format %q", ErrUsage, c.format)
+	}
+
+	cmd, args := args[0], args[1:]
+
+	switch cmd {
+	case "movie":
+		return c.movie(ctx, args)
+	case "actor":
+		return c.actor(ctx, args)
+	case "cast":
+		return c.cast(ctx, args)
+	case "import":
+		return c.importRecords(ctx, args)
+	case "export":
+		return c.export(ctx, args)
+	}
+
+	return fmt.Errorf("%w: unknown command %q", ErrUsage, cmd)
+}
+
+func (c *Command) outFormat(def string) string {
+	if c.format == "" {
+		return def
+	}
+
+	return c.format
+}
+
+func (c *Command) movie(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: movie add|update|rm|show|list", ErrUsage)
+	}
+
+	sub, args := args[0], args[1:]
+
+	switch sub {
+	case "add":
+		fs, f := newMovieFlags("movie add")
+		if _, err := parseWithArgs(fs, args, 0, "movie add [flags]"); err != nil {
+			return err
+		}
+
+		data, err := f.data(fs)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.cat.CreateMovie(ctx, data)
+		if err != nil {
+			return err
+		}
+
+		return c.writeMovie(res)
+	case "update":
+		fs, f := newMovieFlags("movie update")
+		ids, err := parseIDArgs(fs, args, 1, "movie update ID [flags]")
+		if err != nil {
+			return err
+		}
+
+		data, err := f.data(fs)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.cat.UpdateMovie(ctx, ids[0], data)
+		if err != nil {
+			return err
+		}
+
+		return c.writeMovie(res)
+	case "rm":
+		ids, err := parseIDArgs(newFlagSet("movie rm"), args, 1, "movie rm ID")
+		if err != nil {
+			return err
+		}
+
+		return c.cat.DeleteMovie(ctx, ids[0])
+	case "show":
+		ids, err := parseIDArgs(newFlagSet("movie show"), args, 1, "movie show ID")
+		if err != nil {
+			return err
+		}
+
+		res, err := c.cat.Movie(ctx, ids[0])
+		if err != nil {
+			return err
+		}
+
+		return c.writeMovie(res)
+	case "list":
+		if _, err := parseWithArgs(newFlagSet("movie list"), args, 0, "movie list"); err != nil {
+			return err
+		}
+
+		res, err := c.cat.Movies(ctx)
+		if err != nil {
+			return err
+		}
+
+		return writeMovies(c.out, c.outFormat(FormatTable), res)
+	}
+
+	return fmt.Errorf("%w: unknown command movie %s", ErrUsage, sub)
+}
+
+func (c *Command) actor(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: actor add|update|rm|show|list", ErrUsage)
+	}
+
+	sub, args := args[0], args[1:]
+
+	switch sub {
+	case "add":
+		fs, f := newActorFlags("actor add")
+		if _, err := parseWithArgs(fs, args, 0, "actor add [flags]"); err != nil {
+			return err
+		}
+
+		data, err := f.data(fs)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.cat.CreateActor(ctx, data)
+		if err != nil {
+			return err
+		}
+
+		return c.writeActor(res)
+	case "update":
+		fs, f := newActorFlags("actor update")
+		ids, err := parseIDArgs(fs, args, 1, "actor update ID [flags]")
+		if err != nil {
+			return err
+		}
+
+		data, err := f.data(fs)
+		if err != nil {
+			return err
+		}
+
+		res, err := c.cat.UpdateActor(ctx, ids[0], data)
+		if err != nil {
+			return err
+		}
+
+		return c.writeActor(res)
+	case "rm":
+		ids, err := parseIDArgs(newFlagSet("actor rm"), args, 1, "actor rm ID")
+		if err != nil {
+			return err
+		}
+
+		return c.cat.DeleteActor(ctx, ids[0])
+	case "show":
+		ids, err := parseIDArgs(newFlagSet("actor show"), args, 1, "actor show ID")
+		if err != nil {
+			return err
+		}
+
+		res, err := c.cat.Actor(ctx, ids[0])
+		if err != nil {
+			return err
+		}
+
+		return c.writeActor(res)
+	case "list":
+		if _, err := parseWithArgs(newFlagSet("actor list"), args, 0, "actor list"); err != nil {
+			return err
+		}
+
+		res, err := c.cat.Actors(ctx)
+		if err != nil {
+			return err
+		}
+
+		return writeActors(c.out, c.outFormat(FormatTable), res)
+	}
+
+	return fmt.Errorf("%w: unknown command actor %s", ErrUsage, sub)
+}
+
+func (c *Command) cast(ctx context.Context, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("%w: cast link|unlink MOVIE_ID ACTOR_ID", ErrUsage)
+	}
+
+	sub, args := args[0], args[1:]
+
+	if sub != "link" && sub != "unlink" {
+		return fmt.Errorf("%w: unknown command cast %s", ErrUsage, sub)
+	}
+
+	ids, err := parseIDArgs(newFlagSet("cast "+sub), args, 2, "cast "+sub+" MOVIE_ID ACTOR_ID")
+	if err != nil {
+		return err
+	}
+
+	if sub == "unlink" {
+		return c.cat.Unlink(ctx, ids[0], ids[1])
+	}
+
+	return c.cat.Link(ctx, ids[0], ids[1])
+}
+
+// writeMovie выводит один фильм: JSON - объектом, таблицу и CSV - одной строкой
+func (c *Command) writeMovie(m entity.Movie) error {
+	format := c.outFormat(FormatTable)
+	if format == FormatJSON {
+		return write(c.out, format, m, nil, nil)
+	}
+
+	return writeMovies(c.out, format, []entity.Movie{m})
+}
+
+// writeActor выводит одного актера: JSON - объектом, таблицу и CSV - одной строкой
+func (c *Command) writeActor(a entity.Actor) error {
+	format := c.outFormat(FormatTable)
+	if format == FormatJSON {
+		return write(c.out, format, a, nil, nil)
+	}
+
+	return writeActors(c.out, format, []entity.Actor{a})
+}
+
+// parseIDArgs разбирает флаги подкоманды и ровно n позиционных аргументов-id
+func parseIDArgs(fs *flag.FlagSet, args []string, n int, usage string) ([]int, error) {
+	positional, err := parseWithArgs(fs, args, n, usage)
+	if err != nil {
+		return nil, err
+	}
+
+	return parseIDs(positional)
+}
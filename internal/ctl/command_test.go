@@ -0,0 +1,232 @@
+package ctl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"filmoteka/config"
+	"filmoteka/internal/controller/api"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/repo/memory"
+	"filmoteka/pkg/client"
+	"filmoteka/pkg/logger"
+)
+
+const (
+	adminUser = "admin"
+	adminPass = "secret"
+)
+
+// newMemory создает каталог с прямым доступом к хранилищу в памяти, как NewDirect к БД
+func newMemory(s *memory.Storage) Catalogue {
+	return &direct{
+		a:  usecase.NewActors(memory.NewActorsRepo(s), nopLogger{}),
+		m:  usecase.NewMovies(memory.NewMoviesRepo(s), nopLogger{}),
+		am: usecase.NewActorsMovies(memory.NewActorsMoviesRepo(s), nopLogger{}),
+	}
+}
+
+// run выполняет команду и возвращает ее вывод
+func run(cat Catalogue, in, format string, args ...string) (string, error) {
+	var out bytes.Buffer
+	err := New(cat, strings.NewReader(in), &out, format).Run(context.Background(), args)
+
+	return out.String(), err
+}
+
+func mustRun(t *testing.T, cat Catalogue, format string, args ...string) string {
+	t.Helper()
+
+	out, err := run(cat, "", format, args...)
+	if err != nil {
+		t.Fatalf("%s: %s", strings.Join(args, " "), err)
+	}
+
+	return out
+}
+
+func TestExportImport(t *testing.T) {
+	src := newMemory(memory.New())
+
+	mustRun(t, src, "", "movie", "add", "-title", "Alien", "-release-date", "1979", "-rating", "8",
+		"-external", "imdb=tt0078748", "-external", "kinopoisk=386")
+	mustRun(t, src, "", "movie", "add", "-title", "Solaris, \"1972\"", "-description", "Line one\nline two",
+		"-release-date", "20.03.1972")
+	mustRun(t, src, "", "actor", "add", "-name", "Sigourney", "-surname", "Weaver", "-gender", "female",
+		"-birth-date", "1949-10", "-external", "imdb=nm0000244")
+	mustRun(t, src, "", "actor", "add", "-name", "Донатас", "-surname", "Банионис", "-gender", "male")
+
+	for _, format := range []string{FormatJSON, FormatCSV} {
+		t.Run(format, func(t *testing.T) {
+			dst := newMemory(memory.New())
+
+			for _, kind := range []string{"movies", "actors"} {
+				exported := mustRun(t, src, format, "export", kind)
+
+				out, err := run(dst, exported, "", "import", kind, "-format", format, "-")
+				if err != nil {
+					t.Fatalf("import %s: %s", kind, err)
+				}
+				if out != "imported 2 "+kind+"\n" {
+					t.Errorf("import %s output = %q", kind, out)
+				}
+
+				// Каталоги пустые, поэтому записи получают те же id
+				if got := mustRun(t, dst, format, "export", kind); got != exported {
+					t.Errorf("%s after round trip:\n%s\nwant:\n%s", kind, got, exported)
+				}
+			}
+		})
+	}
+
+	// Повторный импорт обновляет записи с внешними идентификаторами и добавляет остальные
+	dst := newMemory(memory.New())
+	file := filepath.Join(t.TempDir(), "movies.csv")
+	if err := os.WriteFile(file, []byte(mustRun(t, src, FormatCSV, "export", "movies")), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	mustRun(t, dst, "", "import", "movies", file)
+	mustRun(t, dst, "", "import", "movies", file)
+
+	movies, err := dst.Movies(context.Background())
+	if err != nil || len(movies) != 3 {
+		t.Fatalf("after repeated import: %d movies, %v, want 3", len(movies), err)
+	}
+}
+
+func TestUpdateChangesOnlyPassedFields(t *testing.T) {
+	cat := newMemory(memory.New())
+
+	mustRun(t, cat, "", "movie", "add", "-title", "Alien", "-rating", "7", "-external", "imdb=tt0078748")
+
+	// ID можно указывать как до флагов, так и после них
+	mustRun(t, cat, "", "movie", "update", "1", "-rating", "8")
+	mustRun(t, cat, "", "movie", "update", "-description", "In space", "1")
+
+	m, err := cat.Movie(context.Background(), 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if str(m.Title) != "Alien" || intString(m.Rating) != "8" || str(m.Description) != "In space" ||
+		m.ExternalIDs[entity.SourceIMDb] != "tt0078748" {
+		t.Errorf("movie after update = %+v", movieRow(m))
+	}
+}
+
+func TestUsage(t *testing.T) {
+	cat := newMemory(memory.New())
+	mustRun(t, cat, "", "movie", "add", "-title", "Alien")
+
+	tests := []struct {
+		format string
+		args   []string
+	}{
+		{"", nil},
+		{"", []string{"film", "list"}},
+		{"", []string{"movie"}},
+		{"", []string{"movie", "play"}},
+		{"", []string{"movie", "show"}},
+		{"", []string{"movie", "show", "abc"}},
+		{"", []string{"movie", "show", "0"}},
+		{"", []string{"movie", "rm", "1", "2"}},
+		{"", []string{"movie", "add", "-year", "1979"}},
+		{"", []string{"movie", "add", "-rating", "high"}},
+		{"", []string{"movie", "add", "-external", "imdb"}},
+		{"", []string{"movie", "list", "extra"}},
+		{"", []string{"cast", "link", "1"}},
+		{"", []string{"cast", "move", "1", "1"}},
+		{"", []string{"export"}},
+		{"", []string{"export", "films"}},
+		{"", []string{"import", "films", "-"}},
+		{"", []string{"import", "movies", "-format", "xml", "-"}},
+		{"xml", []string{"movie", "list"}},
+		// Неизвестный формат не должен изменить каталог
+		{"xml", []string{"movie", "rm", "1"}},
+	}
+
+	for _, tt := range tests {
+		if _, err := run(cat, "", tt.format, tt.args...); !errors.Is(err, ErrUsage) {
+			t.Errorf("%q with format %q: error = %v, want ErrUsage", tt.args, tt.format, err)
+		}
+	}
+
+	if _, err := cat.Movie(context.Background(), 1); err != nil {
+		t.Errorf("movie 1 should survive: %s", err)
+	}
+
+	// Неверное значение поля - ошибка данных, а не вызова
+	_, err := run(cat, "", "", "movie", "add", "-title", "Alien", "-release-date", "1979-13-01")
+	if err == nil || errors.Is(err, ErrUsage) {
+		t.Errorf("invalid date: error = %v, want a non-usage error", err)
+	}
+}
+
+// newServer запускает настоящий роутер API поверх хранилища в памяти
+func newServer(t *testing.T) string {
+	t.Helper()
+
+	s := memory.New()
+	l := logger.New("prod")
+
+	cfg := &config.Config{}
+	cfg.HTTPServer.User = adminUser
+	cfg.HTTPServer.Pass = adminPass
+
+	router := chi.NewRouter()
+	api.NewRouter(cfg, router, l,
+		usecase.NewActors(memory.NewActorsRepo(s), l),
+		usecase.NewMovies(memory.NewMoviesRepo(s), l),
+		usecase.NewActorsMovies(memory.NewActorsMoviesRepo(s), l),
+		nil, nil, nil, nil)
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	return srv.URL
+}
+
+func TestRemote(t *testing.T) {
+	url := newServer(t)
+
+	c, err := client.New(url, client.WithBasicAuth(adminUser, adminPass))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cat := NewRemote(c)
+
+	mustRun(t, cat, "", "movie", "add", "-title", "Alien", "-release-date", "1979", "-rating", "8")
+	mustRun(t, cat, "", "actor", "add", "-name", "Sigourney", "-surname", "Weaver", "-gender", "female")
+	mustRun(t, cat, "", "cast", "link", "1", "1")
+
+	if got, want := mustRun(t, cat, FormatCSV, "movie", "list"),
+		"id,title,description,release_date,rating,external_ids\n1,Alien,,1979,8,\n"; got != want {
+		t.Errorf("movie list:\n%s\nwant:\n%s", got, want)
+	}
+
+	cast, err := c.Movies.Cast(context.Background(), 1)
+	if err != nil || len(cast) != 1 || *cast[0].Id != 1 {
+		t.Errorf("cast of movie 1 = %+v, %v, want actor 1", cast, err)
+	}
+
+	// Без учетных данных сервер не дает изменять каталог
+	anon, err := client.New(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = run(NewRemote(anon), "", "", "movie", "rm", "1")
+	if !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("rm without credentials: error = %v, want ErrUnauthorized", err)
+	}
+}
@@ -0,0 +1,135 @@
+package ctl
+
+import (
+	"context"
+	"database/sql"
+
+	"golang.org/x/exp/slog"
+
+	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/repo"
+)
+
+// direct работает с БД через те же usecase, что и сервер
+type direct struct {
+	a  usecase.Actor
+	m  usecase.Movie
+	am usecase.ActorMovie
+}
+
+// NewDirect создает каталог поверх БД db
+func NewDirect(db *sql.DB) Catalogue {
+	// Логи usecase не должны смешиваться с выводом команд
+	l := nopLogger{}
+
+	return &direct{
+		a:  usecase.NewActors(repo.NewActorsRepo(db), l),
+		m:  usecase.NewMovies(repo.NewMoviesRepo(db), l),
+		am: usecase.NewActorsMovies(repo.NewActorsMoviesRepo(db), l),
+	}
+}
+
+func (d *direct) Movie(ctx context.Context, id int) (entity.Movie, error) {
+	return d.m.Find(ctx, id)
+}
+
+func (d *direct) Movies(ctx context.Context) ([]entity.Movie, error) {
+	return all(ctx, d.m.Next, func(m entity.Movie) int { return *m.Id })
+}
+
+func (d *direct) CreateMovie(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return d.m.Save(ctx, data)
+}
+
+func (d *direct) UpdateMovie(ctx context.Context, id int, data entity.MovieData) (entity.Movie, error) {
+	return d.m.Update(ctx, entity.Movie{Id: &id, MovieData: data})
+}
+
+func (d *direct) UpsertMovie(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return d.m.Upsert(ctx, data)
+}
+
+func (d *direct) DeleteMovie(ctx context.Context, id int) error {
+	_, err := d.m.Delete(ctx, id)
+
+	return err
+}
+
+func (d *direct) Actor(ctx context.Context, id int) (entity.Actor, error) {
+	return d.a.Find(ctx, id)
+}
+
+func (d *direct) Actors(ctx context.Context) ([]entity.Actor, error) {
+	return all(ctx, d.a.Next, func(a entity.Actor) int { return *a.Id })
+}
+
+func (d *direct) CreateActor(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return d.a.Save(ctx, data)
+}
+
+func (d *direct) UpdateActor(ctx context.Context, id int, data entity.ActorData) (entity.Actor, error) {
+	return d.a.Update(ctx, entity.Actor{Id: &id, ActorData: data})
+}
+
+func (d *direct) UpsertActor(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return d.a.Upsert(ctx, data)
+}
+
+func (d *direct) DeleteActor(ctx context.Context, id int) error {
+	_, err := d.a.Delete(ctx, id)
+
+	return err
+}
+
+func (d *direct) Link(ctx context.Context, movieID, actorID int) error {
+	return d.am.Save(ctx, entity.ActorMovie{Actor_id: &actorID, Movie_id: &movieID})
+}
+
+func (d *direct) Unlink(ctx context.Context, movieID, actorID int) error {
+	return d.am.Delete(ctx, entity.ActorMovie{Actor_id: &actorID, Movie_id: &movieID})
+}
+
+// all собирает все страницы, которые возвращает next, по возрастанию id
+func all[T any](ctx context.Context, next func(context.Context) ([]T, error), id func(T) int) ([]T, error) {
+	var res []T
+
+	after := 0
+	for {
+		page, err := next(context.WithValue(ctx, pagination.NextPersonID, after))
+		if err != nil {
+			return nil, err
+		}
+
+		if len(page) == 0 {
+			return res, nil
+		}
+
+		res = append(res, page...)
+		after = id(page[len(page)-1]) + 1
+	}
+}
+
+// nopLogger отбрасывает все записи
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+func (nopLogger) Info(string, ...any) {}
+
+func (nopLogger) Warn(string, ...any) {}
+
+func (nopLogger) Error(string, ...any) {}
+
+func (nopLogger) DebugContext(context.Context, string, ...any) {}
+
+func (nopLogger) InfoContext(context.Context, string, ...any) {}
+
+func (nopLogger) WarnContext(context.Context, string, ...any) {}
+
+func (nopLogger) ErrorContext(context.Context, string, ...any) {}
+
+func (nopLogger) Err(err error) slog.Attr {
+	return slog.String("error", err.Error())
+}
@@ -0,0 +1,184 @@
+package ctl
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"filmoteka/internal/entity"
+)
+
+// movieFlags - поля фильма, которые задаются флагами команд movie add и movie update
+type movieFlags struct {
+	title       string
+	description string
+	releaseDate string
+	rating      int
+	external    externalIDs
+}
+
+func newMovieFlags(name string) (*flag.FlagSet, *movieFlags) {
+	f := &movieFlags{external: externalIDs{}}
+
+	fs := newFlagSet(name)
+	fs.StringVar(&f.title, "title", "", "movie title")
+	fs.StringVar(&f.description, "description", "", "movie description")
+	fs.StringVar(&f.releaseDate, "release-date", "", "release date: 2006-01-02, 2006-01, 2006 or DD.MM.YYYY")
+	fs.IntVar(&f.rating, "rating", 0, "rating from 0 to 10")
+	fs.Var(f.external, "external", "id in external catalogue as source=id, may be repeated")
+
+	return fs, f
+}
+
+// data возвращает только поля, флаги которых переданы явно
+func (f *movieFlags) data(fs *flag.FlagSet) (entity.MovieData, error) {
+	var data entity.MovieData
+	var err error
+
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "title":
+			data.Title = &f.title
+		case "description":
+			data.Description = &f.description
+		case "release-date":
+			data.ReleaseDate, err = parseDate(f.releaseDate)
+		case "rating":
+			data.Rating = &f.rating
+		case "external":
+			data.ExternalIDs = entity.ExternalIDs(f.external)
+		}
+	})
+
+	return data, err
+}
+
+// actorFlags - поля актера, которые задаются флагами команд actor add и actor update
+type actorFlags struct {
+	name        string
+	surname     string
+	patronymic  string
+	gender      string
+	dateOfBirth string
+	external    externalIDs
+}
+
+func newActorFlags(name string) (*flag.FlagSet, *actorFlags) {
+	f := &actorFlags{external: externalIDs{}}
+
+	fs := newFlagSet(name)
+	fs.StringVar(&f.name, "name", "", "actor name")
+	fs.StringVar(&f.surname, "surname", "", "actor surname")
+	fs.StringVar(&f.patronymic, "patronymic", "", "actor patronymic")
+	fs.StringVar(&f.gender, "gender", "", "actor gender")
+	fs.StringVar(&f.dateOfBirth, "birth-date", "", "date of birth: 2006-01-02, 2006-01, 2006 or DD.MM.YYYY")
+	fs.Var(f.external, "external", "id in external catalogue as source=id, may be repeated")
+
+	return fs, f
+}
+
+// data возвращает только поля, флаги которых переданы явно
+func (f *actorFlags) data(fs *flag.FlagSet) (entity.ActorData, error) {
+	var data entity.ActorData
+	var err error
+
+	fs.Visit(func(fl *flag.Flag) {
+		switch fl.Name {
+		case "name":
+			data.Name = &f.name
+		case "surname":
+			data.Surname = &f.surname
+		case "patronymic":
+			data.Patronymic = &f.patronymic
+		case "gender":
+			data.Gender = &f.gender
+		case "birth-date":
+			data.DateOfBirth, err = parseDate(f.dateOfBirth)
+		case "external":
+			data.ExternalIDs = entity.ExternalIDs(f.external)
+		}
+	})
+
+	return data, err
+}
+
+// externalIDs - значение повторяемого флага -external source=id
+type externalIDs entity.ExternalIDs
+
+func (e externalIDs) String() string {
+	return formatExternalIDs(entity.ExternalIDs(e))
+}
+
+func (e externalIDs) Set(v string) error {
+	return setExternalID(entity.ExternalIDs(e), v)
+}
+
+// setExternalID добавляет в ids идентификатор, записанный как source=id
+func setExternalID(ids entity.ExternalIDs, pair string) error {
+	source, id, ok := strings.Cut(pair, "=")
+	if !ok || source == "" || id == "" {
+		return fmt.Errorf("external id %q should be in source=id format", pair)
+	}
+
+	ids[source] = id
+
+	return nil
+}
+
+func parseDate(s string) (*entity.Date, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	d, err := entity.ParseDate(s)
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", s, err)
+	}
+
+	return &d, nil
+}
+
+// newFlagSet создает набор флагов подкоманды. Ошибки разбора возвращаются вызывающему.
+func newFlagSet(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+
+	return fs
+}
+
+// parseWithArgs разбирает флаги подкоманды и возвращает ровно n позиционных аргументов.
+// Позиционные аргументы можно указывать как до флагов, так и после них.
+func parseWithArgs(fs *flag.FlagSet, args []string, n int, usage string) ([]string, error) {
+	var positional []string
+	for len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrUsage, fs.Name(), err)
+	}
+
+	positional = append(positional, fs.Args()...)
+	if len(positional) != n {
+		return nil, fmt.Errorf("%w: %s", ErrUsage, usage)
+	}
+
+	return positional, nil
+}
+
+// parseIDs разбирает позиционные аргументы как положительные id
+func parseIDs(args []string) ([]int, error) {
+	ids := make([]int, 0, len(args))
+	for _, arg := range args {
+		id, err := strconv.Atoi(arg)
+		if err != nil || id <= 0 {
+			return nil, fmt.Errorf("%w: id should be a positive integer, got %q", ErrUsage, arg)
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,158 @@
+package ctl
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"filmoteka/internal/entity"
+)
+
+// Форматы вывода
+const (
+	FormatTable = "table"
+	FormatJSON  = "json"
+	FormatCSV   = "csv"
+)
+
+// Колонки таблиц и CSV. Импорт CSV читает те же колонки.
+var (
+	movieColumns = []string{"id", "title", "description", "release_date", "rating", "external_ids"}
+	actorColumns = []string{"id", "name", "surname", "patronymic", "gender", "date_of_birth", "external_ids"}
+)
+
+// write выводит v в формате format. Для таблицы и CSV используются колонки columns и строки rows.
+func write(w io.Writer, format string, v any, columns []string, rows [][]string) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+
+		return enc.Encode(v)
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(columns); err != nil {
+			return err
+		}
+		if err := cw.WriteAll(rows); err != nil {
+			return err
+		}
+
+		return cw.Error()
+	case FormatTable:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(columns, "\t")))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+
+		return tw.Flush()
+	}
+
+	return fmt.Errorf("%w: unknown output format %q", ErrUsage, format)
+}
+
+func writeMovies(w io.Writer, format string, movies []entity.Movie) error {
+	rows := make([][]string, 0, len(movies))
+	for _, m := range movies {
+		rows = append(rows, movieRow(m))
+	}
+
+	if movies == nil {
+		movies = []entity.Movie{}
+	}
+
+	return write(w, format, movies, movieColumns, rows)
+}
+
+func writeActors(w io.Writer, format string, actors []entity.Actor) error {
+	rows := make([][]string, 0, len(actors))
+	for _, a := range actors {
+		rows = append(rows, actorRow(a))
+	}
+
+	if actors == nil {
+		actors = []entity.Actor{}
+	}
+
+	return write(w, format, actors, actorColumns, rows)
+}
+
+func movieRow(m entity.Movie) []string {
+	return []string{
+		intString(m.Id),
+		str(m.Title),
+		str(m.Description),
+		dateString(m.ReleaseDate),
+		intString(m.Rating),
+		formatExternalIDs(m.ExternalIDs),
+	}
+}
+
+func actorRow(a entity.Actor) []string {
+	return []string{
+		intString(a.Id),
+		str(a.Name),
+		str(a.Surname),
+		str(a.Patronymic),
+		str(a.Gender),
+		dateString(a.DateOfBirth),
+		formatExternalIDs(a.ExternalIDs),
+	}
+}
+
+func str(v *string) string {
+	if v == nil {
+		return ""
+	}
+
+	return *v
+}
+
+func intString(v *int) string {
+	if v == nil {
+		return ""
+	}
+
+	return strconv.Itoa(*v)
+}
+
+func dateString(v *entity.Date) string {
+	if v == nil {
+		return ""
+	}
+
+	return v.String()
+}
+
+// formatExternalIDs записывает внешние идентификаторы как source=id через точку с запятой
+func formatExternalIDs(ids entity.ExternalIDs) string {
+	pairs := make([]string, 0, len(ids))
+	for source, id := range ids {
+		pairs = append(pairs, source+"="+id)
+	}
+	sort.Strings(pairs)
+
+	return strings.Join(pairs, ";")
+}
+
+// parseExternalIDs разбирает строку, записанную formatExternalIDs
+func parseExternalIDs(s string) (entity.ExternalIDs, error) {
+	if s == "" {
+		return nil, nil
+	}
+
+	ids := entity.ExternalIDs{}
+	for _, pair := range strings.Split(s, ";") {
+		if err := setExternalID(ids, pair); err != nil {
+			return nil, err
+		}
+	}
+
+	return ids, nil
+}
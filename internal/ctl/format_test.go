@@ -0,0 +1,90 @@
+package ctl
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"filmoteka/internal/entity"
+)
+
+func testMovie() entity.Movie {
+	id, title, rating := 1, "Alien", 8
+	date := entity.Date{Year: 1979, Month: 5}
+
+	return entity.Movie{Id: &id, MovieData: entity.MovieData{
+		Title:       &title,
+		ReleaseDate: &date,
+		Rating:      &rating,
+		ExternalIDs: entity.ExternalIDs{entity.SourceKinopoisk: "386", entity.SourceIMDb: "tt0078748"},
+	}}
+}
+
+func TestWriteMovies(t *testing.T) {
+	tests := []struct {
+		format string
+		movies []entity.Movie
+		want   string
+	}{
+		{FormatTable, []entity.Movie{testMovie()}, "" +
+			"ID  TITLE  DESCRIPTION  RELEASE_DATE  RATING  EXTERNAL_IDS\n" +
+			"1   Alien               1979-05       8       imdb=tt0078748;kinopoisk=386\n"},
+		{FormatTable, nil, "ID  TITLE  DESCRIPTION  RELEASE_DATE  RATING  EXTERNAL_IDS\n"},
+		{FormatCSV, []entity.Movie{testMovie()}, "" +
+			"id,title,description,release_date,rating,external_ids\n" +
+			"1,Alien,,1979-05,8,imdb=tt0078748;kinopoisk=386\n"},
+		{FormatCSV, nil, "id,title,description,release_date,rating,external_ids\n"},
+		// Пустой список выводится массивом, а не null
+		{FormatJSON, nil, "[]\n"},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := writeMovies(&out, tt.format, tt.movies); err != nil {
+			t.Fatalf("%s: %s", tt.format, err)
+		}
+
+		if out.String() != tt.want {
+			t.Errorf("%s, %d movies:\n%s\nwant:\n%s", tt.format, len(tt.movies), out.String(), tt.want)
+		}
+	}
+}
+
+func TestWriteMoviesJSON(t *testing.T) {
+	want := []entity.Movie{testMovie()}
+
+	var out bytes.Buffer
+	if err := writeMovies(&out, FormatJSON, want); err != nil {
+		t.Fatal(err)
+	}
+
+	var got []entity.Movie
+	if err := json.Unmarshal(out.Bytes(), &got); err != nil {
+		t.Fatalf("output is not a JSON array of movies: %s\n%s", err, out.String())
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("decoded %+v, want %+v", got, want)
+	}
+}
+
+func TestExternalIDs(t *testing.T) {
+	ids := entity.ExternalIDs{entity.SourceTMDb: "348", entity.SourceIMDb: "tt0078748"}
+
+	s := formatExternalIDs(ids)
+	if s != "imdb=tt0078748;tmdb=348" {
+		t.Errorf("formatExternalIDs = %q, sources should be sorted", s)
+	}
+
+	got, err := parseExternalIDs(s)
+	if err != nil || !reflect.DeepEqual(got, ids) {
+		t.Errorf("parseExternalIDs(%q) = %v, %v, want %v", s, got, err, ids)
+	}
+
+	for _, bad := range []string{"imdb", "=tt1", "imdb=", "imdb=tt1;"} {
+		if _, err := parseExternalIDs(bad); err == nil {
+			t.Errorf("parseExternalIDs(%q) should fail", bad)
+		}
+	}
+}
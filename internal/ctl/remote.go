@@ -0,0 +1,74 @@
+package ctl
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+	"filmoteka/pkg/client"
+)
+
+// remote работает с запущенным сервером через HTTP API
+type remote struct {
+	c *client.Client
+}
+
+// NewRemote создает каталог поверх клиента API
+func NewRemote(c *client.Client) Catalogue {
+	return &remote{c: c}
+}
+
+func (r *remote) Movie(ctx context.Context, id int) (entity.Movie, error) {
+	return r.c.Movies.Get(ctx, id)
+}
+
+func (r *remote) Movies(ctx context.Context) ([]entity.Movie, error) {
+	return r.c.Movies.List(ctx, nil).All()
+}
+
+func (r *remote) CreateMovie(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return r.c.Movies.Create(ctx, data)
+}
+
+func (r *remote) UpdateMovie(ctx context.Context, id int, data entity.MovieData) (entity.Movie, error) {
+	return r.c.Movies.Update(ctx, id, data)
+}
+
+func (r *remote) UpsertMovie(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return r.c.Movies.Upsert(ctx, data)
+}
+
+func (r *remote) DeleteMovie(ctx context.Context, id int) error {
+	return r.c.Movies.Delete(ctx, id)
+}
+
+func (r *remote) Actor(ctx context.Context, id int) (entity.Actor, error) {
+	return r.c.Actors.Get(ctx, id)
+}
+
+func (r *remote) Actors(ctx context.Context) ([]entity.Actor, error) {
+	return r.c.Actors.List(ctx, nil).All()
+}
+
+func (r *remote) CreateActor(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return r.c.Actors.Create(ctx, data)
+}
+
+func (r *remote) UpdateActor(ctx context.Context, id int, data entity.ActorData) (entity.Actor, error) {
+	return r.c.Actors.Update(ctx, id, data)
+}
+
+func (r *remote) UpsertActor(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return r.c.Actors.Upsert(ctx, data)
+}
+
+func (r *remote) DeleteActor(ctx context.Context, id int) error {
+	return r.c.Actors.Delete(ctx, id)
+}
+
+func (r *remote) Link(ctx context.Context, movieID, actorID int) error {
+	return r.c.Movies.AddCast(ctx, movieID, actorID)
+}
+
+func (r *remote) Unlink(ctx context.Context, movieID, actorID int) error {
+	return r.c.Movies.RemoveCast(ctx, movieID, actorID)
+}
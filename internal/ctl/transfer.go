@@ -0,0 +1,237 @@
+package ctl
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"filmoteka/internal/entity"
+)
+
+// export выводит весь список фильмов или актеров, по умолчанию в JSON
+func (c *Command) export(ctx context.Context, args []string) error {
+	kind, err := parseWithArgs(newFlagSet("export"), args, 1, "export movies|actors")
+	if err != nil {
+		return err
+	}
+
+	switch kind[0] {
+	case "movies":
+		res, err := c.cat.Movies(ctx)
+		if err != nil {
+			return err
+		}
+
+		return writeMovies(c.out, c.outFormat(FormatJSON), res)
+	case "actors":
+		res, err := c.cat.Actors(ctx)
+		if err != nil {
+			return err
+		}
+
+		return writeActors(c.out, c.outFormat(FormatJSON), res)
+	}
+
+	return fmt.Errorf("%w: export movies|actors", ErrUsage)
+}
+
+// importRecords загружает фильмы или актеров из файла JSON или CSV в формате export.
+// Записи с внешними идентификаторами обновляют уже загруженные (upsert), остальные добавляются.
+// id в файле не учитывается: его назначает БД.
+func (c *Command) importRecords(ctx context.Context, args []string) error {
+	fs := newFlagSet("import")
+	format := fs.String("format", "", "input format: json or csv (by default from file extension, json for stdin)")
+
+	positional, err := parseWithArgs(fs, args, 2, "import movies|actors [-format json|csv] FILE|-")
+	if err != nil {
+		return err
+	}
+
+	kind, name := positional[0], positional[1]
+	if kind != "movies" && kind != "actors" {
+		return fmt.Errorf("%w: import movies|actors [-format json|csv] FILE|-", ErrUsage)
+	}
+
+	if *format == "" {
+		*format = FormatJSON
+		if strings.EqualFold(filepath.Ext(name), ".csv") {
+			*format = FormatCSV
+		}
+	}
+
+	if *format != FormatJSON && *format != FormatCSV {
+		return fmt.Errorf("%w: unknown input format %q", ErrUsage, *format)
+	}
+
+	in := c.in
+	if name != "-" {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		in = f
+	}
+
+	var count int
+	if kind == "movies" {
+		count, err = importMovies(ctx, c.cat, in, *format)
+	} else {
+		count, err = importActors(ctx, c.cat, in, *format)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	_, err = fmt.Fprintf(c.out, "imported %d %s\n", count, kind)
+
+	return err
+}
+
+func importMovies(ctx context.Context, cat Catalogue, in io.Reader, format string) (int, error) {
+	records, err := readRecords(in, format, func(m entity.Movie) entity.MovieData { return m.MovieData }, movieFromRow)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, data := range records {
+		if len(data.ExternalIDs) != 0 {
+			_, err = cat.UpsertMovie(ctx, data)
+		} else {
+			_, err = cat.CreateMovie(ctx, data)
+		}
+
+		if err != nil {
+			return i, fmt.Errorf("record %d: %w", i+1, err)
+		}
+	}
+
+	return len(records), nil
+}
+
+func importActors(ctx context.Context, cat Catalogue, in io.Reader, format string) (int, error) {
+	records, err := readRecords(in, format, func(a entity.Actor) entity.ActorData { return a.ActorData }, actorFromRow)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, data := range records {
+		if len(data.ExternalIDs) != 0 {
+			_, err = cat.UpsertActor(ctx, data)
+		} else {
+			_, err = cat.CreateActor(ctx, data)
+		}
+
+		if err != nil {
+			return i, fmt.Errorf("record %d: %w", i+1, err)
+		}
+	}
+
+	return len(records), nil
+}
+
+// readRecords читает массив JSON из записей E или CSV с заголовком и возвращает данные записей
+func readRecords[E, D any](in io.Reader, format string, data func(E) D, fromRow func(map[string]string) (D, error)) ([]D, error) {
+	var res []D
+
+	if format == FormatJSON {
+		var records []E
+		if err := json.NewDecoder(in).Decode(&records); err != nil {
+			return nil, fmt.Errorf("failed to decode JSON: %w", err)
+		}
+
+		for _, r := range records {
+			res = append(res, data(r))
+		}
+
+		return res, nil
+	}
+
+	cr := csv.NewReader(in)
+
+	header, err := cr.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	for line := 2; ; line++ {
+		rec, err := cr.Read()
+		if err == io.EOF {
+			return res, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		row := make(map[string]string, len(header))
+		for i, col := range header {
+			row[strings.TrimSpace(col)] = rec[i]
+		}
+
+		d, err := fromRow(row)
+		if err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+
+		res = append(res, d)
+	}
+}
+
+func movieFromRow(row map[string]string) (entity.MovieData, error) {
+	var data entity.MovieData
+	var err error
+
+	data.Title = optString(row["title"])
+	data.Description = optString(row["description"])
+
+	if data.ReleaseDate, err = parseDate(row["release_date"]); err != nil {
+		return data, err
+	}
+
+	if v := row["rating"]; v != "" {
+		rating, err := strconv.Atoi(v)
+		if err != nil {
+			return data, fmt.Errorf("rating %q should be an integer", v)
+		}
+		data.Rating = &rating
+	}
+
+	data.ExternalIDs, err = parseExternalIDs(row["external_ids"])
+
+	return data, err
+}
+
+func actorFromRow(row map[string]string) (entity.ActorData, error) {
+	var data entity.ActorData
+	var err error
+
+	data.Name = optString(row["name"])
+	data.Surname = optString(row["surname"])
+	data.Patronymic = optString(row["patronymic"])
+	data.Gender = optString(row["gender"])
+
+	if data.DateOfBirth, err = parseDate(row["date_of_birth"]); err != nil {
+		return data, err
+	}
+
+	data.ExternalIDs, err = parseExternalIDs(row["external_ids"])
+
+	return data, err
+}
+
+// optString возвращает nil для пустой ячейки CSV
+func optString(v string) *string {
+	if v == "" {
+		return nil
+	}
+
+	return &v
+}
@@ -0,0 +1,260 @@
+package entity
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DatePrecision - точность даты. Для старых фильмов часто известен только год выпуска.
+type DatePrecision string
+
+const (
+	PrecisionDay   DatePrecision = "day"
+	PrecisionMonth DatePrecision = "month"
+	PrecisionYear  DatePrecision = "year"
+)
+
+// DateFormat - формат, в котором даты отправляются клиенту
+type DateFormat int
+
+const (
+	// DateFormatISO - ISO 8601: 2006-01-02, 2006-01 или 2006
+	DateFormatISO DateFormat = iota
+	// DateFormatRU - прежний формат API: 02.01.2006, 01.2006 или 2006
+	DateFormatRU
+)
+
+// Date - календарная дата, возможно неполная.
+// Month и Day равны 0, если неизвестны. На входе принимаются форматы
+// ISO 8601 (2006-01-02, 2006-01, 2006, 2006-01-02T15:04:05Z07:00) и DD.MM.YYYY (а также MM.YYYY).
+type Date struct {
+	Year  int
+	Month int
+	Day   int
+
+	format DateFormat
+	// Значение, которое не удалось разобрать. Такая дата не проходит проверку данных.
+	invalid string
+}
+
+// NewDate создает дату с точностью до дня
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{Year: year, Month: int(month), Day: day}
+}
+
+// ParseDate разбирает дату в любом из поддерживаемых форматов
+func ParseDate(s string) (Date, error) {
+	s = strings.TrimSpace(s)
+
+	var parts []string
+	var d Date
+
+	switch {
+	case strings.Contains(s, "T"):
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			return Date{}, fmt.Errorf("invalid date %q", s)
+		}
+		return NewDate(t.Date()), nil
+	case strings.Contains(s, "."):
+		// DD.MM.YYYY или MM.YYYY
+		parts = strings.Split(s, ".")
+		for i, j := 0, len(parts)-1; i < j; i, j = i+1, j-1 {
+			parts[i], parts[j] = parts[j], parts[i]
+		}
+	default:
+		// YYYY-MM-DD, YYYY-MM или YYYY
+		parts = strings.Split(s, "-")
+	}
+
+	if len(parts) == 0 || len(parts) > 3 || len(parts[0]) != 4 {
+		return Date{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	fields := []*int{&d.Year, &d.Month, &d.Day}
+	for i, part := range parts {
+		if i > 0 && len(part) != 2 {
+			return Date{}, fmt.Errorf("invalid date %q", s)
+		}
+
+		// Нулевой месяц или день означает, что его нет, поэтому явно переданный ноль - ошибка
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 || (i > 0 && n == 0) {
+			return Date{}, fmt.Errorf("invalid date %q", s)
+		}
+
+		*fields[i] = n
+	}
+
+	if !d.valid() {
+		return Date{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	return d, nil
+}
+
+// valid проверяет, что дата существует в календаре
+func (d Date) valid() bool {
+	if d.Year < 1 || d.Year > 9999 {
+		return false
+	}
+
+	if d.Month == 0 {
+		return d.Day == 0
+	}
+
+	if d.Month > 12 {
+		return false
+	}
+
+	if d.Day == 0 {
+		return true
+	}
+
+	// time.Date нормализует несуществующие даты (31.02 -> 03.03)
+	t := time.Date(d.Year, time.Month(d.Month), d.Day, 0, 0, 0, 0, time.UTC)
+
+	return t.Day() == d.Day
+}
+
+// Valid сообщает, удалось ли разобрать дату, полученную от клиента
+func (d Date) Valid() bool {
+	return d.invalid == "" && d.valid()
+}
+
+// Precision возвращает точность даты
+func (d Date) Precision() DatePrecision {
+	switch {
+	case d.Month == 0:
+		return PrecisionYear
+	case d.Day == 0:
+		return PrecisionMonth
+	default:
+		return PrecisionDay
+	}
+}
+
+// Time возвращает первый день периода, обозначенного датой
+func (d Date) Time() time.Time {
+	month, day := d.Month, d.Day
+	if month == 0 {
+		month = 1
+	}
+	if day == 0 {
+		day = 1
+	}
+
+	return time.Date(d.Year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// Overlaps сообщает, может ли дата обозначать тот же день, что и other:
+// 1970 совпадает с 1970-05 и 1970-05-12, но не с 1971.
+func (d Date) Overlaps(other Date) bool {
+	if d.Year != other.Year {
+		return false
+	}
+
+	if d.Month != 0 && other.Month != 0 && d.Month != other.Month {
+		return false
+	}
+
+	return d.Day == 0 || other.Day == 0 || d.Day == other.Day
+}
+
+// Format возвращает дату в указанном формате с учетом точности
+func (d Date) Format(f DateFormat) string {
+	if d.invalid != "" {
+		return d.invalid
+	}
+
+	if f == DateFormatRU {
+		switch d.Precision() {
+		case PrecisionYear:
+			return fmt.Sprintf("%04d", d.Year)
+		case PrecisionMonth:
+			return fmt.Sprintf("%02d.%04d", d.Month, d.Year)
+		default:
+			return fmt.Sprintf("%02d.%02d.%04d", d.Day, d.Month, d.Year)
+		}
+	}
+
+	switch d.Precision() {
+	case PrecisionYear:
+		return fmt.Sprintf("%04d", d.Year)
+	case PrecisionMonth:
+		return fmt.Sprintf("%04d-%02d", d.Year, d.Month)
+	default:
+		return fmt.Sprintf("%04d-%02d-%02d", d.Year, d.Month, d.Day)
+	}
+}
+
+func (d Date) String() string {
+	return d.Format(DateFormatISO)
+}
+
+// SetFormat задает формат, в котором дата будет отправлена клиенту
+func (d *Date) SetFormat(f DateFormat) {
+	d.format = f
+}
+
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Format(d.format))
+}
+
+// UnmarshalJSON не возвращает ошибку для неверной даты, чтобы о ней сообщила
+// проверка данных вместе с ошибками остальных полей
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return fmt.Errorf("date should be a string: %w", err)
+	}
+
+	parsed, err := ParseDate(s)
+	if err != nil {
+		*d = Date{invalid: s}
+		return nil
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// Scan читает дату, выбранную из БД в формате ISO 8601 с учетом точности
+func (d *Date) Scan(src any) error {
+	switch v := src.(type) {
+	case time.Time:
+		*d = NewDate(v.Date())
+		return nil
+	case string:
+		return d.scanString(v)
+	case []byte:
+		return d.scanString(string(v))
+	}
+
+	return fmt.Errorf("can NOT scan %T into entity.Date", src)
+}
+
+func (d *Date) scanString(s string) error {
+	parsed, err := ParseDate(s)
+	if err != nil {
+		return err
+	}
+
+	*d = parsed
+
+	return nil
+}
+
+// Value возвращает первый день периода. Точность хранится в БД отдельно.
+func (d Date) Value() (driver.Value, error) {
+	if !d.Valid() {
+		return nil, fmt.Errorf("invalid date %q", d.Format(DateFormatISO))
+	}
+
+	return d.Time().Format("2006-01-02"), nil
+}
@@ -0,0 +1,202 @@
+package entity
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		in        string
+		want      Date
+		precision DatePrecision
+	}{
+		{"1979", Date{Year: 1979}, PrecisionYear},
+		{"1979-05", Date{Year: 1979, Month: 5}, PrecisionMonth},
+		{"1979-05-25", Date{Year: 1979, Month: 5, Day: 25}, PrecisionDay},
+		{" 1979-05-25 ", Date{Year: 1979, Month: 5, Day: 25}, PrecisionDay},
+		{"25.05.1979", Date{Year: 1979, Month: 5, Day: 25}, PrecisionDay},
+		{"05.1979", Date{Year: 1979, Month: 5}, PrecisionMonth},
+		{"1979-05-25T23:30:00+03:00", Date{Year: 1979, Month: 5, Day: 25}, PrecisionDay},
+		{"2000-02-29", Date{Year: 2000, Month: 2, Day: 29}, PrecisionDay},
+		{"0001", Date{Year: 1}, PrecisionYear},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDate(tt.in)
+		if err != nil {
+			t.Errorf("ParseDate(%q): %s", tt.in, err)
+			continue
+		}
+
+		if got != tt.want || got.Precision() != tt.precision || !got.Valid() {
+			t.Errorf("ParseDate(%q) = %+v (%s), want %+v (%s)", tt.in, got, got.Precision(), tt.want, tt.precision)
+		}
+	}
+}
+
+func TestParseDateInvalid(t *testing.T) {
+	for _, in := range []string{
+		"",
+		"79",
+		"19790",
+		"1979-5",
+		"1979-05-5",
+		"1979-13",
+		"1979-00-10",
+		"2020-00",
+		"2020-00-00",
+		"2020-01-00",
+		"00.2020",
+		"00.00.2020",
+		"1979-02-30",
+		"1900-02-29",
+		"1979-05-25-01",
+		"1979/05/25",
+		"year",
+		"1979-ab",
+		"0000",
+		"25.05.79",
+		"32.01.1979",
+		"1979-05-25T25:00:00Z",
+	} {
+		if d, err := ParseDate(in); err == nil {
+			t.Errorf("ParseDate(%q) = %+v, want error", in, d)
+		}
+	}
+}
+
+func TestDateFormat(t *testing.T) {
+	tests := []struct {
+		date Date
+		iso  string
+		ru   string
+	}{
+		{Date{Year: 1979}, "1979", "1979"},
+		{Date{Year: 1979, Month: 5}, "1979-05", "05.1979"},
+		{Date{Year: 1979, Month: 5, Day: 5}, "1979-05-05", "05.05.1979"},
+		{Date{Year: 812}, "0812", "0812"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.date.Format(DateFormatISO); got != tt.iso {
+			t.Errorf("%+v in ISO = %q, want %q", tt.date, got, tt.iso)
+		}
+		if got := tt.date.Format(DateFormatRU); got != tt.ru {
+			t.Errorf("%+v in RU = %q, want %q", tt.date, got, tt.ru)
+		}
+
+		// Отформатированная дата разбирается обратно в ту же дату
+		for _, s := range []string{tt.iso, tt.ru} {
+			if got, err := ParseDate(s); err != nil || got != tt.date {
+				t.Errorf("ParseDate(%q) = %+v, %v, want %+v", s, got, err, tt.date)
+			}
+		}
+	}
+}
+
+func TestDateJSON(t *testing.T) {
+	d := Date{Year: 1979, Month: 5}
+	d.SetFormat(DateFormatRU)
+
+	b, err := json.Marshal(d)
+	if err != nil || string(b) != `"05.1979"` {
+		t.Errorf("Marshal = %s, %v, want \"05.1979\"", b, err)
+	}
+
+	var got Date
+	if err = json.Unmarshal([]byte(`"1979-05"`), &got); err != nil || got != (Date{Year: 1979, Month: 5}) {
+		t.Errorf("Unmarshal = %+v, %v", got, err)
+	}
+
+	// Неверная дата не прерывает разбор запроса: о ней сообщает проверка данных
+	if err = json.Unmarshal([]byte(`"1979-13"`), &got); err != nil {
+		t.Fatalf("Unmarshal of invalid date: %s", err)
+	}
+	if got.Valid() || got.String() != "1979-13" {
+		t.Errorf("invalid date = %+v, valid %t", got, got.Valid())
+	}
+
+	if err = json.Unmarshal([]byte(`1979`), &got); err == nil {
+		t.Error("Unmarshal of a number should fail")
+	}
+}
+
+func TestOverlaps(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"1970", "1970", true},
+		{"1970", "1970-05", true},
+		{"1970", "1970-05-12", true},
+		{"1970-05", "1970-05-12", true},
+		{"1970-05-12", "1970-05-12", true},
+		{"1970", "1971", false},
+		{"1970-05", "1970-06", false},
+		{"1970-05", "1970-06-01", false},
+		{"1970-05-12", "1970-05-13", false},
+		{"1970-05-12", "1971", false},
+	}
+
+	for _, tt := range tests {
+		a, _ := ParseDate(tt.a)
+		b, _ := ParseDate(tt.b)
+
+		if got := a.Overlaps(b); got != tt.want {
+			t.Errorf("%s overlaps %s = %t, want %t", tt.a, tt.b, got, tt.want)
+		}
+		if got := b.Overlaps(a); got != tt.want {
+			t.Errorf("%s overlaps %s = %t, want %t", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+func TestDateScanValue(t *testing.T) {
+	tests := []struct {
+		date  Date
+		value string
+	}{
+		{Date{Year: 1979}, "1979-01-01"},
+		{Date{Year: 1979, Month: 5}, "1979-05-01"},
+		{Date{Year: 1979, Month: 5, Day: 25}, "1979-05-25"},
+	}
+
+	for _, tt := range tests {
+		v, err := tt.date.Value()
+		if err != nil || v != tt.value {
+			t.Errorf("%+v.Value() = %v, %v, want %s", tt.date, v, err, tt.value)
+			continue
+		}
+
+		// БД хранит первый день периода, точность - отдельно. Драйверы возвращают дату как time.Time,
+		// строку или байты, и все они читаются как тот же день.
+		day := Date{Year: tt.date.Year, Month: tt.date.Month, Day: tt.date.Day}
+		if day.Month == 0 {
+			day.Month = 1
+		}
+		if day.Day == 0 {
+			day.Day = 1
+		}
+
+		for _, src := range []any{tt.value, []byte(tt.value), time.Date(day.Year, time.Month(day.Month), day.Day, 0, 0, 0, 0, time.UTC)} {
+			var got Date
+			if err := got.Scan(src); err != nil || got != day {
+				t.Errorf("Scan(%T %v) = %+v, %v, want %+v", src, src, got, err, day)
+			}
+		}
+	}
+
+	var d Date
+	if err := d.Scan(int64(1979)); err == nil {
+		t.Error("Scan of an integer should fail")
+	}
+	if err := d.Scan("1979-13-01"); err == nil {
+		t.Error("Scan of an invalid date should fail")
+	}
+
+	if _, err := (Date{Year: 1979, Month: 13}).Value(); err == nil {
+		t.Error("Value of an invalid date should fail")
+	}
+}
@@ -6,11 +6,12 @@ type Actor struct {
 }
 
 type ActorData struct {
-	Name        *string `db:"name" json:"name,omitempty"`
-	Surname     *string `db:"surname" json:"surname,omitempty"`
-	Patronymic  *string `db:"patronymic" json:"patronymic,omitempty"`
-	Gender      *string `db:"gender" json:"gender,omitempty"`
-	DateOfBirth *string `db:"date_of_birth" json:"date_of_birth,omitempty"`
+	Name        *string     `db:"name" json:"name,omitempty"`
+	Surname     *string     `db:"surname" json:"surname,omitempty"`
+	Patronymic  *string     `db:"patronymic" json:"patronymic,omitempty"`
+	Gender      *string     `db:"gender" json:"gender,omitempty"`
+	DateOfBirth *Date       `db:"date_of_birth" json:"date_of_birth,omitempty"`
+	ExternalIDs ExternalIDs `db:"-" json:"external_ids,omitempty"`
 }
 
 type Movie struct {
@@ -19,10 +20,11 @@ type Movie struct {
 }
 
 type MovieData struct {
-	Title       *string `db:"title" json:"title,omitempty"`
-	Description *string `db:"description" json:"description,omitempty"`
-	ReleaseDate *string `db:"release_date" json:"release_date,omitempty"`
-	Rating      *int    `db:"rating" json:"rating,omitempty"`
+	Title       *string     `db:"title" json:"title,omitempty"`
+	Description *string     `db:"description" json:"description,omitempty"`
+	ReleaseDate *Date       `db:"release_date" json:"release_date,omitempty"`
+	Rating      *int        `db:"rating" json:"rating,omitempty"`
+	ExternalIDs ExternalIDs `db:"-" json:"external_ids,omitempty"`
 }
 
 type ActorMovie struct {
@@ -44,3 +46,44 @@ type MoviesOfActor struct {
 	ActorSurname string   `db:"actor_surname" json:"actor_surname,omitempty"`
 	Movies       []string `db:"movies" json:"movies,omitempty"`
 }
+
+// ActorProfile - актер вместе с id фильмов, в которых он снимался
+type ActorProfile struct {
+	Actor
+	MovieIDs []int `json:"movie_ids,omitempty"`
+}
+
+// DuplicateActors - пара актеров, которые, вероятно, являются одним человеком
+type DuplicateActors struct {
+	Actor        Actor   `json:"actor"`
+	Duplicate    Actor   `json:"duplicate"`
+	Score        float64 `json:"score"`
+	SharedMovies int     `json:"shared_movies"`
+}
+
+// ActorMerge - запрос на слияние актера source_id с актером target_id
+type ActorMerge struct {
+	SourceID *int `json:"source_id,omitempty"`
+	TargetID *int `json:"target_id,omitempty"`
+}
+
+// Внешние каталоги, идентификаторы которых хранятся в БД
+const (
+	SourceIMDb      = "imdb"
+	SourceTMDb      = "tmdb"
+	SourceKinopoisk = "kinopoisk"
+)
+
+// ExternalIDs - идентификаторы фильма или актера во внешних каталогах.
+// Ключ - название каталога (imdb, tmdb, kinopoisk), значение - идентификатор в нем.
+type ExternalIDs map[string]string
+
+// IsExternalSource сообщает, хранятся ли в БД идентификаторы указанного каталога
+func IsExternalSource(source string) bool {
+	switch source {
+	case SourceIMDb, SourceTMDb, SourceKinopoisk:
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,150 @@
+// Package health проверяет, готово ли приложение обслуживать запросы: доступны ли БД,
+// применены ли миграции и не останавливается ли приложение.
+//
+// Живость (liveness) означает только, что процесс отвечает. Готовность (readiness) проверяет
+// зависимости при каждом запросе. Запуск (startup) считается завершенным после первой успешной
+// проверки готовности.
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Состояния приложения и зависимостей
+const (
+	StatusOK           = "ok"
+	StatusFail         = "fail"
+	StatusShuttingDown = "shutting_down"
+	StatusStarting     = "starting"
+)
+
+// Check проверяет зависимость и возвращает ошибку, если она недоступна
+type Check func(ctx context.Context) error
+
+// Dependency - результат проверки зависимости
+type Dependency struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	// Latency - время проверки в миллисекундах
+	Latency float64 `json:"latency_ms"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// Report - результат проверки приложения. Dependencies заполняется, если запрошены подробности.
+type Report struct {
+	Status       string       `json:"status"`
+	Dependencies []Dependency `json:"dependencies,omitempty"`
+}
+
+// OK возвращает true, если приложение готово
+func (r Report) OK() bool {
+	return r.Status == StatusOK
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Health хранит проверки зависимостей и состояние приложения
+type Health struct {
+	timeout time.Duration
+	checks  []namedCheck
+
+	started      atomic.Bool
+	shuttingDown atomic.Bool
+}
+
+// New создает проверку готовности. timeout ограничивает время каждой проверки зависимости.
+func New(timeout time.Duration) *Health {
+	return &Health{timeout: timeout}
+}
+
+// Add добавляет проверку зависимости name. Проверки добавляются до запуска серверов.
+func (h *Health) Add(name string, check Check) {
+	h.checks = append(h.checks, namedCheck{name: name, check: check})
+}
+
+// Shutdown отмечает, что приложение останавливается: с этого момента оно не готово
+func (h *Health) Shutdown() {
+	h.shuttingDown.Store(true)
+}
+
+// Live проверяет живость процесса. Зависимости не проверяются: перезапуск процесса
+// не поможет, если недоступна БД.
+func (h *Health) Live() Report {
+	return Report{Status: StatusOK}
+}
+
+// Ready проверяет все зависимости одновременно
+func (h *Health) Ready(ctx context.Context) Report {
+	deps := make([]Dependency, len(h.checks))
+
+	var wg sync.WaitGroup
+	for i, c := range h.checks {
+		wg.Add(1)
+		go func(i int, c namedCheck) {
+			defer wg.Done()
+			deps[i] = h.run(ctx, c)
+		}(i, c)
+	}
+	wg.Wait()
+
+	res := Report{Status: StatusOK, Dependencies: deps}
+
+	for _, dep := range deps {
+		if dep.Status != StatusOK {
+			res.Status = StatusFail
+		}
+	}
+
+	// Остановка проверяется последней, чтобы не отчитаться о готовности, начавшейся во время проверки
+	if h.shuttingDown.Load() {
+		res.Status = StatusShuttingDown
+	}
+
+	if res.OK() {
+		h.started.Store(true)
+	}
+
+	return res
+}
+
+// Started проверяет, завершен ли запуск. До первой успешной проверки готовности
+// выполняет ее, после - не обращается к зависимостям.
+func (h *Health) Started(ctx context.Context) Report {
+	if h.started.Load() {
+		return Report{Status: StatusOK}
+	}
+
+	res := h.Ready(ctx)
+	if !res.OK() && res.Status != StatusShuttingDown {
+		res.Status = StatusStarting
+	}
+
+	return res
+}
+
+func (h *Health) run(ctx context.Context, c namedCheck) Dependency {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := c.check(ctx)
+
+	dep := Dependency{
+		Name:    c.name,
+		Status:  StatusOK,
+		Latency: float64(time.Since(start).Microseconds()) / 1000,
+	}
+
+	if err != nil {
+		dep.Status = StatusFail
+		dep.Error = err.Error()
+	}
+
+	return dep
+}
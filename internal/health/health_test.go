@@ -0,0 +1,86 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestReady(t *testing.T) {
+	h := New(50 * time.Millisecond)
+	h.Add("primary", func(context.Context) error { return nil })
+
+	if got := h.Ready(context.Background()); !got.OK() || len(got.Dependencies) != 1 {
+		t.Fatalf("Ready() = %+v, want ok with one dependency", got)
+	}
+
+	// Зависимость, которая не отвечает, отмечается ошибкой по истечении timeout
+	h.Add("replica1", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	start := time.Now()
+	got := h.Ready(context.Background())
+
+	if got.Status != StatusFail {
+		t.Fatalf("Ready() status = %s, want %s", got.Status, StatusFail)
+	}
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Ready() took %s, want about the timeout", elapsed)
+	}
+
+	replica := got.Dependencies[1]
+	if replica.Name != "replica1" || replica.Status != StatusFail || replica.Error == "" || replica.Latency < 50 {
+		t.Fatalf("replica1 = %+v, want failed after 50ms", replica)
+	}
+
+	if got.Dependencies[0].Status != StatusOK {
+		t.Fatalf("primary = %+v, want ok", got.Dependencies[0])
+	}
+}
+
+func TestShutdown(t *testing.T) {
+	h := New(time.Second)
+	h.Add("primary", func(context.Context) error { return nil })
+
+	h.Shutdown()
+
+	if got := h.Ready(context.Background()); got.Status != StatusShuttingDown {
+		t.Fatalf("Ready() status = %s, want %s", got.Status, StatusShuttingDown)
+	}
+
+	// Живость от остановки не зависит
+	if got := h.Live(); !got.OK() {
+		t.Fatalf("Live() = %+v, want ok", got)
+	}
+}
+
+func TestStarted(t *testing.T) {
+	h := New(time.Second)
+
+	var err error
+	calls := 0
+	h.Add("primary", func(context.Context) error {
+		calls++
+		return err
+	})
+
+	err = errors.New("connection refused")
+	if got := h.Started(context.Background()); got.Status != StatusStarting {
+		t.Fatalf("Started() status = %s, want %s", got.Status, StatusStarting)
+	}
+
+	err = nil
+	if got := h.Started(context.Background()); !got.OK() {
+		t.Fatalf("Started() = %+v, want ok", got)
+	}
+
+	// После запуска зависимости не проверяются, даже если они стали недоступны
+	err = errors.New("connection refused")
+	if got := h.Started(context.Background()); !got.OK() || calls != 2 {
+		t.Fatalf("Started() = %+v after %d checks, want ok after 2", got, calls)
+	}
+}
@@ -0,0 +1,169 @@
+package imdb
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"filmoteka/internal/entity"
+)
+
+// Ограничения на длину полей из migrations
+const (
+	maxTitleLen = 150
+	maxNameLen  = 50
+)
+
+// Movie - запись о фильме из title.basics.tsv
+type Movie struct {
+	ImdbID string
+	entity.MovieData
+}
+
+// Actor - запись об актере из name.basics.tsv
+type Actor struct {
+	ImdbID string
+	entity.ActorData
+}
+
+// CastLink - связь актера с фильмом из title.principals.tsv
+type CastLink struct {
+	TitleID string
+	NameID  string
+}
+
+// mapMovie преобразует строку title.basics в фильм.
+// Второй результат равен false, если строку нужно пропустить.
+func mapMovie(row Row, titleTypes map[string]bool) (Movie, bool) {
+	id, ok := row.Get("tconst")
+	if !ok {
+		return Movie{}, false
+	}
+
+	if titleType, _ := row.Get("titleType"); !titleTypes[titleType] {
+		return Movie{}, false
+	}
+
+	if isAdult, _ := row.Get("isAdult"); isAdult == "1" {
+		return Movie{}, false
+	}
+
+	title, ok := row.Get("primaryTitle")
+	if !ok {
+		return Movie{}, false
+	}
+	title = truncate(title, maxTitleLen)
+
+	res := Movie{ImdbID: id}
+	res.Title = &title
+
+	// В датасете известен только год выпуска
+	if year, ok := row.Get("startYear"); ok {
+		if date, err := entity.ParseDate(year); err == nil {
+			res.ReleaseDate = &date
+		}
+	}
+
+	return res, true
+}
+
+// mapActor преобразует строку name.basics в актера.
+// Персоны, не являющиеся актерами, пропускаются.
+func mapActor(row Row) (Actor, bool) {
+	id, ok := row.Get("nconst")
+	if !ok {
+		return Actor{}, false
+	}
+
+	gender, ok := genderOf(row)
+	if !ok {
+		return Actor{}, false
+	}
+
+	fullName, ok := row.Get("primaryName")
+	if !ok {
+		return Actor{}, false
+	}
+
+	name, surname := splitName(fullName)
+	if name == "" {
+		return Actor{}, false
+	}
+
+	res := Actor{ImdbID: id}
+	res.Name = &name
+	res.Surname = &surname
+	res.Gender = &gender
+
+	// В датасете известен только год рождения
+	if year, ok := row.Get("birthYear"); ok {
+		if date, err := entity.ParseDate(year); err == nil {
+			res.DateOfBirth = &date
+		}
+	}
+
+	return res, true
+}
+
+// mapCastLink преобразует строку title.principals в связь актера с фильмом.
+func mapCastLink(row Row) (CastLink, bool) {
+	category, _ := row.Get("category")
+	if category != "actor" && category != "actress" {
+		return CastLink{}, false
+	}
+
+	titleID, ok := row.Get("tconst")
+	if !ok {
+		return CastLink{}, false
+	}
+
+	nameID, ok := row.Get("nconst")
+	if !ok {
+		return CastLink{}, false
+	}
+
+	return CastLink{TitleID: titleID, NameID: nameID}, true
+}
+
+// genderOf определяет пол по первой актерской профессии персоны
+func genderOf(row Row) (string, bool) {
+	professions, ok := row.Get("primaryProfession")
+	if !ok {
+		return "", false
+	}
+
+	for _, p := range strings.Split(professions, ",") {
+		switch p {
+		case "actor":
+			return "male", true
+		case "actress":
+			return "female", true
+		}
+	}
+
+	return "", false
+}
+
+// splitName делит полное имя на имя и фамилию: фамилией считается последнее слово.
+// Для имени из одних пробелов возвращаются пустые строки.
+func splitName(fullName string) (string, string) {
+	words := strings.Fields(fullName)
+	switch len(words) {
+	case 0:
+		return "", ""
+	case 1:
+		return truncate(words[0], maxNameLen), ""
+	}
+
+	name := strings.Join(words[:len(words)-1], " ")
+	surname := words[len(words)-1]
+
+	return truncate(name, maxNameLen), truncate(surname, maxNameLen)
+}
+
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n])
+}
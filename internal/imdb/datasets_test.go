@@ -0,0 +1,217 @@
+package imdb
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"filmoteka/internal/entity"
+)
+
+// Файлы testdata повторяют формат датасетов IMDb: TSV в gzip, отсутствующие значения - \N
+
+// readAll читает файл датасета и возвращает строки, которые mapRow не пропустил
+func readAll[T any](t *testing.T, name string, mapRow func(Row) (T, bool)) []T {
+	t.Helper()
+
+	r, err := Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	defer r.Close()
+
+	var res []T
+	for {
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			return res
+		}
+		if err != nil {
+			t.Fatalf("Next: %s", err)
+		}
+
+		if v, ok := mapRow(row); ok {
+			res = append(res, v)
+		}
+	}
+}
+
+func str(s string) *string { return &s }
+
+func year(y int) *entity.Date { return &entity.Date{Year: y} }
+
+func toJSON(v any) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
+
+func TestMapMovie(t *testing.T) {
+	got := readAll(t, "title.basics.tsv.gz", func(row Row) (Movie, bool) {
+		return mapMovie(row, map[string]bool{"movie": true, "tvMovie": true})
+	})
+
+	long := strings.Repeat("A", maxTitleLen)
+
+	want := []Movie{
+		{ImdbID: "tt0118767", MovieData: entity.MovieData{Title: str("Брат"), ReleaseDate: year(1997)}},
+		// Год выпуска \N
+		{ImdbID: "tt0000004", MovieData: entity.MovieData{Title: str("No Year")}},
+		// Название обрезается до длины столбца
+		{ImdbID: "tt0000006", MovieData: entity.MovieData{Title: &long, ReleaseDate: year(2010)}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("movies = %s, want %s", toJSON(got), toJSON(want))
+	}
+}
+
+func TestMapActor(t *testing.T) {
+	got := readAll(t, "name.basics.tsv.gz", mapActor)
+
+	want := []Actor{
+		{ImdbID: "nm0000001", ActorData: entity.ActorData{
+			Name: str("Сергей"), Surname: str("Бодров"), Gender: str("male"), DateOfBirth: year(1971)}},
+		// Пол определяется по первой актерской профессии
+		{ImdbID: "nm0000002", ActorData: entity.ActorData{
+			Name: str("Fred"), Surname: str("Astaire"), Gender: str("male"), DateOfBirth: year(1899)}},
+		{ImdbID: "nm0000003", ActorData: entity.ActorData{
+			Name: str("Lauren"), Surname: str("Bacall"), Gender: str("female"), DateOfBirth: year(1924)}},
+		{ImdbID: "nm0000005", ActorData: entity.ActorData{
+			Name: str("Cher"), Surname: str(""), Gender: str("female")}},
+		// Фамилия - последнее слово имени
+		{ImdbID: "nm0000007", ActorData: entity.ActorData{
+			Name: str("Jean Claude Van"), Surname: str("Damme"), Gender: str("male"), DateOfBirth: year(1960)}},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("actors = %s, want %s", toJSON(got), toJSON(want))
+	}
+}
+
+func TestMapCastLink(t *testing.T) {
+	got := readAll(t, "title.principals.tsv.gz", mapCastLink)
+
+	want := []CastLink{
+		{TitleID: "tt0118767", NameID: "nm0000001"},
+		{TitleID: "tt0118767", NameID: "nm0000003"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("cast = %+v, want %+v", got, want)
+	}
+}
+
+func TestReader(t *testing.T) {
+	// Распакованный файл с CRLF, неполной строкой и без перевода строки в конце
+	path := filepath.Join(t.TempDir(), "name.basics.tsv")
+	data := "nconst\tprimaryName\tbirthYear\r\n" +
+		"nm1\tFred Astaire\t\\N\r\n" +
+		"nm2\tCher\r\n" +
+		"nm3\t\t1946"
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	defer r.Close()
+
+	type value struct {
+		val string
+		ok  bool
+	}
+
+	want := [][]value{
+		{{"nm1", true}, {"Fred Astaire", true}, {"", false}},
+		{{"nm2", true}, {"Cher", true}, {"", false}},
+		{{"nm3", true}, {"", false}, {"1946", true}},
+	}
+
+	for i, w := range want {
+		row, err := r.Next()
+		if err != nil {
+			t.Fatalf("row %d: %s", i+1, err)
+		}
+
+		for j, column := range []string{"nconst", "primaryName", "birthYear"} {
+			if val, ok := row.Get(column); val != w[j].val || ok != w[j].ok {
+				t.Errorf("row %d %s = %q, %t, want %q, %t", i+1, column, val, ok, w[j].val, w[j].ok)
+			}
+		}
+
+		if _, ok := row.Get("deathYear"); ok {
+			t.Errorf("row %d has unknown column", i+1)
+		}
+	}
+
+	if _, err := r.Next(); !errors.Is(err, io.EOF) {
+		t.Errorf("after last row: %v, want io.EOF", err)
+	}
+}
+
+func TestReaderSkip(t *testing.T) {
+	r, err := Open(filepath.Join("testdata", "title.principals.tsv.gz"))
+	if err != nil {
+		t.Fatalf("Open: %s", err)
+	}
+	defer r.Close()
+
+	// Возобновление импорта: первые строки уже загружены
+	if err = r.Skip(3); err != nil {
+		t.Fatalf("Skip: %s", err)
+	}
+
+	row, err := r.Next()
+	if err != nil {
+		t.Fatalf("Next: %s", err)
+	}
+
+	if ordering, _ := row.Get("ordering"); ordering != "4" {
+		t.Errorf("row after skip has ordering %q, want 4", ordering)
+	}
+
+	if err = r.Skip(10); !errors.Is(err, io.EOF) {
+		t.Errorf("Skip past the end: %v, want io.EOF", err)
+	}
+}
+
+func TestOpenNotGzip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "title.basics.tsv.gz")
+	if err := os.WriteFile(path, []byte("tconst\ttitleType\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	if r, err := Open(path); err == nil {
+		r.Close()
+		t.Error("Open of a plain file with .gz extension should fail")
+	}
+
+	if _, err := Open(filepath.Join("testdata", "missing.tsv.gz")); err == nil {
+		t.Error("Open of a missing file should fail")
+	}
+}
+
+func TestSplitName(t *testing.T) {
+	tests := []struct {
+		in, name, surname string
+	}{
+		{"Cher", "Cher", ""},
+		{"  Fred   Astaire ", "Fred", "Astaire"},
+		{"Jean Claude Van Damme", "Jean Claude Van", "Damme"},
+		{strings.Repeat("Я", maxNameLen+5) + " Ли", strings.Repeat("Я", maxNameLen), "Ли"},
+		{"   ", "", ""},
+	}
+
+	for _, tt := range tests {
+		if name, surname := splitName(tt.in); name != tt.name || surname != tt.surname {
+			t.Errorf("splitName(%q) = %q, %q, want %q, %q", tt.in, name, surname, tt.name, tt.surname)
+		}
+	}
+}
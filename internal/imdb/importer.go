@@ -0,0 +1,254 @@
+package imdb
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/lib/pq"
+	"golang.org/x/exp/slog"
+
+	"filmoteka/pkg/logger"
+)
+
+const op = "internal.imdb"
+
+type Config struct {
+	// Каталог с файлами title.basics.tsv.gz, name.basics.tsv.gz, title.principals.tsv.gz
+	Dir string
+	// Количество строк, вставляемых в БД одной транзакцией
+	BatchSize int
+	// Импортируемые типы title.basics (movie, tvMovie, short, ...)
+	TitleTypes []string
+	// Начать импорт заново, игнорируя сохраненный прогресс
+	Restart bool
+}
+
+// Importer загружает датасеты IMDb в БД.
+// Прогресс сохраняется после каждой вставленной пачки строк, поэтому прерванный
+// импорт продолжается с места остановки, а повторный запуск обновляет записи,
+// найденные по идентификатору IMDb, вместо создания дубликатов.
+type Importer struct {
+	db  *sql.DB
+	cfg Config
+	l   logger.Interface
+}
+
+func New(db *sql.DB, cfg Config, l logger.Interface) *Importer {
+	return &Importer{db: db, cfg: cfg, l: l}
+}
+
+// dataset описывает загрузку одного файла: строки файла копируются (COPY)
+// во временную таблицу, из которой затем переносятся в основные таблицы.
+type dataset struct {
+	name    string
+	stage   string
+	columns []string
+	mapRow  func(Row) ([]any, bool)
+	upsert  string
+}
+
+// Run импортирует фильмы, затем актеров, затем связи между ними.
+func (im *Importer) Run(ctx context.Context) error {
+	titleTypes := make(map[string]bool)
+	for _, t := range im.cfg.TitleTypes {
+		titleTypes[t] = true
+	}
+
+	datasets := []dataset{
+		{
+			name:    "title.basics",
+			stage:   stageTitles,
+			columns: []string{"imdb_id", "title", "release_date"},
+			mapRow: func(row Row) ([]any, bool) {
+				m, ok := mapMovie(row, titleTypes)
+				return []any{m.ImdbID, m.Title, m.ReleaseDate}, ok
+			},
+			upsert: upsertTitles,
+		},
+		{
+			name:    "name.basics",
+			stage:   stageNames,
+			columns: []string{"imdb_id", "name", "surname", "gender", "date_of_birth"},
+			mapRow: func(row Row) ([]any, bool) {
+				a, ok := mapActor(row)
+				return []any{a.ImdbID, a.Name, a.Surname, a.Gender, a.DateOfBirth}, ok
+			},
+			upsert: upsertNames,
+		},
+		{
+			name:    "title.principals",
+			stage:   stagePrincipals,
+			columns: []string{"title_id", "name_id"},
+			mapRow: func(row Row) ([]any, bool) {
+				c, ok := mapCastLink(row)
+				return []any{c.TitleID, c.NameID}, ok
+			},
+			upsert: upsertPrincipals,
+		},
+	}
+
+	for _, ds := range datasets {
+		if err := im.importDataset(ctx, ds); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (im *Importer) importDataset(ctx context.Context, ds dataset) error {
+	if im.cfg.Restart {
+		if _, err := im.db.ExecContext(ctx, QueryResetProgress, ds.name); err != nil {
+			return fmt.Errorf("%s: failed to reset progress of %s: %w", op, ds.name, err)
+		}
+	}
+
+	var done int64
+	var finished bool
+	err := im.db.QueryRowContext(ctx, QueryGetProgress, ds.name).Scan(&done, &finished)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s: failed to read progress of %s: %w", op, ds.name, err)
+	}
+
+	if finished {
+		im.l.Info("dataset is already imported", slog.String("dataset", ds.name))
+		return nil
+	}
+
+	path, err := im.findFile(ds.name)
+	if err != nil {
+		return err
+	}
+
+	r, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	// Продолжаем импорт с места остановки
+	if done > 0 {
+		im.l.Info("resuming import", slog.String("dataset", ds.name), slog.Int64("rows_done", done))
+
+		if err = r.Skip(done); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("%s: failed to skip imported rows of %s: %w", op, ds.name, err)
+		}
+	}
+
+	batch := make([][]any, 0, im.cfg.BatchSize)
+	var read int64
+
+	for {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+
+		row, err := r.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("%s: failed to read %s: %w", op, path, err)
+		}
+
+		read++
+
+		if values, ok := ds.mapRow(row); ok {
+			batch = append(batch, values)
+		}
+
+		if len(batch) < im.cfg.BatchSize {
+			continue
+		}
+
+		if err = im.flush(ctx, ds, batch, done+read, false); err != nil {
+			return err
+		}
+
+		done += read
+		read = 0
+		batch = batch[:0]
+	}
+
+	if err = im.flush(ctx, ds, batch, done+read, true); err != nil {
+		return err
+	}
+
+	im.l.Info("dataset imported", slog.String("dataset", ds.name), slog.Int64("rows", done+read))
+
+	return nil
+}
+
+// flush вставляет пачку строк и сохраняет прогресс в одной транзакции
+func (im *Importer) flush(ctx context.Context, ds dataset, batch [][]any, done int64, finished bool) error {
+	tx, err := im.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, ds.stage); err != nil {
+		return fmt.Errorf("%s: failed to create staging table for %s: %w", op, ds.name, err)
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(stageTable, ds.columns...))
+	if err != nil {
+		return fmt.Errorf("%s: failed to prepare COPY for %s: %w", op, ds.name, err)
+	}
+
+	for _, values := range batch {
+		if _, err = stmt.ExecContext(ctx, values...); err != nil {
+			stmt.Close()
+			return fmt.Errorf("%s: COPY of %s failed: %w", op, ds.name, err)
+		}
+	}
+
+	if _, err = stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return fmt.Errorf("%s: COPY of %s failed: %w", op, ds.name, err)
+	}
+
+	if err = stmt.Close(); err != nil {
+		return fmt.Errorf("%s: COPY of %s failed: %w", op, ds.name, err)
+	}
+
+	res, err := tx.ExecContext(ctx, ds.upsert)
+	if err != nil {
+		return fmt.Errorf("%s: failed to upsert %s: %w", op, ds.name, err)
+	}
+
+	if _, err = tx.ExecContext(ctx, QuerySaveProgress, ds.name, done, finished); err != nil {
+		return fmt.Errorf("%s: failed to save progress of %s: %w", op, ds.name, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit batch of %s: %w", op, ds.name, err)
+	}
+
+	affected, _ := res.RowsAffected()
+	im.l.Debug("batch imported",
+		slog.String("dataset", ds.name),
+		slog.Int("rows", len(batch)),
+		slog.Int64("affected", affected),
+		slog.Int64("rows_done", done),
+	)
+
+	return nil
+}
+
+// findFile ищет файл датасета: сначала сжатый, затем распакованный
+func (im *Importer) findFile(name string) (string, error) {
+	for _, ext := range []string{".tsv.gz", ".tsv"} {
+		path := filepath.Join(im.cfg.Dir, name+ext)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+
+	return "", fmt.Errorf("%s: dataset %s was NOT found in %s", op, name, im.cfg.Dir)
+}
@@ -0,0 +1,87 @@
+package imdb
+
+const QueryGetProgress = `SELECT rows_done, finished FROM imdb_import_progress WHERE dataset = $1`
+
+const QueryResetProgress = `DELETE FROM imdb_import_progress WHERE dataset = $1`
+
+const QuerySaveProgress = `INSERT INTO imdb_import_progress (dataset, rows_done, finished, updated_at)
+					VALUES ($1, $2, $3, NOW())
+					ON CONFLICT (dataset) DO UPDATE
+					SET rows_done = EXCLUDED.rows_done, finished = EXCLUDED.finished, updated_at = NOW()`
+
+// Временная таблица, в которую COPY загружает очередную пачку строк.
+// Удаляется автоматически при завершении транзакции.
+const stageTable = "imdb_stage"
+
+const stageTitles = `CREATE TEMP TABLE imdb_stage (
+					id INT, imdb_id TEXT, title TEXT, release_date DATE
+				) ON COMMIT DROP`
+
+const stageNames = `CREATE TEMP TABLE imdb_stage (
+					id INT, imdb_id TEXT, name TEXT, surname TEXT, gender TEXT, date_of_birth DATE
+				) ON COMMIT DROP`
+
+const stagePrincipals = `CREATE TEMP TABLE imdb_stage (
+					title_id TEXT, name_id TEXT
+				) ON COMMIT DROP`
+
+// Уже импортированные фильмы обновляются, новым заранее выделяются id из
+// последовательности, чтобы вместе с фильмом сохранить и его идентификатор IMDb.
+// В датасетах известен только год, поэтому даты сохраняются с точностью до года.
+const upsertTitles = `UPDATE movies
+					SET title = s.title, release_date = s.release_date, release_date_precision = 'year'
+					FROM imdb_stage s
+					JOIN movies_external_ids e ON e.source = 'imdb' AND e.external_id = s.imdb_id
+					WHERE movies.id = e.movie_id;
+
+					UPDATE imdb_stage s SET id = nextval('movies_id_seq')
+					WHERE NOT EXISTS (
+						SELECT 1 FROM movies_external_ids e
+						WHERE e.source = 'imdb' AND e.external_id = s.imdb_id
+					);
+
+					INSERT INTO movies (id, title, release_date, release_date_precision)
+					SELECT id, title, release_date, 'year' FROM imdb_stage
+					WHERE id IS NOT NULL;
+
+					INSERT INTO movies_external_ids (movie_id, source, external_id)
+					SELECT id, 'imdb', imdb_id FROM imdb_stage
+					WHERE id IS NOT NULL`
+
+// Актеры с одинаковыми именем и фамилией нарушают ограничение unique_name_surname,
+// поэтому в БД попадает только первый из однофамильцев.
+const upsertNames = `UPDATE actors
+					SET gender = s.gender::gender, date_of_birth = s.date_of_birth, date_of_birth_precision = 'year'
+					FROM imdb_stage s
+					JOIN actors_external_ids e ON e.source = 'imdb' AND e.external_id = s.imdb_id
+					WHERE actors.id = e.actor_id;
+
+					UPDATE imdb_stage s SET id = nextval('actors_id_seq')
+					FROM (
+						SELECT DISTINCT ON (name, surname) imdb_id FROM imdb_stage
+						ORDER BY name, surname, imdb_id
+					) f
+					WHERE s.imdb_id = f.imdb_id
+					AND NOT EXISTS (
+						SELECT 1 FROM actors_external_ids e
+						WHERE e.source = 'imdb' AND e.external_id = s.imdb_id
+					)
+					AND NOT EXISTS (
+						SELECT 1 FROM actors a
+						WHERE a.name = s.name AND a.surname = s.surname
+					);
+
+					INSERT INTO actors (id, name, surname, gender, date_of_birth, date_of_birth_precision)
+					SELECT id, name, surname, gender::gender, date_of_birth, 'year' FROM imdb_stage
+					WHERE id IS NOT NULL;
+
+					INSERT INTO actors_external_ids (actor_id, source, external_id)
+					SELECT id, 'imdb', imdb_id FROM imdb_stage
+					WHERE id IS NOT NULL`
+
+const upsertPrincipals = `INSERT INTO actors_movies (movie_id, actor_id)
+					SELECT DISTINCT m.movie_id, a.actor_id
+					FROM imdb_stage s
+					JOIN movies_external_ids m ON m.source = 'imdb' AND m.external_id = s.title_id
+					JOIN actors_external_ids a ON a.source = 'imdb' AND a.external_id = s.name_id
+					ON CONFLICT DO NOTHING`
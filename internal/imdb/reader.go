@@ -0,0 +1,124 @@
+package imdb
+
+import (
+	"bufio"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Значение, которым в датасетах IMDb обозначается отсутствие данных
+const nullValue = `\N`
+
+// Reader построчно читает TSV файл датасета IMDb (сжатый gzip или обычный),
+// не загружая его в память целиком.
+type Reader struct {
+	file   *os.File
+	gz     *gzip.Reader
+	buf    *bufio.Reader
+	header map[string]int
+}
+
+// Open открывает файл датасета и читает строку заголовка.
+func Open(path string) (*Reader, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to open %s: %w", op, path, err)
+	}
+
+	r := &Reader{file: f}
+
+	var src io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		r.gz, err = gzip.NewReader(f)
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("%s: %s is not a gzip file: %w", op, path, err)
+		}
+		src = r.gz
+	}
+
+	r.buf = bufio.NewReaderSize(src, 1<<20)
+
+	line, err := r.readLine()
+	if err != nil {
+		r.Close()
+		return nil, fmt.Errorf("%s: failed to read header of %s: %w", op, path, err)
+	}
+
+	r.header = make(map[string]int)
+	for i, name := range line {
+		r.header[name] = i
+	}
+
+	return r, nil
+}
+
+// Next возвращает очередную строку датасета или io.EOF, если строк больше нет.
+func (r *Reader) Next() (Row, error) {
+	fields, err := r.readLine()
+	if err != nil {
+		return Row{}, err
+	}
+
+	return Row{fields: fields, header: r.header}, nil
+}
+
+// Skip пропускает n строк данных. Используется при возобновлении импорта.
+func (r *Reader) Skip(n int64) error {
+	for i := int64(0); i < n; i++ {
+		if _, err := r.buf.ReadSlice('\n'); err != nil {
+			if errors.Is(err, bufio.ErrBufferFull) {
+				i--
+				continue
+			}
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Reader) Close() error {
+	if r.gz != nil {
+		r.gz.Close()
+	}
+
+	return r.file.Close()
+}
+
+func (r *Reader) readLine() ([]string, error) {
+	line, err := r.buf.ReadString('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && len(line) > 0) {
+		return nil, err
+	}
+
+	line = strings.TrimRight(line, "\r\n")
+
+	return strings.Split(line, "\t"), nil
+}
+
+// Row - строка датасета с доступом к полям по имени колонки.
+type Row struct {
+	fields []string
+	header map[string]int
+}
+
+// Get возвращает значение колонки. Второй результат равен false,
+// если колонки нет или значение отсутствует (\N).
+func (r Row) Get(column string) (string, bool) {
+	i, ok := r.header[column]
+	if !ok || i >= len(r.fields) {
+		return "", false
+	}
+
+	val := r.fields[i]
+	if val == nullValue || val == "" {
+		return "", false
+	}
+
+	return val, true
+}
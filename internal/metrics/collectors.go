@@ -0,0 +1,132 @@
+package metrics
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/cache"
+)
+
+// Cache добавляет счетчик попаданий в кеш ответов и промахов по методам
+func (m *Metrics) Cache(c *cache.Cache) {
+	m.registry.MustRegister(&cacheCollector{
+		c: c,
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "cache", "requests_total"),
+			"Response cache lookups by usecase method and result (hit or miss).",
+			[]string{"method", "result"}, nil,
+		),
+	})
+}
+
+// cacheCollector отдает статистику, которую кеш считает сам
+type cacheCollector struct {
+	c    *cache.Cache
+	desc *prometheus.Desc
+}
+
+func (cc *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- cc.desc
+}
+
+func (cc *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for method, counts := range cc.c.Stats().Methods {
+		ch <- prometheus.MustNewConstMetric(cc.desc, prometheus.CounterValue, float64(counts.Hits), method, "hit")
+		ch <- prometheus.MustNewConstMetric(cc.desc, prometheus.CounterValue, float64(counts.Misses), method, "miss")
+	}
+}
+
+// Counter - репозиторий, который возвращает число своих записей
+type Counter interface {
+	Count(ctx context.Context) (int, error)
+}
+
+const (
+	// Число записей каталога запрашивается у БД не чаще catalogueRefresh,
+	// чтобы частый сбор метрик не нагружал БД запросами COUNT(*)
+	catalogueRefresh = 30 * time.Second
+	catalogueTimeout = 5 * time.Second
+)
+
+// Catalogue добавляет число актеров, фильмов и связей актеров с фильмами
+func (m *Metrics) Catalogue(actors, movies, cast Counter) {
+	gauge := func(name, help string, counter Counter) catalogueGauge {
+		return catalogueGauge{
+			name:    name,
+			desc:    prometheus.NewDesc(prometheus.BuildFQName(namespace, "catalogue", name), help, nil, nil),
+			counter: counter,
+		}
+	}
+
+	m.registry.MustRegister(&catalogueCollector{
+		log: m.log,
+		now: time.Now,
+		gauges: []catalogueGauge{
+			gauge("actors", "Number of actors in the catalogue.", actors),
+			gauge("movies", "Number of movies in the catalogue.", movies),
+			gauge("cast_links", "Number of actor-movie links in the catalogue.", cast),
+		},
+	})
+}
+
+type catalogueGauge struct {
+	name    string
+	desc    *prometheus.Desc
+	counter Counter
+
+	value float64
+	// ok равен false, если последний запрос числа записей завершился ошибкой
+	ok bool
+}
+
+type catalogueCollector struct {
+	log usecase.Logger
+	now func() time.Time
+
+	mu        sync.Mutex
+	gauges    []catalogueGauge
+	refreshed time.Time
+}
+
+func (cc *catalogueCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, g := range cc.gauges {
+		ch <- g.desc
+	}
+}
+
+func (cc *catalogueCollector) Collect(ch chan<- prometheus.Metric) {
+	cc.mu.Lock()
+	defer cc.mu.Unlock()
+
+	if cc.now().Sub(cc.refreshed) >= catalogueRefresh {
+		cc.refresh()
+	}
+
+	for _, g := range cc.gauges {
+		if g.ok {
+			ch <- prometheus.MustNewConstMetric(g.desc, prometheus.GaugeValue, g.value)
+		}
+	}
+}
+
+func (cc *catalogueCollector) refresh() {
+	ctx, cancel := context.WithTimeout(context.Background(), catalogueTimeout)
+	defer cancel()
+
+	for i := range cc.gauges {
+		g := &cc.gauges[i]
+
+		count, err := g.counter.Count(ctx)
+		if err != nil {
+			cc.log.Warn("failed to count catalogue records", cc.log.Err(err), "records", g.name)
+		}
+
+		g.value, g.ok = float64(count), err == nil
+	}
+
+	cc.refreshed = cc.now()
+}
@@ -0,0 +1,74 @@
+// Package metrics собирает метрики Prometheus: запросы HTTP по шаблонам маршрутов chi, время работы
+// методов usecase, состояние пулов соединений с БД, попадания в кеш ответов и размер каталога.
+// Метрики отдаются служебным сервером, отдельным от API (см. Handler).
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"filmoteka/internal/usecase"
+)
+
+const namespace = "filmoteka"
+
+// Metrics - реестр метрик приложения
+type Metrics struct {
+	registry *prometheus.Registry
+	log      usecase.Logger
+
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	usecases *prometheus.HistogramVec
+}
+
+func New(l usecase.Logger) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		log:      l,
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "HTTP requests by route pattern, method and status.",
+		}, []string{"route", "method", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by route pattern, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+		usecases: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "usecase",
+			Name:      "duration_seconds",
+			Help:      "Usecase method latency by usecase, method and result (ok or error).",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"usecase", "method", "result"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.requests,
+		m.duration,
+		m.usecases,
+	)
+
+	return m
+}
+
+// Handler отдает метрики в текстовом формате Prometheus
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+// DB добавляет метрики sql.DBStats пула соединений db (go_sql_*) с меткой db_name
+func (m *Metrics) DB(name string, db *sql.DB) {
+	m.registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
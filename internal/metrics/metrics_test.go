@@ -0,0 +1,223 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/exp/slog"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/cache"
+	"filmoteka/internal/usecase/repo/memory"
+	"filmoteka/internal/usecase/repo/sqlite"
+)
+
+// scrape возвращает метрики в текстовом формате Prometheus
+func scrape(t *testing.T, m *Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d\n%s", rec.Code, rec.Body.String())
+	}
+
+	body, _ := io.ReadAll(rec.Body)
+
+	return string(body)
+}
+
+func assertMetrics(t *testing.T, got string, want ...string) {
+	t.Helper()
+
+	for _, line := range want {
+		if !strings.Contains(got, line+"\n") {
+			t.Errorf("metrics do NOT contain %s", line)
+		}
+	}
+}
+
+func TestHTTP(t *testing.T) {
+	m := New(nopLogger{})
+
+	r := chi.NewRouter()
+	r.Use(m.HTTP)
+	r.Route("/movie", func(r chi.Router) {
+		r.Get("/find_by_id/{id}", func(w http.ResponseWriter, r *http.Request) {
+			if chi.URLParam(r, "id") == "42" {
+				http.Error(w, "not found", http.StatusNotFound)
+				return
+			}
+			w.Write([]byte("{}"))
+		})
+	})
+
+	for _, target := range []string{"/movie/find_by_id/1", "/movie/find_by_id/2", "/movie/find_by_id/42", "/unknown/1", "/unknown/2"} {
+		r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, target, nil))
+	}
+
+	assertMetrics(t, scrape(t, m),
+		`filmoteka_http_requests_total{method="GET",route="/movie/find_by_id/{id}",status="200"} 2`,
+		`filmoteka_http_requests_total{method="GET",route="/movie/find_by_id/{id}",status="404"} 1`,
+		`filmoteka_http_requests_total{method="GET",route="unmatched",status="404"} 2`,
+		`filmoteka_http_request_duration_seconds_count{method="GET",route="/movie/find_by_id/{id}",status="200"} 2`,
+	)
+}
+
+func TestUsecase(t *testing.T) {
+	m := New(nopLogger{})
+	s := memory.New()
+	movies := NewMovies(usecase.NewMovies(memory.NewMoviesRepo(s), nopLogger{}), m)
+	ctx := context.Background()
+
+	title := "Брат"
+	if _, err := movies.Save(ctx, entity.MovieData{Title: &title}); err != nil {
+		t.Fatalf("failed to save movie: %s", err)
+	}
+
+	movies.Find(ctx, 1)
+	movies.Find(ctx, 42)
+
+	assertMetrics(t, scrape(t, m),
+		`filmoteka_usecase_duration_seconds_count{method="save",result="ok",usecase="movie"} 1`,
+		`filmoteka_usecase_duration_seconds_count{method="find",result="ok",usecase="movie"} 1`,
+		`filmoteka_usecase_duration_seconds_count{method="find",result="error",usecase="movie"} 1`,
+	)
+}
+
+func TestCatalogue(t *testing.T) {
+	m := New(nopLogger{})
+	s := memory.New()
+	actors, movies, cast := memory.NewActorsRepo(s), memory.NewMoviesRepo(s), memory.NewActorsMoviesRepo(s)
+	ctx := context.Background()
+
+	save := func() {
+		t.Helper()
+
+		title := "Брат"
+		if _, err := movies.Save(ctx, entity.MovieData{Title: &title}); err != nil {
+			t.Fatalf("failed to save movie: %s", err)
+		}
+	}
+
+	save()
+	m.Catalogue(actors, movies, cast)
+
+	assertMetrics(t, scrape(t, m),
+		`filmoteka_catalogue_actors 0`,
+		`filmoteka_catalogue_movies 1`,
+		`filmoteka_catalogue_cast_links 0`,
+	)
+
+	// Число записей обновляется не чаще раза в catalogueRefresh
+	save()
+	assertMetrics(t, scrape(t, m), `filmoteka_catalogue_movies 1`)
+}
+
+func TestCatalogueRefresh(t *testing.T) {
+	now := time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)
+	movies := &countingRepo{count: 1}
+
+	cc := &catalogueCollector{
+		log:    nopLogger{},
+		now:    func() time.Time { return now },
+		gauges: []catalogueGauge{{name: "movies", desc: prometheus.NewDesc("movies", "", nil, nil), counter: movies}},
+	}
+
+	collect := func() int {
+		ch := make(chan prometheus.Metric, 1)
+		cc.Collect(ch)
+		close(ch)
+
+		return len(ch)
+	}
+
+	collect()
+	collect()
+
+	if movies.calls != 1 {
+		t.Fatalf("catalogue was counted %d times, want 1", movies.calls)
+	}
+
+	now = now.Add(catalogueRefresh)
+	movies.err = context.DeadlineExceeded
+
+	if got := collect(); got != 0 {
+		t.Fatalf("collected %d metrics after failed count, want 0", got)
+	}
+
+	if movies.calls != 2 {
+		t.Fatalf("catalogue was counted %d times, want 2", movies.calls)
+	}
+}
+
+func TestCacheAndDB(t *testing.T) {
+	m := New(nopLogger{})
+
+	db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "filmoteka.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	defer db.Close()
+
+	m.DB("sqlite", db)
+
+	s := memory.New()
+	c := cache.New(cache.NewLRU(10, time.Minute), 0, nopLogger{})
+	movies := cache.NewMovies(usecase.NewMovies(memory.NewMoviesRepo(s), nopLogger{}), c)
+	m.Cache(c)
+
+	movies.List(context.Background())
+	movies.List(context.Background())
+
+	assertMetrics(t, scrape(t, m),
+		`filmoteka_cache_requests_total{method="movie.list",result="hit"} 1`,
+		`filmoteka_cache_requests_total{method="movie.list",result="miss"} 1`,
+		`go_sql_max_open_connections{db_name="sqlite"} 1`,
+	)
+}
+
+// countingRepo считает запросы числа записей
+type countingRepo struct {
+	count int
+	err   error
+	calls int
+}
+
+func (r *countingRepo) Count(context.Context) (int, error) {
+	r.calls++
+	return r.count, r.err
+}
+
+// nopLogger отбрасывает все записи
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+func (nopLogger) Info(string, ...any) {}
+
+func (nopLogger) Warn(string, ...any) {}
+
+func (nopLogger) Error(string, ...any) {}
+
+func (nopLogger) DebugContext(context.Context, string, ...any) {}
+
+func (nopLogger) InfoContext(context.Context, string, ...any) {}
+
+func (nopLogger) WarnContext(context.Context, string, ...any) {}
+
+func (nopLogger) ErrorContext(context.Context, string, ...any) {}
+
+func (nopLogger) Err(err error) slog.Attr {
+	return slog.String("error", err.Error())
+}
@@ -0,0 +1,168 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// observe выполняет метод usecase и записывает время его работы
+func observe[T any](m *Metrics, uc, method string, f func() (T, error)) (T, error) {
+	start := time.Now()
+	res, err := f()
+
+	result := "ok"
+	if err != nil {
+		result = "error"
+	}
+
+	m.usecases.WithLabelValues(uc, method, result).Observe(time.Since(start).Seconds())
+
+	return res, err
+}
+
+// Actors записывает время работы методов usecase.Actor
+type Actors struct {
+	uc usecase.Actor
+	m  *Metrics
+}
+
+var _ usecase.Actor = (*Actors)(nil)
+
+func NewActors(uc usecase.Actor, m *Metrics) *Actors {
+	return &Actors{uc: uc, m: m}
+}
+
+func (a *Actors) Save(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return observe(a.m, "actor", "save", func() (entity.Actor, error) { return a.uc.Save(ctx, data) })
+}
+
+func (a *Actors) Update(ctx context.Context, updates entity.Actor) (entity.Actor, error) {
+	return observe(a.m, "actor", "update", func() (entity.Actor, error) { return a.uc.Update(ctx, updates) })
+}
+
+func (a *Actors) Delete(ctx context.Context, id int) (entity.Actor, error) {
+	return observe(a.m, "actor", "delete", func() (entity.Actor, error) { return a.uc.Delete(ctx, id) })
+}
+
+func (a *Actors) Find(ctx context.Context, id int) (entity.Actor, error) {
+	return observe(a.m, "actor", "find", func() (entity.Actor, error) { return a.uc.Find(ctx, id) })
+}
+
+func (a *Actors) FindByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error) {
+	return observe(a.m, "actor", "find_by_external_id", func() (entity.Actor, error) {
+		return a.uc.FindByExternalID(ctx, source, externalID)
+	})
+}
+
+func (a *Actors) Upsert(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return observe(a.m, "actor", "upsert", func() (entity.Actor, error) { return a.uc.Upsert(ctx, data) })
+}
+
+func (a *Actors) List(ctx context.Context) ([]entity.Actor, error) {
+	return observe(a.m, "actor", "list", func() ([]entity.Actor, error) { return a.uc.List(ctx) })
+}
+
+func (a *Actors) Next(ctx context.Context) ([]entity.Actor, error) {
+	return observe(a.m, "actor", "next", func() ([]entity.Actor, error) { return a.uc.Next(ctx) })
+}
+
+func (a *Actors) Duplicates(ctx context.Context, minScore float64) ([]entity.DuplicateActors, error) {
+	return observe(a.m, "actor", "duplicates", func() ([]entity.DuplicateActors, error) {
+		return a.uc.Duplicates(ctx, minScore)
+	})
+}
+
+func (a *Actors) Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error) {
+	return observe(a.m, "actor", "merge", func() (entity.Actor, error) { return a.uc.Merge(ctx, sourceID, targetID) })
+}
+
+// Movies записывает время работы методов usecase.Movie
+type Movies struct {
+	uc usecase.Movie
+	m  *Metrics
+}
+
+var _ usecase.Movie = (*Movies)(nil)
+
+func NewMovies(uc usecase.Movie, m *Metrics) *Movies {
+	return &Movies{uc: uc, m: m}
+}
+
+func (mv *Movies) Save(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return observe(mv.m, "movie", "save", func() (entity.Movie, error) { return mv.uc.Save(ctx, data) })
+}
+
+func (mv *Movies) Update(ctx context.Context, updates entity.Movie) (entity.Movie, error) {
+	return observe(mv.m, "movie", "update", func() (entity.Movie, error) { return mv.uc.Update(ctx, updates) })
+}
+
+func (mv *Movies) Delete(ctx context.Context, id int) (entity.Movie, error) {
+	return observe(mv.m, "movie", "delete", func() (entity.Movie, error) { return mv.uc.Delete(ctx, id) })
+}
+
+func (mv *Movies) Find(ctx context.Context, id int) (entity.Movie, error) {
+	return observe(mv.m, "movie", "find", func() (entity.Movie, error) { return mv.uc.Find(ctx, id) })
+}
+
+func (mv *Movies) FindByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error) {
+	return observe(mv.m, "movie", "find_by_external_id", func() (entity.Movie, error) {
+		return mv.uc.FindByExternalID(ctx, source, externalID)
+	})
+}
+
+func (mv *Movies) Upsert(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return observe(mv.m, "movie", "upsert", func() (entity.Movie, error) { return mv.uc.Upsert(ctx, data) })
+}
+
+func (mv *Movies) FindMovie(ctx context.Context) ([]entity.Movie, error) {
+	return observe(mv.m, "movie", "search", func() ([]entity.Movie, error) { return mv.uc.FindMovie(ctx) })
+}
+
+func (mv *Movies) List(ctx context.Context) ([]entity.Movie, error) {
+	return observe(mv.m, "movie", "list", func() ([]entity.Movie, error) { return mv.uc.List(ctx) })
+}
+
+func (mv *Movies) Next(ctx context.Context) ([]entity.Movie, error) {
+	return observe(mv.m, "movie", "next", func() ([]entity.Movie, error) { return mv.uc.Next(ctx) })
+}
+
+// ActorsMovies записывает время работы методов usecase.ActorMovie
+type ActorsMovies struct {
+	uc usecase.ActorMovie
+	m  *Metrics
+}
+
+var _ usecase.ActorMovie = (*ActorsMovies)(nil)
+
+func NewActorsMovies(uc usecase.ActorMovie, m *Metrics) *ActorsMovies {
+	return &ActorsMovies{uc: uc, m: m}
+}
+
+func (am *ActorsMovies) Save(ctx context.Context, data entity.ActorMovie) error {
+	_, err := observe(am.m, "actor_movie", "save", func() (struct{}, error) { return struct{}{}, am.uc.Save(ctx, data) })
+	return err
+}
+
+func (am *ActorsMovies) Delete(ctx context.Context, data entity.ActorMovie) error {
+	_, err := observe(am.m, "actor_movie", "delete", func() (struct{}, error) { return struct{}{}, am.uc.Delete(ctx, data) })
+	return err
+}
+
+func (am *ActorsMovies) List(ctx context.Context) ([]entity.ActorMovieData, error) {
+	return observe(am.m, "actor_movie", "list", func() ([]entity.ActorMovieData, error) { return am.uc.List(ctx) })
+}
+
+func (am *ActorsMovies) Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+	return observe(am.m, "actor_movie", "cast", func() (map[int][]entity.Actor, error) {
+		return am.uc.Cast(ctx, movieIDs)
+	})
+}
+
+func (am *ActorsMovies) Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+	return observe(am.m, "actor_movie", "filmography", func() (map[int][]entity.Movie, error) {
+		return am.uc.Filmography(ctx, actorIDs)
+	})
+}
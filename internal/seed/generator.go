@@ -0,0 +1,206 @@
+package seed
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"filmoteka/internal/entity"
+)
+
+// Сколько раз генератор ищет незанятую пару имя и фамилия, прежде чем добавить к фамилии номер
+const maxNameAttempts = 50
+
+// Generator создает правдоподобные фильмы, актеров и связи между ними.
+// Одинаковый seed дает одинаковую последовательность записей.
+type Generator struct {
+	rnd *rand.Rand
+	// Хеши занятых пар имя и фамилия: в БД пара должна быть уникальной (unique_name_surname)
+	names map[uint64]struct{}
+}
+
+func NewGenerator(seed int64) *Generator {
+	return &Generator{
+		rnd:   rand.New(rand.NewSource(seed)),
+		names: make(map[uint64]struct{}),
+	}
+}
+
+// Actor возвращает актера с номером id. Половина актеров - с русскими именами, половина - с английскими.
+func (g *Generator) Actor(id int) entity.Actor {
+	var a entity.ActorData
+
+	if g.rnd.Intn(2) == 0 {
+		p := ruNames[g.rnd.Intn(len(ruNames))]
+		female := p.gender == "female"
+
+		surname := g.uniqueSurname(p.name, id, func() string {
+			s := ruSurnames[g.rnd.Intn(len(ruSurnames))]
+			if female {
+				return s[1]
+			}
+			return s[0]
+		})
+
+		a.Name, a.Surname, a.Gender = &p.name, &surname, &p.gender
+
+		// Отчество есть примерно у двух третей актеров с русскими именами
+		if g.rnd.Intn(3) != 0 {
+			father := ruNames[g.rnd.Intn(len(ruNames)/2)]
+			patronymic := father.patronymic[0]
+			if female {
+				patronymic = father.patronymic[1]
+			}
+			a.Patronymic = &patronymic
+		}
+	} else {
+		p := enNames[g.rnd.Intn(len(enNames))]
+
+		surname := g.uniqueSurname(p.name, id, func() string {
+			return enSurnames[g.rnd.Intn(len(enSurnames))]
+		})
+
+		a.Name, a.Surname, a.Gender = &p.name, &surname, &p.gender
+	}
+
+	birth := g.date(1930, 2005)
+	a.DateOfBirth = &birth
+
+	return entity.Actor{Id: &id, ActorData: a}
+}
+
+// uniqueSurname подбирает фамилию, которая вместе с именем name еще не встречалась:
+// сначала простую, затем двойную, и в крайнем случае - с номером актера
+func (g *Generator) uniqueSurname(name string, id int, surname func() string) string {
+	for attempt := 0; attempt < maxNameAttempts; attempt++ {
+		s := surname()
+		if attempt > 0 {
+			if second := surname(); second != s {
+				s += "-" + second
+			}
+		}
+
+		if g.take(name, s) {
+			return s
+		}
+	}
+
+	s := surname() + " " + strconv.Itoa(id)
+	g.take(name, s)
+
+	return s
+}
+
+func (g *Generator) take(name, surname string) bool {
+	h := fnv.New64a()
+	h.Write([]byte(name))
+	h.Write([]byte{0})
+	h.Write([]byte(surname))
+
+	key := h.Sum64()
+	if _, ok := g.names[key]; ok {
+		return false
+	}
+
+	g.names[key] = struct{}{}
+
+	return true
+}
+
+// Movie возвращает фильм с номером id
+func (g *Generator) Movie(id int) entity.Movie {
+	var m entity.MovieData
+	var title, description string
+
+	if g.rnd.Intn(2) == 0 {
+		title = ruTitleHeads[g.rnd.Intn(len(ruTitleHeads))] + " " + ruTitleTails[g.rnd.Intn(len(ruTitleTails))]
+		description = ruPlots[g.rnd.Intn(len(ruPlots))]
+	} else {
+		title = "The " + enTitleAdjectives[g.rnd.Intn(len(enTitleAdjectives))] + " " + enTitleNouns[g.rnd.Intn(len(enTitleNouns))]
+		description = enPlots[g.rnd.Intn(len(enPlots))]
+	}
+
+	// Каждый десятый фильм - продолжение
+	if g.rnd.Intn(10) == 0 {
+		title += " " + sequels[g.rnd.Intn(len(sequels))]
+	}
+
+	m.Title, m.Description = &title, &description
+
+	release := g.date(1950, 2024)
+	m.ReleaseDate = &release
+
+	// Рейтинг распределен нормально вокруг 6
+	rating := int(math.Round(g.rnd.NormFloat64()*1.8 + 6))
+	if rating < 0 {
+		rating = 0
+	}
+	if rating > 10 {
+		rating = 10
+	}
+	m.Rating = &rating
+
+	return entity.Movie{Id: &id, MovieData: m}
+}
+
+// Cast возвращает id актеров фильма: в среднем perMovie актеров из actors.
+// Актеры с меньшими id снимаются чаще, как популярные актеры в настоящем каталоге.
+func (g *Generator) Cast(actors, perMovie int) []int {
+	if actors == 0 || perMovie == 0 {
+		return nil
+	}
+
+	n := 1 + g.rnd.Intn(2*perMovie-1)
+	if n > actors {
+		n = actors
+	}
+
+	cast := make([]int, 0, n)
+	for len(cast) < n {
+		id := 1 + int(float64(actors)*math.Pow(g.rnd.Float64(), 2))
+		if !contains(cast, id) {
+			cast = append(cast, id)
+		}
+	}
+
+	return cast
+}
+
+// date возвращает случайную дату между началом года from и концом года to
+func (g *Generator) date(from, to int) entity.Date {
+	start := time.Date(from, time.January, 1, 0, 0, 0, 0, time.UTC)
+	days := int(time.Date(to+1, time.January, 1, 0, 0, 0, 0, time.UTC).Sub(start).Hours() / 24)
+
+	d := start.AddDate(0, 0, g.rnd.Intn(days))
+
+	return entity.NewDate(d.Year(), d.Month(), d.Day())
+}
+
+func contains(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+// actorRow и movieRow возвращают значения колонок для COPY
+func actorRow(a entity.Actor) []any {
+	return []any{*a.Id, *a.Name, *a.Surname, nullable(a.Patronymic), *a.Gender, a.DateOfBirth.String()}
+}
+
+func movieRow(m entity.Movie) []any {
+	return []any{*m.Id, *m.Title, *m.Description, m.ReleaseDate.String(), *m.Rating}
+}
+
+func nullable(v *string) any {
+	if v == nil {
+		return nil
+	}
+
+	return *v
+}
@@ -0,0 +1,119 @@
+package seed
+
+import (
+	"reflect"
+	"testing"
+)
+
+// dataset - данные в том порядке, в каком их загружает Seeder
+type dataset struct {
+	actors [][]any
+	movies [][]any
+	cast   [][]int
+}
+
+func generate(seed int64, actors, movies, perMovie int) dataset {
+	g := NewGenerator(seed)
+
+	var d dataset
+	for id := 1; id <= actors; id++ {
+		d.actors = append(d.actors, actorRow(g.Actor(id)))
+	}
+	for id := 1; id <= movies; id++ {
+		d.movies = append(d.movies, movieRow(g.Movie(id)))
+	}
+	for id := 1; id <= movies; id++ {
+		d.cast = append(d.cast, g.Cast(actors, perMovie))
+	}
+
+	return d
+}
+
+func TestSameSeedSameData(t *testing.T) {
+	first := generate(42, 500, 300, 5)
+	second := generate(42, 500, 300, 5)
+
+	if !reflect.DeepEqual(first, second) {
+		t.Fatal("the same seed produced different data")
+	}
+
+	if other := generate(43, 500, 300, 5); reflect.DeepEqual(first, other) {
+		t.Fatal("different seeds produced the same data")
+	}
+}
+
+func TestUniqueNames(t *testing.T) {
+	// Актеров больше, чем простых пар имя и фамилия: генератору приходится составлять двойные фамилии
+	const actors = 20000
+
+	g := NewGenerator(1)
+	seen := make(map[[2]string]int, actors)
+
+	for id := 1; id <= actors; id++ {
+		a := g.Actor(id)
+
+		if a.Name == nil || a.Surname == nil || a.Gender == nil || a.DateOfBirth == nil {
+			t.Fatalf("actor %d has empty required fields: %+v", id, a)
+		}
+
+		key := [2]string{*a.Name, *a.Surname}
+		if prev, ok := seen[key]; ok {
+			t.Fatalf("actors %d and %d are both %s %s", prev, id, key[0], key[1])
+		}
+		seen[key] = id
+	}
+}
+
+func TestCast(t *testing.T) {
+	tests := []struct {
+		name     string
+		actors   int
+		perMovie int
+	}{
+		{"many actors", 1000, 5},
+		{"fewer actors than cast", 3, 10},
+		{"single actor", 1, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewGenerator(7)
+
+			total := 0
+			for movie := 0; movie < 1000; movie++ {
+				cast := g.Cast(tt.actors, tt.perMovie)
+
+				if len(cast) == 0 || len(cast) > tt.actors || len(cast) > 2*tt.perMovie-1 {
+					t.Fatalf("cast size %d, want 1..min(%d, %d)", len(cast), tt.actors, 2*tt.perMovie-1)
+				}
+
+				seen := make(map[int]bool, len(cast))
+				for _, id := range cast {
+					if id < 1 || id > tt.actors {
+						t.Fatalf("actor id %d out of 1..%d", id, tt.actors)
+					}
+					if seen[id] {
+						t.Fatalf("actor %d appears twice in cast %v", id, cast)
+					}
+					seen[id] = true
+				}
+
+				total += len(cast)
+			}
+
+			// В среднем perMovie актеров, если их хватает
+			if tt.actors > 2*tt.perMovie {
+				if avg := float64(total) / 1000; avg < float64(tt.perMovie)-0.5 || avg > float64(tt.perMovie)+0.5 {
+					t.Errorf("average cast size %.2f, want about %d", avg, tt.perMovie)
+				}
+			}
+		})
+	}
+
+	if cast := NewGenerator(1).Cast(0, 5); cast != nil {
+		t.Errorf("cast without actors = %v, want nil", cast)
+	}
+	if cast := NewGenerator(1).Cast(10, 0); cast != nil {
+		t.Errorf("cast with perMovie 0 = %v, want nil", cast)
+	}
+}
@@ -0,0 +1,182 @@
+package seed
+
+// person - имя с полом; patronymic - отчество, образованное от имени (только для русских имен)
+type person struct {
+	name       string
+	gender     string
+	patronymic [2]string
+}
+
+// Русские имена. Отчество хранится в мужской и женской форме.
+var ruNames = []person{
+	{"Александр", "male", [2]string{"Александрович", "Александровна"}},
+	{"Алексей", "male", [2]string{"Алексеевич", "Алексеевна"}},
+	{"Андрей", "male", [2]string{"Андреевич", "Андреевна"}},
+	{"Антон", "male", [2]string{"Антонович", "Антоновна"}},
+	{"Борис", "male", [2]string{"Борисович", "Борисовна"}},
+	{"Вадим", "male", [2]string{"Вадимович", "Вадимовна"}},
+	{"Василий", "male", [2]string{"Васильевич", "Васильевна"}},
+	{"Виктор", "male", [2]string{"Викторович", "Викторовна"}},
+	{"Владимир", "male", [2]string{"Владимирович", "Владимировна"}},
+	{"Георгий", "male", [2]string{"Георгиевич", "Георгиевна"}},
+	{"Григорий", "male", [2]string{"Григорьевич", "Григорьевна"}},
+	{"Дмитрий", "male", [2]string{"Дмитриевич", "Дмитриевна"}},
+	{"Евгений", "male", [2]string{"Евгеньевич", "Евгеньевна"}},
+	{"Иван", "male", [2]string{"Иванович", "Ивановна"}},
+	{"Игорь", "male", [2]string{"Игоревич", "Игоревна"}},
+	{"Кирилл", "male", [2]string{"Кириллович", "Кирилловна"}},
+	{"Константин", "male", [2]string{"Константинович", "Константиновна"}},
+	{"Леонид", "male", [2]string{"Леонидович", "Леонидовна"}},
+	{"Максим", "male", [2]string{"Максимович", "Максимовна"}},
+	{"Михаил", "male", [2]string{"Михайлович", "Михайловна"}},
+	{"Никита", "male", [2]string{"Никитич", "Никитична"}},
+	{"Николай", "male", [2]string{"Николаевич", "Николаевна"}},
+	{"Олег", "male", [2]string{"Олегович", "Олеговна"}},
+	{"Павел", "male", [2]string{"Павлович", "Павловна"}},
+	{"Петр", "male", [2]string{"Петрович", "Петровна"}},
+	{"Роман", "male", [2]string{"Романович", "Романовна"}},
+	{"Сергей", "male", [2]string{"Сергеевич", "Сергеевна"}},
+	{"Степан", "male", [2]string{"Степанович", "Степановна"}},
+	{"Федор", "male", [2]string{"Федорович", "Федоровна"}},
+	{"Юрий", "male", [2]string{"Юрьевич", "Юрьевна"}},
+	{"Алина", "female", [2]string{}},
+	{"Алла", "female", [2]string{}},
+	{"Анастасия", "female", [2]string{}},
+	{"Анна", "female", [2]string{}},
+	{"Валентина", "female", [2]string{}},
+	{"Вера", "female", [2]string{}},
+	{"Виктория", "female", [2]string{}},
+	{"Галина", "female", [2]string{}},
+	{"Дарья", "female", [2]string{}},
+	{"Екатерина", "female", [2]string{}},
+	{"Елена", "female", [2]string{}},
+	{"Елизавета", "female", [2]string{}},
+	{"Ирина", "female", [2]string{}},
+	{"Ксения", "female", [2]string{}},
+	{"Лариса", "female", [2]string{}},
+	{"Любовь", "female", [2]string{}},
+	{"Людмила", "female", [2]string{}},
+	{"Марина", "female", [2]string{}},
+	{"Мария", "female", [2]string{}},
+	{"Надежда", "female", [2]string{}},
+	{"Наталья", "female", [2]string{}},
+	{"Нина", "female", [2]string{}},
+	{"Ольга", "female", [2]string{}},
+	{"Полина", "female", [2]string{}},
+	{"Светлана", "female", [2]string{}},
+	{"София", "female", [2]string{}},
+	{"Татьяна", "female", [2]string{}},
+	{"Ульяна", "female", [2]string{}},
+	{"Юлия", "female", [2]string{}},
+	{"Яна", "female", [2]string{}},
+}
+
+// Русские фамилии в мужской и женской форме
+var ruSurnames = [][2]string{
+	{"Иванов", "Иванова"}, {"Смирнов", "Смирнова"}, {"Кузнецов", "Кузнецова"}, {"Попов", "Попова"},
+	{"Васильев", "Васильева"}, {"Петров", "Петрова"}, {"Соколов", "Соколова"}, {"Михайлов", "Михайлова"},
+	{"Новиков", "Новикова"}, {"Федоров", "Федорова"}, {"Морозов", "Морозова"}, {"Волков", "Волкова"},
+	{"Алексеев", "Алексеева"}, {"Лебедев", "Лебедева"}, {"Семенов", "Семенова"}, {"Егоров", "Егорова"},
+	{"Павлов", "Павлова"}, {"Козлов", "Козлова"}, {"Степанов", "Степанова"}, {"Николаев", "Николаева"},
+	{"Орлов", "Орлова"}, {"Андреев", "Андреева"}, {"Макаров", "Макарова"}, {"Никитин", "Никитина"},
+	{"Захаров", "Захарова"}, {"Зайцев", "Зайцева"}, {"Соловьев", "Соловьева"}, {"Борисов", "Борисова"},
+	{"Яковлев", "Яковлева"}, {"Григорьев", "Григорьева"}, {"Романов", "Романова"}, {"Воробьев", "Воробьева"},
+	{"Сергеев", "Сергеева"}, {"Кузьмин", "Кузьмина"}, {"Фролов", "Фролова"}, {"Александров", "Александрова"},
+	{"Дмитриев", "Дмитриева"}, {"Королев", "Королева"}, {"Гусев", "Гусева"}, {"Киселев", "Киселева"},
+	{"Ильин", "Ильина"}, {"Максимов", "Максимова"}, {"Поляков", "Полякова"}, {"Сорокин", "Сорокина"},
+	{"Виноградов", "Виноградова"}, {"Ковалев", "Ковалева"}, {"Белов", "Белова"}, {"Медведев", "Медведева"},
+	{"Антонов", "Антонова"}, {"Тарасов", "Тарасова"}, {"Жуков", "Жукова"}, {"Баранов", "Баранова"},
+	{"Филиппов", "Филиппова"}, {"Комаров", "Комарова"}, {"Давыдов", "Давыдова"}, {"Беляев", "Беляева"},
+	{"Герасимов", "Герасимова"}, {"Богданов", "Богданова"}, {"Осипов", "Осипова"}, {"Сидоров", "Сидорова"},
+	{"Матвеев", "Матвеева"}, {"Титов", "Титова"}, {"Марков", "Маркова"}, {"Миронов", "Миронова"},
+	{"Крылов", "Крылова"}, {"Куликов", "Куликова"}, {"Карпов", "Карпова"}, {"Власов", "Власова"},
+	{"Мельников", "Мельникова"}, {"Денисов", "Денисова"}, {"Гаврилов", "Гаврилова"}, {"Тихонов", "Тихонова"},
+	{"Казаков", "Казакова"}, {"Афанасьев", "Афанасьева"}, {"Данилов", "Данилова"}, {"Савельев", "Савельева"},
+	{"Тимофеев", "Тимофеева"}, {"Фомин", "Фомина"}, {"Чернов", "Чернова"}, {"Абрамов", "Абрамова"},
+	{"Мартынов", "Мартынова"}, {"Ефимов", "Ефимова"}, {"Федотов", "Федотова"}, {"Щербаков", "Щербакова"},
+	{"Назаров", "Назарова"}, {"Калинин", "Калинина"}, {"Исаев", "Исаева"}, {"Чернышев", "Чернышева"},
+	{"Быков", "Быкова"}, {"Маслов", "Маслова"}, {"Родионов", "Родионова"}, {"Коновалов", "Коновалова"},
+	{"Лазарев", "Лазарева"}, {"Воронин", "Воронина"}, {"Климов", "Климова"}, {"Филатов", "Филатова"},
+	{"Пономарев", "Пономарева"}, {"Голубев", "Голубева"}, {"Кудрявцев", "Кудрявцева"}, {"Прохоров", "Прохорова"},
+	{"Высоцкий", "Высоцкая"}, {"Вишневский", "Вишневская"}, {"Островский", "Островская"}, {"Ковальский", "Ковальская"},
+}
+
+// Английские имена
+var enNames = []person{
+	{name: "James", gender: "male"}, {name: "John", gender: "male"}, {name: "Robert", gender: "male"},
+	{name: "Michael", gender: "male"}, {name: "William", gender: "male"}, {name: "David", gender: "male"},
+	{name: "Richard", gender: "male"}, {name: "Joseph", gender: "male"}, {name: "Thomas", gender: "male"},
+	{name: "Charles", gender: "male"}, {name: "Daniel", gender: "male"}, {name: "Matthew", gender: "male"},
+	{name: "Anthony", gender: "male"}, {name: "Mark", gender: "male"}, {name: "Steven", gender: "male"},
+	{name: "Paul", gender: "male"}, {name: "Andrew", gender: "male"}, {name: "Kevin", gender: "male"},
+	{name: "Brian", gender: "male"}, {name: "George", gender: "male"}, {name: "Edward", gender: "male"},
+	{name: "Ryan", gender: "male"}, {name: "Jacob", gender: "male"}, {name: "Gary", gender: "male"},
+	{name: "Nicholas", gender: "male"}, {name: "Eric", gender: "male"}, {name: "Jonathan", gender: "male"},
+	{name: "Samuel", gender: "male"}, {name: "Patrick", gender: "male"}, {name: "Henry", gender: "male"},
+	{name: "Mary", gender: "female"}, {name: "Patricia", gender: "female"}, {name: "Jennifer", gender: "female"},
+	{name: "Linda", gender: "female"}, {name: "Elizabeth", gender: "female"}, {name: "Barbara", gender: "female"},
+	{name: "Susan", gender: "female"}, {name: "Jessica", gender: "female"}, {name: "Sarah", gender: "female"},
+	{name: "Karen", gender: "female"}, {name: "Nancy", gender: "female"}, {name: "Lisa", gender: "female"},
+	{name: "Margaret", gender: "female"}, {name: "Sandra", gender: "female"}, {name: "Ashley", gender: "female"},
+	{name: "Emily", gender: "female"}, {name: "Donna", gender: "female"}, {name: "Michelle", gender: "female"},
+	{name: "Carol", gender: "female"}, {name: "Amanda", gender: "female"}, {name: "Melissa", gender: "female"},
+	{name: "Deborah", gender: "female"}, {name: "Stephanie", gender: "female"}, {name: "Rebecca", gender: "female"},
+	{name: "Laura", gender: "female"}, {name: "Helen", gender: "female"}, {name: "Emma", gender: "female"},
+	{name: "Olivia", gender: "female"}, {name: "Grace", gender: "female"}, {name: "Julia", gender: "female"},
+}
+
+// Английские фамилии
+var enSurnames = []string{
+	"Smith", "Johnson", "Williams", "Brown", "Jones", "Miller", "Davis", "Wilson", "Anderson", "Taylor",
+	"Thomas", "Moore", "Martin", "Jackson", "Thompson", "White", "Harris", "Clark", "Lewis", "Robinson",
+	"Walker", "Young", "Allen", "King", "Wright", "Scott", "Hill", "Green", "Adams", "Baker",
+	"Nelson", "Carter", "Mitchell", "Roberts", "Turner", "Phillips", "Campbell", "Parker", "Evans", "Edwards",
+	"Collins", "Stewart", "Morris", "Murphy", "Cook", "Rogers", "Morgan", "Cooper", "Peterson", "Reed",
+	"Bailey", "Bell", "Howard", "Ward", "Cox", "Richardson", "Wood", "Watson", "Brooks", "Bennett",
+	"Gray", "Hughes", "Price", "Sanders", "Myers", "Long", "Ross", "Foster", "Powell", "Jenkins",
+	"Perry", "Russell", "Sullivan", "Fisher", "Henderson", "Coleman", "Simmons", "Patterson", "Jordan", "Reynolds",
+	"Hamilton", "Graham", "Wallace", "Woods", "Cole", "West", "Owens", "Marshall", "Ellis", "Harrison",
+	"Gibson", "Mcdonald", "Kennedy", "Wells", "Stone", "Hawkins", "Dunn", "Hudson", "Spencer", "Fletcher",
+}
+
+// Части названий фильмов: "<тема> <дополнение>" для русских и "The <эпитет> <предмет>" для английских
+var (
+	ruTitleHeads = []string{
+		"Тайна", "Тень", "Легенда", "Песня", "Дорога", "Ночь", "Возвращение", "Последний день", "Сердце",
+		"Голос", "Хроники", "Письма", "Зима", "Лето", "Дети", "Огни", "Берег", "Время", "Память", "Побег",
+	}
+	ruTitleTails = []string{
+		"острова", "севера", "океана", "старого города", "королевы", "механика", "капитана", "маяка",
+		"пустыни", "ветра", "реки", "звезд", "тайги", "гор", "солдата", "художника", "профессора", "детства",
+		"большого дома", "долгой зимы",
+	}
+	enTitleAdjectives = []string{
+		"Silent", "Last", "Hidden", "Broken", "Golden", "Dark", "Lost", "Final", "Endless", "Crimson",
+		"Frozen", "Burning", "Secret", "Forgotten", "Distant", "Wild", "Quiet", "Iron", "Midnight", "Electric",
+	}
+	enTitleNouns = []string{
+		"River", "Kingdom", "Shadow", "Horizon", "Promise", "Garden", "Storm", "Empire", "Voyage", "Witness",
+		"Mirror", "Harbor", "Frontier", "Symphony", "Island", "Station", "Letter", "Crown", "Road", "Summer",
+	}
+	sequels = []string{"2", "3", "II", "III", "Reloaded", "Returns"}
+)
+
+// Части описаний фильмов
+var (
+	ruPlots = []string{
+		"Молодой следователь распутывает дело, которое двадцать лет считалось закрытым.",
+		"Семья переезжает в старый дом на окраине, и вскоре начинают происходить странные вещи.",
+		"Экипаж исследовательского судна теряет связь с берегом посреди шторма.",
+		"Двое друзей детства встречаются спустя годы и решают исполнить давнюю мечту.",
+		"Учительница из маленького городка получает письмо, которое меняет ее жизнь.",
+		"Гениальный изобретатель пытается спасти свое дело от разорения.",
+	}
+	enPlots = []string{
+		"A retired detective is pulled back for one last case that hits close to home.",
+		"Two strangers stranded in a small town uncover a secret that binds them together.",
+		"A young musician travels across the country to find the father she never knew.",
+		"When a storm cuts off an island, its residents must face the truth about the past.",
+		"An ambitious scientist races against time to prove a discovery no one believes.",
+		"A family reunion turns into a fight over an inheritance nobody expected.",
+	}
+)
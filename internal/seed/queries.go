@@ -0,0 +1,10 @@
+package seed
+
+const QueryNotEmpty = `SELECT EXISTS (SELECT 1 FROM actors) OR EXISTS (SELECT 1 FROM movies)`
+
+const QueryTruncate = `TRUNCATE actors_movies, actors_external_ids, movies_external_ids, actor_redirects, actors, movies
+					RESTART IDENTITY`
+
+// id вставлены явно, поэтому последовательности нужно передвинуть за последний id
+const QueryResetSequences = `SELECT setval('actors_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM actors;
+					SELECT setval('movies_id_seq', COALESCE(MAX(id), 0) + 1, false) FROM movies`
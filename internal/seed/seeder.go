@@ -0,0 +1,173 @@
+// Package seed наполняет БД сгенерированными фильмами, актерами и связями между ними
+// для разработки и нагрузочного тестирования.
+package seed
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+	"golang.org/x/exp/slog"
+
+	"filmoteka/pkg/logger"
+)
+
+const op = "internal.seed"
+
+// Через сколько записей сообщать о прогрессе
+const progressEvery = 100000
+
+var ErrNotEmpty = errors.New("database already contains actors or movies")
+
+type Config struct {
+	// Начальное значение генератора: одинаковый seed дает одинаковые данные
+	Seed int64
+	// Количество актеров и фильмов
+	Actors int
+	Movies int
+	// Среднее количество актеров в фильме
+	CastPerMovie int
+	// Удалить все фильмы и актеров перед наполнением
+	Truncate bool
+}
+
+// Seeder загружает сгенерированные данные через COPY в одной транзакции.
+// Записи получают id по порядку, начиная с 1, поэтому с одним seed у одной и той же
+// записи всегда один и тот же id - это удобно для воспроизведения ошибок.
+type Seeder struct {
+	db  *sql.DB
+	cfg Config
+	l   logger.Interface
+}
+
+func New(db *sql.DB, cfg Config, l logger.Interface) *Seeder {
+	return &Seeder{db: db, cfg: cfg, l: l}
+}
+
+// table описывает загрузку одной таблицы: rows вызывает send для каждой строки
+type table struct {
+	name    string
+	columns []string
+	rows    func(send func(values ...any) error) error
+}
+
+func (s *Seeder) Run(ctx context.Context) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	if s.cfg.Truncate {
+		if _, err = tx.ExecContext(ctx, QueryTruncate); err != nil {
+			return fmt.Errorf("%s: failed to truncate tables: %w", op, err)
+		}
+	} else {
+		var exists bool
+		if err = tx.QueryRowContext(ctx, QueryNotEmpty).Scan(&exists); err != nil {
+			return fmt.Errorf("%s: failed to check tables: %w", op, err)
+		}
+		if exists {
+			return fmt.Errorf("%s: %w", op, ErrNotEmpty)
+		}
+	}
+
+	g := NewGenerator(s.cfg.Seed)
+
+	tables := []table{
+		{
+			name:    "actors",
+			columns: []string{"id", "name", "surname", "patronymic", "gender", "date_of_birth"},
+			rows: func(send func(values ...any) error) error {
+				for id := 1; id <= s.cfg.Actors; id++ {
+					if err := send(actorRow(g.Actor(id))...); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name:    "movies",
+			columns: []string{"id", "title", "description", "release_date", "rating"},
+			rows: func(send func(values ...any) error) error {
+				for id := 1; id <= s.cfg.Movies; id++ {
+					if err := send(movieRow(g.Movie(id))...); err != nil {
+						return err
+					}
+				}
+				return nil
+			},
+		},
+		{
+			name:    "actors_movies",
+			columns: []string{"movie_id", "actor_id"},
+			rows: func(send func(values ...any) error) error {
+				for id := 1; id <= s.cfg.Movies; id++ {
+					for _, actorID := range g.Cast(s.cfg.Actors, s.cfg.CastPerMovie) {
+						if err := send(id, actorID); err != nil {
+							return err
+						}
+					}
+				}
+				return nil
+			},
+		},
+	}
+
+	for _, t := range tables {
+		if err = s.copy(ctx, tx, t); err != nil {
+			return err
+		}
+	}
+
+	if _, err = tx.ExecContext(ctx, QueryResetSequences); err != nil {
+		return fmt.Errorf("%s: failed to reset id sequences: %w", op, err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("%s: failed to commit: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Seeder) copy(ctx context.Context, tx *sql.Tx, t table) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn(t.name, t.columns...))
+	if err != nil {
+		return fmt.Errorf("%s: failed to prepare COPY for %s: %w", op, t.name, err)
+	}
+
+	var done int64
+	err = t.rows(func(values ...any) error {
+		if _, err := stmt.ExecContext(ctx, values...); err != nil {
+			return err
+		}
+
+		done++
+		if done%progressEvery == 0 {
+			s.l.Info("seeding", slog.String("table", t.name), slog.Int64("rows_done", done))
+		}
+
+		return nil
+	})
+
+	if err == nil {
+		_, err = stmt.ExecContext(ctx)
+	}
+
+	if err != nil {
+		stmt.Close()
+		return fmt.Errorf("%s: COPY of %s failed: %w", op, t.name, err)
+	}
+
+	if err = stmt.Close(); err != nil {
+		return fmt.Errorf("%s: COPY of %s failed: %w", op, t.name, err)
+	}
+
+	s.l.Info("table seeded", slog.String("table", t.name), slog.Int64("rows", done))
+
+	return nil
+}
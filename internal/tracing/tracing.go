@@ -0,0 +1,104 @@
+// Package tracing настраивает трассировку OpenTelemetry: спаны запросов HTTP по шаблонам маршрутов chi
+// и методов usecase. Спаны запросов к БД создает драйвер pkg/tracesql внутри этих трасс.
+//
+// Контекст трассы клиента принимается из заголовков W3C traceparent и tracestate.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"filmoteka/config"
+)
+
+const (
+	op          = "internal.tracing"
+	serviceName = "filmoteka"
+)
+
+// Tracing создает спаны приложения
+type Tracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TextMapPropagator
+
+	// shutdown отправляет накопленные спаны и освобождает экспортер
+	shutdown func(ctx context.Context) error
+}
+
+// New настраивает экспорт спанов по cfg и делает провайдер глобальным, чтобы его
+// использовали драйвер БД и библиотеки. Возвращает описание того, куда пишутся спаны.
+func New(ctx context.Context, cfg config.Tracing) (*Tracing, string, error) {
+	exporter, closeFile, target, err := newExporter(ctx, cfg)
+	if err != nil {
+		return nil, "", fmt.Errorf("%s: failed to create exporter: %w", op, err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName))),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	t := newTracing(provider)
+	t.shutdown = func(ctx context.Context) error {
+		return errors.Join(provider.Shutdown(ctx), closeFile())
+	}
+
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(t.propagator)
+
+	return t, target, nil
+}
+
+func newTracing(provider trace.TracerProvider) *Tracing {
+	return &Tracing{
+		tracer:     provider.Tracer("filmoteka/internal/tracing"),
+		propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+		shutdown:   func(context.Context) error { return nil },
+	}
+}
+
+// newExporter выбирает, куда отправлять спаны: коллектору OTLP, в файл или в stdout
+func newExporter(ctx context.Context, cfg config.Tracing) (sdktrace.SpanExporter, func() error, string, error) {
+	nop := func() error { return nil }
+
+	if cfg.Endpoint != "" {
+		// Адрес и остальные параметры экспортер читает из OTEL_EXPORTER_OTLP_*
+		exporter, err := otlptracehttp.New(ctx)
+		return exporter, nop, cfg.Endpoint, err
+	}
+
+	if cfg.File == "" {
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		return exporter, nop, "stdout", err
+	}
+
+	f, err := os.OpenFile(cfg.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	exporter, err := stdouttrace.New(stdouttrace.WithWriter(f))
+	if err != nil {
+		f.Close()
+		return nil, nil, "", err
+	}
+
+	return exporter, f.Close, cfg.File, nil
+}
+
+// Shutdown отправляет спаны, которые еще не отправлены, и закрывает экспортер
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	return t.shutdown(ctx)
+}
@@ -0,0 +1,176 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/repo/sqlite"
+	"filmoteka/pkg/tracesql"
+)
+
+// Провайдер общий для всех тестов и глобальный, как в приложении: драйвер БД
+// привязывается к первому глобальному провайдеру
+var (
+	recorder = tracetest.NewSpanRecorder()
+	provider = sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+)
+
+func init() {
+	otel.SetTracerProvider(provider)
+}
+
+// spans возвращает законченные спаны трассы id
+func spans(id trace.TraceID) map[string]sdktrace.ReadOnlySpan {
+	res := map[string]sdktrace.ReadOnlySpan{}
+
+	for _, s := range recorder.Ended() {
+		if s.SpanContext().TraceID() == id {
+			res[s.Name()] = s
+		}
+	}
+
+	return res
+}
+
+func attr(s sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func TestHTTP(t *testing.T) {
+	tr := newTracing(provider)
+
+	r := chi.NewRouter()
+	r.Use(tr.HTTP)
+	r.Route("/movie", func(r chi.Router) {
+		r.Get("/find_by_id/{id}", func(w http.ResponseWriter, r *http.Request) {
+			_, span := otel.Tracer("test").Start(r.Context(), "handler")
+			span.End()
+
+			http.Error(w, "failed", http.StatusInternalServerError)
+		})
+	})
+
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+	req := httptest.NewRequest(http.MethodGet, "/movie/find_by_id/1", nil)
+	req.Header.Set("traceparent", traceparent)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	id, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	got := spans(id)
+
+	server, ok := got["GET /movie/find_by_id/{id}"]
+	if !ok {
+		t.Fatalf("no span for the route in the client trace, got %v", got)
+	}
+
+	if server.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("span parent = %s, want client span 00f067aa0ba902b7", server.Parent().SpanID())
+	}
+
+	if server.Status().Code != codes.Error {
+		t.Errorf("span status = %s, want Error", server.Status().Code)
+	}
+
+	if status := attr(server, semconv.HTTPResponseStatusCodeKey).AsInt64(); status != http.StatusInternalServerError {
+		t.Errorf("status attribute = %d, want 500", status)
+	}
+
+	if handler, ok := got["handler"]; !ok || handler.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Errorf("handler span is not a child of the request span")
+	}
+}
+
+func TestUsecaseAndSQL(t *testing.T) {
+	tr := newTracing(provider)
+
+	db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "filmoteka.db"))
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	defer db.Close()
+
+	movies := NewMovies(usecase.NewMovies(sqlite.NewMoviesRepo(db), nopLogger{}), tr)
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), "root")
+
+	title := "Брат"
+	if _, err = movies.Save(ctx, entity.MovieData{Title: &title}); err != nil {
+		t.Fatalf("failed to save movie: %s", err)
+	}
+
+	search := context.WithValue(ctx, filter.FilterOptionsContextKey, map[string][]string{"title": {"брат"}})
+	movies.FindMovie(search)
+	movies.Find(ctx, 42)
+
+	root.End()
+	got := spans(root.SpanContext().TraceID())
+
+	for _, name := range []string{"movie.save", "movie.search", "movie.find", "MovieQuerySave", "MovieQueryFindMovie", "MovieQueryFind"} {
+		if _, ok := got[name]; !ok {
+			t.Errorf("no span %s", name)
+		}
+	}
+
+	if t.Failed() {
+		t.FailNow()
+	}
+
+	if got["MovieQueryFindMovie"].Parent().SpanID() != got["movie.search"].SpanContext().SpanID() {
+		t.Errorf("query span is not a child of the usecase span")
+	}
+
+	// Фильм без актеров не находится поиском с JOIN по актерам
+	if rows := attr(got["MovieQueryFindMovie"], tracesql.RowsReturnedKey); rows.Type() != attribute.INT64 || rows.AsInt64() != 0 {
+		t.Errorf("search span rows = %v, want 0", rows.Emit())
+	}
+
+	if got["movie.find"].Status().Code != codes.Error {
+		t.Errorf("failed usecase span status = %s, want Error", got["movie.find"].Status().Code)
+	}
+}
+
+// nopLogger отбрасывает все записи
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+func (nopLogger) Info(string, ...any) {}
+
+func (nopLogger) Warn(string, ...any) {}
+
+func (nopLogger) Error(string, ...any) {}
+
+func (nopLogger) DebugContext(context.Context, string, ...any) {}
+
+func (nopLogger) InfoContext(context.Context, string, ...any) {}
+
+func (nopLogger) WarnContext(context.Context, string, ...any) {}
+
+func (nopLogger) ErrorContext(context.Context, string, ...any) {}
+
+func (nopLogger) Err(err error) slog.Attr {
+	return slog.String("error", err.Error())
+}
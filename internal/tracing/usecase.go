@@ -0,0 +1,193 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/codes"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// span выполняет метод usecase в спане name ("movie.search"). Запросы к БД внутри метода
+// становятся дочерними спанами.
+func span[T any](ctx context.Context, t *Tracing, name string, f func(ctx context.Context) (T, error)) (T, error) {
+	ctx, s := t.tracer.Start(ctx, name)
+	defer s.End()
+
+	res, err := f(ctx)
+	if err != nil {
+		s.RecordError(err)
+		s.SetStatus(codes.Error, err.Error())
+	}
+
+	return res, err
+}
+
+// Actors создает спаны методов usecase.Actor
+type Actors struct {
+	uc usecase.Actor
+	t  *Tracing
+}
+
+var _ usecase.Actor = (*Actors)(nil)
+
+func NewActors(uc usecase.Actor, t *Tracing) *Actors {
+	return &Actors{uc: uc, t: t}
+}
+
+func (a *Actors) Save(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return span(ctx, a.t, "actor.save", func(ctx context.Context) (entity.Actor, error) { return a.uc.Save(ctx, data) })
+}
+
+func (a *Actors) Update(ctx context.Context, updates entity.Actor) (entity.Actor, error) {
+	return span(ctx, a.t, "actor.update", func(ctx context.Context) (entity.Actor, error) {
+		return a.uc.Update(ctx, updates)
+	})
+}
+
+func (a *Actors) Delete(ctx context.Context, id int) (entity.Actor, error) {
+	return span(ctx, a.t, "actor.delete", func(ctx context.Context) (entity.Actor, error) {
+		return a.uc.Delete(ctx, id)
+	})
+}
+
+func (a *Actors) Find(ctx context.Context, id int) (entity.Actor, error) {
+	return span(ctx, a.t, "actor.find", func(ctx context.Context) (entity.Actor, error) { return a.uc.Find(ctx, id) })
+}
+
+func (a *Actors) FindByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error) {
+	return span(ctx, a.t, "actor.find_by_external_id", func(ctx context.Context) (entity.Actor, error) {
+		return a.uc.FindByExternalID(ctx, source, externalID)
+	})
+}
+
+func (a *Actors) Upsert(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return span(ctx, a.t, "actor.upsert", func(ctx context.Context) (entity.Actor, error) {
+		return a.uc.Upsert(ctx, data)
+	})
+}
+
+func (a *Actors) List(ctx context.Context) ([]entity.Actor, error) {
+	return span(ctx, a.t, "actor.list", func(ctx context.Context) ([]entity.Actor, error) { return a.uc.List(ctx) })
+}
+
+func (a *Actors) Next(ctx context.Context) ([]entity.Actor, error) {
+	return span(ctx, a.t, "actor.next", func(ctx context.Context) ([]entity.Actor, error) { return a.uc.Next(ctx) })
+}
+
+func (a *Actors) Duplicates(ctx context.Context, minScore float64) ([]entity.DuplicateActors, error) {
+	return span(ctx, a.t, "actor.duplicates", func(ctx context.Context) ([]entity.DuplicateActors, error) {
+		return a.uc.Duplicates(ctx, minScore)
+	})
+}
+
+func (a *Actors) Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error) {
+	return span(ctx, a.t, "actor.merge", func(ctx context.Context) (entity.Actor, error) {
+		return a.uc.Merge(ctx, sourceID, targetID)
+	})
+}
+
+// Movies создает спаны методов usecase.Movie
+type Movies struct {
+	uc usecase.Movie
+	t  *Tracing
+}
+
+var _ usecase.Movie = (*Movies)(nil)
+
+func NewMovies(uc usecase.Movie, t *Tracing) *Movies {
+	return &Movies{uc: uc, t: t}
+}
+
+func (mv *Movies) Save(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return span(ctx, mv.t, "movie.save", func(ctx context.Context) (entity.Movie, error) {
+		return mv.uc.Save(ctx, data)
+	})
+}
+
+func (mv *Movies) Update(ctx context.Context, updates entity.Movie) (entity.Movie, error) {
+	return span(ctx, mv.t, "movie.update", func(ctx context.Context) (entity.Movie, error) {
+		return mv.uc.Update(ctx, updates)
+	})
+}
+
+func (mv *Movies) Delete(ctx context.Context, id int) (entity.Movie, error) {
+	return span(ctx, mv.t, "movie.delete", func(ctx context.Context) (entity.Movie, error) {
+		return mv.uc.Delete(ctx, id)
+	})
+}
+
+func (mv *Movies) Find(ctx context.Context, id int) (entity.Movie, error) {
+	return span(ctx, mv.t, "movie.find", func(ctx context.Context) (entity.Movie, error) { return mv.uc.Find(ctx, id) })
+}
+
+func (mv *Movies) FindByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error) {
+	return span(ctx, mv.t, "movie.find_by_external_id", func(ctx context.Context) (entity.Movie, error) {
+		return mv.uc.FindByExternalID(ctx, source, externalID)
+	})
+}
+
+func (mv *Movies) Upsert(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return span(ctx, mv.t, "movie.upsert", func(ctx context.Context) (entity.Movie, error) {
+		return mv.uc.Upsert(ctx, data)
+	})
+}
+
+func (mv *Movies) FindMovie(ctx context.Context) ([]entity.Movie, error) {
+	return span(ctx, mv.t, "movie.search", func(ctx context.Context) ([]entity.Movie, error) {
+		return mv.uc.FindMovie(ctx)
+	})
+}
+
+func (mv *Movies) List(ctx context.Context) ([]entity.Movie, error) {
+	return span(ctx, mv.t, "movie.list", func(ctx context.Context) ([]entity.Movie, error) { return mv.uc.List(ctx) })
+}
+
+func (mv *Movies) Next(ctx context.Context) ([]entity.Movie, error) {
+	return span(ctx, mv.t, "movie.next", func(ctx context.Context) ([]entity.Movie, error) { return mv.uc.Next(ctx) })
+}
+
+// ActorsMovies создает спаны методов usecase.ActorMovie
+type ActorsMovies struct {
+	uc usecase.ActorMovie
+	t  *Tracing
+}
+
+var _ usecase.ActorMovie = (*ActorsMovies)(nil)
+
+func NewActorsMovies(uc usecase.ActorMovie, t *Tracing) *ActorsMovies {
+	return &ActorsMovies{uc: uc, t: t}
+}
+
+func (am *ActorsMovies) Save(ctx context.Context, data entity.ActorMovie) error {
+	_, err := span(ctx, am.t, "actor_movie.save", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, am.uc.Save(ctx, data)
+	})
+	return err
+}
+
+func (am *ActorsMovies) Delete(ctx context.Context, data entity.ActorMovie) error {
+	_, err := span(ctx, am.t, "actor_movie.delete", func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, am.uc.Delete(ctx, data)
+	})
+	return err
+}
+
+func (am *ActorsMovies) List(ctx context.Context) ([]entity.ActorMovieData, error) {
+	return span(ctx, am.t, "actor_movie.list", func(ctx context.Context) ([]entity.ActorMovieData, error) {
+		return am.uc.List(ctx)
+	})
+}
+
+func (am *ActorsMovies) Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+	return span(ctx, am.t, "actor_movie.cast", func(ctx context.Context) (map[int][]entity.Actor, error) {
+		return am.uc.Cast(ctx, movieIDs)
+	})
+}
+
+func (am *ActorsMovies) Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+	return span(ctx, am.t, "actor_movie.filmography", func(ctx context.Context) (map[int][]entity.Movie, error) {
+		return am.uc.Filmography(ctx, actorIDs)
+	})
+}
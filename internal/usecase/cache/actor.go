@@ -0,0 +1,100 @@
+package cache
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// Actors кеширует ответы usecase.Actor. Поиск дубликатов нужен редко и не кешируется.
+type Actors struct {
+	uc usecase.Actor
+	c  *Cache
+}
+
+var _ usecase.Actor = (*Actors)(nil)
+
+func NewActors(uc usecase.Actor, c *Cache) *Actors {
+	return &Actors{uc: uc, c: c}
+}
+
+// Find может вернуть актера с другим id, если запрошенный был слит с ним,
+// поэтому ответ помечается обоими id
+func (a *Actors) Find(ctx context.Context, id int) (entity.Actor, error) {
+	return cached(ctx, a.c, "actor.find", key(ctx, "actor.find", id), func() (entity.Actor, error) {
+		return a.uc.Find(ctx, id)
+	}, func(res entity.Actor) []string {
+		return idTags(actorTag, &id, res.Id)
+	})
+}
+
+func (a *Actors) FindByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error) {
+	return cached(ctx, a.c, "actor.find_by_external_id", key(ctx, "actor.find_by_external_id", source, externalID), func() (entity.Actor, error) {
+		return a.uc.FindByExternalID(ctx, source, externalID)
+	}, func(res entity.Actor) []string {
+		return idTags(actorTag, res.Id)
+	})
+}
+
+func (a *Actors) List(ctx context.Context) ([]entity.Actor, error) {
+	return cached(ctx, a.c, "actor.list", key(ctx, "actor.list"), func() ([]entity.Actor, error) {
+		return a.uc.List(ctx)
+	}, tags[[]entity.Actor](tagActors))
+}
+
+func (a *Actors) Next(ctx context.Context) ([]entity.Actor, error) {
+	return cached(ctx, a.c, "actor.next", key(ctx, "actor.next"), func() ([]entity.Actor, error) {
+		return a.uc.Next(ctx)
+	}, tags[[]entity.Actor](tagActors))
+}
+
+func (a *Actors) Duplicates(ctx context.Context, minScore float64) ([]entity.DuplicateActors, error) {
+	return a.uc.Duplicates(ctx, minScore)
+}
+
+func (a *Actors) Save(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	res, err := a.uc.Save(ctx, data)
+	if err == nil {
+		a.c.invalidate(ctx, tagActors)
+	}
+
+	return res, err
+}
+
+func (a *Actors) Update(ctx context.Context, updates entity.Actor) (entity.Actor, error) {
+	res, err := a.uc.Update(ctx, updates)
+	if err == nil {
+		a.c.invalidate(ctx, append(idTags(actorTag, updates.Id), tagActors)...)
+	}
+
+	return res, err
+}
+
+func (a *Actors) Delete(ctx context.Context, id int) (entity.Actor, error) {
+	res, err := a.uc.Delete(ctx, id)
+	if err == nil {
+		a.c.invalidate(ctx, actorTag(id), filmographyTag(id), tagActors)
+	}
+
+	return res, err
+}
+
+func (a *Actors) Upsert(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	res, err := a.uc.Upsert(ctx, data)
+	if err == nil {
+		a.c.invalidate(ctx, append(idTags(actorTag, res.Id), tagActors)...)
+	}
+
+	return res, err
+}
+
+// Merge переносит фильмы source к target, поэтому затрагивает составы и фильмографии обоих
+func (a *Actors) Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error) {
+	res, err := a.uc.Merge(ctx, sourceID, targetID)
+	if err == nil {
+		a.c.invalidate(ctx, actorTag(sourceID), actorTag(targetID), filmographyTag(sourceID), filmographyTag(targetID), tagActors)
+	}
+
+	return res, err
+}
@@ -0,0 +1,95 @@
+package cache
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// ActorsMovies кеширует ответы usecase.ActorMovie. Ответы помечаются id всех актеров и фильмов,
+// которые в них вошли, поэтому изменение одного фильма не сбрасывает составы других.
+type ActorsMovies struct {
+	uc usecase.ActorMovie
+	c  *Cache
+}
+
+var _ usecase.ActorMovie = (*ActorsMovies)(nil)
+
+func NewActorsMovies(uc usecase.ActorMovie, c *Cache) *ActorsMovies {
+	return &ActorsMovies{uc: uc, c: c}
+}
+
+func (am *ActorsMovies) List(ctx context.Context) ([]entity.ActorMovieData, error) {
+	return cached(ctx, am.c, "actor_movie.list", key(ctx, "actor_movie.list"), func() ([]entity.ActorMovieData, error) {
+		return am.uc.List(ctx)
+	}, func(res []entity.ActorMovieData) []string {
+		t := []string{tagCast}
+		for _, row := range res {
+			t = append(t, actorTag(row.ActorID), movieTag(row.MovieID))
+		}
+
+		return t
+	})
+}
+
+func (am *ActorsMovies) Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+	return cached(ctx, am.c, "actor_movie.cast", key(ctx, "actor_movie.cast", ids(movieIDs)), func() (map[int][]entity.Actor, error) {
+		return am.uc.Cast(ctx, movieIDs)
+	}, func(res map[int][]entity.Actor) []string {
+		t := make([]string, 0, len(movieIDs))
+		for _, id := range movieIDs {
+			t = append(t, castTag(id))
+		}
+
+		for _, actors := range res {
+			for _, a := range actors {
+				t = append(t, idTags(actorTag, a.Id)...)
+			}
+		}
+
+		return t
+	})
+}
+
+func (am *ActorsMovies) Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+	return cached(ctx, am.c, "actor_movie.filmography", key(ctx, "actor_movie.filmography", ids(actorIDs)), func() (map[int][]entity.Movie, error) {
+		return am.uc.Filmography(ctx, actorIDs)
+	}, func(res map[int][]entity.Movie) []string {
+		t := make([]string, 0, len(actorIDs))
+		for _, id := range actorIDs {
+			t = append(t, filmographyTag(id))
+		}
+
+		for _, movies := range res {
+			for _, m := range movies {
+				t = append(t, idTags(movieTag, m.Id)...)
+			}
+		}
+
+		return t
+	})
+}
+
+func (am *ActorsMovies) Save(ctx context.Context, data entity.ActorMovie) error {
+	err := am.uc.Save(ctx, data)
+	if err == nil {
+		am.c.invalidate(ctx, castTags(data)...)
+	}
+
+	return err
+}
+
+func (am *ActorsMovies) Delete(ctx context.Context, data entity.ActorMovie) error {
+	err := am.uc.Delete(ctx, data)
+	if err == nil {
+		am.c.invalidate(ctx, castTags(data)...)
+	}
+
+	return err
+}
+
+// castTags возвращает теги ответов, которые меняются, когда актер входит в состав фильма или выходит из него
+func castTags(data entity.ActorMovie) []string {
+	return append(append(idTags(filmographyTag, data.Actor_id), idTags(castTag, data.Movie_id)...), tagCast)
+}
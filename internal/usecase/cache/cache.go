@@ -0,0 +1,264 @@
+// Package cache кеширует ответы usecase на частые запросы чтения: поиск фильма по id,
+// списки фильмов и актеров, состав фильмов.
+//
+// Ключ записи строится из метода, его аргументов и параметров фильтрации, сортировки и пагинации
+// из контекста запроса. Каждая запись помечается тегами данных, из которых собран ответ
+// (movie:1, actor:2, cast:1, списки movies и actors, связи actors_movies), и при изменении данных
+// через usecase удаляются только записи с затронутыми тегами. Изменения в обход приложения
+// (другие экземпляры, команды ctl) попадают в ответы по истечении TTL хранилища.
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/pagination"
+	sortoptions "filmoteka/internal/controller/middleware/sort"
+	"filmoteka/internal/usecase"
+)
+
+// Store - хранилище записей кеша. Значения передаются байтами, а записи связаны с данными
+// через теги, поэтому Store можно реализовать поверх Redis-совместимого хранилища:
+// SET с EX для записи и множество ключей (SADD) для каждого тега.
+type Store interface {
+	// Get возвращает запись по ключу. Если записи нет или она устарела, ok равен false.
+	Get(ctx context.Context, key string) (value []byte, ok bool, err error)
+	// Set сохраняет запись и помечает ее тегами
+	Set(ctx context.Context, key string, value []byte, tags []string) error
+	// Invalidate удаляет все записи, помеченные любым из тегов
+	Invalidate(ctx context.Context, tags ...string) error
+}
+
+// Теги записей
+const (
+	// tagActors - ответы, которые зависят от любого актера: списки и поиск
+	tagActors = "actors"
+	// tagMovies - ответы, которые зависят от любого фильма: списки и поиск
+	tagMovies = "movies"
+	// tagCast - ответы, которые зависят от состава всех фильмов
+	tagCast = "actors_movies"
+)
+
+// actorTag - ответы, в которые входят данные актера
+func actorTag(id int) string {
+	return "actor:" + strconv.Itoa(id)
+}
+
+// movieTag - ответы, в которые входят данные фильма
+func movieTag(id int) string {
+	return "movie:" + strconv.Itoa(id)
+}
+
+// castTag - ответы со списком актеров фильма
+func castTag(movieID int) string {
+	return "cast:" + strconv.Itoa(movieID)
+}
+
+// filmographyTag - ответы со списком фильмов актера
+func filmographyTag(actorID int) string {
+	return "filmography:" + strconv.Itoa(actorID)
+}
+
+// Counts - число попаданий в кеш и промахов
+type Counts struct {
+	Hits   uint64 `json:"hits"`
+	Misses uint64 `json:"misses"`
+}
+
+// Stats - попадания и промахи всего кеша и каждого метода (movie.find, actor_movie.list, ...)
+type Stats struct {
+	Counts
+	Methods map[string]Counts `json:"methods"`
+}
+
+// Cache связывает декораторы usecase с хранилищем и считает попадания и промахи
+type Cache struct {
+	store Store
+	lag   time.Duration
+	log   usecase.Logger
+	now   func() time.Time
+
+	// Ответ, загруженный из БД до изменения данных, не сохраняется после удаления затронутых записей:
+	// запись идет под mu.RLock и только если epoch не изменился с начала загрузки
+	mu    sync.RWMutex
+	epoch uint64
+	// invalidated - когда данные последний раз изменялись
+	invalidated time.Time
+
+	statsMu sync.Mutex
+	stats   map[string]Counts
+}
+
+// New создает кеш поверх store. lag - сколько реплики БД отстают от основной: в течение lag
+// после изменения данных ответы не сохраняются, чтобы в кеш не попали данные с отставшей реплики.
+// Без реплик lag равен 0.
+func New(store Store, lag time.Duration, l usecase.Logger) *Cache {
+	return &Cache{
+		store: store,
+		lag:   lag,
+		log:   l,
+		now:   time.Now,
+		stats: map[string]Counts{},
+	}
+}
+
+// Stats возвращает число попаданий и промахов. Для выключенного кеша (nil) все счетчики равны 0.
+func (c *Cache) Stats() Stats {
+	res := Stats{Methods: map[string]Counts{}}
+	if c == nil {
+		return res
+	}
+
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	for method, counts := range c.stats {
+		res.Methods[method] = counts
+		res.Hits += counts.Hits
+		res.Misses += counts.Misses
+	}
+
+	return res
+}
+
+func (c *Cache) count(method string, hit bool) {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+
+	counts := c.stats[method]
+	if hit {
+		counts.Hits++
+	} else {
+		counts.Misses++
+	}
+	c.stats[method] = counts
+}
+
+// invalidate удаляет записи, помеченные тегами измененных данных
+func (c *Cache) invalidate(ctx context.Context, tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.epoch++
+	c.invalidated = c.now()
+
+	if err := c.store.Invalidate(ctx, tags...); err != nil {
+		c.log.Error("failed to invalidate cache", c.log.Err(err), "tags", tags)
+	}
+}
+
+// set сохраняет ответ, если данные не изменялись с начала его загрузки
+func (c *Cache) set(ctx context.Context, epoch uint64, key string, value []byte, tags []string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.epoch != epoch || c.now().Sub(c.invalidated) < c.lag {
+		return
+	}
+
+	if err := c.store.Set(ctx, key, value, tags); err != nil {
+		c.log.Warn("failed to save response to cache", c.log.Err(err), "key", key)
+	}
+}
+
+// cached возвращает ответ метода из кеша или загружает его через load и сохраняет с тегами tags.
+// Ошибки хранилища не мешают ответу: он загружается из БД.
+func cached[T any](ctx context.Context, c *Cache, method, key string, load func() (T, error), tags func(T) []string) (T, error) {
+	value, ok, err := c.store.Get(ctx, key)
+	if err != nil {
+		c.log.Warn("failed to read response from cache", c.log.Err(err), "key", key)
+	}
+
+	if ok {
+		var res T
+		if err = json.Unmarshal(value, &res); err == nil {
+			c.count(method, true)
+			return res, nil
+		}
+
+		c.log.Warn("failed to decode cached response", c.log.Err(err), "key", key)
+	}
+
+	c.count(method, false)
+
+	c.mu.RLock()
+	epoch := c.epoch
+	c.mu.RUnlock()
+
+	res, err := load()
+	if err != nil {
+		return res, err
+	}
+
+	value, err = json.Marshal(res)
+	if err != nil {
+		c.log.Warn("failed to encode response for cache", c.log.Err(err), "key", key)
+		return res, nil
+	}
+
+	c.set(ctx, epoch, key, value, tags(res))
+
+	return res, nil
+}
+
+// key строит ключ записи из метода, аргументов и параметров запроса из контекста.
+// Порядок параметров в запросе на ключ не влияет.
+func key(ctx context.Context, method string, args ...any) string {
+	var b strings.Builder
+	b.WriteString(method)
+
+	for _, arg := range args {
+		fmt.Fprintf(&b, "/%v", arg)
+	}
+
+	q := url.Values{}
+
+	filterOptions, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
+	for field, values := range filterOptions {
+		q["filter."+field] = values
+	}
+
+	sortOptions, _ := ctx.Value(sortoptions.SortOptionsContextKey).(map[string]string)
+	for field, order := range sortOptions {
+		q.Set("sort."+field, order)
+	}
+
+	if personID, ok := ctx.Value(pagination.NextPersonID).(int); ok {
+		q.Set(string(pagination.NextPersonID), strconv.Itoa(personID))
+	}
+
+	if len(q) > 0 {
+		b.WriteString("?")
+		b.WriteString(q.Encode())
+	}
+
+	return b.String()
+}
+
+// ids возвращает отсортированную копию списка id, чтобы порядок id в запросе не влиял на ключ
+func ids(list []int) string {
+	sorted := append([]int(nil), list...)
+	sort.Ints(sorted)
+
+	parts := make([]string, len(sorted))
+	for i, id := range sorted {
+		parts[i] = strconv.Itoa(id)
+	}
+
+	return strings.Join(parts, ",")
+}
+
+// tags возвращает постоянный набор тегов
+func tags[T any](list ...string) func(T) []string {
+	return func(T) []string {
+		return list
+	}
+}
@@ -0,0 +1,513 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/pagination"
+	sortoptions "filmoteka/internal/controller/middleware/sort"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/repo/memory"
+)
+
+// clock - время, которое переводится вручную
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+type env struct {
+	c            *Cache
+	clock        *clock
+	actors       *Actors
+	movies       *Movies
+	actorsMovies *ActorsMovies
+}
+
+// newEnv создает кеш поверх хранилища в памяти с фильмами 1 "Брат" и 2 "Брат 2" и актерами
+// 1 Сергей Бодров (снимался в обоих фильмах) и 2 Виктор Сухоруков (в фильме 1)
+func newEnv(t *testing.T, lag time.Duration) env {
+	t.Helper()
+
+	ctx := context.Background()
+	l := nopLogger{}
+	s := memory.New()
+
+	actors := usecase.NewActors(memory.NewActorsRepo(s), l)
+	movies := usecase.NewMovies(memory.NewMoviesRepo(s), l)
+	actorsMovies := usecase.NewActorsMovies(memory.NewActorsMoviesRepo(s), l)
+
+	for _, m := range []struct {
+		title  string
+		rating int
+	}{{"Брат", 8}, {"Брат 2", 7}} {
+		if _, err := movies.Save(ctx, entity.MovieData{Title: ptr(m.title), Rating: ptr(m.rating)}); err != nil {
+			t.Fatalf("failed to save movie: %s", err)
+		}
+	}
+
+	for _, a := range [][2]string{{"Сергей", "Бодров"}, {"Виктор", "Сухоруков"}} {
+		if _, err := actors.Save(ctx, entity.ActorData{Name: ptr(a[0]), Surname: ptr(a[1])}); err != nil {
+			t.Fatalf("failed to save actor: %s", err)
+		}
+	}
+
+	for _, am := range [][2]int{{1, 1}, {1, 2}, {2, 1}} {
+		if err := actorsMovies.Save(ctx, entity.ActorMovie{Actor_id: ptr(am[0]), Movie_id: ptr(am[1])}); err != nil {
+			t.Fatalf("failed to save cast: %s", err)
+		}
+	}
+
+	c := &clock{t: time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)}
+
+	store := NewLRU(100, time.Minute)
+	store.now = c.now
+
+	cache := New(store, lag, l)
+	cache.now = c.now
+	// Данные загружены до создания кеша
+	cache.invalidated = c.t.Add(-lag)
+
+	return env{
+		c:            cache,
+		clock:        c,
+		actors:       NewActors(actors, cache),
+		movies:       NewMovies(movies, cache),
+		actorsMovies: NewActorsMovies(actorsMovies, cache),
+	}
+}
+
+func (e env) counts(method string) Counts {
+	return e.c.Stats().Methods[method]
+}
+
+func findTitle(t *testing.T, e env, id int) string {
+	t.Helper()
+
+	m, err := e.movies.Find(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to find movie %d: %s", id, err)
+	}
+
+	return *m.Title
+}
+
+func TestFindIsCached(t *testing.T) {
+	e := newEnv(t, 0)
+
+	for i := 0; i < 3; i++ {
+		if got := findTitle(t, e, 1); got != "Брат" {
+			t.Fatalf("title = %q, want Брат", got)
+		}
+	}
+
+	want := Counts{Hits: 2, Misses: 1}
+	if got := e.counts("movie.find"); got != want {
+		t.Fatalf("movie.find = %+v, want %+v", got, want)
+	}
+
+	if got := e.c.Stats().Counts; got != want {
+		t.Fatalf("total = %+v, want %+v", got, want)
+	}
+}
+
+func TestErrorsAreNotCached(t *testing.T) {
+	e := newEnv(t, 0)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := e.movies.Find(ctx, 42); err == nil {
+			t.Fatalf("movie 42 was found")
+		}
+	}
+
+	if got := e.counts("movie.find"); got.Hits != 0 {
+		t.Fatalf("movie.find = %+v, want no hits", got)
+	}
+}
+
+func TestUpdateInvalidatesOnlyChangedMovie(t *testing.T) {
+	e := newEnv(t, 0)
+	ctx := context.Background()
+
+	findTitle(t, e, 1)
+	findTitle(t, e, 2)
+
+	if _, err := e.movies.Update(ctx, entity.Movie{Id: ptr(1), MovieData: entity.MovieData{Title: ptr("Брат (1997)")}}); err != nil {
+		t.Fatalf("failed to update movie: %s", err)
+	}
+
+	if got := findTitle(t, e, 1); got != "Брат (1997)" {
+		t.Fatalf("title after update = %q", got)
+	}
+
+	findTitle(t, e, 2)
+
+	want := Counts{Hits: 1, Misses: 3}
+	if got := e.counts("movie.find"); got != want {
+		t.Fatalf("movie.find = %+v, want %+v: only movie 2 should stay cached", got, want)
+	}
+}
+
+func TestListIsKeyedByQuery(t *testing.T) {
+	e := newEnv(t, 0)
+
+	withRating := func(rating string) context.Context {
+		return context.WithValue(context.Background(), filter.FilterOptionsContextKey, map[string][]string{"rating": {rating}})
+	}
+
+	list := func(ctx context.Context) []entity.Movie {
+		t.Helper()
+
+		res, err := e.movies.List(ctx)
+		if err != nil {
+			t.Fatalf("failed to list movies: %s", err)
+		}
+
+		return res
+	}
+
+	if got := list(withRating("8")); len(got) != 1 || *got[0].Title != "Брат" {
+		t.Fatalf("movies with rating 8 = %v", got)
+	}
+
+	if got := list(withRating("7")); len(got) != 1 || *got[0].Title != "Брат 2" {
+		t.Fatalf("movies with rating 7 = %v", got)
+	}
+
+	list(withRating("8"))
+
+	// Новый фильм попадает в список, который уже был в кеше
+	if _, err := e.movies.Save(context.Background(), entity.MovieData{Title: ptr("Сестры"), Rating: ptr(8)}); err != nil {
+		t.Fatalf("failed to save movie: %s", err)
+	}
+
+	if got := list(withRating("8")); len(got) != 2 {
+		t.Fatalf("movies with rating 8 after save = %d, want 2", len(got))
+	}
+
+	want := Counts{Hits: 1, Misses: 3}
+	if got := e.counts("movie.list"); got != want {
+		t.Fatalf("movie.list = %+v, want %+v", got, want)
+	}
+}
+
+func TestKey(t *testing.T) {
+	ctx := context.Background()
+
+	withOptions := func(f map[string][]string, s map[string]string, next int) context.Context {
+		ctx := context.WithValue(ctx, filter.FilterOptionsContextKey, f)
+		ctx = context.WithValue(ctx, sortoptions.SortOptionsContextKey, s)
+		return context.WithValue(ctx, pagination.NextPersonID, next)
+	}
+
+	base := withOptions(map[string][]string{"rating": {"8"}, "title": {"Брат"}}, map[string]string{"rating": "DESC", "title": "ASC"}, 0)
+
+	if key(base, "movie.list") != key(withOptions(map[string][]string{"title": {"Брат"}, "rating": {"8"}}, map[string]string{"title": "ASC", "rating": "DESC"}, 0), "movie.list") {
+		t.Fatalf("key depends on the order of query options")
+	}
+
+	different := map[string]context.Context{
+		"filter":     withOptions(map[string][]string{"rating": {"7"}, "title": {"Брат"}}, map[string]string{"rating": "DESC", "title": "ASC"}, 0),
+		"sort":       withOptions(map[string][]string{"rating": {"8"}, "title": {"Брат"}}, map[string]string{"rating": "ASC", "title": "ASC"}, 0),
+		"pagination": withOptions(map[string][]string{"rating": {"8"}, "title": {"Брат"}}, map[string]string{"rating": "DESC", "title": "ASC"}, 10),
+	}
+
+	for name, ctx := range different {
+		if key(ctx, "movie.list") == key(base, "movie.list") {
+			t.Errorf("key does NOT depend on %s", name)
+		}
+	}
+
+	if key(ctx, "movie.find", 1) == key(ctx, "movie.find", 12) {
+		t.Errorf("key does NOT depend on arguments")
+	}
+
+	if key(ctx, "actor_movie.cast", ids([]int{2, 1})) != key(ctx, "actor_movie.cast", ids([]int{1, 2})) {
+		t.Errorf("key depends on the order of ids")
+	}
+}
+
+func TestCastInvalidation(t *testing.T) {
+	e := newEnv(t, 0)
+	ctx := context.Background()
+
+	cast := func(movieID int) int {
+		t.Helper()
+
+		res, err := e.actorsMovies.Cast(ctx, []int{movieID})
+		if err != nil {
+			t.Fatalf("failed to get cast: %s", err)
+		}
+
+		return len(res[movieID])
+	}
+
+	cast(1)
+	cast(2)
+
+	// Актер 2 снимается во втором фильме: состав первого остается в кеше
+	if err := e.actorsMovies.Save(ctx, entity.ActorMovie{Actor_id: ptr(2), Movie_id: ptr(2)}); err != nil {
+		t.Fatalf("failed to save cast: %s", err)
+	}
+
+	if got := cast(2); got != 2 {
+		t.Fatalf("cast of movie 2 = %d actors, want 2", got)
+	}
+	cast(1)
+
+	want := Counts{Hits: 1, Misses: 3}
+	if got := e.counts("actor_movie.cast"); got != want {
+		t.Fatalf("actor_movie.cast = %+v, want %+v", got, want)
+	}
+
+	// Переименование актера меняет составы, в которые он входит
+	if _, err := e.actors.Update(ctx, entity.Actor{Id: ptr(2), ActorData: entity.ActorData{Name: ptr("Виктор Иванович")}}); err != nil {
+		t.Fatalf("failed to update actor: %s", err)
+	}
+
+	res, err := e.actorsMovies.Cast(ctx, []int{1})
+	if err != nil {
+		t.Fatalf("failed to get cast: %s", err)
+	}
+
+	if got := *res[1][1].Name; got != "Виктор Иванович" {
+		t.Fatalf("name in cast after update = %q", got)
+	}
+}
+
+func TestMergeInvalidatesFilmography(t *testing.T) {
+	e := newEnv(t, 0)
+	ctx := context.Background()
+
+	filmography := func(actorID int) []entity.Movie {
+		t.Helper()
+
+		res, err := e.actorsMovies.Filmography(ctx, []int{actorID})
+		if err != nil {
+			t.Fatalf("failed to get filmography: %s", err)
+		}
+
+		return res[actorID]
+	}
+
+	if got := filmography(2); len(got) != 1 {
+		t.Fatalf("filmography of actor 2 = %d movies, want 1", len(got))
+	}
+
+	if _, err := e.actors.Merge(ctx, 1, 2); err != nil {
+		t.Fatalf("failed to merge actors: %s", err)
+	}
+
+	if got := filmography(2); len(got) != 2 {
+		t.Fatalf("filmography of actor 2 after merge = %d movies, want 2", len(got))
+	}
+
+	// Слитый актер находится по старому id
+	a, err := e.actors.Find(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to find merged actor: %s", err)
+	}
+
+	if *a.Id != 2 {
+		t.Fatalf("merged actor id = %d, want 2", *a.Id)
+	}
+}
+
+// racingMovies - usecase, данные которого изменяются, пока загружается ответ
+type racingMovies struct {
+	usecase.Movie
+	c *Cache
+}
+
+func (m racingMovies) Find(ctx context.Context, id int) (entity.Movie, error) {
+	res, err := m.Movie.Find(ctx, id)
+	m.c.invalidate(ctx, movieTag(id))
+
+	return res, err
+}
+
+func TestResponseLoadedBeforeWriteIsNotStored(t *testing.T) {
+	e := newEnv(t, 0)
+
+	racing := NewMovies(racingMovies{Movie: e.movies.uc, c: e.c}, e.c)
+	if _, err := racing.Find(context.Background(), 1); err != nil {
+		t.Fatalf("failed to find movie: %s", err)
+	}
+
+	findTitle(t, e, 1)
+
+	if got := e.counts("movie.find"); got.Hits != 0 {
+		t.Fatalf("movie.find = %+v: response loaded before write was cached", got)
+	}
+}
+
+func TestNothingIsStoredWhileReplicasLag(t *testing.T) {
+	e := newEnv(t, 5*time.Second)
+	ctx := context.Background()
+
+	if _, err := e.movies.Update(ctx, entity.Movie{Id: ptr(2), MovieData: entity.MovieData{Rating: ptr(6)}}); err != nil {
+		t.Fatalf("failed to update movie: %s", err)
+	}
+
+	e.clock.t = e.clock.t.Add(4 * time.Second)
+	findTitle(t, e, 1)
+	findTitle(t, e, 1)
+
+	if got := e.counts("movie.find"); got.Hits != 0 {
+		t.Fatalf("movie.find = %+v: response was cached while replicas lag", got)
+	}
+
+	e.clock.t = e.clock.t.Add(time.Second)
+	findTitle(t, e, 1)
+	findTitle(t, e, 1)
+
+	want := Counts{Hits: 1, Misses: 3}
+	if got := e.counts("movie.find"); got != want {
+		t.Fatalf("movie.find = %+v, want %+v", got, want)
+	}
+}
+
+// brokenStore - недоступное хранилище
+type brokenStore struct{}
+
+var errUnavailable = errors.New("store is unavailable")
+
+func (brokenStore) Get(context.Context, string) ([]byte, bool, error) {
+	return nil, false, errUnavailable
+}
+
+func (brokenStore) Set(context.Context, string, []byte, []string) error {
+	return errUnavailable
+}
+
+func (brokenStore) Invalidate(context.Context, ...string) error {
+	return errUnavailable
+}
+
+func TestUnavailableStore(t *testing.T) {
+	e := newEnv(t, 0)
+	c := New(brokenStore{}, 0, nopLogger{})
+	movies := NewMovies(e.movies.uc, c)
+	ctx := context.Background()
+
+	m, err := movies.Find(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if *m.Title != "Брат" {
+		t.Fatalf("title = %q, want Брат", *m.Title)
+	}
+
+	if _, err = movies.Save(ctx, entity.MovieData{Title: ptr("Сестры")}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestDisabledCacheStats(t *testing.T) {
+	var c *Cache
+
+	if got := c.Stats(); got.Hits != 0 || got.Misses != 0 || got.Methods == nil {
+		t.Fatalf("stats of disabled cache = %+v", got)
+	}
+}
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRU(2, 0)
+
+	s.Set(ctx, "a", []byte("1"), nil)
+	s.Set(ctx, "b", []byte("2"), nil)
+	s.Get(ctx, "a")
+	s.Set(ctx, "c", []byte("3"), nil)
+
+	if _, ok, _ := s.Get(ctx, "b"); ok {
+		t.Fatalf("least recently used entry was NOT evicted")
+	}
+
+	for _, key := range []string{"a", "c"} {
+		if _, ok, _ := s.Get(ctx, key); !ok {
+			t.Fatalf("entry %s was evicted", key)
+		}
+	}
+
+	if s.Len() != 2 {
+		t.Fatalf("len = %d, want 2", s.Len())
+	}
+}
+
+func TestLRUExpires(t *testing.T) {
+	ctx := context.Background()
+	c := &clock{t: time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)}
+
+	s := NewLRU(10, time.Minute)
+	s.now = c.now
+
+	s.Set(ctx, "a", []byte("1"), []string{"movies"})
+	c.t = c.t.Add(time.Minute)
+
+	if _, ok, _ := s.Get(ctx, "a"); ok {
+		t.Fatalf("expired entry was returned")
+	}
+
+	if s.Len() != 0 || len(s.keys) != 0 {
+		t.Fatalf("expired entry was NOT removed: len = %d, tags = %v", s.Len(), s.keys)
+	}
+}
+
+func TestLRUInvalidate(t *testing.T) {
+	ctx := context.Background()
+	s := NewLRU(10, 0)
+
+	s.Set(ctx, "movie.find/1", []byte("1"), []string{"movie:1"})
+	s.Set(ctx, "movie.find/2", []byte("2"), []string{"movie:2"})
+	s.Set(ctx, "movie.list", []byte("[1,2]"), []string{"movies"})
+	s.Set(ctx, "actor_movie.cast/1", []byte("{}"), []string{"movie:1", "actor:1"})
+
+	s.Invalidate(ctx, "movie:1", "movies")
+
+	if _, ok, _ := s.Get(ctx, "movie.find/2"); !ok || s.Len() != 1 {
+		t.Fatalf("invalidate removed wrong entries: len = %d", s.Len())
+	}
+
+	if _, ok := s.keys["actor:1"]; ok {
+		t.Fatalf("tag of removed entry was kept")
+	}
+}
+
+// nopLogger отбрасывает все записи
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...any) {}
+
+func (nopLogger) Info(string, ...any) {}
+
+func (nopLogger) Warn(string, ...any) {}
+
+func (nopLogger) Error(string, ...any) {}
+
+func (nopLogger) DebugContext(context.Context, string, ...any) {}
+
+func (nopLogger) InfoContext(context.Context, string, ...any) {}
+
+func (nopLogger) WarnContext(context.Context, string, ...any) {}
+
+func (nopLogger) ErrorContext(context.Context, string, ...any) {}
+
+func (nopLogger) Err(err error) slog.Attr {
+	return slog.String("error", err.Error())
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
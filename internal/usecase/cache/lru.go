@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"time"
+)
+
+// LRU - хранилище в памяти процесса. При переполнении вытесняются записи, которые дольше всего
+// не читались. Записи старше ttl считаются отсутствующими; при ttl равном 0 они не устаревают.
+type LRU struct {
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu sync.Mutex
+	// order - записи от недавно прочитанных к давно не читанным
+	order *list.List
+	items map[string]*list.Element
+	// keys - ключи записей, помеченных тегом
+	keys map[string]map[string]struct{}
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	tags    []string
+	expires time.Time
+}
+
+// NewLRU создает хранилище не больше чем на size записей
+func NewLRU(size int, ttl time.Duration) *LRU {
+	return &LRU{
+		size:  size,
+		ttl:   ttl,
+		now:   time.Now,
+		order: list.New(),
+		items: map[string]*list.Element{},
+		keys:  map[string]map[string]struct{}{},
+	}
+}
+
+func (s *LRU) Get(_ context.Context, key string) ([]byte, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+
+	e := el.Value.(*lruEntry)
+	if s.ttl > 0 && !s.now().Before(e.expires) {
+		s.remove(el)
+		return nil, false, nil
+	}
+
+	s.order.MoveToFront(el)
+
+	return e.value, true, nil
+}
+
+func (s *LRU) Set(_ context.Context, key string, value []byte, tags []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.items[key]; ok {
+		s.remove(el)
+	}
+
+	e := &lruEntry{key: key, value: value, tags: tags, expires: s.now().Add(s.ttl)}
+	s.items[key] = s.order.PushFront(e)
+
+	for _, tag := range tags {
+		if s.keys[tag] == nil {
+			s.keys[tag] = map[string]struct{}{}
+		}
+		s.keys[tag][key] = struct{}{}
+	}
+
+	for s.order.Len() > s.size {
+		s.remove(s.order.Back())
+	}
+
+	return nil
+}
+
+func (s *LRU) Invalidate(_ context.Context, tags ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range s.keys[tag] {
+			s.remove(s.items[key])
+		}
+	}
+
+	return nil
+}
+
+// Len возвращает число записей, включая устаревшие, но еще не удаленные
+func (s *LRU) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.order.Len()
+}
+
+func (s *LRU) remove(el *list.Element) {
+	e := el.Value.(*lruEntry)
+
+	s.order.Remove(el)
+	delete(s.items, e.key)
+
+	for _, tag := range e.tags {
+		delete(s.keys[tag], e.key)
+		if len(s.keys[tag]) == 0 {
+			delete(s.keys, tag)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package cache
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// Movies кеширует ответы usecase.Movie
+type Movies struct {
+	uc usecase.Movie
+	c  *Cache
+}
+
+var _ usecase.Movie = (*Movies)(nil)
+
+func NewMovies(uc usecase.Movie, c *Cache) *Movies {
+	return &Movies{uc: uc, c: c}
+}
+
+func (m *Movies) Find(ctx context.Context, id int) (entity.Movie, error) {
+	return cached(ctx, m.c, "movie.find", key(ctx, "movie.find", id), func() (entity.Movie, error) {
+		return m.uc.Find(ctx, id)
+	}, tags[entity.Movie](movieTag(id)))
+}
+
+func (m *Movies) FindByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error) {
+	return cached(ctx, m.c, "movie.find_by_external_id", key(ctx, "movie.find_by_external_id", source, externalID), func() (entity.Movie, error) {
+		return m.uc.FindByExternalID(ctx, source, externalID)
+	}, func(res entity.Movie) []string {
+		return idTags(movieTag, res.Id)
+	})
+}
+
+// FindMovie ищет и по имени актера, поэтому ответ зависит и от актеров, и от состава фильмов
+func (m *Movies) FindMovie(ctx context.Context) ([]entity.Movie, error) {
+	return cached(ctx, m.c, "movie.search", key(ctx, "movie.search"), func() ([]entity.Movie, error) {
+		return m.uc.FindMovie(ctx)
+	}, tags[[]entity.Movie](tagMovies, tagActors, tagCast))
+}
+
+func (m *Movies) List(ctx context.Context) ([]entity.Movie, error) {
+	return cached(ctx, m.c, "movie.list", key(ctx, "movie.list"), func() ([]entity.Movie, error) {
+		return m.uc.List(ctx)
+	}, tags[[]entity.Movie](tagMovies))
+}
+
+func (m *Movies) Next(ctx context.Context) ([]entity.Movie, error) {
+	return cached(ctx, m.c, "movie.next", key(ctx, "movie.next"), func() ([]entity.Movie, error) {
+		return m.uc.Next(ctx)
+	}, tags[[]entity.Movie](tagMovies))
+}
+
+// Новый фильм еще не снят ни в одном составе и меняет только списки
+func (m *Movies) Save(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	res, err := m.uc.Save(ctx, data)
+	if err == nil {
+		m.c.invalidate(ctx, tagMovies)
+	}
+
+	return res, err
+}
+
+func (m *Movies) Update(ctx context.Context, updates entity.Movie) (entity.Movie, error) {
+	res, err := m.uc.Update(ctx, updates)
+	if err == nil {
+		m.c.invalidate(ctx, append(idTags(movieTag, updates.Id), tagMovies)...)
+	}
+
+	return res, err
+}
+
+func (m *Movies) Delete(ctx context.Context, id int) (entity.Movie, error) {
+	res, err := m.uc.Delete(ctx, id)
+	if err == nil {
+		m.c.invalidate(ctx, movieTag(id), castTag(id), tagMovies)
+	}
+
+	return res, err
+}
+
+func (m *Movies) Upsert(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	res, err := m.uc.Upsert(ctx, data)
+	if err == nil {
+		m.c.invalidate(ctx, append(idTags(movieTag, res.Id), tagMovies)...)
+	}
+
+	return res, err
+}
+
+// idTags возвращает теги для заданных id
+func idTags(tag func(int) string, ids ...*int) []string {
+	res := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != nil {
+			res = append(res, tag(*id))
+		}
+	}
+
+	return res
+}
@@ -0,0 +1,202 @@
+package usecase
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"filmoteka/internal/entity"
+)
+
+// Веса признаков при оценке сходства двух актеров
+const (
+	nameWeight        = 0.6
+	birthWeight       = 0.25
+	sharedMovieWeight = 0.05
+	maxSharedMovies   = 3
+
+	// Пары с меньшим сходством имен не рассматриваются
+	minNameSimilarity = 0.8
+	// Актеры сравниваются только внутри группы с одинаковым началом фамилии
+	blockPrefixLen = 2
+)
+
+// Транслитерация кириллицы, чтобы "Иван Петров" и "Ivan Petrov" совпадали
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// normalizeName приводит имя к нижнему регистру латиницей без знаков препинания
+func normalizeName(s string) string {
+	var b strings.Builder
+
+	for _, r := range strings.ToLower(s) {
+		if t, ok := translit[r]; ok {
+			b.WriteString(t)
+			continue
+		}
+
+		if unicode.IsLetter(r) {
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
+
+type dedupActor struct {
+	profile entity.ActorProfile
+	name    string
+	surname string
+	movies  map[int]bool
+}
+
+// findDuplicates возвращает пары актеров со сходством не ниже minScore,
+// отсортированные по убыванию сходства
+func findDuplicates(profiles []entity.ActorProfile, minScore float64) []entity.DuplicateActors {
+	blocks := make(map[string][]dedupActor)
+
+	for _, p := range profiles {
+		a := dedupActor{
+			profile: p,
+			name:    normalizeName(deref(p.Name)),
+			surname: normalizeName(deref(p.Surname)),
+			movies:  make(map[int]bool, len(p.MovieIDs)),
+		}
+
+		for _, id := range p.MovieIDs {
+			a.movies[id] = true
+		}
+
+		key := a.surname
+		if len(key) > blockPrefixLen {
+			key = key[:blockPrefixLen]
+		}
+
+		blocks[key] = append(blocks[key], a)
+	}
+
+	res := []entity.DuplicateActors{}
+
+	for _, block := range blocks {
+		for i := 0; i < len(block); i++ {
+			for j := i + 1; j < len(block); j++ {
+				pair, ok := compareActors(block[i], block[j])
+				if ok && pair.Score >= minScore {
+					res = append(res, pair)
+				}
+			}
+		}
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Score != res[j].Score {
+			return res[i].Score > res[j].Score
+		}
+		return *res[i].Actor.Id < *res[j].Actor.Id
+	})
+
+	return res
+}
+
+func compareActors(a, b dedupActor) (entity.DuplicateActors, bool) {
+	nameScore := similarity(a.name+" "+a.surname, b.name+" "+b.surname)
+	if nameScore < minNameSimilarity {
+		return entity.DuplicateActors{}, false
+	}
+
+	score := nameWeight * nameScore
+
+	// Разные отчества говорят о разных людях, отсутствие отчества - нет
+	if pa, pb := deref(a.profile.Patronymic), deref(b.profile.Patronymic); pa != "" && pb != "" &&
+		similarity(normalizeName(pa), normalizeName(pb)) < minNameSimilarity {
+		return entity.DuplicateActors{}, false
+	}
+
+	// Неполные даты (известен только год) совпадают с любой датой внутри периода
+	if da, db := a.profile.DateOfBirth, b.profile.DateOfBirth; da != nil && db != nil {
+		if !da.Overlaps(*db) {
+			return entity.DuplicateActors{}, false
+		}
+		score += birthWeight
+	}
+
+	shared := 0
+	for id := range a.movies {
+		if b.movies[id] {
+			shared++
+		}
+	}
+
+	score += sharedMovieWeight * float64(minInt(shared, maxSharedMovies))
+
+	// Первым в паре идет актер с меньшим id
+	first, second := a.profile.Actor, b.profile.Actor
+	if *second.Id < *first.Id {
+		first, second = second, first
+	}
+
+	return entity.DuplicateActors{
+		Actor:        first,
+		Duplicate:    second,
+		Score:        float64(int(score*100+0.5)) / 100,
+		SharedMovies: shared,
+	}, true
+}
+
+// similarity - нормированное расстояние Левенштейна: 1 для одинаковых строк, 0 для совсем разных
+func similarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) == 0 && len(rb) == 0 {
+		return 1
+	}
+
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, minInt(cur[j-1]+1, prev[j-1]+cost))
+		}
+		prev, cur = cur, prev
+	}
+
+	return 1 - float64(prev[len(rb)])/float64(maxInt(len(ra), len(rb)))
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+
+	return *s
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,197 @@
+package usecase
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"filmoteka/internal/entity"
+)
+
+func TestNormalizeName(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"Иван", "ivan"},
+		{"IVAN", "ivan"},
+		{"Щукин", "shchukin"},
+		{"Ёлкин", "elkin"},
+		{"Хрущёва", "khrushcheva"},
+		{"Д'Артаньян", "dartanyan"},
+		{"Jean-Luc", "jeanluc"},
+		{"Müller", "müller"},
+		{"  ", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeName(tt.in); got != tt.want {
+			t.Errorf("normalizeName(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"", "", 1},
+		{"ivan", "ivan", 1},
+		{"ivan", "", 0},
+		{"abc", "xyz", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+		// Расстояние считается в символах, а не в байтах
+		{"ёж", "еж", 0.5},
+		{"oleg orlov", "olga orlov", 0.8},
+	}
+
+	for _, tt := range tests {
+		if got := similarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := similarity(tt.b, tt.a); got != tt.want {
+			t.Errorf("similarity(%q, %q) = %v, want %v", tt.b, tt.a, got, tt.want)
+		}
+	}
+}
+
+// profile создает актера. birth - дата в формате ParseDate или пустая строка.
+func profile(id int, name, surname, patronymic, birth string, movies ...int) entity.ActorProfile {
+	p := entity.ActorProfile{MovieIDs: movies}
+	p.Id = &id
+	p.Name = &name
+	p.Surname = &surname
+
+	if patronymic != "" {
+		p.Patronymic = &patronymic
+	}
+
+	if birth != "" {
+		d, err := entity.ParseDate(birth)
+		if err != nil {
+			panic(err)
+		}
+		p.DateOfBirth = &d
+	}
+
+	return p
+}
+
+func TestFindDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles []entity.ActorProfile
+		minScore float64
+		// Пары в виде actor-duplicate:score/shared_movies
+		want []string
+	}{
+		{
+			name:     "cyrillic and latin",
+			profiles: []entity.ActorProfile{profile(1, "Иван", "Петров", "", ""), profile(2, "Ivan", "Petrov", "", "")},
+			want:     []string{"1-2:0.60/0"},
+		},
+		{
+			name: "same birth date and shared movies",
+			profiles: []entity.ActorProfile{
+				profile(1, "Сергей", "Бодров", "", "1971-12-27", 1, 2),
+				profile(2, "Sergey", "Bodrov", "", "1971-12-27", 2, 1, 3),
+			},
+			want: []string{"1-2:0.95/2"},
+		},
+		{
+			name: "shared movies are capped",
+			profiles: []entity.ActorProfile{
+				profile(1, "Иван", "Петров", "", "", 1, 2, 3, 4, 5),
+				profile(2, "Иван", "Петров", "", "", 1, 2, 3, 4, 5),
+			},
+			want: []string{"1-2:0.75/5"},
+		},
+		{
+			name: "year overlaps full date",
+			profiles: []entity.ActorProfile{
+				profile(1, "Иван", "Петров", "", "1970"),
+				profile(2, "Иван", "Петров", "", "1970-03-15"),
+			},
+			want: []string{"1-2:0.85/0"},
+		},
+		{
+			name: "different birth dates",
+			profiles: []entity.ActorProfile{
+				profile(1, "Иван", "Петров", "", "1970-03-15"),
+				profile(2, "Иван", "Петров", "", "1971"),
+			},
+		},
+		{
+			name: "different patronymics",
+			profiles: []entity.ActorProfile{
+				profile(1, "Иван", "Петров", "Сергеевич", ""),
+				profile(2, "Иван", "Петров", "Николаевич", ""),
+			},
+		},
+		{
+			name: "missing patronymic",
+			profiles: []entity.ActorProfile{
+				profile(1, "Иван", "Петров", "Сергеевич", ""),
+				profile(2, "Ivan", "Petrov", "", ""),
+			},
+			want: []string{"1-2:0.60/0"},
+		},
+		{
+			name:     "different names",
+			profiles: []entity.ActorProfile{profile(1, "Иван", "Петров", "", ""), profile(2, "Олег", "Петров", "", "")},
+		},
+		{
+			name:     "name similarity on threshold",
+			profiles: []entity.ActorProfile{profile(1, "Oleg", "Orlov", "", ""), profile(2, "Olga", "Orlov", "", "")},
+			want:     []string{"1-2:0.48/0"},
+		},
+		{
+			name:     "name similarity below threshold",
+			profiles: []entity.ActorProfile{profile(1, "Oleg", "Orlov", "", ""), profile(2, "Olya", "Orlova", "", "")},
+		},
+		{
+			name:     "score on min score",
+			profiles: []entity.ActorProfile{profile(1, "Иван", "Петров", "", ""), profile(2, "Ivan", "Petrov", "", "")},
+			minScore: 0.6,
+			want:     []string{"1-2:0.60/0"},
+		},
+		{
+			name:     "score below min score",
+			profiles: []entity.ActorProfile{profile(1, "Иван", "Петров", "", ""), profile(2, "Ivan", "Petrov", "", "")},
+			minScore: 0.61,
+		},
+		{
+			// Похожие имена с разным началом фамилии попадают в разные группы и не сравниваются
+			name:     "different surname blocks",
+			profiles: []entity.ActorProfile{profile(1, "Ivan", "Petrov", "", ""), profile(2, "Ivan", "Fetrov", "", "")},
+		},
+		{
+			name:     "short surnames",
+			profiles: []entity.ActorProfile{profile(1, "Ли", "У", "", ""), profile(2, "Li", "U", "", "")},
+			want:     []string{"1-2:0.60/0"},
+		},
+		{
+			name: "sorted by score, first actor has smaller id",
+			profiles: []entity.ActorProfile{
+				profile(5, "Ivan", "Petrov", "", ""),
+				profile(3, "Иван", "Петров", "", ""),
+				profile(4, "Анна", "Смирнова", "", "1985-07"),
+				profile(2, "Anna", "Smirnova", "", "1985-07-01"),
+			},
+			want: []string{"2-4:0.85/0", "3-5:0.60/0"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, d := range findDuplicates(tt.profiles, tt.minScore) {
+				got = append(got, fmt.Sprintf("%d-%d:%.2f/%d", *d.Actor.Id, *d.Duplicate.Id, d.Score, d.SharedMovies))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("duplicates = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/lib/pq"
+
+	"filmoteka/pkg/validator"
+)
+
+// ErrorKind - вид ошибки предметной области. По нему контроллеры выбирают статус ответа.
+type ErrorKind int
+
+const (
+	KindInternal ErrorKind = iota
+	KindNotFound
+	KindConflict
+	KindValidation
+	KindUnauthorized
+	KindUnavailable
+)
+
+// Error - ошибка предметной области.
+// Code - стабильный машиночитаемый код ошибки (например, actor_not_found),
+// Message - описание ошибки, которое можно показать клиенту.
+// Fields - ошибки отдельных полей запроса, если ошибка вызвана неверными данными.
+// Err - исходная ошибка, клиенту не показывается.
+type Error struct {
+	Kind    ErrorKind
+	Code    string
+	Message string
+	Fields  []validator.FieldError
+	Err     error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Message
+	}
+
+	return e.Message + ": " + e.Err.Error()
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Wrap сохраняет исходную ошибку
+func (e *Error) Wrap(err error) *Error {
+	e.Err = err
+	return e
+}
+
+func NotFound(code, message string) *Error {
+	return &Error{Kind: KindNotFound, Code: code, Message: message}
+}
+
+func Conflict(code, message string) *Error {
+	return &Error{Kind: KindConflict, Code: code, Message: message}
+}
+
+func Validation(code, message string) *Error {
+	return &Error{Kind: KindValidation, Code: code, Message: message}
+}
+
+func Unauthorized(code, message string) *Error {
+	return &Error{Kind: KindUnauthorized, Code: code, Message: message}
+}
+
+func Unavailable(code, message string) *Error {
+	return &Error{Kind: KindUnavailable, Code: code, Message: message}
+}
+
+func Internal(err error) *Error {
+	return &Error{Kind: KindInternal, Code: "internal_error", Message: "internal server error", Err: err}
+}
+
+// AsError возвращает ошибку предметной области из цепочки err.
+// Неизвестные ошибки считаются внутренними.
+func AsError(err error) *Error {
+	var e *Error
+	if errors.As(err, &e) {
+		return e
+	}
+
+	return Internal(err)
+}
+
+// Ошибки хранилищ, которые не сообщают кодов ошибок PostgreSQL (например, хранилища в памяти).
+// repoError преобразует их так же, как нарушения соответствующих ограничений PostgreSQL.
+var (
+	// ErrAlreadyExists - нарушено ограничение уникальности
+	ErrAlreadyExists = errors.New("record already exists")
+	// ErrReferenced - на удаляемую запись ссылаются другие записи
+	ErrReferenced = errors.New("record is referenced by other records")
+	// ErrInvalidData - данные не подходят под ограничения хранилища (длина строки, диапазон значений и т.п.)
+	ErrInvalidData = errors.New("record data violates storage constraints")
+)
+
+// Классы и коды ошибок PostgreSQL: https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pqUniqueViolation     = "23505"
+	pqForeignKeyViolation = "23503"
+	pqNotNullViolation    = "23502"
+	pqCheckViolation      = "23514"
+	pqClassDataException  = "22"
+	pqClassConnection     = "08"
+	pqClassResources      = "53"
+	pqClassOperator       = "57"
+)
+
+// repoError преобразует ошибку репозитория в ошибку предметной области.
+// name - название сущности, из него составляется код ошибки (actor_not_found и т.п.).
+func repoError(name, method string, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	// Ошибка уже преобразована
+	var e *Error
+	if errors.As(err, &e) {
+		return err
+	}
+
+	wrapped := fmt.Errorf("%s: repo.%s returned error: %w", op, method, err)
+	title := strings.ReplaceAll(name, "_", " ")
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound(name+"_not_found", title+" was NOT found").Wrap(wrapped)
+	}
+
+	var netErr net.Error
+	if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr) {
+		return Unavailable("database_unavailable", "database is unavailable").Wrap(wrapped)
+	}
+
+	var code pq.ErrorCode
+	var class pq.ErrorClass
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		code, class = pqErr.Code, pqErr.Code.Class()
+	}
+
+	switch {
+	case errors.Is(err, ErrAlreadyExists), code == pqUniqueViolation:
+		return Conflict(name+"_already_exists", title+" already exists").Wrap(wrapped)
+	case errors.Is(err, ErrReferenced), code == pqForeignKeyViolation:
+		return Conflict(name+"_referenced", title+" is referenced by other records").Wrap(wrapped)
+	case errors.Is(err, ErrInvalidData), code == pqNotNullViolation, code == pqCheckViolation,
+		class == pqClassDataException:
+		return Validation("invalid_"+name, "provided "+title+" data is invalid").Wrap(wrapped)
+	case class == pqClassConnection, class == pqClassResources, class == pqClassOperator:
+		return Unavailable("database_unavailable", "database is unavailable").Wrap(wrapped)
+	}
+
+	return Internal(wrapped)
+}
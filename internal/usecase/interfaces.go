@@ -16,8 +16,12 @@ type (
 		Update(ctx context.Context, updates entity.Actor) (entity.Actor, error)
 		Delete(ctx context.Context, id int) (entity.Actor, error)
 		Find(ctx context.Context, id int) (entity.Actor, error)
+		FindByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error)
+		Upsert(ctx context.Context, data entity.ActorData) (entity.Actor, error)
 		List(ctx context.Context) ([]entity.Actor, error)
 		Next(ctx context.Context) ([]entity.Actor, error)
+		Duplicates(ctx context.Context, minScore float64) ([]entity.DuplicateActors, error)
+		Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error)
 	}
 
 	Movie interface {
@@ -25,6 +29,8 @@ type (
 		Update(ctx context.Context, updates entity.Movie) (entity.Movie, error)
 		Delete(ctx context.Context, id int) (entity.Movie, error)
 		Find(ctx context.Context, id int) (entity.Movie, error)
+		FindByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error)
+		Upsert(ctx context.Context, data entity.MovieData) (entity.Movie, error)
 		FindMovie(ctx context.Context) ([]entity.Movie, error)
 		List(ctx context.Context) ([]entity.Movie, error)
 		Next(ctx context.Context) ([]entity.Movie, error)
@@ -33,10 +39,12 @@ type (
 	ActorMovie interface {
 		Save(ctx context.Context, data entity.ActorMovie) error
 		// Update(ctx context.Context, updates entity.ActorMovie) (entity.ActorMovie, error)
-		// Delete(ctx context.Context, id int) (entity.ActorMovie, error)
+		Delete(ctx context.Context, data entity.ActorMovie) error
 		// Find(ctx context.Context, id int) (entity.ActorMovie, error)
 		List(ctx context.Context) ([]entity.ActorMovieData, error)
 		// Next(ctx context.Context) ([]entity.ActorMovie, error)
+		Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error)
+		Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error)
 	}
 
 	ActorsRepo interface {
@@ -44,8 +52,13 @@ type (
 		Update(ctx context.Context, updates entity.Actor) (entity.Actor, error)
 		Delete(ctx context.Context, id int) (entity.Actor, error)
 		Get(ctx context.Context, id int) (entity.Actor, error)
+		GetByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error)
+		MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error)
 		List(ctx context.Context) ([]entity.Actor, error)
 		Next(ctx context.Context) ([]entity.Actor, error)
+		Profiles(ctx context.Context) ([]entity.ActorProfile, error)
+		Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error)
+		Count(ctx context.Context) (int, error)
 	}
 
 	MoviesRepo interface {
@@ -53,18 +66,24 @@ type (
 		Update(ctx context.Context, updates entity.Movie) (entity.Movie, error)
 		Delete(ctx context.Context, id int) (entity.Movie, error)
 		Get(ctx context.Context, id int) (entity.Movie, error)
+		GetByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error)
+		MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error)
 		GetMovie(ctx context.Context) ([]entity.Movie, error)
 		List(ctx context.Context) ([]entity.Movie, error)
 		Next(ctx context.Context) ([]entity.Movie, error)
+		Count(ctx context.Context) (int, error)
 	}
 
 	ActorsMoviesRepo interface {
 		Save(ctx context.Context, data entity.ActorMovie) error
 		// Update(ctx context.Context, updates entity.ActorMovie) (entity.ActorMovie, error)
-		// Delete(ctx context.Context, id int) (entity.ActorMovie, error)
+		Delete(ctx context.Context, data entity.ActorMovie) error
 		// Get(ctx context.Context, id int) (entity.ActorMovie, error)
 		List(ctx context.Context) ([]entity.ActorMovieData, error)
 		// Next(ctx context.Context) ([]entity.ActorMovie, error)
+		Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error)
+		Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error)
+		Count(ctx context.Context) (int, error)
 	}
 
 	Logger interface {
@@ -72,6 +91,10 @@ type (
 		Info(msg string, args ...any)
 		Warn(msg string, args ...any)
 		Error(msg string, args ...any)
+		DebugContext(ctx context.Context, msg string, args ...any)
+		InfoContext(ctx context.Context, msg string, args ...any)
+		WarnContext(ctx context.Context, msg string, args ...any)
+		ErrorContext(ctx context.Context, msg string, args ...any)
 		Err(err error) slog.Attr
 	}
 )
@@ -4,15 +4,13 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strings"
-	"time"
 
-	"filmoteka/internal/controller/middleware/filter"
 	"filmoteka/internal/controller/middleware/pagination"
 	"filmoteka/internal/entity"
 
 	"github.com/Masterminds/squirrel"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 const op = "internal.usecase.repo"
@@ -26,7 +24,9 @@ func NewActorsRepo(db *sql.DB) *ActorsRepo {
 	return &ActorsRepo{db: sqlx.NewDb(db, "postgres")}
 }
 
-const ActorQueryFind = `SELECT *, TO_CHAR(date_of_birth, 'DD.MM.YYYY') AS date_of_birth FROM actors WHERE  id = $1`
+// Актер, объединенный с другим, доступен по старому id через таблицу actor_redirects
+const ActorQueryFind = `SELECT ` + actorColumns + ` FROM actors
+					WHERE id = COALESCE((SELECT new_id FROM actor_redirects WHERE old_id = $1), $1)`
 
 func (r *ActorsRepo) Get(ctx context.Context, id int) (entity.Actor, error) {
 
@@ -37,31 +37,68 @@ func (r *ActorsRepo) Get(ctx context.Context, id int) (entity.Actor, error) {
 		return entity.Actor{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
+	res.ExternalIDs, err = actorsExternalIDs.get(ctx, r.db, *res.Id)
+
+	if err != nil {
+		return entity.Actor{}, err
+	}
+
 	return res, nil
 }
 
-const ActorQuerySave = `INSERT INTO actors(name, surname, patronymic, gender, date_of_birth)
-					VALUES($1, $2, $3, $4, TO_DATE($5, 'DD.MM.YYYY'))
+// GetByExternalID возвращает актера по его идентификатору во внешнем каталоге
+func (r *ActorsRepo) GetByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error) {
+
+	id, err := actorsExternalIDs.find(ctx, r.db, source, externalID)
+
+	if err != nil {
+		return entity.Actor{}, err
+	}
+
+	return r.Get(ctx, id)
+}
+
+// MatchExternalIDs возвращает id актера, которому принадлежит любой из внешних идентификаторов
+func (r *ActorsRepo) MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error) {
+	return actorsExternalIDs.match(ctx, r.db, ids)
+}
+
+const ActorQuerySave = `INSERT INTO actors(name, surname, patronymic, gender, date_of_birth, date_of_birth_precision)
+					VALUES($1, $2, $3, $4, $5, $6)
 					ON CONFLICT (name, surname) DO NOTHING
     				RETURNING id`
 
 func (r *ActorsRepo) Save(ctx context.Context, data entity.ActorData) (int, error) {
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
 	var res int
 
-	err := r.db.GetContext(ctx, &res, ActorQuerySave,
+	err = tx.GetContext(ctx, &res, ActorQuerySave,
 		data.Name,
 		data.Surname,
 		data.Patronymic,
 		data.Gender,
 		data.DateOfBirth,
+		precision(data.DateOfBirth),
 	)
 
 	if err != nil {
 		return res, err
 	}
 
-	return res, nil
+	// Сохраняем идентификаторы актера во внешних каталогах
+	err = actorsExternalIDs.save(ctx, tx, res, data.ExternalIDs)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return res, tx.Commit()
 }
 
 func (r *ActorsRepo) Update(ctx context.Context, updates entity.Actor) (entity.Actor, error) {
@@ -78,28 +115,54 @@ func (r *ActorsRepo) Update(ctx context.Context, updates entity.Actor) (entity.A
 		return entity.Actor{}, fmt.Errorf("%s: Error: %w", op, err)
 	}
 
-	qb = qb.SetMap(data)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Actor{}, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
 
-	// Составим выражение для оператора SQL Where
-	stmt := fmt.Sprintf(" WHERE id = %d", *updates.Id)
+	var res entity.Actor
 
-	sql, i, err := qb.ToSql()
+	// Если изменяются только внешние идентификаторы, таблицу actors не обновляем
+	if len(data) == 0 {
+		err = tx.GetContext(ctx, &res, ActorQueryFind, *updates.Id)
+	} else {
+		qb = qb.SetMap(data)
+
+		// Составим выражение для оператора SQL Where
+		stmt := fmt.Sprintf(" WHERE id = %d", *updates.Id)
 
-	sql = sql + stmt + " RETURNING id, name, surname, patronymic, gender, TO_CHAR(date_of_birth, 'DD.MM.YYYY') AS date_of_birth"
+		var sql string
+		var i []interface{}
+
+		sql, i, err = qb.ToSql()
+
+		sql = sql + stmt + " RETURNING " + actorColumns
+
+		if err != nil {
+			return entity.Actor{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+		}
+
+		err = tx.GetContext(ctx, &res, sql, i...)
+	}
 
 	if err != nil {
-		return entity.Actor{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+		return entity.Actor{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
-	var res entity.Actor
+	err = actorsExternalIDs.save(ctx, tx, *res.Id, updates.ExternalIDs)
 
-	err = r.db.GetContext(ctx, &res, sql, i...)
+	if err != nil {
+		return entity.Actor{}, err
+	}
+
+	res.ExternalIDs, err = actorsExternalIDs.get(ctx, tx, *res.Id)
 
 	if err != nil {
-		return entity.Actor{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+		return entity.Actor{}, err
 	}
 
-	return res, nil
+	return res, tx.Commit()
 }
 
 func (r *ActorsRepo) Delete(ctx context.Context, id int) (entity.Actor, error) {
@@ -114,7 +177,7 @@ func (r *ActorsRepo) Delete(ctx context.Context, id int) (entity.Actor, error) {
 
 	sql, i, err := qb.ToSql()
 
-	sql = sql + stmt + " RETURNING id, name, surname, patronymic, gender, TO_CHAR(date_of_birth, 'DD.MM.YYYY') AS date_of_birth"
+	sql = sql + stmt + " RETURNING " + actorColumns
 
 	if err != nil {
 		return entity.Actor{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
@@ -132,33 +195,35 @@ func (r *ActorsRepo) Delete(ctx context.Context, id int) (entity.Actor, error) {
 }
 
 func (r *ActorsRepo) List(ctx context.Context) ([]entity.Actor, error) {
+	return r.list(ctx, squirrel.And{})
+}
 
-	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+func (r *ActorsRepo) Next(ctx context.Context) ([]entity.Actor, error) {
 
-	// Подготавливаем SQL запрос
-	qb := psql.Select("id, name, surname, patronymic, gender, TO_CHAR(date_of_birth, 'DD.MM.YYYY') AS date_of_birth").From("actors")
+	// Условие пагинации
+	personID := ctx.Value(pagination.NextPersonID).(int)
 
-	// Составим выражение для оператора SQL Where ... AND ...
-	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
+	return r.list(ctx, squirrel.GtOrEq{"actors.id": personID})
+}
 
-	stmt := []string{}
-	for k, v := range filter_options {
-		for _, val := range v {
-			stmt = append(stmt, fmt.Sprintf("%s = '%s'", k, val))
-		}
-	}
+// list возвращает первую страницу актеров, подходящих под параметры фильтрации и условие cond
+func (r *ActorsRepo) list(ctx context.Context, cond squirrel.Sqlizer) ([]entity.Actor, error) {
 
-	// Решаем, использовать оператор WHERE или нет
-	if len(stmt) != 0 {
-		qb = qb.Where(strings.Join(stmt, " AND "))
-	}
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
-	// Используем оператор ORDER BY
-	qb = qb.OrderBy("id ASC")
+	// Составим выражение для оператора SQL Where ... AND ...
+	filters, err := where(ctx, actorFilters)
 
-	qb = qb.Limit(pageSize)
+	if err != nil {
+		return []entity.Actor{}, err
+	}
 
-	sql, i, err := qb.ToSql()
+	sql, i, err := psql.Select(actorColumns).
+		From("actors").
+		Where(append(filters, cond)).
+		OrderBy("actors.id ASC").
+		Limit(pageSize).
+		ToSql()
 
 	if err != nil {
 		return []entity.Actor{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
@@ -171,51 +236,20 @@ func (r *ActorsRepo) List(ctx context.Context) ([]entity.Actor, error) {
 		return []entity.Actor{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
-	return res, nil
-}
-
-func (r *ActorsRepo) Next(ctx context.Context) ([]entity.Actor, error) {
-
-	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
-
-	// Подготавливаем SQL запрос
-	qb := psql.Select("id, name, surname, patronymic, gender, TO_CHAR(date_of_birth, 'DD.MM.YYYY') AS date_of_birth").From("actors")
-
-	// Составим выражение для оператора SQL Where ... AND ...
-	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
-
-	stmt := []string{}
-	for k, v := range filter_options {
-		for _, val := range v {
-			stmt = append(stmt, fmt.Sprintf("%s = '%s'", k, val))
-		}
+	// Дополним страницу внешними идентификаторами
+	ids := make([]int, 0, len(res))
+	for _, a := range res {
+		ids = append(ids, *a.Id)
 	}
 
-	// Решаем, использовать оператор WHERE или нет
-	if len(stmt) != 0 {
-		qb = qb.Where(strings.Join(stmt, " AND "))
-	}
-
-	// Условие пагинации
-	personID := ctx.Value(pagination.NextPersonID).(int)
-	qb = qb.Where(fmt.Sprintf("id >= %d", personID))
-
-	// Используем оператор ORDER BY
-	qb = qb.OrderBy("id ASC")
-
-	qb = qb.Limit(pageSize)
-
-	sql, i, err := qb.ToSql()
+	externalIDs, err := actorsExternalIDs.getMany(ctx, r.db, ids)
 
 	if err != nil {
-		return []entity.Actor{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+		return []entity.Actor{}, err
 	}
 
-	res := []entity.Actor{}
-	err = r.db.SelectContext(ctx, &res, sql, i...)
-
-	if err != nil {
-		return []entity.Actor{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	for i := range res {
+		res[i].ExternalIDs = externalIDs[*res[i].Id]
 	}
 
 	return res, nil
@@ -241,21 +275,153 @@ func getMapActor(updates entity.Actor) (map[string]interface{}, error) {
 	}
 
 	if val := updates.ActorData.DateOfBirth; val != nil {
-		layout := "02.01.2006" // шаблон формата даты в строке
+		res["date_of_birth"] = *val
+		res["date_of_birth_precision"] = val.Precision()
+	}
 
-		// распарсим строку в формат даты
-		date, err := time.Parse(layout, *val)
-		if err != nil {
-			return res, fmt.Errorf("%s: Ошибка при парсинге даты", err)
-		}
+	if len(res) == 0 && len(updates.ExternalIDs) == 0 {
+		return res, fmt.Errorf("%s: Data for update operation were NOT specified", op)
+	}
 
-		// Преобразовать дату в формат PostgreSQL
-		res["date_of_birth"] = date.Format("2006-01-02")
+	return res, nil
+}
+
+const ActorQueryProfiles = `SELECT
+			actors.id, actors.name, actors.surname, actors.patronymic, actors.gender,
+			` + actorDateOfBirth + `,
+			COALESCE(ARRAY_AGG(actors_movies.movie_id) FILTER (WHERE actors_movies.movie_id IS NOT NULL), '{}') AS movie_ids
+			FROM actors
+			LEFT JOIN actors_movies ON actors.id = actors_movies.actor_id
+			GROUP BY actors.id
+			ORDER BY actors.id`
+
+type actorProfileRow struct {
+	entity.Actor
+	MovieIDs pq.Int64Array `db:"movie_ids"`
+}
+
+// Profiles возвращает всех актеров вместе с id фильмов, в которых они снимались
+func (r *ActorsRepo) Profiles(ctx context.Context) ([]entity.ActorProfile, error) {
+
+	var rows []actorProfileRow
+	err := r.db.SelectContext(ctx, &rows, ActorQueryProfiles)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
-	if len(res) == 0 {
-		return res, fmt.Errorf("%s: Data for update operation were NOT specified", op)
+	res := make([]entity.ActorProfile, 0, len(rows))
+	for _, row := range rows {
+		profile := entity.ActorProfile{Actor: row.Actor, MovieIDs: make([]int, 0, len(row.MovieIDs))}
+		for _, id := range row.MovieIDs {
+			profile.MovieIDs = append(profile.MovieIDs, int(id))
+		}
+		res = append(res, profile)
 	}
 
 	return res, nil
 }
+
+const (
+	ActorMergeQueryLock = `SELECT COUNT(*) FROM (SELECT id FROM actors WHERE id IN ($1, $2) FOR UPDATE) locked`
+
+	// Пустые поля сохраняемого актера заполняются данными объединяемого
+	ActorMergeQueryFill = `UPDATE actors
+					SET patronymic = COALESCE(actors.patronymic, source.patronymic),
+						gender = COALESCE(actors.gender, source.gender),
+						date_of_birth = COALESCE(actors.date_of_birth, source.date_of_birth),
+						date_of_birth_precision = CASE WHEN actors.date_of_birth IS NULL
+							THEN source.date_of_birth_precision ELSE actors.date_of_birth_precision END
+					FROM actors source
+					WHERE actors.id = $2 AND source.id = $1`
+
+	ActorMergeQueryMoveMovies = `INSERT INTO actors_movies (movie_id, actor_id)
+					SELECT movie_id, $2 FROM actors_movies WHERE actor_id = $1
+					ON CONFLICT DO NOTHING`
+
+	ActorMergeQueryDeleteMovies = `DELETE FROM actors_movies WHERE actor_id = $1`
+
+	// Идентификаторы каталогов, которых нет у сохраняемого актера, переносятся
+	ActorMergeQueryMoveExternalIDs = `UPDATE actors_external_ids SET actor_id = $2
+					WHERE actor_id = $1
+					AND source NOT IN (SELECT source FROM actors_external_ids WHERE actor_id = $2)`
+
+	// Ссылки на объединяемого актера перенаправляются на сохраняемого
+	ActorMergeQueryMoveRedirects = `UPDATE actor_redirects SET new_id = $2 WHERE new_id = $1`
+
+	ActorMergeQueryRedirect = `INSERT INTO actor_redirects (old_id, new_id) VALUES ($1, $2)`
+
+	ActorMergeQueryDelete = `DELETE FROM actors WHERE id = $1`
+)
+
+// Merge переносит все связи с фильмами актера sourceID на актера targetID и удаляет актера sourceID.
+// Старый id продолжает указывать на актера targetID.
+func (r *ActorsRepo) Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error) {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Actor{}, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Проверяем наличие обоих актеров и блокируем их до конца транзакции
+	var count int
+	err = tx.GetContext(ctx, &count, ActorMergeQueryLock, sourceID, targetID)
+
+	if err != nil {
+		return entity.Actor{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	if count != 2 {
+		return entity.Actor{}, fmt.Errorf("%s: actors to merge were NOT found: %w", op, sql.ErrNoRows)
+	}
+
+	both := []interface{}{sourceID, targetID}
+	source := []interface{}{sourceID}
+
+	steps := []struct {
+		query string
+		args  []interface{}
+	}{
+		{ActorMergeQueryFill, both},
+		{ActorMergeQueryMoveMovies, both},
+		{ActorMergeQueryDeleteMovies, source},
+		{ActorMergeQueryMoveExternalIDs, both},
+		{ActorMergeQueryMoveRedirects, both},
+		{ActorMergeQueryRedirect, both},
+		{ActorMergeQueryDelete, source},
+	}
+
+	for _, step := range steps {
+		if _, err = tx.ExecContext(ctx, step.query, step.args...); err != nil {
+			return entity.Actor{}, fmt.Errorf("%s: DB method 'Exec' returned error: %w", op, err)
+		}
+	}
+
+	var res entity.Actor
+	err = tx.GetContext(ctx, &res, ActorQueryFind, targetID)
+
+	if err != nil {
+		return entity.Actor{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	res.ExternalIDs, err = actorsExternalIDs.get(ctx, tx, targetID)
+
+	if err != nil {
+		return entity.Actor{}, err
+	}
+
+	return res, tx.Commit()
+}
+
+// Count возвращает число актеров
+func (r *ActorsRepo) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM actors`)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: DB returned error: %w", op, err)
+	}
+
+	return count, nil
+}
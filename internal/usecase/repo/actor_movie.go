@@ -7,6 +7,7 @@ import (
 
 	"filmoteka/internal/entity"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 )
 
 type ActorsMoviesRepo struct {
@@ -35,7 +36,7 @@ func (r *ActorsMoviesRepo) Save(ctx context.Context, data entity.ActorMovie) err
 	}
 
 	if count1 == 0 {
-		return fmt.Errorf("actor_id was NOT found in database table 'actors'")
+		return fmt.Errorf("%s: actor_id was NOT found in database table 'actors': %w", op, sql.ErrNoRows)
 	}
 
 	//Проверяем наличие фильма в таблице movies
@@ -52,7 +53,7 @@ func (r *ActorsMoviesRepo) Save(ctx context.Context, data entity.ActorMovie) err
 	}
 
 	if count2 == 0 {
-		return fmt.Errorf("movie_id was NOT found in database table 'movies'")
+		return fmt.Errorf("%s: movie_id was NOT found in database table 'movies': %w", op, sql.ErrNoRows)
 	}
 
 	// Вносим данные в базу данных в таблицу movie_actors
@@ -68,6 +69,32 @@ func (r *ActorsMoviesRepo) Save(ctx context.Context, data entity.ActorMovie) err
 	return nil
 }
 
+const ActorMovieQueryDelete = `DELETE FROM actors_movies WHERE actor_id = $1 AND movie_id = $2`
+
+func (r *ActorsMoviesRepo) Delete(ctx context.Context, data entity.ActorMovie) error {
+
+	res, err := r.db.ExecContext(ctx, ActorMovieQueryDelete,
+		data.Actor_id,
+		data.Movie_id,
+	)
+
+	if err != nil {
+		return fmt.Errorf("%s: DB method 'Exec' returned error: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+
+	if err != nil {
+		return fmt.Errorf("%s: failed to get the number of deleted rows: %w", op, err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("%s: actor is NOT in the cast of the movie: %w", op, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
 const ListActorsAndMoviesQuery = `SELECT 
 			actors.id AS actor_id,
 			actors.name AS actor_name,
@@ -90,3 +117,71 @@ func (r *ActorsMoviesRepo) List(ctx context.Context) ([]entity.ActorMovieData, e
 
 	return data, nil
 }
+
+const ActorMovieQueryCast = `SELECT actors_movies.movie_id AS owner_id, ` + actorColumns + `
+			FROM actors_movies
+			JOIN actors ON actors.id = actors_movies.actor_id
+			WHERE actors_movies.movie_id = ANY($1)
+			ORDER BY actors.id`
+
+type castRow struct {
+	OwnerID int `db:"owner_id"`
+	entity.Actor
+}
+
+// Cast возвращает актеров нескольких фильмов одним запросом. Ключ - id фильма.
+func (r *ActorsMoviesRepo) Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+	var rows []castRow
+	err := r.db.SelectContext(ctx, &rows, ActorMovieQueryCast, pq.Array(movieIDs))
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	res := make(map[int][]entity.Actor, len(movieIDs))
+	for _, row := range rows {
+		res[row.OwnerID] = append(res[row.OwnerID], row.Actor)
+	}
+
+	return res, nil
+}
+
+const ActorMovieQueryFilmography = `SELECT actors_movies.actor_id AS owner_id, ` + movieColumns + `
+			FROM actors_movies
+			JOIN movies ON movies.id = actors_movies.movie_id
+			WHERE actors_movies.actor_id = ANY($1)
+			ORDER BY movies.release_date, movies.id`
+
+type filmographyRow struct {
+	OwnerID int `db:"owner_id"`
+	entity.Movie
+}
+
+// Filmography возвращает фильмы нескольких актеров одним запросом. Ключ - id актера.
+func (r *ActorsMoviesRepo) Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+	var rows []filmographyRow
+	err := r.db.SelectContext(ctx, &rows, ActorMovieQueryFilmography, pq.Array(actorIDs))
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	res := make(map[int][]entity.Movie, len(actorIDs))
+	for _, row := range rows {
+		res[row.OwnerID] = append(res[row.OwnerID], row.Movie)
+	}
+
+	return res, nil
+}
+
+// Count возвращает число связей актеров с фильмами
+func (r *ActorsMoviesRepo) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM actors_movies`)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: DB returned error: %w", op, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,41 @@
+package repo
+
+import "filmoteka/internal/entity"
+
+// Даты хранятся в БД как первый день периода вместе с точностью (day, month, year)
+// и выбираются строкой ISO 8601 той же точности: 2006-01-02, 2006-01 или 2006.
+const (
+	actorDateOfBirth = `CASE date_of_birth_precision
+						WHEN 'year' THEN TO_CHAR(date_of_birth, 'YYYY')
+						WHEN 'month' THEN TO_CHAR(date_of_birth, 'YYYY-MM')
+						ELSE TO_CHAR(date_of_birth, 'YYYY-MM-DD')
+					END AS date_of_birth`
+
+	movieReleaseDate = `CASE release_date_precision
+						WHEN 'year' THEN TO_CHAR(release_date, 'YYYY')
+						WHEN 'month' THEN TO_CHAR(release_date, 'YYYY-MM')
+						ELSE TO_CHAR(release_date, 'YYYY-MM-DD')
+					END AS release_date`
+
+	actorColumns = "id, name, surname, patronymic, gender, " + actorDateOfBirth
+	movieColumns = "id, title, description, " + movieReleaseDate + ", rating"
+)
+
+// precision возвращает точность даты для сохранения в БД
+func precision(d *entity.Date) entity.DatePrecision {
+	if d == nil {
+		return entity.PrecisionDay
+	}
+
+	return d.Precision()
+}
+
+// filterDate приводит дату из параметров фильтрации к ISO 8601, который PostgreSQL
+// разбирает одинаково при любом значении DateStyle
+func filterDate(val string) string {
+	if d, err := entity.ParseDate(val); err == nil {
+		return d.Time().Format("2006-01-02")
+	}
+
+	return val
+}
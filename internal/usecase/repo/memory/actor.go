@@ -0,0 +1,332 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+type ActorsRepo struct {
+	s *Storage
+}
+
+func NewActorsRepo(s *Storage) *ActorsRepo {
+	return &ActorsRepo{s: s}
+}
+
+// Get возвращает актера. Актер, объединенный с другим, доступен по старому id.
+func (r *ActorsRepo) Get(ctx context.Context, id int) (entity.Actor, error) {
+	var res entity.Actor
+
+	err := r.s.read(ctx, func() (err error) {
+		res, err = r.s.actor(id)
+		return err
+	})
+
+	return res, err
+}
+
+// GetByExternalID возвращает актера по его идентификатору во внешнем каталоге
+func (r *ActorsRepo) GetByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error) {
+	var res entity.Actor
+
+	err := r.s.read(ctx, func() error {
+		id, err := r.s.actorsExternalIDs.find(source, externalID)
+		if err != nil {
+			return err
+		}
+
+		res, err = r.s.actor(id)
+		return err
+	})
+
+	return res, err
+}
+
+// MatchExternalIDs возвращает id актера, которому принадлежит любой из внешних идентификаторов
+func (r *ActorsRepo) MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error) {
+	var res int
+
+	err := r.s.read(ctx, func() (err error) {
+		res, err = r.s.actorsExternalIDs.match(ids)
+		return err
+	})
+
+	return res, err
+}
+
+func (r *ActorsRepo) Save(ctx context.Context, data entity.ActorData) (int, error) {
+	var res int
+
+	err := r.s.write(ctx, func() error {
+		id := r.s.nextActorID()
+
+		if err := checkActor(data); err != nil {
+			return err
+		}
+
+		// Как INSERT ... ON CONFLICT (name, surname) DO NOTHING: актер не сохраняется и id не возвращается
+		if r.s.actorNamed(*data.Name, *data.Surname) != 0 {
+			return fmt.Errorf("%s: actor with the same name and surname already exists: %w", op, sql.ErrNoRows)
+		}
+
+		// Сохраняем идентификаторы актера во внешних каталогах
+		if err := r.s.actorsExternalIDs.check(id, data.ExternalIDs); err != nil {
+			return err
+		}
+
+		r.s.actors[id] = cloneActor(entity.Actor{Id: &id, ActorData: data})
+		r.s.actorsExternalIDs.set(id, data.ExternalIDs)
+
+		res = id
+
+		return nil
+	})
+
+	return res, err
+}
+
+func (r *ActorsRepo) Update(ctx context.Context, updates entity.Actor) (entity.Actor, error) {
+	u := updates.ActorData
+	if u.Name == nil && u.Surname == nil && u.Patronymic == nil && u.Gender == nil && u.DateOfBirth == nil &&
+		len(u.ExternalIDs) == 0 {
+		return entity.Actor{}, fmt.Errorf("%s: Data for update operation were NOT specified", op)
+	}
+
+	var res entity.Actor
+
+	err := r.s.write(ctx, func() error {
+		if updates.Id == nil {
+			return fmt.Errorf("%s: actor was NOT found: %w", op, sql.ErrNoRows)
+		}
+
+		id := *updates.Id
+
+		stored, ok := r.s.actors[id]
+		if !ok {
+			return fmt.Errorf("%s: actor was NOT found: %w", op, sql.ErrNoRows)
+		}
+
+		a := cloneActor(stored)
+
+		if u.Name != nil {
+			a.Name = u.Name
+		}
+		if u.Surname != nil {
+			a.Surname = u.Surname
+		}
+		if u.Patronymic != nil {
+			a.Patronymic = u.Patronymic
+		}
+		if u.Gender != nil {
+			a.Gender = u.Gender
+		}
+		if u.DateOfBirth != nil {
+			a.DateOfBirth = u.DateOfBirth
+		}
+
+		if err := checkActor(a.ActorData); err != nil {
+			return err
+		}
+
+		if other := r.s.actorNamed(*a.Name, *a.Surname); other != 0 && other != id {
+			return fmt.Errorf("%s: actor %d has the same name and surname: %w", op, other, usecase.ErrAlreadyExists)
+		}
+
+		if err := r.s.actorsExternalIDs.check(id, u.ExternalIDs); err != nil {
+			return err
+		}
+
+		r.s.actors[id] = cloneActor(a)
+		r.s.actorsExternalIDs.set(id, u.ExternalIDs)
+
+		res = cloneActor(a)
+		res.ExternalIDs = r.s.actorsExternalIDs.get(id)
+
+		return nil
+	})
+
+	return res, err
+}
+
+func (r *ActorsRepo) Delete(ctx context.Context, id int) (entity.Actor, error) {
+	var res entity.Actor
+
+	err := r.s.write(ctx, func() error {
+		a, ok := r.s.actors[id]
+		if !ok {
+			return fmt.Errorf("%s: actor was NOT found: %w", op, sql.ErrNoRows)
+		}
+
+		// Как внешний ключ таблицы actors_movies
+		if len(r.s.moviesOf(id)) != 0 {
+			return fmt.Errorf("%s: actor is in the cast of movies: %w", op, usecase.ErrReferenced)
+		}
+
+		delete(r.s.actors, id)
+		r.s.actorsExternalIDs.delete(id)
+
+		// Как ON DELETE CASCADE таблицы actor_redirects
+		for oldID, newID := range r.s.redirects {
+			if newID == id {
+				delete(r.s.redirects, oldID)
+			}
+		}
+
+		res = cloneActor(a)
+
+		return nil
+	})
+
+	return res, err
+}
+
+func (r *ActorsRepo) List(ctx context.Context) ([]entity.Actor, error) {
+	var res []entity.Actor
+
+	err := r.s.read(ctx, func() (err error) {
+		res, err = filterRows(ctx, r.s.sortedActors(), actorColumns)
+		if err != nil {
+			return err
+		}
+
+		res = limit(res)
+
+		// Дополним страницу внешними идентификаторами
+		for i := range res {
+			res[i].ExternalIDs = r.s.actorsExternalIDs.get(*res[i].Id)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return []entity.Actor{}, err
+	}
+
+	return res, nil
+}
+
+func (r *ActorsRepo) Next(ctx context.Context) ([]entity.Actor, error) {
+	var res []entity.Actor
+
+	err := r.s.read(ctx, func() (err error) {
+		res, err = filterRows(ctx, r.s.sortedActors(), actorColumns)
+		if err != nil {
+			return err
+		}
+
+		// Условие пагинации
+		res = limit(nextPage(ctx, res, func(a entity.Actor) int { return *a.Id }))
+
+		// Дополним страницу внешними идентификаторами
+		for i := range res {
+			res[i].ExternalIDs = r.s.actorsExternalIDs.get(*res[i].Id)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return []entity.Actor{}, err
+	}
+
+	return res, nil
+}
+
+// Profiles возвращает всех актеров вместе с id фильмов, в которых они снимались
+func (r *ActorsRepo) Profiles(ctx context.Context) ([]entity.ActorProfile, error) {
+	var res []entity.ActorProfile
+
+	err := r.s.read(ctx, func() error {
+		actors := r.s.sortedActors()
+
+		res = make([]entity.ActorProfile, 0, len(actors))
+		for _, a := range actors {
+			res = append(res, entity.ActorProfile{Actor: a, MovieIDs: r.s.moviesOf(*a.Id)})
+		}
+
+		return nil
+	})
+
+	return res, err
+}
+
+// Merge переносит все связи с фильмами актера sourceID на актера targetID и удаляет актера sourceID.
+// Старый id продолжает указывать на актера targetID.
+func (r *ActorsRepo) Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error) {
+	var res entity.Actor
+
+	err := r.s.write(ctx, func() (err error) {
+		source, foundSource := r.s.actors[sourceID]
+		target, foundTarget := r.s.actors[targetID]
+
+		if !foundSource || !foundTarget || sourceID == targetID {
+			return fmt.Errorf("%s: actors to merge were NOT found: %w", op, sql.ErrNoRows)
+		}
+
+		// Пустые поля сохраняемого актера заполняются данными объединяемого
+		target = cloneActor(target)
+		if target.Patronymic == nil {
+			target.Patronymic = clone(source.Patronymic)
+		}
+		if target.Gender == nil {
+			target.Gender = clone(source.Gender)
+		}
+		if target.DateOfBirth == nil {
+			target.DateOfBirth = cloneDate(source.DateOfBirth)
+		}
+		r.s.actors[targetID] = target
+
+		// Фильмы, в которых снимались оба актера, остаются у сохраняемого в одном экземпляре
+		for _, movieID := range r.s.moviesOf(sourceID) {
+			delete(r.s.cast, castKey{movieID: movieID, actorID: sourceID})
+			r.s.cast[castKey{movieID: movieID, actorID: targetID}] = struct{}{}
+		}
+
+		// Идентификаторы каталогов, которых нет у сохраняемого актера, переносятся
+		r.s.actorsExternalIDs.move(sourceID, targetID)
+
+		// Ссылки на объединяемого актера перенаправляются на сохраняемого
+		for oldID, newID := range r.s.redirects {
+			if newID == sourceID {
+				r.s.redirects[oldID] = targetID
+			}
+		}
+		r.s.redirects[sourceID] = targetID
+
+		delete(r.s.actors, sourceID)
+
+		res, err = r.s.actor(targetID)
+
+		return err
+	})
+
+	return res, err
+}
+
+// actorNamed возвращает id актера с указанными именем и фамилией или 0, если такого нет
+func (s *Storage) actorNamed(name, surname string) int {
+	for id, a := range s.actors {
+		if *a.Name == name && *a.Surname == surname {
+			return id
+		}
+	}
+
+	return 0
+}
+
+// Count возвращает число актеров
+func (r *ActorsRepo) Count(ctx context.Context) (int, error) {
+	var count int
+
+	err := r.s.read(ctx, func() error {
+		count = len(r.s.actors)
+		return nil
+	})
+
+	return count, err
+}
@@ -0,0 +1,165 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+type ActorsMoviesRepo struct {
+	s *Storage
+}
+
+func NewActorsMoviesRepo(s *Storage) *ActorsMoviesRepo {
+	return &ActorsMoviesRepo{s: s}
+}
+
+func (r *ActorsMoviesRepo) Save(ctx context.Context, data entity.ActorMovie) error {
+	return r.s.write(ctx, func() error {
+		// Проверяем наличие актера и фильма
+		if _, ok := r.s.actors[value(data.Actor_id)]; !ok {
+			return fmt.Errorf("%s: actor_id was NOT found in table 'actors': %w", op, sql.ErrNoRows)
+		}
+
+		if _, ok := r.s.movies[value(data.Movie_id)]; !ok {
+			return fmt.Errorf("%s: movie_id was NOT found in table 'movies': %w", op, sql.ErrNoRows)
+		}
+
+		key := castKey{movieID: *data.Movie_id, actorID: *data.Actor_id}
+		if _, ok := r.s.cast[key]; ok {
+			return fmt.Errorf("%s: actor is already in the cast of the movie: %w", op, usecase.ErrAlreadyExists)
+		}
+
+		r.s.cast[key] = struct{}{}
+
+		return nil
+	})
+}
+
+func (r *ActorsMoviesRepo) Delete(ctx context.Context, data entity.ActorMovie) error {
+	return r.s.write(ctx, func() error {
+		key := castKey{movieID: value(data.Movie_id), actorID: value(data.Actor_id)}
+		if _, ok := r.s.cast[key]; !ok {
+			return fmt.Errorf("%s: actor is NOT in the cast of the movie: %w", op, sql.ErrNoRows)
+		}
+
+		delete(r.s.cast, key)
+
+		return nil
+	})
+}
+
+func (r *ActorsMoviesRepo) List(ctx context.Context) ([]entity.ActorMovieData, error) {
+	var data []entity.ActorMovieData
+
+	err := r.s.read(ctx, func() error {
+		for k := range r.s.cast {
+			a, m := r.s.actors[k.actorID], r.s.movies[k.movieID]
+
+			data = append(data, entity.ActorMovieData{
+				ActorID:      k.actorID,
+				ActorName:    *a.Name,
+				ActorSurname: *a.Surname,
+				MovieID:      k.movieID,
+				MovieTitle:   *m.Title,
+			})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(data, func(i, j int) bool {
+		if data[i].ActorID != data[j].ActorID {
+			return data[i].ActorID < data[j].ActorID
+		}
+		return data[i].MovieID < data[j].MovieID
+	})
+
+	return data, nil
+}
+
+// Cast возвращает актеров нескольких фильмов, упорядоченных по id. Ключ - id фильма.
+func (r *ActorsMoviesRepo) Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+	res := make(map[int][]entity.Actor, len(movieIDs))
+
+	err := r.s.read(ctx, func() error {
+		for _, movieID := range movieIDs {
+			if _, ok := res[movieID]; ok {
+				continue
+			}
+
+			for _, actorID := range r.s.castOf(movieID) {
+				res[movieID] = append(res[movieID], cloneActor(r.s.actors[actorID]))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Filmography возвращает фильмы нескольких актеров, упорядоченные по дате выхода. Ключ - id актера.
+func (r *ActorsMoviesRepo) Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+	res := make(map[int][]entity.Movie, len(actorIDs))
+
+	err := r.s.read(ctx, func() error {
+		for _, actorID := range actorIDs {
+			if _, ok := res[actorID]; ok {
+				continue
+			}
+
+			for _, movieID := range r.s.moviesOf(actorID) {
+				res[actorID] = append(res[actorID], cloneMovie(r.s.movies[movieID]))
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	// Фильмы уже упорядочены по id, поэтому фильмы с одинаковой датой остаются в порядке id
+	for _, movies := range res {
+		sort.SliceStable(movies, func(i, j int) bool {
+			return compare(dateTime(movies[i].ReleaseDate), dateTime(movies[j].ReleaseDate)) < 0
+		})
+	}
+
+	return res, nil
+}
+
+// value возвращает значение необязательного id или 0, которого нет среди id записей
+func value(id *int) int {
+	if id == nil {
+		return 0
+	}
+
+	return *id
+}
+
+// Count возвращает число связей актеров с фильмами
+func (r *ActorsMoviesRepo) Count(ctx context.Context) (int, error) {
+	var count int
+
+	err := r.s.read(ctx, func() error {
+		count = len(r.s.cast)
+		return nil
+	})
+
+	return count, err
+}
@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"fmt"
+	"unicode/utf8"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// Ограничения таблиц actors и movies из миграций
+
+func checkActor(a entity.ActorData) error {
+	if a.Name == nil || a.Surname == nil {
+		return fmt.Errorf("%s: actor name and surname are required: %w", op, usecase.ErrInvalidData)
+	}
+
+	fields := []struct {
+		column string
+		val    *string
+	}{{"name", a.Name}, {"surname", a.Surname}, {"patronymic", a.Patronymic}}
+
+	for _, f := range fields {
+		if err := checkLength(f.column, f.val, 50); err != nil {
+			return err
+		}
+	}
+
+	if a.Gender != nil && *a.Gender != "male" && *a.Gender != "female" {
+		return fmt.Errorf("%s: invalid gender %q: %w", op, *a.Gender, usecase.ErrInvalidData)
+	}
+
+	return checkDate("date_of_birth", a.DateOfBirth)
+}
+
+func checkMovie(m entity.MovieData) error {
+	if m.Title == nil {
+		return fmt.Errorf("%s: movie title is required: %w", op, usecase.ErrInvalidData)
+	}
+
+	if err := checkLength("title", m.Title, 150); err != nil {
+		return err
+	}
+
+	if err := checkLength("description", m.Description, 1000); err != nil {
+		return err
+	}
+
+	if m.Rating != nil && (*m.Rating < 0 || *m.Rating > 10) {
+		return fmt.Errorf("%s: rating %d is out of range 0..10: %w", op, *m.Rating, usecase.ErrInvalidData)
+	}
+
+	return checkDate("release_date", m.ReleaseDate)
+}
+
+// checkLength проверяет длину строки в символах, как тип VARCHAR(max)
+func checkLength(column string, val *string, max int) error {
+	if val != nil && utf8.RuneCountInString(*val) > max {
+		return fmt.Errorf("%s: %s is longer than %d characters: %w", op, column, max, usecase.ErrInvalidData)
+	}
+
+	return nil
+}
+
+func checkDate(column string, d *entity.Date) error {
+	if d != nil && !d.Valid() {
+		return fmt.Errorf("%s: invalid %s %q: %w", op, column, d.String(), usecase.ErrInvalidData)
+	}
+
+	return nil
+}
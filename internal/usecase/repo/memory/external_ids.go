@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"unicode/utf8"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// Наибольшая длина идентификатора во внешнем каталоге, как у столбца external_id
+const externalIDMaxLength = 64
+
+// externalIDs - таблица идентификаторов фильмов или актеров во внешних каталогах
+type externalIDs struct {
+	// id записи -> каталог -> идентификатор
+	byOwner map[int]entity.ExternalIDs
+	// каталог -> идентификатор -> id записи
+	bySource map[string]map[string]int
+}
+
+func newExternalIDs() *externalIDs {
+	return &externalIDs{
+		byOwner:  make(map[int]entity.ExternalIDs),
+		bySource: make(map[string]map[string]int),
+	}
+}
+
+// get возвращает копию внешних идентификаторов записи или nil, если их нет
+func (t *externalIDs) get(id int) entity.ExternalIDs {
+	ids, ok := t.byOwner[id]
+	if !ok {
+		return nil
+	}
+
+	res := make(entity.ExternalIDs, len(ids))
+	for source, externalID := range ids {
+		res[source] = externalID
+	}
+
+	return res
+}
+
+// find возвращает id записи с указанным внешним идентификатором
+func (t *externalIDs) find(source, externalID string) (int, error) {
+	if !entity.IsExternalSource(source) {
+		return 0, fmt.Errorf("%s: unknown external source %q: %w", op, source, usecase.ErrInvalidData)
+	}
+
+	id, ok := t.bySource[source][externalID]
+	if !ok {
+		return 0, fmt.Errorf("%s: external id was NOT found: %w", op, sql.ErrNoRows)
+	}
+
+	return id, nil
+}
+
+// match возвращает id записи, которой принадлежит хотя бы один из внешних идентификаторов.
+// Если идентификаторы принадлежат разным записям, возвращается ошибка.
+func (t *externalIDs) match(ids entity.ExternalIDs) (int, error) {
+	found := make(map[int]struct{})
+
+	for source, externalID := range ids {
+		id, err := t.find(source, externalID)
+		if err == nil {
+			found[id] = struct{}{}
+			continue
+		}
+
+		if !errors.Is(err, sql.ErrNoRows) {
+			return 0, err
+		}
+	}
+
+	res := make([]int, 0, len(found))
+	for id := range found {
+		res = append(res, id)
+	}
+	sort.Ints(res)
+
+	switch len(res) {
+	case 0:
+		return 0, sql.ErrNoRows
+	case 1:
+		return res[0], nil
+	default:
+		return 0, fmt.Errorf("%s: external ids belong to different records: %v", op, res)
+	}
+}
+
+// check проверяет, что внешние идентификаторы можно сохранить для записи id
+func (t *externalIDs) check(id int, ids entity.ExternalIDs) error {
+	for source, externalID := range ids {
+		if !entity.IsExternalSource(source) {
+			return fmt.Errorf("%s: unknown external source %q: %w", op, source, usecase.ErrInvalidData)
+		}
+
+		if utf8.RuneCountInString(externalID) > externalIDMaxLength {
+			return fmt.Errorf("%s: external id is longer than %d characters: %w", op, externalIDMaxLength, usecase.ErrInvalidData)
+		}
+
+		if owner, ok := t.bySource[source][externalID]; ok && owner != id {
+			return fmt.Errorf("%s: %s id %q belongs to record %d: %w", op, source, externalID, owner, usecase.ErrAlreadyExists)
+		}
+	}
+
+	return nil
+}
+
+// set добавляет или заменяет внешние идентификаторы записи. Перед вызовом нужна проверка check.
+func (t *externalIDs) set(id int, ids entity.ExternalIDs) {
+	for source, externalID := range ids {
+		owned := t.byOwner[id]
+		if owned == nil {
+			owned = entity.ExternalIDs{}
+			t.byOwner[id] = owned
+		}
+
+		if old, ok := owned[source]; ok {
+			delete(t.bySource[source], old)
+		}
+
+		if t.bySource[source] == nil {
+			t.bySource[source] = make(map[string]int)
+		}
+
+		owned[source] = externalID
+		t.bySource[source][externalID] = id
+	}
+}
+
+// move переносит к записи to идентификаторы записи from из тех каталогов, которых у to нет.
+// Остальные идентификаторы from удаляются.
+func (t *externalIDs) move(from, to int) {
+	moved := entity.ExternalIDs{}
+	for source, externalID := range t.byOwner[from] {
+		if _, ok := t.byOwner[to][source]; !ok {
+			moved[source] = externalID
+		}
+	}
+
+	t.delete(from)
+	t.set(to, moved)
+}
+
+// delete удаляет все внешние идентификаторы записи
+func (t *externalIDs) delete(id int) {
+	for source, externalID := range t.byOwner[id] {
+		delete(t.bySource[source], externalID)
+	}
+
+	delete(t.byOwner, id)
+}
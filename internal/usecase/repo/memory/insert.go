@@ -0,0 +1,349 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// Insert добавляет в таблицу строку так же, как INSERT INTO table (columns) VALUES (...) в postgres:
+// с явно заданными id, датами в виде первого дня периода и точностью в отдельном столбце.
+// Так в хранилище загружаются данные, подготовленные для postgres, например фикстуры тестов.
+// Следующие записи получают id больше всех вставленных.
+func (s *Storage) Insert(ctx context.Context, table string, row map[string]any) error {
+	return s.write(ctx, func() error {
+		r := insertRow(row)
+
+		var err error
+
+		switch table {
+		case "actors":
+			err = s.insertActor(r)
+		case "movies":
+			err = s.insertMovie(r)
+		case "actors_movies":
+			err = s.insertCast(r)
+		case "actors_external_ids":
+			err = s.insertExternalID(r, "actor_id", s.actorsExternalIDs, func(id int) bool { _, ok := s.actors[id]; return ok })
+		case "movies_external_ids":
+			err = s.insertExternalID(r, "movie_id", s.moviesExternalIDs, func(id int) bool { _, ok := s.movies[id]; return ok })
+		case "actor_redirects":
+			err = s.insertRedirect(r)
+		default:
+			err = fmt.Errorf("table %q does not exist", table)
+		}
+
+		if err != nil {
+			return fmt.Errorf("%s: failed to insert into %s: %w", op, table, err)
+		}
+
+		return nil
+	})
+}
+
+func (s *Storage) insertActor(r insertRow) error {
+	var a entity.Actor
+	var err error
+
+	if err = r.columns("id", "name", "surname", "patronymic", "gender", "date_of_birth", "date_of_birth_precision"); err != nil {
+		return err
+	}
+
+	if a.Id, err = r.int("id"); err != nil {
+		return err
+	}
+	if a.Name, err = r.text("name"); err != nil {
+		return err
+	}
+	if a.Surname, err = r.text("surname"); err != nil {
+		return err
+	}
+	if a.Patronymic, err = r.text("patronymic"); err != nil {
+		return err
+	}
+	if a.Gender, err = r.text("gender"); err != nil {
+		return err
+	}
+	if a.DateOfBirth, err = r.date("date_of_birth"); err != nil {
+		return err
+	}
+
+	if a.Id == nil {
+		id := s.nextActorID()
+		a.Id = &id
+	}
+
+	if err = checkActor(a.ActorData); err != nil {
+		return err
+	}
+
+	if _, ok := s.actors[*a.Id]; ok {
+		return fmt.Errorf("actor %d already exists: %w", *a.Id, usecase.ErrAlreadyExists)
+	}
+
+	if other := s.actorNamed(*a.Name, *a.Surname); other != 0 {
+		return fmt.Errorf("actor %d has the same name and surname: %w", other, usecase.ErrAlreadyExists)
+	}
+
+	s.actors[*a.Id] = cloneActor(a)
+
+	if *a.Id > s.lastActorID {
+		s.lastActorID = *a.Id
+	}
+
+	return nil
+}
+
+func (s *Storage) insertMovie(r insertRow) error {
+	var m entity.Movie
+	var err error
+
+	if err = r.columns("id", "title", "description", "release_date", "release_date_precision", "rating"); err != nil {
+		return err
+	}
+
+	if m.Id, err = r.int("id"); err != nil {
+		return err
+	}
+	if m.Title, err = r.text("title"); err != nil {
+		return err
+	}
+	if m.Description, err = r.text("description"); err != nil {
+		return err
+	}
+	if m.ReleaseDate, err = r.date("release_date"); err != nil {
+		return err
+	}
+	if m.Rating, err = r.int("rating"); err != nil {
+		return err
+	}
+
+	if m.Id == nil {
+		id := s.nextMovieID()
+		m.Id = &id
+	}
+
+	if err = checkMovie(m.MovieData); err != nil {
+		return err
+	}
+
+	if _, ok := s.movies[*m.Id]; ok {
+		return fmt.Errorf("movie %d already exists: %w", *m.Id, usecase.ErrAlreadyExists)
+	}
+
+	s.movies[*m.Id] = cloneMovie(m)
+
+	if *m.Id > s.lastMovieID {
+		s.lastMovieID = *m.Id
+	}
+
+	return nil
+}
+
+func (s *Storage) insertCast(r insertRow) error {
+	if err := r.columns("movie_id", "actor_id"); err != nil {
+		return err
+	}
+
+	movieID, err := r.int("movie_id")
+	if err != nil {
+		return err
+	}
+
+	actorID, err := r.int("actor_id")
+	if err != nil {
+		return err
+	}
+
+	if _, ok := s.movies[value(movieID)]; !ok {
+		return fmt.Errorf("movie was NOT found: %w", usecase.ErrInvalidData)
+	}
+
+	if _, ok := s.actors[value(actorID)]; !ok {
+		return fmt.Errorf("actor was NOT found: %w", usecase.ErrInvalidData)
+	}
+
+	key := castKey{movieID: *movieID, actorID: *actorID}
+	if _, ok := s.cast[key]; ok {
+		return fmt.Errorf("actor is already in the cast of the movie: %w", usecase.ErrAlreadyExists)
+	}
+
+	s.cast[key] = struct{}{}
+
+	return nil
+}
+
+func (s *Storage) insertExternalID(r insertRow, ownerColumn string, t *externalIDs, exists func(int) bool) error {
+	if err := r.columns(ownerColumn, "source", "external_id"); err != nil {
+		return err
+	}
+
+	id, err := r.int(ownerColumn)
+	if err != nil {
+		return err
+	}
+
+	source, err := r.text("source")
+	if err != nil {
+		return err
+	}
+
+	externalID, err := r.text("external_id")
+	if err != nil {
+		return err
+	}
+
+	if id == nil || source == nil || externalID == nil {
+		return fmt.Errorf("%s, source and external_id are required: %w", ownerColumn, usecase.ErrInvalidData)
+	}
+
+	if !exists(*id) {
+		return fmt.Errorf("record %d was NOT found: %w", *id, usecase.ErrInvalidData)
+	}
+
+	if _, ok := t.byOwner[*id][*source]; ok {
+		return fmt.Errorf("record %d already has %s id: %w", *id, *source, usecase.ErrAlreadyExists)
+	}
+
+	ids := entity.ExternalIDs{*source: *externalID}
+	if err = t.check(*id, ids); err != nil {
+		return err
+	}
+
+	t.set(*id, ids)
+
+	return nil
+}
+
+func (s *Storage) insertRedirect(r insertRow) error {
+	if err := r.columns("old_id", "new_id"); err != nil {
+		return err
+	}
+
+	oldID, err := r.int("old_id")
+	if err != nil {
+		return err
+	}
+
+	newID, err := r.int("new_id")
+	if err != nil {
+		return err
+	}
+
+	if oldID == nil {
+		return fmt.Errorf("old_id is required: %w", usecase.ErrInvalidData)
+	}
+
+	if _, ok := s.actors[value(newID)]; !ok {
+		return fmt.Errorf("actor was NOT found: %w", usecase.ErrInvalidData)
+	}
+
+	if _, ok := s.redirects[*oldID]; ok {
+		return fmt.Errorf("actor %d is already redirected: %w", *oldID, usecase.ErrAlreadyExists)
+	}
+
+	s.redirects[*oldID] = *newID
+
+	return nil
+}
+
+// insertRow - значения столбцов вставляемой строки
+type insertRow map[string]any
+
+// columns проверяет, что в строке нет неизвестных столбцов
+func (r insertRow) columns(known ...string) error {
+	names := make([]string, 0, len(r))
+	for name := range r {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+next:
+	for _, name := range names {
+		for _, k := range known {
+			if name == k {
+				continue next
+			}
+		}
+
+		return fmt.Errorf("column %q does not exist", name)
+	}
+
+	return nil
+}
+
+func (r insertRow) int(column string) (*int, error) {
+	switch v := r[column].(type) {
+	case nil:
+		return nil, nil
+	case int:
+		return &v, nil
+	case int64:
+		n := int(v)
+		return &n, nil
+	case string:
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid integer %q in column %s: %w", v, column, usecase.ErrInvalidData)
+		}
+		return &n, nil
+	default:
+		return nil, fmt.Errorf("invalid value %v in column %s: %w", v, column, usecase.ErrInvalidData)
+	}
+}
+
+func (r insertRow) text(column string) (*string, error) {
+	switch v := r[column].(type) {
+	case nil:
+		return nil, nil
+	case string:
+		return &v, nil
+	case int, int64:
+		s := fmt.Sprint(v)
+		return &s, nil
+	default:
+		return nil, fmt.Errorf("invalid value %v in column %s: %w", v, column, usecase.ErrInvalidData)
+	}
+}
+
+// date читает дату и ее точность из столбца <column>_precision (по умолчанию day)
+func (r insertRow) date(column string) (*entity.Date, error) {
+	var d entity.Date
+
+	switch v := r[column].(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		d = entity.NewDate(v.Date())
+	case string:
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date %q in column %s: %w", v, column, usecase.ErrInvalidData)
+		}
+		d = entity.NewDate(t.Date())
+	default:
+		return nil, fmt.Errorf("invalid value %v in column %s: %w", v, column, usecase.ErrInvalidData)
+	}
+
+	precision, err := r.text(column + "_precision")
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case precision == nil, *precision == string(entity.PrecisionDay):
+	case *precision == string(entity.PrecisionMonth):
+		d.Day = 0
+	case *precision == string(entity.PrecisionYear):
+		d.Month, d.Day = 0, 0
+	default:
+		return nil, fmt.Errorf("invalid precision %q in column %s_precision: %w", *precision, column, usecase.ErrInvalidData)
+	}
+
+	return &d, nil
+}
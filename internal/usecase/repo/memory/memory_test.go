@@ -0,0 +1,73 @@
+package memory_test
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"testing"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase/repo/memory"
+	"filmoteka/internal/usecase/repo/repotest"
+)
+
+func TestRepos(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		s := memory.New()
+
+		if err := repotest.Load(context.Background(), s.Insert); err != nil {
+			t.Fatalf("failed to load fixtures: %s", err)
+		}
+
+		return repotest.Repos{
+			Actors:       memory.NewActorsRepo(s),
+			Movies:       memory.NewMoviesRepo(s),
+			ActorsMovies: memory.NewActorsMoviesRepo(s),
+		}
+	})
+}
+
+func TestStorageConcurrentAccess(t *testing.T) {
+	s := memory.New()
+	actors := memory.NewActorsRepo(s)
+	ctx := context.Background()
+
+	const workers = 20
+
+	ids := make(chan int, workers)
+	var wg sync.WaitGroup
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			name, surname := "Актер", strconv.Itoa(i)
+			id, err := actors.Save(ctx, entity.ActorData{Name: &name, Surname: &surname})
+			if err != nil {
+				t.Errorf("failed to save actor: %s", err)
+				return
+			}
+			ids <- id
+
+			if _, err = actors.List(ctx); err != nil {
+				t.Errorf("failed to list actors: %s", err)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(ids)
+
+	seen := make(map[int]bool)
+	for id := range ids {
+		if seen[id] {
+			t.Fatalf("id %d is given to several actors", id)
+		}
+		seen[id] = true
+	}
+
+	if len(seen) != workers {
+		t.Fatalf("saved %d actors, want %d", len(seen), workers)
+	}
+}
@@ -0,0 +1,288 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/sort"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+type MoviesRepo struct {
+	s *Storage
+}
+
+func NewMoviesRepo(s *Storage) *MoviesRepo {
+	return &MoviesRepo{s: s}
+}
+
+func (r *MoviesRepo) Get(ctx context.Context, id int) (entity.Movie, error) {
+	var res entity.Movie
+
+	err := r.s.read(ctx, func() (err error) {
+		res, err = r.s.movie(id)
+		return err
+	})
+
+	return res, err
+}
+
+// GetByExternalID возвращает фильм по его идентификатору во внешнем каталоге
+func (r *MoviesRepo) GetByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error) {
+	var res entity.Movie
+
+	err := r.s.read(ctx, func() error {
+		id, err := r.s.moviesExternalIDs.find(source, externalID)
+		if err != nil {
+			return err
+		}
+
+		res, err = r.s.movie(id)
+		return err
+	})
+
+	return res, err
+}
+
+// MatchExternalIDs возвращает id фильма, которому принадлежит любой из внешних идентификаторов
+func (r *MoviesRepo) MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error) {
+	var res int
+
+	err := r.s.read(ctx, func() (err error) {
+		res, err = r.s.moviesExternalIDs.match(ids)
+		return err
+	})
+
+	return res, err
+}
+
+// GetMovie ищет фильмы, в которых есть актеры, по части названия и части имени актера без учета регистра
+func (r *MoviesRepo) GetMovie(ctx context.Context) ([]entity.Movie, error) {
+	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
+
+	var title, actor string
+
+	if titles := filter_options["title"]; len(titles) != 0 {
+		title = strings.ToLower(titles[0])
+	}
+
+	if actors := filter_options["actor_name"]; len(actors) != 0 {
+		actor = strings.ToLower(actors[0])
+	}
+
+	var res []entity.Movie
+
+	err := r.s.read(ctx, func() error {
+		for _, m := range r.s.sortedMovies() {
+			if !strings.Contains(strings.ToLower(*m.Title), title) {
+				continue
+			}
+
+			if !r.s.castMatches(*m.Id, actor) {
+				continue
+			}
+
+			m.ExternalIDs = r.s.moviesExternalIDs.get(*m.Id)
+			res = append(res, m)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return []entity.Movie{}, err
+	}
+
+	return res, nil
+}
+
+// castMatches сообщает, есть ли в фильме актер, имя которого содержит name
+func (s *Storage) castMatches(movieID int, name string) bool {
+	for _, actorID := range s.castOf(movieID) {
+		if strings.Contains(strings.ToLower(*s.actors[actorID].Name), name) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *MoviesRepo) Save(ctx context.Context, data entity.MovieData) (int, error) {
+	var res int
+
+	err := r.s.write(ctx, func() error {
+		id := r.s.nextMovieID()
+
+		if err := checkMovie(data); err != nil {
+			return err
+		}
+
+		// Сохраняем идентификаторы фильма во внешних каталогах
+		if err := r.s.moviesExternalIDs.check(id, data.ExternalIDs); err != nil {
+			return err
+		}
+
+		r.s.movies[id] = cloneMovie(entity.Movie{Id: &id, MovieData: data})
+		r.s.moviesExternalIDs.set(id, data.ExternalIDs)
+
+		res = id
+
+		return nil
+	})
+
+	return res, err
+}
+
+func (r *MoviesRepo) Update(ctx context.Context, updates entity.Movie) (entity.Movie, error) {
+	u := updates.MovieData
+	if u.Title == nil && u.Description == nil && u.ReleaseDate == nil && u.Rating == nil && len(u.ExternalIDs) == 0 {
+		return entity.Movie{}, fmt.Errorf("%s: Data for update operation were NOT specified", op)
+	}
+
+	var res entity.Movie
+
+	err := r.s.write(ctx, func() error {
+		if updates.Id == nil {
+			return fmt.Errorf("%s: movie was NOT found: %w", op, sql.ErrNoRows)
+		}
+
+		id := *updates.Id
+
+		stored, ok := r.s.movies[id]
+		if !ok {
+			return fmt.Errorf("%s: movie was NOT found: %w", op, sql.ErrNoRows)
+		}
+
+		m := cloneMovie(stored)
+
+		if u.Title != nil {
+			m.Title = u.Title
+		}
+		if u.Description != nil {
+			m.Description = u.Description
+		}
+		if u.ReleaseDate != nil {
+			m.ReleaseDate = u.ReleaseDate
+		}
+		if u.Rating != nil {
+			m.Rating = u.Rating
+		}
+
+		if err := checkMovie(m.MovieData); err != nil {
+			return err
+		}
+
+		if err := r.s.moviesExternalIDs.check(id, u.ExternalIDs); err != nil {
+			return err
+		}
+
+		r.s.movies[id] = cloneMovie(m)
+		r.s.moviesExternalIDs.set(id, u.ExternalIDs)
+
+		res = cloneMovie(m)
+		res.ExternalIDs = r.s.moviesExternalIDs.get(id)
+
+		return nil
+	})
+
+	return res, err
+}
+
+func (r *MoviesRepo) Delete(ctx context.Context, id int) (entity.Movie, error) {
+	var res entity.Movie
+
+	err := r.s.write(ctx, func() error {
+		m, ok := r.s.movies[id]
+		if !ok {
+			return fmt.Errorf("%s: movie was NOT found: %w", op, sql.ErrNoRows)
+		}
+
+		// Как внешний ключ таблицы actors_movies
+		if len(r.s.castOf(id)) != 0 {
+			return fmt.Errorf("%s: movie has a cast: %w", op, usecase.ErrReferenced)
+		}
+
+		delete(r.s.movies, id)
+		r.s.moviesExternalIDs.delete(id)
+
+		res = cloneMovie(m)
+
+		return nil
+	})
+
+	return res, err
+}
+
+// List возвращает фильмы, отсортированные по параметрам сортировки, а без них - по убыванию рейтинга
+func (r *MoviesRepo) List(ctx context.Context) ([]entity.Movie, error) {
+	var res []entity.Movie
+
+	err := r.s.read(ctx, func() (err error) {
+		res, err = filterRows(ctx, r.s.sortedMovies(), movieColumns)
+		if err != nil {
+			return err
+		}
+
+		if err = sortRows(ctx, res, movieColumns, "rating", sort.DESC); err != nil {
+			return err
+		}
+
+		res = limit(res)
+
+		// Дополним страницу внешними идентификаторами
+		for i := range res {
+			res[i].ExternalIDs = r.s.moviesExternalIDs.get(*res[i].Id)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return []entity.Movie{}, err
+	}
+
+	return res, nil
+}
+
+func (r *MoviesRepo) Next(ctx context.Context) ([]entity.Movie, error) {
+	var res []entity.Movie
+
+	err := r.s.read(ctx, func() (err error) {
+		res, err = filterRows(ctx, r.s.sortedMovies(), movieColumns)
+		if err != nil {
+			return err
+		}
+
+		// Условие пагинации
+		res = limit(nextPage(ctx, res, func(m entity.Movie) int { return *m.Id }))
+
+		// Дополним страницу внешними идентификаторами
+		for i := range res {
+			res[i].ExternalIDs = r.s.moviesExternalIDs.get(*res[i].Id)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return []entity.Movie{}, err
+	}
+
+	return res, nil
+}
+
+// Count возвращает число фильмов
+func (r *MoviesRepo) Count(ctx context.Context) (int, error) {
+	var count int
+
+	err := r.s.read(ctx, func() error {
+		count = len(r.s.movies)
+		return nil
+	})
+
+	return count, err
+}
@@ -0,0 +1,243 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/pagination"
+	sortoptions "filmoteka/internal/controller/middleware/sort"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// columnKind - тип столбца. От него зависит, как разбирается значение из параметров запроса.
+type columnKind int
+
+const (
+	kindInt columnKind = iota
+	kindText
+	kindDate
+	kindEnum
+)
+
+// column - столбец таблицы, по которому можно фильтровать и сортировать записи.
+// value возвращает int, string, time.Time или nil, если значение NULL.
+type column[T any] struct {
+	kind   columnKind
+	values []string
+	value  func(T) any
+}
+
+var (
+	genders    = []string{"male", "female"}
+	precisions = []string{string(entity.PrecisionDay), string(entity.PrecisionMonth), string(entity.PrecisionYear)}
+)
+
+var actorColumns = map[string]column[entity.Actor]{
+	"id":                      {kind: kindInt, value: func(a entity.Actor) any { return *a.Id }},
+	"name":                    {kind: kindText, value: func(a entity.Actor) any { return text(a.Name) }},
+	"surname":                 {kind: kindText, value: func(a entity.Actor) any { return text(a.Surname) }},
+	"patronymic":              {kind: kindText, value: func(a entity.Actor) any { return text(a.Patronymic) }},
+	"gender":                  {kind: kindEnum, values: genders, value: func(a entity.Actor) any { return text(a.Gender) }},
+	"date_of_birth":           {kind: kindDate, value: func(a entity.Actor) any { return dateTime(a.DateOfBirth) }},
+	"date_of_birth_precision": {kind: kindEnum, values: precisions, value: func(a entity.Actor) any { return datePrecision(a.DateOfBirth) }},
+}
+
+var movieColumns = map[string]column[entity.Movie]{
+	"id":                     {kind: kindInt, value: func(m entity.Movie) any { return *m.Id }},
+	"title":                  {kind: kindText, value: func(m entity.Movie) any { return text(m.Title) }},
+	"description":            {kind: kindText, value: func(m entity.Movie) any { return text(m.Description) }},
+	"release_date":           {kind: kindDate, value: func(m entity.Movie) any { return dateTime(m.ReleaseDate) }},
+	"release_date_precision": {kind: kindEnum, values: precisions, value: func(m entity.Movie) any { return datePrecision(m.ReleaseDate) }},
+	"rating": {kind: kindInt, value: func(m entity.Movie) any {
+		if m.Rating == nil {
+			return nil
+		}
+		return *m.Rating
+	}},
+}
+
+func text(s *string) any {
+	if s == nil {
+		return nil
+	}
+
+	return *s
+}
+
+// dateTime возвращает первый день периода: так дата хранится в postgres
+func dateTime(d *entity.Date) any {
+	if d == nil {
+		return nil
+	}
+
+	return d.Time()
+}
+
+func datePrecision(d *entity.Date) any {
+	if d == nil {
+		return string(entity.PrecisionDay)
+	}
+
+	return string(d.Precision())
+}
+
+// parse разбирает значение столбца из параметров запроса
+func (c column[T]) parse(name, val string) (any, error) {
+	switch c.kind {
+	case kindInt:
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid integer %q for column %s: %w", op, val, name, usecase.ErrInvalidData)
+		}
+		return n, nil
+	case kindDate:
+		d, err := entity.ParseDate(val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid date %q for column %s: %w", op, val, name, usecase.ErrInvalidData)
+		}
+		return d.Time(), nil
+	case kindEnum:
+		for _, v := range c.values {
+			if v == val {
+				return val, nil
+			}
+		}
+		return nil, fmt.Errorf("%s: invalid value %q for column %s: %w", op, val, name, usecase.ErrInvalidData)
+	}
+
+	return val, nil
+}
+
+// compare сравнивает значения столбцов одного типа. NULL больше любого значения:
+// при сортировке по возрастанию такие записи идут последними, как в postgres.
+func compare(a, b any) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	switch a := a.(type) {
+	case int:
+		return a - b.(int)
+	case string:
+		return strings.Compare(a, b.(string))
+	case time.Time:
+		return a.Compare(b.(time.Time))
+	}
+
+	return 0
+}
+
+// filterRows оставляет записи, у которых столбцы равны значениям из параметров фильтрации,
+// как условие WHERE column = 'value' AND ... репозитория postgres. NULL не равен ничему.
+func filterRows[T any](ctx context.Context, rows []T, columns map[string]column[T]) ([]T, error) {
+	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
+
+	type condition struct {
+		column column[T]
+		value  any
+	}
+
+	conditions := []condition{}
+	for k, v := range filter_options {
+		c, ok := columns[k]
+		if !ok {
+			return nil, fmt.Errorf("%s: column %q does not exist", op, k)
+		}
+
+		for _, val := range v {
+			parsed, err := c.parse(k, val)
+			if err != nil {
+				return nil, err
+			}
+			conditions = append(conditions, condition{column: c, value: parsed})
+		}
+	}
+
+	res := make([]T, 0, len(rows))
+
+next:
+	for _, row := range rows {
+		for _, cond := range conditions {
+			val := cond.column.value(row)
+			if val == nil || compare(val, cond.value) != 0 {
+				continue next
+			}
+		}
+
+		res = append(res, row)
+	}
+
+	return res, nil
+}
+
+// sortRows сортирует записи по параметрам сортировки, а без них - по столбцу def в порядке defOrder.
+// Записи с одинаковыми значениями остаются в прежнем порядке.
+func sortRows[T any](ctx context.Context, rows []T, columns map[string]column[T], def, defOrder string) error {
+	sort_options, _ := ctx.Value(sortoptions.SortOptionsContextKey).(map[string]string)
+	if len(sort_options) == 0 {
+		sort_options = map[string]string{def: defOrder}
+	}
+
+	// Порядок ключей map случаен, поэтому столбцы сравниваются по алфавиту
+	names := make([]string, 0, len(sort_options))
+	for k := range sort_options {
+		if _, ok := columns[k]; !ok {
+			return fmt.Errorf("%s: column %q does not exist", op, k)
+		}
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	sort.SliceStable(rows, func(i, j int) bool {
+		for _, name := range names {
+			c := columns[name]
+
+			res := compare(c.value(rows[i]), c.value(rows[j]))
+			if strings.EqualFold(sort_options[name], sortoptions.DESC) {
+				res = -res
+			}
+
+			if res != 0 {
+				return res < 0
+			}
+		}
+
+		return false
+	})
+
+	return nil
+}
+
+// nextPage оставляет записи, начиная с id из параметра пагинации
+func nextPage[T any](ctx context.Context, rows []T, id func(T) int) []T {
+	personID, _ := ctx.Value(pagination.NextPersonID).(int)
+
+	res := make([]T, 0, len(rows))
+	for _, row := range rows {
+		if id(row) >= personID {
+			res = append(res, row)
+		}
+	}
+
+	return res
+}
+
+// limit оставляет первую страницу записей
+func limit[T any](rows []T) []T {
+	if len(rows) > pageSize {
+		return rows[:pageSize]
+	}
+
+	return rows
+}
@@ -0,0 +1,221 @@
+// Package memory - репозитории, которые хранят данные в памяти процесса.
+// Репозитории повторяют поведение репозиториев PostgreSQL из пакета repo: фильтрацию, сортировку,
+// пагинацию и ограничения таблиц. Данные пропадают при остановке сервера, поэтому хранилище
+// подходит для разработки, демонстрации и тестов.
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"sync"
+
+	"filmoteka/internal/entity"
+)
+
+const op = "internal.usecase.repo.memory"
+const pageSize = 10
+
+// Storage - таблицы, общие для всех репозиториев. Репозитории одного Storage видят изменения
+// друг друга, как репозитории одной БД. Каждый метод репозитория выполняется целиком под блокировкой,
+// поэтому при ошибке данные не изменяются, как при откате транзакции.
+type Storage struct {
+	mu sync.RWMutex
+
+	actors map[int]entity.Actor
+	movies map[int]entity.Movie
+
+	// Таблица actors_movies
+	cast map[castKey]struct{}
+
+	actorsExternalIDs *externalIDs
+	moviesExternalIDs *externalIDs
+
+	// Таблица actor_redirects: актер, объединенный с другим, доступен по старому id
+	redirects map[int]int
+
+	// Последние выданные id, как у последовательностей SERIAL
+	lastActorID int
+	lastMovieID int
+}
+
+type castKey struct {
+	movieID int
+	actorID int
+}
+
+func New() *Storage {
+	return &Storage{
+		actors:            make(map[int]entity.Actor),
+		movies:            make(map[int]entity.Movie),
+		cast:              make(map[castKey]struct{}),
+		actorsExternalIDs: newExternalIDs(),
+		moviesExternalIDs: newExternalIDs(),
+		redirects:         make(map[int]int),
+	}
+}
+
+// read выполняет f под блокировкой на чтение
+func (s *Storage) read(ctx context.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return f()
+}
+
+// write выполняет f под блокировкой на запись
+func (s *Storage) write(ctx context.Context, f func() error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return f()
+}
+
+// nextActorID выдает id нового актера. Как и значение последовательности, id расходуется,
+// даже если актер не будет сохранен.
+func (s *Storage) nextActorID() int {
+	s.lastActorID++
+	return s.lastActorID
+}
+
+func (s *Storage) nextMovieID() int {
+	s.lastMovieID++
+	return s.lastMovieID
+}
+
+// actor возвращает актера вместе с внешними идентификаторами.
+// Актер, объединенный с другим, находится по старому id.
+func (s *Storage) actor(id int) (entity.Actor, error) {
+	if newID, ok := s.redirects[id]; ok {
+		id = newID
+	}
+
+	a, ok := s.actors[id]
+	if !ok {
+		return entity.Actor{}, fmt.Errorf("%s: actor was NOT found: %w", op, sql.ErrNoRows)
+	}
+
+	a = cloneActor(a)
+	a.ExternalIDs = s.actorsExternalIDs.get(id)
+
+	return a, nil
+}
+
+// movie возвращает фильм вместе с внешними идентификаторами
+func (s *Storage) movie(id int) (entity.Movie, error) {
+	m, ok := s.movies[id]
+	if !ok {
+		return entity.Movie{}, fmt.Errorf("%s: movie was NOT found: %w", op, sql.ErrNoRows)
+	}
+
+	m = cloneMovie(m)
+	m.ExternalIDs = s.moviesExternalIDs.get(id)
+
+	return m, nil
+}
+
+// sortedActors возвращает копии всех актеров в порядке id
+func (s *Storage) sortedActors() []entity.Actor {
+	res := make([]entity.Actor, 0, len(s.actors))
+	for _, a := range s.actors {
+		res = append(res, cloneActor(a))
+	}
+
+	sort.Slice(res, func(i, j int) bool { return *res[i].Id < *res[j].Id })
+
+	return res
+}
+
+// sortedMovies возвращает копии всех фильмов в порядке id
+func (s *Storage) sortedMovies() []entity.Movie {
+	res := make([]entity.Movie, 0, len(s.movies))
+	for _, m := range s.movies {
+		res = append(res, cloneMovie(m))
+	}
+
+	sort.Slice(res, func(i, j int) bool { return *res[i].Id < *res[j].Id })
+
+	return res
+}
+
+// castOf возвращает id актеров фильма по возрастанию
+func (s *Storage) castOf(movieID int) []int {
+	res := []int{}
+	for k := range s.cast {
+		if k.movieID == movieID {
+			res = append(res, k.actorID)
+		}
+	}
+
+	sort.Ints(res)
+
+	return res
+}
+
+// moviesOf возвращает id фильмов актера по возрастанию
+func (s *Storage) moviesOf(actorID int) []int {
+	res := []int{}
+	for k := range s.cast {
+		if k.actorID == actorID {
+			res = append(res, k.movieID)
+		}
+	}
+
+	sort.Ints(res)
+
+	return res
+}
+
+// Записи хранятся копиями: изменение значения, полученного от репозитория или
+// переданного в него, не должно менять данные хранилища
+
+func cloneActor(a entity.Actor) entity.Actor {
+	a.Id = clone(a.Id)
+	a.Name = clone(a.Name)
+	a.Surname = clone(a.Surname)
+	a.Patronymic = clone(a.Patronymic)
+	a.Gender = clone(a.Gender)
+	a.DateOfBirth = cloneDate(a.DateOfBirth)
+	a.ExternalIDs = nil
+
+	return a
+}
+
+func cloneMovie(m entity.Movie) entity.Movie {
+	m.Id = clone(m.Id)
+	m.Title = clone(m.Title)
+	m.Description = clone(m.Description)
+	m.ReleaseDate = cloneDate(m.ReleaseDate)
+	m.Rating = clone(m.Rating)
+	m.ExternalIDs = nil
+
+	return m
+}
+
+func clone[T any](p *T) *T {
+	if p == nil {
+		return nil
+	}
+
+	v := *p
+
+	return &v
+}
+
+// cloneDate копирует только саму дату, без формата вывода, выбранного клиентом
+func cloneDate(d *entity.Date) *entity.Date {
+	if d == nil {
+		return nil
+	}
+
+	return &entity.Date{Year: d.Year, Month: d.Month, Day: d.Day}
+}
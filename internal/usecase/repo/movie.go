@@ -4,12 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	"strings"
-	"time"
 
 	"filmoteka/internal/controller/middleware/filter"
 	"filmoteka/internal/controller/middleware/pagination"
-	"filmoteka/internal/controller/middleware/sort"
 	"filmoteka/internal/entity"
 
 	"github.com/Masterminds/squirrel"
@@ -24,7 +21,7 @@ func NewMoviesRepo(db *sql.DB) *MoviesRepo {
 	return &MoviesRepo{db: sqlx.NewDb(db, "postgres")}
 }
 
-const MovieQueryFind = `SELECT *, TO_CHAR(release_date, 'DD.MM.YYYY') AS release_date FROM movies WHERE id = $1`
+const MovieQueryFind = `SELECT ` + movieColumns + ` FROM movies WHERE id = $1`
 
 func (r *MoviesRepo) Get(ctx context.Context, id int) (entity.Movie, error) {
 
@@ -35,11 +32,34 @@ func (r *MoviesRepo) Get(ctx context.Context, id int) (entity.Movie, error) {
 		return entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
+	res.ExternalIDs, err = moviesExternalIDs.get(ctx, r.db, id)
+
+	if err != nil {
+		return entity.Movie{}, err
+	}
+
 	return res, nil
 }
 
+// GetByExternalID возвращает фильм по его идентификатору во внешнем каталоге
+func (r *MoviesRepo) GetByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error) {
+
+	id, err := moviesExternalIDs.find(ctx, r.db, source, externalID)
+
+	if err != nil {
+		return entity.Movie{}, err
+	}
+
+	return r.Get(ctx, id)
+}
+
+// MatchExternalIDs возвращает id фильма, которому принадлежит любой из внешних идентификаторов
+func (r *MoviesRepo) MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error) {
+	return moviesExternalIDs.match(ctx, r.db, ids)
+}
+
 const MovieQueryFindMovie = `
-SELECT DISTINCT movies.id, movies.title, movies.description, TO_CHAR(movies.release_date, 'DD.MM.YYYY') AS release_date, movies.rating
+SELECT DISTINCT movies.id, movies.title, movies.description, ` + movieReleaseDate + `, movies.rating
 FROM movies
 JOIN actors_movies ON movies.id = actors_movies.movie_id
 JOIN actors ON actors_movies.actor_id = actors.id
@@ -72,21 +92,44 @@ func (r *MoviesRepo) GetMovie(ctx context.Context) ([]entity.Movie, error) {
 		return []entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
+	// Дополним найденные фильмы внешними идентификаторами
+	ids := make([]int, 0, len(res))
+	for _, m := range res {
+		ids = append(ids, *m.Id)
+	}
+
+	externalIDs, err := moviesExternalIDs.getMany(ctx, r.db, ids)
+
+	if err != nil {
+		return []entity.Movie{}, err
+	}
+
+	for i := range res {
+		res[i].ExternalIDs = externalIDs[*res[i].Id]
+	}
+
 	return res, nil
 }
 
-const MovieQuerySave = `INSERT INTO movies(title, description, release_date, rating)
-					VALUES($1, $2, TO_DATE($3, 'DD.MM.YYYY'), $4)
+const MovieQuerySave = `INSERT INTO movies(title, description, release_date, release_date_precision, rating)
+					VALUES($1, $2, $3, $4, $5)
 					RETURNING id`
 
 func (r *MoviesRepo) Save(ctx context.Context, data entity.MovieData) (int, error) {
 
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
 	var res int
 
-	err := r.db.GetContext(ctx, &res, MovieQuerySave,
+	err = tx.GetContext(ctx, &res, MovieQuerySave,
 		data.Title,
 		data.Description,
 		data.ReleaseDate,
+		precision(data.ReleaseDate),
 		data.Rating,
 	)
 
@@ -94,7 +137,14 @@ func (r *MoviesRepo) Save(ctx context.Context, data entity.MovieData) (int, erro
 		return 0, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
-	return res, nil
+	// Сохраняем идентификаторы фильма во внешних каталогах
+	err = moviesExternalIDs.save(ctx, tx, res, data.ExternalIDs)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return res, tx.Commit()
 }
 
 func (r *MoviesRepo) Update(ctx context.Context, updates entity.Movie) (entity.Movie, error) {
@@ -111,28 +161,54 @@ func (r *MoviesRepo) Update(ctx context.Context, updates entity.Movie) (entity.M
 		return entity.Movie{}, fmt.Errorf("%s: Error: %w", op, err)
 	}
 
-	qb = qb.SetMap(data)
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Movie{}, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
 
-	// Составим выражение для оператора SQL Where
-	stmt := fmt.Sprintf(" WHERE id = %d", *updates.Id)
+	var res entity.Movie
 
-	sql, i, err := qb.ToSql()
+	// Если изменяются только внешние идентификаторы, таблицу movies не обновляем
+	if len(data) == 0 {
+		err = tx.GetContext(ctx, &res, MovieQueryFind, *updates.Id)
+	} else {
+		qb = qb.SetMap(data)
+
+		// Составим выражение для оператора SQL Where
+		stmt := fmt.Sprintf(" WHERE id = %d", *updates.Id)
+
+		var sql string
+		var i []interface{}
+
+		sql, i, err = qb.ToSql()
+
+		sql = sql + stmt + " RETURNING " + movieColumns
+
+		if err != nil {
+			return entity.Movie{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+		}
 
-	sql = sql + stmt + " RETURNING id, title, description, TO_CHAR(release_date, 'DD.MM.YYYY') AS release_date, rating"
+		err = tx.GetContext(ctx, &res, sql, i...)
+	}
 
 	if err != nil {
-		return entity.Movie{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+		return entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
-	var res entity.Movie
+	err = moviesExternalIDs.save(ctx, tx, *res.Id, updates.ExternalIDs)
 
-	err = r.db.GetContext(ctx, &res, sql, i...)
+	if err != nil {
+		return entity.Movie{}, err
+	}
+
+	res.ExternalIDs, err = moviesExternalIDs.get(ctx, tx, *res.Id)
 
 	if err != nil {
-		return entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+		return entity.Movie{}, err
 	}
 
-	return res, nil
+	return res, tx.Commit()
 }
 
 func (r *MoviesRepo) Delete(ctx context.Context, id int) (entity.Movie, error) {
@@ -147,7 +223,7 @@ func (r *MoviesRepo) Delete(ctx context.Context, id int) (entity.Movie, error) {
 
 	sql, i, err := qb.ToSql()
 
-	sql = sql + stmt + " RETURNING id, title, description, TO_CHAR(release_date, 'DD.MM.YYYY') AS release_date, rating"
+	sql = sql + stmt + " RETURNING " + movieColumns
 
 	if err != nil {
 		return entity.Movie{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
@@ -164,48 +240,45 @@ func (r *MoviesRepo) Delete(ctx context.Context, id int) (entity.Movie, error) {
 	return res, nil
 }
 
+// List возвращает фильмы, отсортированные по параметрам сортировки, а без них - по убыванию рейтинга
 func (r *MoviesRepo) List(ctx context.Context) ([]entity.Movie, error) {
 
-	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+	// Используем оператор ORDER BY для сортировки
+	order, err := orderBy(ctx, movieFilters, "movies.rating DESC")
 
-	// Подготавливаем SQL запрос
-	qb := psql.Select("id, title, description, rating, TO_CHAR(release_date, 'DD.MM.YYYY') AS release_date").From("movies")
+	if err != nil {
+		return []entity.Movie{}, err
+	}
 
-	// Составим выражение для оператора SQL Where ... AND ...
-	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
+	return r.list(ctx, squirrel.And{}, order)
+}
 
-	stmt := []string{}
-	for k, v := range filter_options {
-		for _, val := range v {
-			stmt = append(stmt, fmt.Sprintf("%s = '%s'", k, val))
-		}
-	}
+func (r *MoviesRepo) Next(ctx context.Context) ([]entity.Movie, error) {
 
-	// Решаем, использовать оператор WHERE или нет
-	if len(stmt) != 0 {
-		qb = qb.Where(strings.Join(stmt, " AND "))
-	}
+	// Условие пагинации
+	personID := ctx.Value(pagination.NextPersonID).(int)
 
-	// Используем оператор ORDER BY для сортировки
-	sort_options, _ := ctx.Value(sort.SortOptionsContextKey).(map[string]string)
+	return r.list(ctx, squirrel.GtOrEq{"movies.id": personID}, "movies.id ASC")
+}
 
-	stmt = []string{}
+// list возвращает первую страницу фильмов, подходящих под параметры фильтрации и условие cond
+func (r *MoviesRepo) list(ctx context.Context, cond squirrel.Sqlizer, order string) ([]entity.Movie, error) {
 
-	for k, v := range sort_options {
-		stmt = append(stmt, fmt.Sprintf("%s %s", k, v))
-	}
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
 
-	str := strings.Join(stmt, ", ")
+	// Составим выражение для оператора SQL Where ... AND ...
+	filters, err := where(ctx, movieFilters)
 
-	if len(str) == 0 {
-		qb = qb.OrderBy("rating DESC")
-	} else {
-		qb = qb.OrderBy(str)
+	if err != nil {
+		return []entity.Movie{}, err
 	}
 
-	qb = qb.Limit(pageSize)
-
-	sql, i, err := qb.ToSql()
+	sql, i, err := psql.Select(movieColumns).
+		From("movies").
+		Where(append(filters, cond)).
+		OrderBy(order).
+		Limit(pageSize).
+		ToSql()
 
 	if err != nil {
 		return []entity.Movie{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
@@ -218,51 +291,20 @@ func (r *MoviesRepo) List(ctx context.Context) ([]entity.Movie, error) {
 		return []entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
 	}
 
-	return res, nil
-}
-
-func (r *MoviesRepo) Next(ctx context.Context) ([]entity.Movie, error) {
-
-	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
-
-	// Подготавливаем SQL запрос
-	qb := psql.Select("id, name, surname, patronymic, gender, TO_CHAR(date_of_birth, 'DD.MM.YYYY') AS date_of_birth").From("movies")
-
-	// Составим выражение для оператора SQL Where ... AND ...
-	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
-
-	stmt := []string{}
-	for k, v := range filter_options {
-		for _, val := range v {
-			stmt = append(stmt, fmt.Sprintf("%s = '%s'", k, val))
-		}
-	}
-
-	// Решаем, использовать оператор WHERE или нет
-	if len(stmt) != 0 {
-		qb = qb.Where(strings.Join(stmt, " AND "))
+	// Дополним страницу внешними идентификаторами
+	ids := make([]int, 0, len(res))
+	for _, m := range res {
+		ids = append(ids, *m.Id)
 	}
 
-	// Условие пагинации
-	personID := ctx.Value(pagination.NextPersonID).(int)
-	qb = qb.Where(fmt.Sprintf("id >= %d", personID))
-
-	// Используем оператор ORDER BY
-	qb = qb.OrderBy("id ASC")
-
-	qb = qb.Limit(pageSize)
-
-	sql, i, err := qb.ToSql()
+	externalIDs, err := moviesExternalIDs.getMany(ctx, r.db, ids)
 
 	if err != nil {
-		return []entity.Movie{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+		return []entity.Movie{}, err
 	}
 
-	res := []entity.Movie{}
-	err = r.db.SelectContext(ctx, &res, sql, i...)
-
-	if err != nil {
-		return []entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	for i := range res {
+		res[i].ExternalIDs = externalIDs[*res[i].Id]
 	}
 
 	return res, nil
@@ -280,25 +322,29 @@ func getMapMovie(updates entity.Movie) (map[string]interface{}, error) {
 	}
 
 	if val := updates.MovieData.ReleaseDate; val != nil {
-		layout := "02.01.2006" // шаблон формата даты в строке
-
-		// распарсим строку в формат даты
-		date, err := time.Parse(layout, *val)
-		if err != nil {
-			return res, fmt.Errorf("%s: Ошибка при парсинге даты", err)
-		}
-
-		// Преобразовать дату в формат PostgreSQL
-		res["release_date"] = date.Format("2006-01-02")
+		res["release_date"] = *val
+		res["release_date_precision"] = val.Precision()
 	}
 
 	if val := updates.Rating; val != nil {
 		res["rating"] = *val
 	}
 
-	if len(res) == 0 {
+	if len(res) == 0 && len(updates.ExternalIDs) == 0 {
 		return res, fmt.Errorf("%s: Data for update operation were NOT specified", op)
 	}
 
 	return res, nil
 }
+
+// Count возвращает число фильмов
+func (r *MoviesRepo) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM movies`)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: DB returned error: %w", op, err)
+	}
+
+	return count, nil
+}
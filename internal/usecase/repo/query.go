@@ -0,0 +1,92 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	sortpkg "sort"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/sort"
+)
+
+// column - столбец таблицы, по которому можно фильтровать и сортировать записи.
+// Значения фильтров передаются в запрос параметрами, их тип проверяет postgres.
+type column struct {
+	name string
+	// date - значение фильтра приводится к ISO 8601 функцией filterDate
+	date bool
+}
+
+var actorFilters = map[string]column{
+	"id":                      {name: "actors.id"},
+	"name":                    {name: "actors.name"},
+	"surname":                 {name: "actors.surname"},
+	"patronymic":              {name: "actors.patronymic"},
+	"gender":                  {name: "actors.gender"},
+	"date_of_birth":           {name: "actors.date_of_birth", date: true},
+	"date_of_birth_precision": {name: "actors.date_of_birth_precision"},
+}
+
+var movieFilters = map[string]column{
+	"id":                     {name: "movies.id"},
+	"title":                  {name: "movies.title"},
+	"description":            {name: "movies.description"},
+	"release_date":           {name: "movies.release_date", date: true},
+	"release_date_precision": {name: "movies.release_date_precision"},
+	"rating":                 {name: "movies.rating"},
+}
+
+// where составляет условие WHERE column = $1 AND ... из параметров фильтрации
+func where(ctx context.Context, columns map[string]column) (squirrel.And, error) {
+	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
+
+	res := squirrel.And{}
+	for k, v := range filter_options {
+		c, ok := columns[k]
+		if !ok {
+			return nil, fmt.Errorf("%s: column %q does not exist", op, k)
+		}
+
+		for _, val := range v {
+			if c.date {
+				val = filterDate(val)
+			}
+			res = append(res, squirrel.Eq{c.name: val})
+		}
+	}
+
+	return res, nil
+}
+
+// orderBy составляет выражение ORDER BY из параметров сортировки, а без них возвращает def.
+// Имена столбцов указаны с таблицей: иначе ORDER BY сортировал бы по строке с датой из списка SELECT.
+func orderBy(ctx context.Context, columns map[string]column, def string) (string, error) {
+	sort_options, _ := ctx.Value(sort.SortOptionsContextKey).(map[string]string)
+	if len(sort_options) == 0 {
+		return def, nil
+	}
+
+	// Порядок ключей map случаен, поэтому столбцы перечисляются по алфавиту
+	names := make([]string, 0, len(sort_options))
+	for k := range sort_options {
+		if _, ok := columns[k]; !ok {
+			return "", fmt.Errorf("%s: column %q does not exist", op, k)
+		}
+		names = append(names, k)
+	}
+	sortpkg.Strings(names)
+
+	stmt := make([]string, 0, len(names))
+	for _, k := range names {
+		if strings.EqualFold(sort_options[k], sort.DESC) {
+			stmt = append(stmt, columns[k].name+" DESC")
+		} else {
+			stmt = append(stmt, columns[k].name+" ASC")
+		}
+	}
+
+	return strings.Join(stmt, ", "), nil
+}
@@ -0,0 +1,76 @@
+package replica
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+type ActorsRepo struct {
+	r        *Router
+	primary  usecase.ActorsRepo
+	replicas []usecase.ActorsRepo
+}
+
+func NewActorsRepo(r *Router, primary usecase.ActorsRepo, replicas ...usecase.ActorsRepo) *ActorsRepo {
+	return &ActorsRepo{r: r, primary: primary, replicas: replicas}
+}
+
+func (a *ActorsRepo) Save(ctx context.Context, data entity.ActorData) (int, error) {
+	return write(ctx, a.r, func() (int, error) { return a.primary.Save(ctx, data) })
+}
+
+func (a *ActorsRepo) Update(ctx context.Context, updates entity.Actor) (entity.Actor, error) {
+	return write(ctx, a.r, func() (entity.Actor, error) { return a.primary.Update(ctx, updates) })
+}
+
+func (a *ActorsRepo) Delete(ctx context.Context, id int) (entity.Actor, error) {
+	return write(ctx, a.r, func() (entity.Actor, error) { return a.primary.Delete(ctx, id) })
+}
+
+func (a *ActorsRepo) Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error) {
+	return write(ctx, a.r, func() (entity.Actor, error) { return a.primary.Merge(ctx, sourceID, targetID) })
+}
+
+// MatchExternalIDs выполняется на основной БД: по его результату upsert выбирает между Save и Update,
+// и устаревшие данные реплики привели бы к созданию дубликата
+func (a *ActorsRepo) MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error) {
+	return a.primary.MatchExternalIDs(ctx, ids)
+}
+
+func (a *ActorsRepo) Get(ctx context.Context, id int) (entity.Actor, error) {
+	return read(ctx, a.r, a.primary, a.replicas, func(repo usecase.ActorsRepo) (entity.Actor, error) {
+		return repo.Get(ctx, id)
+	})
+}
+
+func (a *ActorsRepo) GetByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error) {
+	return read(ctx, a.r, a.primary, a.replicas, func(repo usecase.ActorsRepo) (entity.Actor, error) {
+		return repo.GetByExternalID(ctx, source, externalID)
+	})
+}
+
+func (a *ActorsRepo) List(ctx context.Context) ([]entity.Actor, error) {
+	return read(ctx, a.r, a.primary, a.replicas, func(repo usecase.ActorsRepo) ([]entity.Actor, error) {
+		return repo.List(ctx)
+	})
+}
+
+func (a *ActorsRepo) Next(ctx context.Context) ([]entity.Actor, error) {
+	return read(ctx, a.r, a.primary, a.replicas, func(repo usecase.ActorsRepo) ([]entity.Actor, error) {
+		return repo.Next(ctx)
+	})
+}
+
+func (a *ActorsRepo) Profiles(ctx context.Context) ([]entity.ActorProfile, error) {
+	return read(ctx, a.r, a.primary, a.replicas, func(repo usecase.ActorsRepo) ([]entity.ActorProfile, error) {
+		return repo.Profiles(ctx)
+	})
+}
+
+func (a *ActorsRepo) Count(ctx context.Context) (int, error) {
+	return read(ctx, a.r, a.primary, a.replicas, func(repo usecase.ActorsRepo) (int, error) {
+		return repo.Count(ctx)
+	})
+}
@@ -0,0 +1,52 @@
+package replica
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+type ActorsMoviesRepo struct {
+	r        *Router
+	primary  usecase.ActorsMoviesRepo
+	replicas []usecase.ActorsMoviesRepo
+}
+
+func NewActorsMoviesRepo(r *Router, primary usecase.ActorsMoviesRepo, replicas ...usecase.ActorsMoviesRepo) *ActorsMoviesRepo {
+	return &ActorsMoviesRepo{r: r, primary: primary, replicas: replicas}
+}
+
+func (am *ActorsMoviesRepo) Save(ctx context.Context, data entity.ActorMovie) error {
+	_, err := write(ctx, am.r, func() (struct{}, error) { return struct{}{}, am.primary.Save(ctx, data) })
+	return err
+}
+
+func (am *ActorsMoviesRepo) Delete(ctx context.Context, data entity.ActorMovie) error {
+	_, err := write(ctx, am.r, func() (struct{}, error) { return struct{}{}, am.primary.Delete(ctx, data) })
+	return err
+}
+
+func (am *ActorsMoviesRepo) List(ctx context.Context) ([]entity.ActorMovieData, error) {
+	return read(ctx, am.r, am.primary, am.replicas, func(repo usecase.ActorsMoviesRepo) ([]entity.ActorMovieData, error) {
+		return repo.List(ctx)
+	})
+}
+
+func (am *ActorsMoviesRepo) Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+	return read(ctx, am.r, am.primary, am.replicas, func(repo usecase.ActorsMoviesRepo) (map[int][]entity.Actor, error) {
+		return repo.Cast(ctx, movieIDs)
+	})
+}
+
+func (am *ActorsMoviesRepo) Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+	return read(ctx, am.r, am.primary, am.replicas, func(repo usecase.ActorsMoviesRepo) (map[int][]entity.Movie, error) {
+		return repo.Filmography(ctx, actorIDs)
+	})
+}
+
+func (am *ActorsMoviesRepo) Count(ctx context.Context) (int, error) {
+	return read(ctx, am.r, am.primary, am.replicas, func(repo usecase.ActorsMoviesRepo) (int, error) {
+		return repo.Count(ctx)
+	})
+}
@@ -0,0 +1,71 @@
+package replica
+
+import (
+	"context"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+type MoviesRepo struct {
+	r        *Router
+	primary  usecase.MoviesRepo
+	replicas []usecase.MoviesRepo
+}
+
+func NewMoviesRepo(r *Router, primary usecase.MoviesRepo, replicas ...usecase.MoviesRepo) *MoviesRepo {
+	return &MoviesRepo{r: r, primary: primary, replicas: replicas}
+}
+
+func (m *MoviesRepo) Save(ctx context.Context, data entity.MovieData) (int, error) {
+	return write(ctx, m.r, func() (int, error) { return m.primary.Save(ctx, data) })
+}
+
+func (m *MoviesRepo) Update(ctx context.Context, updates entity.Movie) (entity.Movie, error) {
+	return write(ctx, m.r, func() (entity.Movie, error) { return m.primary.Update(ctx, updates) })
+}
+
+func (m *MoviesRepo) Delete(ctx context.Context, id int) (entity.Movie, error) {
+	return write(ctx, m.r, func() (entity.Movie, error) { return m.primary.Delete(ctx, id) })
+}
+
+// MatchExternalIDs выполняется на основной БД, как и у актеров
+func (m *MoviesRepo) MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error) {
+	return m.primary.MatchExternalIDs(ctx, ids)
+}
+
+func (m *MoviesRepo) Get(ctx context.Context, id int) (entity.Movie, error) {
+	return read(ctx, m.r, m.primary, m.replicas, func(repo usecase.MoviesRepo) (entity.Movie, error) {
+		return repo.Get(ctx, id)
+	})
+}
+
+func (m *MoviesRepo) GetByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error) {
+	return read(ctx, m.r, m.primary, m.replicas, func(repo usecase.MoviesRepo) (entity.Movie, error) {
+		return repo.GetByExternalID(ctx, source, externalID)
+	})
+}
+
+func (m *MoviesRepo) GetMovie(ctx context.Context) ([]entity.Movie, error) {
+	return read(ctx, m.r, m.primary, m.replicas, func(repo usecase.MoviesRepo) ([]entity.Movie, error) {
+		return repo.GetMovie(ctx)
+	})
+}
+
+func (m *MoviesRepo) List(ctx context.Context) ([]entity.Movie, error) {
+	return read(ctx, m.r, m.primary, m.replicas, func(repo usecase.MoviesRepo) ([]entity.Movie, error) {
+		return repo.List(ctx)
+	})
+}
+
+func (m *MoviesRepo) Next(ctx context.Context) ([]entity.Movie, error) {
+	return read(ctx, m.r, m.primary, m.replicas, func(repo usecase.MoviesRepo) ([]entity.Movie, error) {
+		return repo.Next(ctx)
+	})
+}
+
+func (m *MoviesRepo) Count(ctx context.Context) (int, error) {
+	return read(ctx, m.r, m.primary, m.replicas, func(repo usecase.MoviesRepo) (int, error) {
+		return repo.Count(ctx)
+	})
+}
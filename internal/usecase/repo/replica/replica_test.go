@@ -0,0 +1,202 @@
+package replica
+
+import (
+	"context"
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"filmoteka/internal/controller/middleware/client"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/internal/usecase/repo/memory"
+)
+
+// storage - хранилище в памяти с одним актером, по фамилии которого видно, какая БД ответила
+func storage(t *testing.T, surname string) usecase.ActorsRepo {
+	t.Helper()
+
+	r := memory.NewActorsRepo(memory.New())
+
+	name := "Актер"
+	if _, err := r.Save(context.Background(), entity.ActorData{Name: &name, Surname: &surname}); err != nil {
+		t.Fatalf("failed to save actor: %s", err)
+	}
+
+	return r
+}
+
+// clock - время, которое переводится вручную
+type clock struct {
+	t time.Time
+}
+
+func (c *clock) now() time.Time {
+	return c.t
+}
+
+func newRouter(window time.Duration) (*Router, *clock) {
+	c := &clock{t: time.Date(2023, 11, 1, 12, 0, 0, 0, time.UTC)}
+
+	r := NewRouter(window)
+	r.now = c.now
+
+	return r, c
+}
+
+// servedBy возвращает фамилию актера 1 из БД, выбранной для чтения
+func servedBy(t *testing.T, ctx context.Context, r *ActorsRepo) string {
+	t.Helper()
+
+	a, err := r.Get(ctx, 1)
+	if err != nil {
+		t.Fatalf("failed to get actor: %s", err)
+	}
+
+	return *a.Surname
+}
+
+func TestReadsGoToReplicas(t *testing.T) {
+	router, _ := newRouter(time.Second)
+	r := NewActorsRepo(router, storage(t, "primary"), storage(t, "replica1"), storage(t, "replica2"))
+	ctx := context.Background()
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		got = append(got, servedBy(t, ctx, r))
+	}
+
+	want := []string{"replica1", "replica2", "replica1", "replica2"}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("reads were served by %v, want %v", got, want)
+		}
+	}
+}
+
+func TestWritesGoToPrimary(t *testing.T) {
+	router, _ := newRouter(time.Second)
+	primary := storage(t, "primary")
+	r := NewActorsRepo(router, primary, storage(t, "replica"))
+	ctx := context.Background()
+
+	name, surname := "Новый", "Актер"
+	id, err := r.Save(ctx, entity.ActorData{Name: &name, Surname: &surname})
+	if err != nil {
+		t.Fatalf("failed to save actor: %s", err)
+	}
+
+	if _, err = primary.Get(ctx, id); err != nil {
+		t.Fatalf("actor was NOT saved to primary: %s", err)
+	}
+
+	// Клиент неизвестен: чтение идет с реплики, где нового актера еще нет
+	if _, err = r.Get(ctx, id); err == nil {
+		t.Fatalf("read without client was served by primary")
+	}
+}
+
+func TestMatchExternalIDsGoesToPrimary(t *testing.T) {
+	router, _ := newRouter(time.Second)
+	primary := storage(t, "primary")
+	r := NewActorsRepo(router, primary, storage(t, "replica"))
+	ctx := context.Background()
+
+	name, surname := "Новый", "Актер"
+	id, err := primary.Save(ctx, entity.ActorData{Name: &name, Surname: &surname, ExternalIDs: entity.ExternalIDs{"imdb": "nm0000001"}})
+	if err != nil {
+		t.Fatalf("failed to save actor: %s", err)
+	}
+
+	got, err := r.MatchExternalIDs(ctx, entity.ExternalIDs{"imdb": "nm0000001"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got != id {
+		t.Fatalf("id = %d, want %d", got, id)
+	}
+}
+
+func TestReadYourWrites(t *testing.T) {
+	router, c := newRouter(5 * time.Second)
+	r := NewActorsRepo(router, storage(t, "primary"), storage(t, "replica"))
+
+	writer := client.WithAddr(context.Background(), "10.0.0.1:5000")
+	// Тот же клиент с другого порта
+	writerAgain := client.WithAddr(context.Background(), "10.0.0.1:5001")
+	other := client.WithAddr(context.Background(), "10.0.0.2:5000")
+
+	if got := servedBy(t, writer, r); got != "replica" {
+		t.Fatalf("read before write was served by %s", got)
+	}
+
+	surname := "Обновленный"
+	if _, err := r.Update(writer, entity.Actor{Id: ptr(1), ActorData: entity.ActorData{Surname: &surname}}); err != nil {
+		t.Fatalf("failed to update actor: %s", err)
+	}
+
+	c.t = c.t.Add(4 * time.Second)
+
+	if got := servedBy(t, writerAgain, r); got != surname {
+		t.Fatalf("read after write was served by %s, want primary", got)
+	}
+
+	if got := servedBy(t, other, r); got != "replica" {
+		t.Fatalf("read of other client was served by %s, want replica", got)
+	}
+
+	c.t = c.t.Add(time.Second)
+
+	if got := servedBy(t, writer, r); got != "replica" {
+		t.Fatalf("read after stickiness window was served by %s, want replica", got)
+	}
+}
+
+func TestFailedWriteIsNotSticky(t *testing.T) {
+	router, _ := newRouter(5 * time.Second)
+	r := NewActorsRepo(router, storage(t, "primary"), storage(t, "replica"))
+	ctx := client.WithAddr(context.Background(), "10.0.0.1:5000")
+
+	if _, err := r.Delete(ctx, 42); err == nil {
+		t.Fatalf("actor 42 was deleted")
+	}
+
+	if got := servedBy(t, ctx, r); got != "replica" {
+		t.Fatalf("read after failed write was served by %s, want replica", got)
+	}
+}
+
+func TestSweepExpiredWrites(t *testing.T) {
+	router, c := newRouter(time.Second)
+
+	router.wrote(client.WithAddr(context.Background(), "10.0.0.1:5000"))
+	c.t = c.t.Add(2 * time.Second)
+	router.wrote(client.WithAddr(context.Background(), "10.0.0.2:5000"))
+
+	if len(router.writes) != 1 {
+		t.Fatalf("writes = %v, want only 10.0.0.2", router.writes)
+	}
+}
+
+// unavailableRepo - реплика, к которой нельзя подключиться
+type unavailableRepo struct {
+	usecase.ActorsRepo
+}
+
+func (unavailableRepo) Get(context.Context, int) (entity.Actor, error) {
+	return entity.Actor{}, driver.ErrBadConn
+}
+
+func TestUnavailableReplicaFallsBackToPrimary(t *testing.T) {
+	router, _ := newRouter(time.Second)
+	r := NewActorsRepo(router, storage(t, "primary"), unavailableRepo{})
+
+	if got := servedBy(t, context.Background(), r); got != "primary" {
+		t.Fatalf("read was served by %s, want primary", got)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
@@ -0,0 +1,117 @@
+// Package replica распределяет запросы репозиториев между основной БД и репликами:
+// чтение идет с реплик по очереди, а запись - в основную БД.
+//
+// Реплики отстают от основной БД, поэтому клиент, который только что изменил данные, в течение
+// окна Router читает из основной БД и видит свои изменения. Клиент определяется по адресу
+// из контекста запроса (middleware client). Без адреса клиента чтение всегда идет с реплик.
+package replica
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"filmoteka/internal/controller/middleware/client"
+)
+
+// Router выбирает БД для запроса и запоминает, когда клиенты последний раз изменяли данные
+type Router struct {
+	window time.Duration
+	now    func() time.Time
+
+	// next - номер следующей реплики
+	next atomic.Uint64
+
+	mu     sync.Mutex
+	writes map[string]time.Time
+	// swept - когда из writes последний раз удалялись устаревшие записи
+	swept time.Time
+}
+
+// NewRouter создает Router, который после изменения данных читает для клиента из основной БД
+// в течение window
+func NewRouter(window time.Duration) *Router {
+	return &Router{window: window, now: time.Now, writes: map[string]time.Time{}}
+}
+
+// wrote запоминает, что клиент из контекста изменил данные
+func (r *Router) wrote(ctx context.Context) {
+	id, ok := client.ID(ctx)
+	if !ok || r.window <= 0 {
+		return
+	}
+
+	now := r.now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.writes[id] = now
+
+	// Устаревшие записи удаляются не чаще одного раза за окно
+	if now.Sub(r.swept) < r.window {
+		return
+	}
+
+	for k, t := range r.writes {
+		if now.Sub(t) >= r.window {
+			delete(r.writes, k)
+		}
+	}
+
+	r.swept = now
+}
+
+// sticky сообщает, изменял ли клиент из контекста данные в течение окна
+func (r *Router) sticky(ctx context.Context) bool {
+	id, ok := client.ID(ctx)
+	if !ok {
+		return false
+	}
+
+	r.mu.Lock()
+	t, ok := r.writes[id]
+	r.mu.Unlock()
+
+	return ok && r.now().Sub(t) < r.window
+}
+
+// pick возвращает номер реплики из n
+func (r *Router) pick(n int) int {
+	return int((r.next.Add(1) - 1) % uint64(n))
+}
+
+// read выполняет чтение f на реплике или, если клиент недавно изменял данные, на основной БД.
+// Если реплика недоступна, чтение повторяется на основной БД.
+func read[R, T any](ctx context.Context, r *Router, primary R, replicas []R, f func(R) (T, error)) (T, error) {
+	if len(replicas) == 0 || r.sticky(ctx) {
+		return f(primary)
+	}
+
+	res, err := f(replicas[r.pick(len(replicas))])
+	if err != nil && unavailable(err) && ctx.Err() == nil {
+		return f(primary)
+	}
+
+	return res, err
+}
+
+// write выполняет запись f на основной БД и запоминает клиента
+func write[T any](ctx context.Context, r *Router, f func() (T, error)) (T, error) {
+	res, err := f()
+	if err == nil {
+		r.wrote(ctx)
+	}
+
+	return res, err
+}
+
+// unavailable сообщает, что ошибка вызвана недоступностью БД, а не самим запросом
+func unavailable(err error) bool {
+	var netErr net.Error
+	return errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr)
+}
@@ -0,0 +1,499 @@
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"sort"
+	"strings"
+	"testing"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/entity"
+)
+
+const (
+	actorIvan  = `{"id":1,"name":"Иван","surname":"Петров","patronymic":"Сергеевич","gender":"male","date_of_birth":"1970-03-15","external_ids":{"imdb":"nm0000001"}}`
+	actorAnna  = `{"id":2,"name":"Анна","surname":"Смирнова","gender":"female","date_of_birth":"1985-07"}`
+	actorJohn  = `{"id":3,"name":"John","surname":"Smith","gender":"male","date_of_birth":"1960","external_ids":{"tmdb":"6384"}}`
+	actorMaria = `{"id":5,"name":"Мария","surname":"Иванова","gender":"female"}`
+)
+
+func (s suite) actorsGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		want    string
+		wantErr error
+	}{
+		{name: "with external ids", id: 1, want: actorIvan},
+		{name: "month precision", id: 2, want: actorAnna},
+		{name: "no date of birth", id: 5, want: actorMaria},
+		{name: "merged actor by old id", id: 100, want: actorJohn},
+		{name: "not found", id: 42, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Actors
+
+			got, err := r.Get(context.Background(), tt.id)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func (s suite) actorsGetByExternalID(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		externalID string
+		want       string
+		wantErr    error
+	}{
+		{name: "imdb", source: entity.SourceIMDb, externalID: "nm0000001", want: actorIvan},
+		{name: "tmdb", source: entity.SourceTMDb, externalID: "6384", want: actorJohn},
+		{name: "not found", source: entity.SourceIMDb, externalID: "nm9999999", wantErr: sql.ErrNoRows},
+		{name: "id of other source", source: entity.SourceKinopoisk, externalID: "nm0000001", wantErr: sql.ErrNoRows},
+		{name: "unknown source", source: "wikidata", externalID: "Q1", wantErr: errInvalidText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Actors
+
+			got, err := r.GetByExternalID(context.Background(), tt.source, tt.externalID)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func (s suite) actorsMatchExternalIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     entity.ExternalIDs
+		want    int
+		wantErr error
+	}{
+		{name: "one id", ids: entity.ExternalIDs{"imdb": "nm0000001"}, want: 1},
+		{name: "one of ids is known", ids: entity.ExternalIDs{"imdb": "nm0000404", "tmdb": "6384"}, want: 3},
+		{name: "unknown ids", ids: entity.ExternalIDs{"imdb": "nm0000404"}, wantErr: sql.ErrNoRows},
+		{name: "ids of different actors", ids: entity.ExternalIDs{"imdb": "nm0000001", "tmdb": "6384"}, wantErr: errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Actors
+
+			got, err := r.MatchExternalIDs(context.Background(), tt.ids)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			if got != tt.want {
+				t.Fatalf("id = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func (s suite) actorsSave(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    entity.ActorData
+		want    string
+		wantErr error
+	}{
+		{
+			name: "all fields",
+			data: entity.ActorData{
+				Name: ptr("Олег"), Surname: ptr("Меньшиков"), Patronymic: ptr("Евгеньевич"), Gender: ptr("male"),
+				DateOfBirth: date("1960-11-08"), ExternalIDs: entity.ExternalIDs{"kinopoisk": "7063"},
+			},
+			want: `{"id":6,"name":"Олег","surname":"Меньшиков","patronymic":"Евгеньевич","gender":"male","date_of_birth":"1960-11-08","external_ids":{"kinopoisk":"7063"}}`,
+		},
+		{
+			name: "required fields only",
+			data: entity.ActorData{Name: ptr("Олег"), Surname: ptr("Меньшиков")},
+			want: `{"id":6,"name":"Олег","surname":"Меньшиков"}`,
+		},
+		{
+			name: "year precision",
+			data: entity.ActorData{Name: ptr("Олег"), Surname: ptr("Меньшиков"), DateOfBirth: date("1960")},
+			want: `{"id":6,"name":"Олег","surname":"Меньшиков","date_of_birth":"1960"}`,
+		},
+		{
+			// INSERT ... ON CONFLICT DO NOTHING не возвращает id
+			name:    "same name and surname",
+			data:    entity.ActorData{Name: ptr("Иван"), Surname: ptr("Петров")},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name:    "external id of other actor",
+			data:    entity.ActorData{Name: ptr("Олег"), Surname: ptr("Меньшиков"), ExternalIDs: entity.ExternalIDs{"imdb": "nm0000001"}},
+			wantErr: errUniqueViolation,
+		},
+		{
+			name:    "unknown gender",
+			data:    entity.ActorData{Name: ptr("Олег"), Surname: ptr("Меньшиков"), Gender: ptr("unknown")},
+			wantErr: errInvalidText,
+		},
+		{
+			name:    "name too long",
+			data:    entity.ActorData{Name: ptr(strings.Repeat("а", 51)), Surname: ptr("Меньшиков")},
+			wantErr: errStringTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := s.newRepos(t)
+			r := repos.Actors
+			ctx := context.Background()
+
+			id, err := r.Save(ctx, tt.data)
+			if checkErr(t, err, tt.wantErr) {
+				// Транзакция репозитория откатилась целиком
+				if _, err = r.Get(ctx, 6); !matchErr(err, sql.ErrNoRows) {
+					t.Fatalf("actor was saved despite error: %v", err)
+				}
+				return
+			}
+
+			got, err := r.Get(ctx, id)
+			if err != nil {
+				t.Fatalf("failed to get saved actor: %s", err)
+			}
+
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func (s suite) actorsUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		updates entity.Actor
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "one field",
+			updates: entity.Actor{Id: ptr(2), ActorData: entity.ActorData{Surname: ptr("Смирнова-Петрова")}},
+			want:    `{"id":2,"name":"Анна","surname":"Смирнова-Петрова","gender":"female","date_of_birth":"1985-07"}`,
+		},
+		{
+			name:    "date precision",
+			updates: entity.Actor{Id: ptr(1), ActorData: entity.ActorData{DateOfBirth: date("1971")}},
+			want:    `{"id":1,"name":"Иван","surname":"Петров","patronymic":"Сергеевич","gender":"male","date_of_birth":"1971","external_ids":{"imdb":"nm0000001"}}`,
+		},
+		{
+			name:    "external ids only",
+			updates: entity.Actor{Id: ptr(5), ActorData: entity.ActorData{ExternalIDs: entity.ExternalIDs{"tmdb": "1"}}},
+			want:    `{"id":5,"name":"Мария","surname":"Иванова","gender":"female","external_ids":{"tmdb":"1"}}`,
+		},
+		{
+			name:    "replace external id",
+			updates: entity.Actor{Id: ptr(1), ActorData: entity.ActorData{ExternalIDs: entity.ExternalIDs{"imdb": "nm0000002"}}},
+			want:    `{"id":1,"name":"Иван","surname":"Петров","patronymic":"Сергеевич","gender":"male","date_of_birth":"1970-03-15","external_ids":{"imdb":"nm0000002"}}`,
+		},
+		{
+			name:    "not found",
+			updates: entity.Actor{Id: ptr(42), ActorData: entity.ActorData{Name: ptr("Олег")}},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name:    "same name and surname as other actor",
+			updates: entity.Actor{Id: ptr(4), ActorData: entity.ActorData{Name: ptr("John")}},
+			wantErr: errUniqueViolation,
+		},
+		{
+			name:    "no fields",
+			updates: entity.Actor{Id: ptr(1)},
+			wantErr: errAny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Actors
+
+			got, err := r.Update(context.Background(), tt.updates)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func (s suite) actorsDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		want    string
+		wantErr error
+	}{
+		{name: "without movies", id: 5, want: actorMaria},
+		{name: "in cast of movie", id: 1, wantErr: errForeignKeyViolation},
+		{name: "not found", id: 42, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Actors
+			ctx := context.Background()
+
+			got, err := r.Delete(ctx, tt.id)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+
+			if _, err = r.Get(ctx, tt.id); !matchErr(err, sql.ErrNoRows) {
+				t.Fatalf("deleted actor is still found: %v", err)
+			}
+		})
+	}
+}
+
+func (s suite) actorsList(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter map[string][]string
+		want   []int
+	}{
+		{name: "all", want: []int{1, 2, 3, 4, 5}},
+		{name: "by gender", filter: map[string][]string{"gender": {"female"}}, want: []int{2, 5}},
+		{name: "by several fields", filter: map[string][]string{"name": {"John"}, "surname": {"Smith"}}, want: []int{3}},
+		{name: "by date in old format", filter: map[string][]string{"date_of_birth": {"15.03.1970"}}, want: []int{1}},
+		{name: "nothing found", filter: map[string][]string{"name": {"Никто"}}, want: nil},
+		{name: "quote in value", filter: map[string][]string{"name": {"Иван' OR '1'='1"}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Actors
+
+			ctx := context.Background()
+			if tt.filter != nil {
+				ctx = context.WithValue(ctx, filter.FilterOptionsContextKey, tt.filter)
+			}
+
+			got, err := r.List(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertIDs(t, actorIDs(got), tt.want, true)
+		})
+	}
+
+	// Записи списка возвращаются вместе с внешними идентификаторами, как в Get
+	t.Run("with external ids", func(t *testing.T) {
+		got, err := s.newRepos(t).Actors.List(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(got) == 0 {
+			t.Fatal("no actors")
+		}
+
+		assertJSON(t, got[0], actorIvan)
+	})
+}
+
+func (s suite) actorsNext(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   int
+		filter map[string][]string
+		want   []int
+	}{
+		{name: "from first", from: 0, want: []int{1, 2, 3, 4, 5}},
+		{name: "from middle", from: 3, want: []int{3, 4, 5}},
+		{name: "after last", from: 6, want: nil},
+		{name: "with filter", from: 2, filter: map[string][]string{"gender": {"male"}}, want: []int{3, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Actors
+
+			ctx := context.WithValue(context.Background(), pagination.NextPersonID, tt.from)
+			if tt.filter != nil {
+				ctx = context.WithValue(ctx, filter.FilterOptionsContextKey, tt.filter)
+			}
+
+			got, err := r.Next(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertIDs(t, actorIDs(got), tt.want, true)
+		})
+	}
+
+	// Записи страницы возвращаются вместе с внешними идентификаторами, как в Get
+	t.Run("with external ids", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), pagination.NextPersonID, 0)
+
+		got, err := s.newRepos(t).Actors.Next(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		if len(got) == 0 {
+			t.Fatal("no actors")
+		}
+
+		assertJSON(t, got[0], actorIvan)
+	})
+}
+
+func (s suite) actorsProfiles(t *testing.T) {
+	r := s.newRepos(t).Actors
+
+	got, err := r.Profiles(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	want := map[int][]int{1: {1, 2}, 2: {1}, 3: {3}, 4: {3}, 5: {}}
+
+	if len(got) != len(want) {
+		t.Fatalf("got %d profiles, want %d", len(got), len(want))
+	}
+
+	for _, p := range got {
+		movies := append([]int{}, p.MovieIDs...)
+		sort.Ints(movies)
+
+		assertIDs(t, movies, want[*p.Id], true)
+	}
+}
+
+func (s suite) actorsMerge(t *testing.T) {
+	tests := []struct {
+		name    string
+		source  int
+		target  int
+		want    string
+		wantErr error
+		// Проверки после слияния
+		check func(t *testing.T, repos Repos)
+	}{
+		{
+			name:   "duplicate in same movie",
+			source: 4, target: 3,
+			want: actorJohn,
+			check: func(t *testing.T, repos Repos) {
+				assertCast(t, repos, 3, []int{3})
+				assertActorID(t, repos, 4, 3)
+			},
+		},
+		{
+			name:   "empty fields are filled",
+			source: 2, target: 5,
+			want: `{"id":5,"name":"Мария","surname":"Иванова","gender":"female","date_of_birth":"1985-07"}`,
+			check: func(t *testing.T, repos Repos) {
+				assertFilmography(t, repos, 5, []int{1})
+			},
+		},
+		{
+			name:   "external ids are moved",
+			source: 1, target: 2,
+			want: `{"id":2,"name":"Анна","surname":"Смирнова","patronymic":"Сергеевич","gender":"female","date_of_birth":"1985-07","external_ids":{"imdb":"nm0000001"}}`,
+			check: func(t *testing.T, repos Repos) {
+				assertFilmography(t, repos, 2, []int{1, 2})
+			},
+		},
+		{
+			name:   "old redirects follow target",
+			source: 3, target: 4,
+			want: `{"id":4,"name":"Jon","surname":"Smith","gender":"male","date_of_birth":"1960","external_ids":{"tmdb":"6384"}}`,
+			check: func(t *testing.T, repos Repos) {
+				assertActorID(t, repos, 100, 4)
+				assertActorID(t, repos, 3, 4)
+			},
+		},
+		{name: "source not found", source: 42, target: 1, wantErr: sql.ErrNoRows},
+		{name: "target not found", source: 1, target: 42, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := s.newRepos(t)
+			r := repos.Actors
+
+			got, err := r.Merge(context.Background(), tt.source, tt.target)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+
+			if tt.check != nil {
+				tt.check(t, repos)
+			}
+		})
+	}
+}
+
+// assertActorID проверяет, что актер с id находится под id want
+func assertActorID(t *testing.T, repos Repos, id, want int) {
+	t.Helper()
+
+	got, err := repos.Actors.Get(context.Background(), id)
+	if err != nil {
+		t.Fatalf("failed to get actor %d: %s", id, err)
+	}
+
+	if *got.Id != want {
+		t.Fatalf("actor %d is found as %d, want %d", id, *got.Id, want)
+	}
+}
+
+func assertCast(t *testing.T, repos Repos, movieID int, want []int) {
+	t.Helper()
+
+	cast, err := repos.ActorsMovies.Cast(context.Background(), []int{movieID})
+	if err != nil {
+		t.Fatalf("failed to get cast of movie %d: %s", movieID, err)
+	}
+
+	assertIDs(t, actorIDs(cast[movieID]), want, true)
+}
+
+func assertFilmography(t *testing.T, repos Repos, actorID int, want []int) {
+	t.Helper()
+
+	movies, err := repos.ActorsMovies.Filmography(context.Background(), []int{actorID})
+	if err != nil {
+		t.Fatalf("failed to get movies of actor %d: %s", actorID, err)
+	}
+
+	assertIDs(t, movieIDs(movies[actorID]), want, true)
+}
+
+func (s suite) actorsCount(t *testing.T) {
+	got, err := s.newRepos(t).Actors.Count(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got != 5 {
+		t.Fatalf("count = %d, want 5", got)
+	}
+}
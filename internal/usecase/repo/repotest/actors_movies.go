@@ -0,0 +1,166 @@
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"testing"
+
+	"filmoteka/internal/entity"
+)
+
+func (s suite) actorsMoviesSave(t *testing.T) {
+	tests := []struct {
+		name     string
+		actorID  int
+		movieID  int
+		wantCast []int
+		wantErr  error
+	}{
+		{name: "movie without cast", actorID: 5, movieID: 4, wantCast: []int{5}},
+		{name: "movie with cast", actorID: 3, movieID: 1, wantCast: []int{1, 2, 3}},
+		{name: "already in cast", actorID: 1, movieID: 1, wantErr: errUniqueViolation},
+		{name: "actor not found", actorID: 42, movieID: 1, wantErr: sql.ErrNoRows},
+		{name: "movie not found", actorID: 1, movieID: 42, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := s.newRepos(t)
+			r := repos.ActorsMovies
+
+			err := r.Save(context.Background(), entity.ActorMovie{Actor_id: &tt.actorID, Movie_id: &tt.movieID})
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertCast(t, repos, tt.movieID, tt.wantCast)
+		})
+	}
+}
+
+func (s suite) actorsMoviesDelete(t *testing.T) {
+	tests := []struct {
+		name     string
+		actorID  int
+		movieID  int
+		wantCast []int
+		wantErr  error
+	}{
+		{name: "one of cast", actorID: 2, movieID: 1, wantCast: []int{1}},
+		{name: "last in cast", actorID: 1, movieID: 2, wantCast: nil},
+		{name: "not in cast", actorID: 5, movieID: 1, wantErr: sql.ErrNoRows},
+		{name: "movie not found", actorID: 1, movieID: 42, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := s.newRepos(t)
+			r := repos.ActorsMovies
+
+			err := r.Delete(context.Background(), entity.ActorMovie{Actor_id: &tt.actorID, Movie_id: &tt.movieID})
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertCast(t, repos, tt.movieID, tt.wantCast)
+		})
+	}
+}
+
+func (s suite) actorsMoviesList(t *testing.T) {
+	r := s.newRepos(t).ActorsMovies
+
+	got, err := r.List(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	pairs := make([]string, 0, len(got))
+	for _, row := range got {
+		pairs = append(pairs, fmt.Sprintf("%s %s - %s", row.ActorName, row.ActorSurname, row.MovieTitle))
+	}
+	sort.Strings(pairs)
+
+	assertJSON(t, pairs, `[
+		"John Smith - The Matrix",
+		"Jon Smith - The Matrix",
+		"Анна Смирнова - Брат",
+		"Иван Петров - Брат",
+		"Иван Петров - Сибирский цирюльник"
+	]`)
+}
+
+func (s suite) actorsMoviesCast(t *testing.T) {
+	tests := []struct {
+		name     string
+		movieIDs []int
+		want     map[int][]int
+	}{
+		{name: "one movie", movieIDs: []int{3}, want: map[int][]int{3: {3, 4}}},
+		{name: "several movies", movieIDs: []int{1, 2, 4}, want: map[int][]int{1: {1, 2}, 2: {1}}},
+		{name: "movie not found", movieIDs: []int{42}, want: map[int][]int{}},
+		{name: "no movies", movieIDs: []int{}, want: map[int][]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).ActorsMovies
+
+			got, err := r.Cast(context.Background(), tt.movieIDs)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ids := make(map[int][]int, len(got))
+			for movieID, actors := range got {
+				ids[movieID] = actorIDs(actors)
+			}
+
+			assertJSON(t, ids, toJSON(t, tt.want))
+		})
+	}
+}
+
+func (s suite) actorsMoviesFilmography(t *testing.T) {
+	tests := []struct {
+		name     string
+		actorIDs []int
+		want     map[int][]int
+	}{
+		// Фильмы упорядочены по дате выхода
+		{name: "one actor", actorIDs: []int{1}, want: map[int][]int{1: {1, 2}}},
+		{name: "several actors", actorIDs: []int{2, 3, 5}, want: map[int][]int{2: {1}, 3: {3}}},
+		{name: "actor not found", actorIDs: []int{42}, want: map[int][]int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).ActorsMovies
+
+			got, err := r.Filmography(context.Background(), tt.actorIDs)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			ids := make(map[int][]int, len(got))
+			for actorID, movies := range got {
+				ids[actorID] = movieIDs(movies)
+			}
+
+			assertJSON(t, ids, toJSON(t, tt.want))
+		})
+	}
+}
+
+func (s suite) actorsMoviesCount(t *testing.T) {
+	got, err := s.newRepos(t).ActorsMovies.Count(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got != 5 {
+		t.Fatalf("count = %d, want 5", got)
+	}
+}
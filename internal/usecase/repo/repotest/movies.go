@@ -0,0 +1,407 @@
+package repotest
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"testing"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/controller/middleware/sort"
+	"filmoteka/internal/entity"
+)
+
+const (
+	movieBrat   = `{"id":1,"title":"Брат","description":"Демобилизованный Данила Багров приезжает в Петербург","release_date":"1997-12-12","rating":8,"external_ids":{"imdb":"tt0118767"}}`
+	movieBarber = `{"id":2,"title":"Сибирский цирюльник","release_date":"1998","rating":7}`
+	movieMatrix = `{"id":3,"title":"The Matrix","release_date":"1999-03-31","rating":9,"external_ids":{"kinopoisk":"301"}}`
+	movieShort  = `{"id":4,"title":"Короткометражка","release_date":"2001-05","rating":5}`
+)
+
+func (s suite) moviesGet(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		want    string
+		wantErr error
+	}{
+		{name: "with external ids", id: 1, want: movieBrat},
+		{name: "year precision", id: 2, want: movieBarber},
+		{name: "month precision", id: 4, want: movieShort},
+		{name: "not found", id: 42, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+
+			got, err := r.Get(context.Background(), tt.id)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func (s suite) moviesGetByExternalID(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		externalID string
+		want       string
+		wantErr    error
+	}{
+		{name: "imdb", source: entity.SourceIMDb, externalID: "tt0118767", want: movieBrat},
+		{name: "kinopoisk", source: entity.SourceKinopoisk, externalID: "301", want: movieMatrix},
+		{name: "not found", source: entity.SourceTMDb, externalID: "603", wantErr: sql.ErrNoRows},
+		{name: "unknown source", source: "wikidata", externalID: "Q83495", wantErr: errInvalidText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+
+			got, err := r.GetByExternalID(context.Background(), tt.source, tt.externalID)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func (s suite) moviesMatchExternalIDs(t *testing.T) {
+	tests := []struct {
+		name    string
+		ids     entity.ExternalIDs
+		want    int
+		wantErr error
+	}{
+		{name: "one id", ids: entity.ExternalIDs{"kinopoisk": "301"}, want: 3},
+		{name: "one of ids is known", ids: entity.ExternalIDs{"imdb": "tt0118767", "tmdb": "20992"}, want: 1},
+		{name: "unknown ids", ids: entity.ExternalIDs{"tmdb": "20992"}, wantErr: sql.ErrNoRows},
+		{name: "ids of different movies", ids: entity.ExternalIDs{"imdb": "tt0118767", "kinopoisk": "301"}, wantErr: errAny},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+
+			got, err := r.MatchExternalIDs(context.Background(), tt.ids)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			if got != tt.want {
+				t.Fatalf("id = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func (s suite) moviesGetMovie(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter map[string][]string
+		want   []int
+	}{
+		// Ищутся только фильмы, в которых есть актеры
+		{name: "no filter", want: []int{1, 2, 3}},
+		{name: "by title ignoring case", filter: map[string][]string{"title": {"брат"}}, want: []int{1}},
+		{name: "by part of actor name", filter: map[string][]string{"actor_name": {"jo"}}, want: []int{3}},
+		{name: "by title and actor", filter: map[string][]string{"title": {"ц"}, "actor_name": {"Иван"}}, want: []int{2}},
+		{name: "nothing found", filter: map[string][]string{"title": {"Брат 2"}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+
+			ctx := context.Background()
+			if tt.filter != nil {
+				ctx = context.WithValue(ctx, filter.FilterOptionsContextKey, tt.filter)
+			}
+
+			got, err := r.GetMovie(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertIDs(t, movieIDs(got), tt.want, false)
+		})
+	}
+}
+
+func (s suite) moviesSave(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    entity.MovieData
+		want    string
+		wantErr error
+	}{
+		{
+			name: "all fields",
+			data: entity.MovieData{
+				Title: ptr("Курьер"), Description: ptr("Иван Мироширин влюбляется в дочь профессора"),
+				ReleaseDate: date("1986-12-01"), Rating: ptr(8), ExternalIDs: entity.ExternalIDs{"tmdb": "20992"},
+			},
+			want: `{"id":5,"title":"Курьер","description":"Иван Мироширин влюбляется в дочь профессора","release_date":"1986-12-01","rating":8,"external_ids":{"tmdb":"20992"}}`,
+		},
+		{
+			name: "title only",
+			data: entity.MovieData{Title: ptr("Курьер")},
+			want: `{"id":5,"title":"Курьер"}`,
+		},
+		{
+			name: "month precision",
+			data: entity.MovieData{Title: ptr("Курьер"), ReleaseDate: date("12.1986")},
+			want: `{"id":5,"title":"Курьер","release_date":"1986-12"}`,
+		},
+		{
+			name:    "rating out of range",
+			data:    entity.MovieData{Title: ptr("Курьер"), Rating: ptr(11)},
+			wantErr: errCheckViolation,
+		},
+		{
+			name:    "external id of other movie",
+			data:    entity.MovieData{Title: ptr("Курьер"), ExternalIDs: entity.ExternalIDs{"imdb": "tt0118767"}},
+			wantErr: errUniqueViolation,
+		},
+		{
+			name:    "title too long",
+			data:    entity.MovieData{Title: ptr(strings.Repeat("а", 151))},
+			wantErr: errStringTooLong,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+			ctx := context.Background()
+
+			id, err := r.Save(ctx, tt.data)
+			if checkErr(t, err, tt.wantErr) {
+				// Транзакция репозитория откатилась целиком
+				if _, err = r.Get(ctx, 5); !matchErr(err, sql.ErrNoRows) {
+					t.Fatalf("movie was saved despite error: %v", err)
+				}
+				return
+			}
+
+			got, err := r.Get(ctx, id)
+			if err != nil {
+				t.Fatalf("failed to get saved movie: %s", err)
+			}
+
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func (s suite) moviesUpdate(t *testing.T) {
+	tests := []struct {
+		name    string
+		updates entity.Movie
+		want    string
+		wantErr error
+	}{
+		{
+			name:    "one field",
+			updates: entity.Movie{Id: ptr(3), MovieData: entity.MovieData{Rating: ptr(10)}},
+			want:    `{"id":3,"title":"The Matrix","release_date":"1999-03-31","rating":10,"external_ids":{"kinopoisk":"301"}}`,
+		},
+		{
+			name:    "date precision",
+			updates: entity.Movie{Id: ptr(4), MovieData: entity.MovieData{ReleaseDate: date("2001")}},
+			want:    `{"id":4,"title":"Короткометражка","release_date":"2001","rating":5}`,
+		},
+		{
+			name:    "external ids only",
+			updates: entity.Movie{Id: ptr(2), MovieData: entity.MovieData{ExternalIDs: entity.ExternalIDs{"imdb": "tt0120102"}}},
+			want:    `{"id":2,"title":"Сибирский цирюльник","release_date":"1998","rating":7,"external_ids":{"imdb":"tt0120102"}}`,
+		},
+		{
+			name:    "not found",
+			updates: entity.Movie{Id: ptr(42), MovieData: entity.MovieData{Rating: ptr(1)}},
+			wantErr: sql.ErrNoRows,
+		},
+		{
+			name:    "rating out of range",
+			updates: entity.Movie{Id: ptr(1), MovieData: entity.MovieData{Rating: ptr(-1)}},
+			wantErr: errCheckViolation,
+		},
+		{
+			name:    "no fields",
+			updates: entity.Movie{Id: ptr(1)},
+			wantErr: errAny,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+
+			got, err := r.Update(context.Background(), tt.updates)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+		})
+	}
+}
+
+func (s suite) moviesDelete(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      int
+		want    string
+		wantErr error
+	}{
+		{name: "without cast", id: 4, want: movieShort},
+		{name: "with cast", id: 1, wantErr: errForeignKeyViolation},
+		{name: "not found", id: 42, wantErr: sql.ErrNoRows},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+			ctx := context.Background()
+
+			got, err := r.Delete(ctx, tt.id)
+			if checkErr(t, err, tt.wantErr) {
+				return
+			}
+
+			assertJSON(t, got, tt.want)
+
+			if _, err = r.Get(ctx, tt.id); !matchErr(err, sql.ErrNoRows) {
+				t.Fatalf("deleted movie is still found: %v", err)
+			}
+		})
+	}
+}
+
+func (s suite) moviesList(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter map[string][]string
+		sort   map[string]string
+		want   []int
+	}{
+		{name: "by rating by default", want: []int{3, 1, 2, 4}},
+		{name: "by release date", sort: map[string]string{"release_date": sort.ASC}, want: []int{1, 2, 3, 4}},
+		{name: "by rating ascending", sort: map[string]string{"rating": sort.ASC}, want: []int{4, 2, 1, 3}},
+		{name: "by rating filter", filter: map[string][]string{"rating": {"7"}}, want: []int{2}},
+		{name: "by release year", filter: map[string][]string{"release_date": {"1998"}}, want: []int{2}},
+		{name: "nothing found", filter: map[string][]string{"rating": {"1"}}, want: nil},
+		{name: "quote in value", filter: map[string][]string{"title": {"Брат' OR '1'='1"}}, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+
+			ctx := context.Background()
+			if tt.filter != nil {
+				ctx = context.WithValue(ctx, filter.FilterOptionsContextKey, tt.filter)
+			}
+			if tt.sort != nil {
+				ctx = context.WithValue(ctx, sort.SortOptionsContextKey, tt.sort)
+			}
+
+			got, err := r.List(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertIDs(t, movieIDs(got), tt.want, true)
+		})
+	}
+
+	// Имена столбцов из параметров запроса попадают в SQL только после проверки
+	t.Run("unknown column", func(t *testing.T) {
+		r := s.newRepos(t).Movies
+
+		ctx := context.WithValue(context.Background(), filter.FilterOptionsContextKey,
+			map[string][]string{"1=1 OR title": {"Брат"}})
+		if _, err := r.List(ctx); err == nil {
+			t.Error("filter by unknown column: no error")
+		}
+
+		ctx = context.WithValue(context.Background(), sort.SortOptionsContextKey,
+			map[string]string{"rating; DROP TABLE movies": sort.ASC})
+		if _, err := r.List(ctx); err == nil {
+			t.Error("sort by unknown column: no error")
+		}
+	})
+
+	// Записи списка возвращаются вместе с внешними идентификаторами, как в Get
+	t.Run("with external ids", func(t *testing.T) {
+		got, err := s.newRepos(t).Movies.List(context.Background())
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		assertJSON(t, got, "["+movieMatrix+","+movieBrat+","+movieBarber+","+movieShort+"]")
+	})
+}
+
+func (s suite) moviesNext(t *testing.T) {
+	tests := []struct {
+		name   string
+		from   int
+		filter map[string][]string
+		want   []int
+	}{
+		{name: "from first", from: 0, want: []int{1, 2, 3, 4}},
+		{name: "from middle", from: 2, want: []int{2, 3, 4}},
+		{name: "after last", from: 5, want: nil},
+		{name: "with filter", from: 1, filter: map[string][]string{"rating": {"9"}}, want: []int{3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := s.newRepos(t).Movies
+
+			ctx := context.WithValue(context.Background(), pagination.NextPersonID, tt.from)
+			if tt.filter != nil {
+				ctx = context.WithValue(ctx, filter.FilterOptionsContextKey, tt.filter)
+			}
+
+			got, err := r.Next(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			assertIDs(t, movieIDs(got), tt.want, true)
+		})
+	}
+
+	// Записи страницы возвращаются вместе с внешними идентификаторами, как в Get
+	t.Run("with external ids", func(t *testing.T) {
+		ctx := context.WithValue(context.Background(), pagination.NextPersonID, 0)
+
+		got, err := s.newRepos(t).Movies.Next(ctx)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+
+		assertJSON(t, got, "["+movieBrat+","+movieBarber+","+movieMatrix+","+movieShort+"]")
+	})
+}
+
+func (s suite) moviesCount(t *testing.T) {
+	got, err := s.newRepos(t).Movies.Count(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	if got != 4 {
+		t.Fatalf("count = %d, want 4", got)
+	}
+}
@@ -0,0 +1,255 @@
+// Package repotest - общий набор тестов репозиториев. Его должно проходить каждое хранилище:
+// postgres (integration-test), SQLite (repo/sqlite) и хранилище в памяти (repo/memory).
+//
+// Перед каждым тестом хранилище заполняется данными из fixtures.yml. Ожидаемые ошибки не зависят
+// от хранилища: postgres сообщает о нарушении ограничений кодом ошибки, остальные хранилища -
+// ошибками usecase.ErrAlreadyExists, usecase.ErrReferenced и usecase.ErrInvalidData.
+package repotest
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/lib/pq"
+	"gopkg.in/yaml.v3"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+//go:embed fixtures.yml
+var fixtures []byte
+
+// Repos - репозитории одного хранилища
+type Repos struct {
+	Actors       usecase.ActorsRepo
+	Movies       usecase.MoviesRepo
+	ActorsMovies usecase.ActorsMoviesRepo
+}
+
+// Run запускает набор тестов. newRepos вызывается в каждом тесте и должен возвращать
+// репозитории хранилища, заполненного данными из Load, которое не видят другие тесты.
+func Run(t *testing.T, newRepos func(t *testing.T) Repos) {
+	s := suite{newRepos: newRepos}
+
+	t.Run("ActorsRepo", func(t *testing.T) {
+		t.Run("Get", s.actorsGet)
+		t.Run("GetByExternalID", s.actorsGetByExternalID)
+		t.Run("MatchExternalIDs", s.actorsMatchExternalIDs)
+		t.Run("Save", s.actorsSave)
+		t.Run("Update", s.actorsUpdate)
+		t.Run("Delete", s.actorsDelete)
+		t.Run("List", s.actorsList)
+		t.Run("Next", s.actorsNext)
+		t.Run("Profiles", s.actorsProfiles)
+		t.Run("Merge", s.actorsMerge)
+		t.Run("Count", s.actorsCount)
+	})
+
+	t.Run("MoviesRepo", func(t *testing.T) {
+		t.Run("Get", s.moviesGet)
+		t.Run("GetByExternalID", s.moviesGetByExternalID)
+		t.Run("MatchExternalIDs", s.moviesMatchExternalIDs)
+		t.Run("GetMovie", s.moviesGetMovie)
+		t.Run("Save", s.moviesSave)
+		t.Run("Update", s.moviesUpdate)
+		t.Run("Delete", s.moviesDelete)
+		t.Run("List", s.moviesList)
+		t.Run("Next", s.moviesNext)
+		t.Run("Count", s.moviesCount)
+	})
+
+	t.Run("ActorsMoviesRepo", func(t *testing.T) {
+		t.Run("Save", s.actorsMoviesSave)
+		t.Run("Delete", s.actorsMoviesDelete)
+		t.Run("List", s.actorsMoviesList)
+		t.Run("Cast", s.actorsMoviesCast)
+		t.Run("Filmography", s.actorsMoviesFilmography)
+		t.Run("Count", s.actorsMoviesCount)
+	})
+}
+
+type suite struct {
+	newRepos func(t *testing.T) Repos
+}
+
+// Load передает insert строки таблиц из fixtures.yml в порядке, в котором они перечислены в файле.
+// Строка - значения столбцов таблицы postgres, id записей заданы явно.
+func Load(ctx context.Context, insert func(ctx context.Context, table string, row map[string]any) error) error {
+	// yaml.Node сохраняет порядок таблиц, а map - нет
+	var doc yaml.Node
+	if err := yaml.Unmarshal(fixtures, &doc); err != nil {
+		return fmt.Errorf("fixtures.yml: %w", err)
+	}
+
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	tables := doc.Content[0].Content
+
+	for i := 0; i+1 < len(tables); i += 2 {
+		table := tables[i].Value
+
+		var rows []map[string]any
+		if err := tables[i+1].Decode(&rows); err != nil {
+			return fmt.Errorf("fixtures.yml: table %s: %w", table, err)
+		}
+
+		for _, row := range rows {
+			if err := insert(ctx, table, row); err != nil {
+				return fmt.Errorf("fixtures.yml: table %s: %w", table, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Ожидаемые ошибки репозиториев
+var (
+	// errAny - любая ошибка
+	errAny = errors.New("any error")
+
+	errUniqueViolation     = constraintErr{err: usecase.ErrAlreadyExists, code: "23505"}
+	errForeignKeyViolation = constraintErr{err: usecase.ErrReferenced, code: "23503"}
+	errCheckViolation      = constraintErr{err: usecase.ErrInvalidData, code: "23514"}
+	errStringTooLong       = constraintErr{err: usecase.ErrInvalidData, code: "22001"}
+	errInvalidText         = constraintErr{err: usecase.ErrInvalidData, code: "22P02"}
+)
+
+// constraintErr - нарушение ограничения хранилища: ошибка postgres с кодом code
+// или ошибка err у остальных хранилищ
+type constraintErr struct {
+	err  error
+	code pq.ErrorCode
+}
+
+func (e constraintErr) Error() string {
+	return fmt.Sprintf("%s (pq error %s, %s)", e.err, string(e.code), e.code.Name())
+}
+
+// checkErr сравнивает ошибку с ожидаемой и сообщает, была ли ошибка ожидаемой
+// (тогда результат вызова проверять не нужно)
+func checkErr(t *testing.T, err, want error) bool {
+	t.Helper()
+
+	switch {
+	case want == nil && err != nil:
+		t.Fatalf("unexpected error: %v", err)
+	case want != nil && !matchErr(err, want):
+		t.Fatalf("error = %v, want %v", err, want)
+	}
+
+	return want != nil
+}
+
+func matchErr(err, want error) bool {
+	if err == nil {
+		return false
+	}
+
+	if want == errAny {
+		return true
+	}
+
+	var constraint constraintErr
+	if errors.As(want, &constraint) {
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) {
+			return pqErr.Code == constraint.code
+		}
+
+		return errors.Is(err, constraint.err)
+	}
+
+	return errors.Is(err, want)
+}
+
+// assertJSON сравнивает JSON-представление got с ожидаемым
+func assertJSON(t *testing.T, got any, want string) {
+	t.Helper()
+
+	b, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("failed to marshal %T: %s", got, err)
+	}
+
+	var gotValue, wantValue any
+	if err = json.Unmarshal(b, &gotValue); err != nil {
+		t.Fatalf("failed to unmarshal %s: %s", b, err)
+	}
+	if err = json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("invalid expected JSON %s: %s", want, err)
+	}
+
+	if !reflect.DeepEqual(gotValue, wantValue) {
+		t.Fatalf("got  %s\nwant %s", b, want)
+	}
+}
+
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		t.Fatalf("failed to marshal %T: %s", v, err)
+	}
+
+	return string(b)
+}
+
+// assertIDs сравнивает id записей с ожидаемыми. Если порядок не важен, id сортируются.
+func assertIDs(t *testing.T, got, want []int, ordered bool) {
+	t.Helper()
+
+	if !ordered {
+		got = append([]int(nil), got...)
+		sort.Ints(got)
+	}
+
+	if len(got) == 0 && len(want) == 0 {
+		return
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("ids = %v, want %v", got, want)
+	}
+}
+
+func actorIDs(actors []entity.Actor) []int {
+	ids := make([]int, 0, len(actors))
+	for _, a := range actors {
+		ids = append(ids, *a.Id)
+	}
+
+	return ids
+}
+
+func movieIDs(movies []entity.Movie) []int {
+	ids := make([]int, 0, len(movies))
+	for _, m := range movies {
+		ids = append(ids, *m.Id)
+	}
+
+	return ids
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func date(s string) *entity.Date {
+	d, err := entity.ParseDate(s)
+	if err != nil {
+		panic(err)
+	}
+
+	return &d
+}
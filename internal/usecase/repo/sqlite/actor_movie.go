@@ -0,0 +1,206 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"filmoteka/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type ActorsMoviesRepo struct {
+	db *sqlx.DB
+}
+
+func NewActorsMoviesRepo(db *sql.DB) *ActorsMoviesRepo {
+	return &ActorsMoviesRepo{db: sqlx.NewDb(db, "sqlite")}
+}
+
+const ActorMovieQuerySave = `INSERT INTO actors_movies (actor_id, movie_id) VALUES ($1, $2)`
+
+func (r *ActorsMoviesRepo) Save(ctx context.Context, data entity.ActorMovie) error {
+
+	//Проверяем наличие актера в таблице actors
+	ActorQuery := `SELECT COUNT(*) FROM actors WHERE id = $1`
+
+	var count1 int
+
+	err := r.db.GetContext(ctx, &count1, ActorQuery,
+		data.Actor_id,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if count1 == 0 {
+		return fmt.Errorf("%s: actor_id was NOT found in database table 'actors': %w", op, sql.ErrNoRows)
+	}
+
+	//Проверяем наличие фильма в таблице movies
+	MovieQuery := `SELECT COUNT(*) FROM movies WHERE id = $1`
+
+	var count2 int
+
+	err = r.db.GetContext(ctx, &count2, MovieQuery,
+		data.Movie_id,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	if count2 == 0 {
+		return fmt.Errorf("%s: movie_id was NOT found in database table 'movies': %w", op, sql.ErrNoRows)
+	}
+
+	// Вносим данные в базу данных в таблицу movie_actors
+	_, err = r.db.ExecContext(ctx, ActorMovieQuerySave,
+		data.Actor_id,
+		data.Movie_id,
+	)
+
+	if err != nil {
+		return dbError(err)
+	}
+
+	return nil
+}
+
+const ActorMovieQueryDelete = `DELETE FROM actors_movies WHERE actor_id = $1 AND movie_id = $2`
+
+func (r *ActorsMoviesRepo) Delete(ctx context.Context, data entity.ActorMovie) error {
+
+	res, err := r.db.ExecContext(ctx, ActorMovieQueryDelete,
+		data.Actor_id,
+		data.Movie_id,
+	)
+
+	if err != nil {
+		return fmt.Errorf("%s: DB method 'Exec' returned error: %w", op, err)
+	}
+
+	count, err := res.RowsAffected()
+
+	if err != nil {
+		return fmt.Errorf("%s: failed to get the number of deleted rows: %w", op, err)
+	}
+
+	if count == 0 {
+		return fmt.Errorf("%s: actor is NOT in the cast of the movie: %w", op, sql.ErrNoRows)
+	}
+
+	return nil
+}
+
+const ListActorsAndMoviesQuery = `SELECT
+			actors.id AS actor_id,
+			actors.name AS actor_name,
+			actors.surname AS actor_surname,
+			movies.id AS movie_id,
+			movies.title AS movie_title
+			FROM actors
+			JOIN
+			actors_movies ON actors.id = actors_movies.actor_id
+			JOIN
+			movies ON actors_movies.movie_id = movies.id`
+
+func (r *ActorsMoviesRepo) List(ctx context.Context) ([]entity.ActorMovieData, error) {
+	var data []entity.ActorMovieData
+	err := r.db.SelectContext(ctx, &data, ListActorsAndMoviesQuery)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: DB returned error: %w", op, err)
+	}
+
+	return data, nil
+}
+
+type castRow struct {
+	OwnerID int `db:"owner_id"`
+	entity.Actor
+}
+
+// Cast возвращает актеров нескольких фильмов одним запросом. Ключ - id фильма.
+func (r *ActorsMoviesRepo) Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	// ANY($1) в SQLite нет, поэтому список id передается через IN
+	sql, i, err := psql.Select("actors_movies.movie_id AS owner_id", actorColumns).
+		From("actors_movies").
+		Join("actors ON actors.id = actors_movies.actor_id").
+		Where(squirrel.Eq{"actors_movies.movie_id": movieIDs}).
+		OrderBy("actors.id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+	}
+
+	var rows []castRow
+	err = r.db.SelectContext(ctx, &rows, sql, i...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	res := make(map[int][]entity.Actor, len(movieIDs))
+	for _, row := range rows {
+		res[row.OwnerID] = append(res[row.OwnerID], row.Actor)
+	}
+
+	return res, nil
+}
+
+type filmographyRow struct {
+	OwnerID int `db:"owner_id"`
+	entity.Movie
+}
+
+// Filmography возвращает фильмы нескольких актеров одним запросом. Ключ - id актера.
+func (r *ActorsMoviesRepo) Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	// Фильмы без даты выхода идут последними, как в postgres
+	sql, i, err := psql.Select("actors_movies.actor_id AS owner_id", movieColumns).
+		From("actors_movies").
+		Join("movies ON movies.id = actors_movies.movie_id").
+		Where(squirrel.Eq{"actors_movies.actor_id": actorIDs}).
+		OrderBy("movies.release_date NULLS LAST", "movies.id").
+		ToSql()
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+	}
+
+	var rows []filmographyRow
+	err = r.db.SelectContext(ctx, &rows, sql, i...)
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	res := make(map[int][]entity.Movie, len(actorIDs))
+	for _, row := range rows {
+		res[row.OwnerID] = append(res[row.OwnerID], row.Movie)
+	}
+
+	return res, nil
+}
+
+// Count возвращает число связей актеров с фильмами
+func (r *ActorsMoviesRepo) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM actors_movies`)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: DB returned error: %w", op, err)
+	}
+
+	return count, nil
+}
@@ -0,0 +1,32 @@
+package sqlite
+
+import "filmoteka/internal/entity"
+
+// Даты хранятся в БД строкой YYYY-MM-DD - первым днем периода - вместе с точностью (day, month, year)
+// и выбираются строкой ISO 8601 той же точности: 2006-01-02, 2006-01 или 2006.
+// TO_CHAR из postgres заменяет обрезка строки.
+const (
+	actorDateOfBirth = `CASE actors.date_of_birth_precision
+						WHEN 'year' THEN SUBSTR(actors.date_of_birth, 1, 4)
+						WHEN 'month' THEN SUBSTR(actors.date_of_birth, 1, 7)
+						ELSE actors.date_of_birth
+					END AS date_of_birth`
+
+	movieReleaseDate = `CASE movies.release_date_precision
+						WHEN 'year' THEN SUBSTR(movies.release_date, 1, 4)
+						WHEN 'month' THEN SUBSTR(movies.release_date, 1, 7)
+						ELSE movies.release_date
+					END AS release_date`
+
+	actorColumns = "actors.id, actors.name, actors.surname, actors.patronymic, actors.gender, " + actorDateOfBirth
+	movieColumns = "movies.id, movies.title, movies.description, " + movieReleaseDate + ", movies.rating"
+)
+
+// precision возвращает точность даты для сохранения в БД
+func precision(d *entity.Date) entity.DatePrecision {
+	if d == nil {
+		return entity.PrecisionDay
+	}
+
+	return d.Precision()
+}
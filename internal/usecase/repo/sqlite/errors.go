@@ -0,0 +1,31 @@
+package sqlite
+
+import (
+	"errors"
+	"fmt"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+
+	"filmoteka/internal/usecase"
+)
+
+// dbError дополняет нарушение ограничения SQLite ошибкой usecase, по которой usecase выбирает
+// вид ошибки так же, как по коду ошибки postgres. Остальные ошибки возвращаются как есть.
+func dbError(err error) error {
+	var e *sqlite.Error
+	if !errors.As(err, &e) {
+		return err
+	}
+
+	switch e.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE, sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY:
+		return fmt.Errorf("%w: %w", err, usecase.ErrAlreadyExists)
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return fmt.Errorf("%w: %w", err, usecase.ErrReferenced)
+	case sqlite3.SQLITE_CONSTRAINT_CHECK, sqlite3.SQLITE_CONSTRAINT_NOTNULL:
+		return fmt.Errorf("%w: %w", err, usecase.ErrInvalidData)
+	}
+
+	return err
+}
@@ -0,0 +1,143 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// externalIDs - таблица идентификаторов фильмов или актеров во внешних каталогах
+type externalIDs struct {
+	table  string
+	column string
+}
+
+var (
+	actorsExternalIDs = externalIDs{table: "actors_external_ids", column: "actor_id"}
+	moviesExternalIDs = externalIDs{table: "movies_external_ids", column: "movie_id"}
+)
+
+type externalIDRow struct {
+	OwnerID    int    `db:"owner_id"`
+	Source     string `db:"source"`
+	ExternalID string `db:"external_id"`
+}
+
+// get возвращает внешние идентификаторы одной записи
+func (t externalIDs) get(ctx context.Context, q sqlx.QueryerContext, id int) (entity.ExternalIDs, error) {
+	res, err := t.getMany(ctx, q, []int{id})
+	if err != nil {
+		return nil, err
+	}
+
+	return res[id], nil
+}
+
+// getMany возвращает внешние идентификаторы нескольких записей одним запросом
+func (t externalIDs) getMany(ctx context.Context, q sqlx.QueryerContext, ids []int) (map[int]entity.ExternalIDs, error) {
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	sql_, args, err := psql.Select(t.column+" AS owner_id", "source", "external_id").
+		From(t.table).
+		Where(squirrel.Eq{t.column: ids}).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+	}
+
+	var rows []externalIDRow
+	if err = sqlx.SelectContext(ctx, q, &rows, sql_, args...); err != nil {
+		return nil, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	res := make(map[int]entity.ExternalIDs)
+	for _, row := range rows {
+		if res[row.OwnerID] == nil {
+			res[row.OwnerID] = entity.ExternalIDs{}
+		}
+		res[row.OwnerID][row.Source] = row.ExternalID
+	}
+
+	return res, nil
+}
+
+// save добавляет или заменяет внешние идентификаторы записи
+func (t externalIDs) save(ctx context.Context, e sqlx.ExecerContext, id int, ids entity.ExternalIDs) error {
+	query := fmt.Sprintf(`INSERT INTO %s (%s, source, external_id) VALUES ($1, $2, $3)
+					ON CONFLICT (%s, source) DO UPDATE SET external_id = EXCLUDED.external_id`,
+		t.table, t.column, t.column)
+
+	for source, externalID := range ids {
+		if _, err := e.ExecContext(ctx, query, id, source, externalID); err != nil {
+			return fmt.Errorf("%s: DB method 'Exec' returned error: %w", op, dbError(err))
+		}
+	}
+
+	return nil
+}
+
+// find возвращает id записи с указанным внешним идентификатором
+func (t externalIDs) find(ctx context.Context, q sqlx.QueryerContext, source, externalID string) (int, error) {
+	if err := checkSource(source); err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM %s WHERE source = $1 AND external_id = $2`, t.column, t.table)
+
+	var id int
+	err := sqlx.GetContext(ctx, q, &id, query, source, externalID)
+	if err != nil {
+		return 0, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	return id, nil
+}
+
+// match возвращает id записи, которой принадлежит хотя бы один из внешних идентификаторов.
+// Если идентификаторы принадлежат разным записям, возвращается ошибка.
+func (t externalIDs) match(ctx context.Context, q sqlx.QueryerContext, ids entity.ExternalIDs) (int, error) {
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	or := squirrel.Or{}
+	for source, externalID := range ids {
+		if err := checkSource(source); err != nil {
+			return 0, err
+		}
+		or = append(or, squirrel.Eq{"source": source, "external_id": externalID})
+	}
+
+	sql_, args, err := psql.Select("DISTINCT " + t.column).From(t.table).Where(or).ToSql()
+	if err != nil {
+		return 0, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+	}
+
+	var res []int
+	if err = sqlx.SelectContext(ctx, q, &res, sql_, args...); err != nil {
+		return 0, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	switch len(res) {
+	case 0:
+		return 0, sql.ErrNoRows
+	case 1:
+		return res[0], nil
+	default:
+		return 0, fmt.Errorf("%s: external ids belong to different records: %v", op, res)
+	}
+}
+
+// checkSource проверяет каталог в условии запроса. В postgres это делает тип external_source,
+// а SQLite просто не нашел бы записей.
+func checkSource(source string) error {
+	if !entity.IsExternalSource(source) {
+		return fmt.Errorf("%s: unknown external source %q: %w", op, source, usecase.ErrInvalidData)
+	}
+
+	return nil
+}
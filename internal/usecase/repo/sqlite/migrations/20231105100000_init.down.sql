@@ -0,0 +1,6 @@
+DROP TABLE IF EXISTS actor_redirects;
+DROP TABLE IF EXISTS actors_external_ids;
+DROP TABLE IF EXISTS movies_external_ids;
+DROP TABLE IF EXISTS actors_movies;
+DROP TABLE IF EXISTS movies;
+DROP TABLE IF EXISTS actors;
@@ -0,0 +1,55 @@
+-- Схема SQLite повторяет схему postgres после всех миграций из каталога migrations.
+-- Типов VARCHAR, ENUM и DATE в SQLite нет, поэтому их ограничения заданы через CHECK.
+-- Даты хранятся строкой YYYY-MM-DD (первый день периода) вместе с точностью, как в postgres.
+
+CREATE TABLE IF NOT EXISTS actors (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL CHECK (length(name) <= 50),
+    surname TEXT NOT NULL CHECK (length(surname) <= 50),
+    patronymic TEXT CHECK (length(patronymic) <= 50),
+    gender TEXT CHECK (gender IN ('male', 'female')),
+    date_of_birth TEXT CHECK (date_of_birth = date(date_of_birth)),
+    date_of_birth_precision TEXT NOT NULL DEFAULT 'day' CHECK (date_of_birth_precision IN ('day', 'month', 'year')),
+    CONSTRAINT unique_name_surname UNIQUE (name, surname)
+);
+
+CREATE TABLE IF NOT EXISTS movies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL CHECK (length(title) <= 150),
+    description TEXT CHECK (length(description) <= 1000),
+    release_date TEXT CHECK (release_date = date(release_date)),
+    release_date_precision TEXT NOT NULL DEFAULT 'day' CHECK (release_date_precision IN ('day', 'month', 'year')),
+    rating INTEGER CHECK (rating >= 0 AND rating <= 10)
+);
+
+CREATE TABLE IF NOT EXISTS actors_movies (
+    movie_id INTEGER REFERENCES movies(id),
+    actor_id INTEGER REFERENCES actors(id),
+    PRIMARY KEY (movie_id, actor_id)
+);
+
+CREATE TABLE IF NOT EXISTS movies_external_ids (
+    movie_id INTEGER NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    source TEXT NOT NULL CHECK (source IN ('imdb', 'tmdb', 'kinopoisk')),
+    external_id TEXT NOT NULL CHECK (length(external_id) <= 64),
+    PRIMARY KEY (movie_id, source),
+    CONSTRAINT unique_movie_external_id UNIQUE (source, external_id)
+);
+
+CREATE TABLE IF NOT EXISTS actors_external_ids (
+    actor_id INTEGER NOT NULL REFERENCES actors(id) ON DELETE CASCADE,
+    source TEXT NOT NULL CHECK (source IN ('imdb', 'tmdb', 'kinopoisk')),
+    external_id TEXT NOT NULL CHECK (length(external_id) <= 64),
+    PRIMARY KEY (actor_id, source),
+    CONSTRAINT unique_actor_external_id UNIQUE (source, external_id)
+);
+
+CREATE TABLE IF NOT EXISTS actor_redirects (
+    old_id INTEGER PRIMARY KEY,
+    new_id INTEGER NOT NULL REFERENCES actors(id) ON DELETE CASCADE,
+    merged_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS actors_movies_actor_id ON actors_movies (actor_id);
+
+CREATE INDEX IF NOT EXISTS actor_redirects_new_id ON actor_redirects (new_id);
@@ -0,0 +1,326 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/entity"
+
+	"github.com/Masterminds/squirrel"
+	"github.com/jmoiron/sqlx"
+)
+
+type MoviesRepo struct {
+	db *sqlx.DB
+}
+
+func NewMoviesRepo(db *sql.DB) *MoviesRepo {
+	return &MoviesRepo{db: sqlx.NewDb(db, "sqlite")}
+}
+
+const MovieQueryFind = `SELECT ` + movieColumns + ` FROM movies WHERE id = $1`
+
+func (r *MoviesRepo) Get(ctx context.Context, id int) (entity.Movie, error) {
+
+	var res entity.Movie
+	err := r.db.GetContext(ctx, &res, MovieQueryFind, id)
+
+	if err != nil {
+		return entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	res.ExternalIDs, err = moviesExternalIDs.get(ctx, r.db, id)
+
+	if err != nil {
+		return entity.Movie{}, err
+	}
+
+	return res, nil
+}
+
+// GetByExternalID возвращает фильм по его идентификатору во внешнем каталоге
+func (r *MoviesRepo) GetByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error) {
+
+	id, err := moviesExternalIDs.find(ctx, r.db, source, externalID)
+
+	if err != nil {
+		return entity.Movie{}, err
+	}
+
+	return r.Get(ctx, id)
+}
+
+// MatchExternalIDs возвращает id фильма, которому принадлежит любой из внешних идентификаторов
+func (r *MoviesRepo) MatchExternalIDs(ctx context.Context, ids entity.ExternalIDs) (int, error) {
+	return moviesExternalIDs.match(ctx, r.db, ids)
+}
+
+// LOWER и LIKE в SQLite не учитывают регистр только у латиницы, поэтому используется unicode_lower
+const MovieQueryFindMovie = `
+SELECT DISTINCT ` + movieColumns + `
+FROM movies
+JOIN actors_movies ON movies.id = actors_movies.movie_id
+JOIN actors ON actors_movies.actor_id = actors.id
+WHERE ($1 = '' OR INSTR(unicode_lower(movies.title), unicode_lower($1)) > 0)
+AND ($2 = '' OR INSTR(unicode_lower(actors.name), unicode_lower($2)) > 0)`
+
+func (r *MoviesRepo) GetMovie(ctx context.Context) ([]entity.Movie, error) {
+
+	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
+	titles := filter_options["title"]
+	actors := filter_options["actor_name"]
+
+	var title, actor string
+	if len(titles) != 0 {
+		title = titles[0]
+	}
+
+	if len(actors) != 0 {
+		actor = actors[0]
+	}
+
+	var res []entity.Movie
+	err := r.db.SelectContext(ctx, &res, MovieQueryFindMovie, title, actor)
+
+	if err != nil {
+		return []entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	// Дополним найденные фильмы внешними идентификаторами
+	ids := make([]int, 0, len(res))
+	for _, m := range res {
+		ids = append(ids, *m.Id)
+	}
+
+	externalIDs, err := moviesExternalIDs.getMany(ctx, r.db, ids)
+
+	if err != nil {
+		return []entity.Movie{}, err
+	}
+
+	for i := range res {
+		res[i].ExternalIDs = externalIDs[*res[i].Id]
+	}
+
+	return res, nil
+}
+
+const MovieQuerySave = `INSERT INTO movies(title, description, release_date, release_date_precision, rating)
+					VALUES($1, $2, $3, $4, $5)
+					RETURNING id`
+
+func (r *MoviesRepo) Save(ctx context.Context, data entity.MovieData) (int, error) {
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var res int
+
+	err = tx.GetContext(ctx, &res, MovieQuerySave,
+		data.Title,
+		data.Description,
+		data.ReleaseDate,
+		precision(data.ReleaseDate),
+		data.Rating,
+	)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, dbError(err))
+	}
+
+	// Сохраняем идентификаторы фильма во внешних каталогах
+	err = moviesExternalIDs.save(ctx, tx, res, data.ExternalIDs)
+
+	if err != nil {
+		return 0, err
+	}
+
+	return res, tx.Commit()
+}
+
+func (r *MoviesRepo) Update(ctx context.Context, updates entity.Movie) (entity.Movie, error) {
+
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	// Составим выражение для оператора SQL SET
+	data, err := getMapMovie(updates)
+
+	if err != nil {
+		return entity.Movie{}, fmt.Errorf("%s: Error: %w", op, err)
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return entity.Movie{}, fmt.Errorf("%s: failed to begin transaction: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	var res entity.Movie
+
+	// Если изменяются только внешние идентификаторы, таблицу movies не обновляем
+	if len(data) == 0 {
+		err = tx.GetContext(ctx, &res, MovieQueryFind, *updates.Id)
+	} else {
+		var sql string
+		var i []interface{}
+
+		sql, i, err = psql.Update("movies").
+			SetMap(data).
+			Where(squirrel.Eq{"id": *updates.Id}).
+			Suffix("RETURNING " + movieColumns).
+			ToSql()
+
+		if err != nil {
+			return entity.Movie{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+		}
+
+		err = tx.GetContext(ctx, &res, sql, i...)
+	}
+
+	if err != nil {
+		return entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, dbError(err))
+	}
+
+	err = moviesExternalIDs.save(ctx, tx, *res.Id, updates.ExternalIDs)
+
+	if err != nil {
+		return entity.Movie{}, err
+	}
+
+	res.ExternalIDs, err = moviesExternalIDs.get(ctx, tx, *res.Id)
+
+	if err != nil {
+		return entity.Movie{}, err
+	}
+
+	return res, tx.Commit()
+}
+
+const MovieQueryDelete = `DELETE FROM movies WHERE id = $1 RETURNING ` + movieColumns
+
+func (r *MoviesRepo) Delete(ctx context.Context, id int) (entity.Movie, error) {
+
+	var res entity.Movie
+	err := r.db.GetContext(ctx, &res, MovieQueryDelete, id)
+
+	if err != nil {
+		return entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, dbError(err))
+	}
+
+	return res, nil
+}
+
+// List возвращает фильмы, отсортированные по параметрам сортировки, а без них - по убыванию рейтинга
+func (r *MoviesRepo) List(ctx context.Context) ([]entity.Movie, error) {
+
+	// Используем оператор ORDER BY для сортировки
+	order, err := orderBy(ctx, movieFilters, "movies.rating DESC NULLS FIRST")
+
+	if err != nil {
+		return []entity.Movie{}, err
+	}
+
+	return r.list(ctx, squirrel.And{}, order)
+}
+
+func (r *MoviesRepo) Next(ctx context.Context) ([]entity.Movie, error) {
+
+	// Условие пагинации
+	personID := ctx.Value(pagination.NextPersonID).(int)
+
+	return r.list(ctx, squirrel.GtOrEq{"movies.id": personID}, "movies.id ASC")
+}
+
+// list возвращает первую страницу фильмов, подходящих под параметры фильтрации и условие cond
+func (r *MoviesRepo) list(ctx context.Context, cond squirrel.Sqlizer, order string) ([]entity.Movie, error) {
+
+	psql := squirrel.StatementBuilder.PlaceholderFormat(squirrel.Dollar)
+
+	// Составим выражение для оператора SQL Where ... AND ...
+	filters, err := where(ctx, movieFilters)
+
+	if err != nil {
+		return []entity.Movie{}, err
+	}
+
+	sql, i, err := psql.Select(movieColumns).
+		From("movies").
+		Where(append(filters, cond)).
+		OrderBy(order).
+		Limit(pageSize).
+		ToSql()
+
+	if err != nil {
+		return []entity.Movie{}, fmt.Errorf("%s: squirrel failed to build sql statement : %w", op, err)
+	}
+
+	res := []entity.Movie{}
+	err = r.db.SelectContext(ctx, &res, sql, i...)
+
+	if err != nil {
+		return []entity.Movie{}, fmt.Errorf("%s: DB method 'Query' returned error: %w", op, err)
+	}
+
+	// Дополним страницу внешними идентификаторами
+	ids := make([]int, 0, len(res))
+	for _, m := range res {
+		ids = append(ids, *m.Id)
+	}
+
+	externalIDs, err := moviesExternalIDs.getMany(ctx, r.db, ids)
+
+	if err != nil {
+		return []entity.Movie{}, err
+	}
+
+	for i := range res {
+		res[i].ExternalIDs = externalIDs[*res[i].Id]
+	}
+
+	return res, nil
+}
+
+func getMapMovie(updates entity.Movie) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+
+	if val := updates.MovieData.Title; val != nil {
+		res["title"] = *val
+	}
+
+	if val := updates.MovieData.Description; val != nil {
+		res["description"] = *val
+	}
+
+	if val := updates.MovieData.ReleaseDate; val != nil {
+		res["release_date"] = *val
+		res["release_date_precision"] = val.Precision()
+	}
+
+	if val := updates.Rating; val != nil {
+		res["rating"] = *val
+	}
+
+	if len(res) == 0 && len(updates.ExternalIDs) == 0 {
+		return res, fmt.Errorf("%s: Data for update operation were NOT specified", op)
+	}
+
+	return res, nil
+}
+
+// Count возвращает число фильмов
+func (r *MoviesRepo) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, `SELECT COUNT(*) FROM movies`)
+
+	if err != nil {
+		return 0, fmt.Errorf("%s: DB returned error: %w", op, err)
+	}
+
+	return count, nil
+}
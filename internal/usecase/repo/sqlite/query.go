@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	sortpkg "sort"
+	"strconv"
+	"strings"
+
+	"github.com/Masterminds/squirrel"
+
+	"filmoteka/internal/controller/middleware/filter"
+	"filmoteka/internal/controller/middleware/sort"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+)
+
+// columnKind - тип столбца. От него зависит, как разбирается значение из параметров запроса.
+type columnKind int
+
+const (
+	kindInt columnKind = iota
+	kindText
+	kindDate
+	kindEnum
+)
+
+// column - столбец таблицы, по которому можно фильтровать и сортировать записи.
+// SQLite не проверяет типы значений в условиях, как postgres, поэтому они проверяются здесь.
+type column struct {
+	name   string
+	kind   columnKind
+	values []string
+}
+
+var (
+	genders    = []string{"male", "female"}
+	precisions = []string{string(entity.PrecisionDay), string(entity.PrecisionMonth), string(entity.PrecisionYear)}
+)
+
+var actorFilters = map[string]column{
+	"id":                      {name: "actors.id", kind: kindInt},
+	"name":                    {name: "actors.name", kind: kindText},
+	"surname":                 {name: "actors.surname", kind: kindText},
+	"patronymic":              {name: "actors.patronymic", kind: kindText},
+	"gender":                  {name: "actors.gender", kind: kindEnum, values: genders},
+	"date_of_birth":           {name: "actors.date_of_birth", kind: kindDate},
+	"date_of_birth_precision": {name: "actors.date_of_birth_precision", kind: kindEnum, values: precisions},
+}
+
+var movieFilters = map[string]column{
+	"id":                     {name: "movies.id", kind: kindInt},
+	"title":                  {name: "movies.title", kind: kindText},
+	"description":            {name: "movies.description", kind: kindText},
+	"release_date":           {name: "movies.release_date", kind: kindDate},
+	"release_date_precision": {name: "movies.release_date_precision", kind: kindEnum, values: precisions},
+	"rating":                 {name: "movies.rating", kind: kindInt},
+}
+
+// parse разбирает значение столбца из параметров запроса
+func (c column) parse(val string) (any, error) {
+	switch c.kind {
+	case kindInt:
+		n, err := strconv.Atoi(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid integer %q for column %s: %w", op, val, c.name, usecase.ErrInvalidData)
+		}
+		return n, nil
+	case kindDate:
+		// Дата сравнивается с первым днем периода, как в postgres
+		d, err := entity.ParseDate(val)
+		if err != nil {
+			return nil, fmt.Errorf("%s: invalid date %q for column %s: %w", op, val, c.name, usecase.ErrInvalidData)
+		}
+		return d.Time().Format("2006-01-02"), nil
+	case kindEnum:
+		for _, v := range c.values {
+			if v == val {
+				return val, nil
+			}
+		}
+		return nil, fmt.Errorf("%s: invalid value %q for column %s: %w", op, val, c.name, usecase.ErrInvalidData)
+	}
+
+	return val, nil
+}
+
+// where составляет условие WHERE column = value AND ... из параметров фильтрации
+func where(ctx context.Context, columns map[string]column) (squirrel.And, error) {
+	filter_options, _ := ctx.Value(filter.FilterOptionsContextKey).(map[string][]string)
+
+	res := squirrel.And{}
+	for k, v := range filter_options {
+		c, ok := columns[k]
+		if !ok {
+			return nil, fmt.Errorf("%s: column %q does not exist", op, k)
+		}
+
+		for _, val := range v {
+			parsed, err := c.parse(val)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, squirrel.Eq{c.name: parsed})
+		}
+	}
+
+	return res, nil
+}
+
+// orderBy составляет выражение ORDER BY из параметров сортировки, а без них возвращает def.
+// NULL, как в postgres, больше любого значения: при сортировке по возрастанию такие записи идут последними.
+func orderBy(ctx context.Context, columns map[string]column, def string) (string, error) {
+	sort_options, _ := ctx.Value(sort.SortOptionsContextKey).(map[string]string)
+	if len(sort_options) == 0 {
+		return def, nil
+	}
+
+	// Порядок ключей map случаен, поэтому столбцы перечисляются по алфавиту
+	names := make([]string, 0, len(sort_options))
+	for k := range sort_options {
+		if _, ok := columns[k]; !ok {
+			return "", fmt.Errorf("%s: column %q does not exist", op, k)
+		}
+		names = append(names, k)
+	}
+	sortpkg.Strings(names)
+
+	stmt := make([]string, 0, len(names))
+	for _, k := range names {
+		if strings.EqualFold(sort_options[k], sort.DESC) {
+			stmt = append(stmt, columns[k].name+" DESC NULLS FIRST")
+		} else {
+			stmt = append(stmt, columns[k].name+" ASC NULLS LAST")
+		}
+	}
+
+	return strings.Join(stmt, ", "), nil
+}
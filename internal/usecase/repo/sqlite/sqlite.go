@@ -0,0 +1,94 @@
+// Package sqlite - репозитории в файле SQLite для небольших установок без postgres.
+// Схема БД повторяет схему postgres, миграции - свои (каталог migrations) и применяются при открытии БД.
+//
+// Драйвер modernc.org/sqlite написан на Go, поэтому сервер остается одним бинарным файлом без cgo.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"embed"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/golang-migrate/migrate/v4"
+	sqlitemigrate "github.com/golang-migrate/migrate/v4/database/sqlite"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"modernc.org/sqlite"
+
+	"filmoteka/pkg/tracesql"
+)
+
+const op = "internal.usecase.repo.sqlite"
+const pageSize uint64 = 10
+
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+func init() {
+	// LOWER в SQLite переводит в нижний регистр только латиницу
+	sqlite.MustRegisterDeterministicScalarFunction("unicode_lower", 1,
+		func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+			switch v := args[0].(type) {
+			case string:
+				return strings.ToLower(v), nil
+			case []byte:
+				return strings.ToLower(string(v)), nil
+			}
+
+			return args[0], nil
+		})
+}
+
+// Open открывает файл БД path (создает, если его нет) и применяет миграции.
+// SQLite не допускает одновременной записи из нескольких соединений, поэтому соединение одно:
+// запросы выполняются по очереди, а внутри транзакции нельзя обращаться к БД в обход нее.
+func Open(ctx context.Context, path string) (*sql.DB, error) {
+	db, err := tracesql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)", semconv.DBSystemSqlite)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to open database: %w", op, err)
+	}
+
+	db.SetMaxOpenConns(1)
+
+	if err = db.PingContext(ctx); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: failed to open database %s: %w", op, path, err)
+	}
+
+	if err = migrateUp(db); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return db, nil
+}
+
+// migrateUp применяет все встроенные миграции
+func migrateUp(db *sql.DB) error {
+	src, err := iofs.New(migrationsFS, "migrations")
+	if err != nil {
+		return fmt.Errorf("%s: failed to read embedded migrations: %w", op, err)
+	}
+	defer src.Close()
+
+	// migrate.Close закрыл бы и db, поэтому не вызывается
+	dbDriver, err := sqlitemigrate.WithInstance(db, &sqlitemigrate.Config{})
+	if err != nil {
+		return fmt.Errorf("%s: failed to init migrate: %w", op, err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, "sqlite", dbDriver)
+	if err != nil {
+		return fmt.Errorf("%s: failed to init migrate: %w", op, err)
+	}
+
+	if err = m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		return fmt.Errorf("%s: failed to migrate database: %w", op, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package sqlite_test
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase/repo/repotest"
+	"filmoteka/internal/usecase/repo/sqlite"
+)
+
+func TestRepos(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) repotest.Repos {
+		ctx := context.Background()
+		db := open(t, filepath.Join(t.TempDir(), "filmoteka.db"))
+
+		err := repotest.Load(ctx, func(ctx context.Context, table string, row map[string]any) error {
+			return insert(ctx, db, table, row)
+		})
+		if err != nil {
+			t.Fatalf("failed to load fixtures: %s", err)
+		}
+
+		return repotest.Repos{
+			Actors:       sqlite.NewActorsRepo(db),
+			Movies:       sqlite.NewMoviesRepo(db),
+			ActorsMovies: sqlite.NewActorsMoviesRepo(db),
+		}
+	})
+}
+
+// Миграции применяются при каждом открытии БД, уже примененные пропускаются
+func TestOpenExisting(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "filmoteka.db")
+
+	name, surname := "Иван", "Петров"
+	id, err := sqlite.NewActorsRepo(open(t, path)).Save(ctx, entity.ActorData{Name: &name, Surname: &surname})
+	if err != nil {
+		t.Fatalf("failed to save actor: %s", err)
+	}
+
+	got, err := sqlite.NewActorsRepo(open(t, path)).Get(ctx, id)
+	if err != nil {
+		t.Fatalf("failed to get actor after reopening: %s", err)
+	}
+
+	if *got.Name != name || *got.Surname != surname {
+		t.Fatalf("got %s %s, want %s %s", *got.Name, *got.Surname, name, surname)
+	}
+}
+
+func open(t *testing.T, path string) *sql.DB {
+	t.Helper()
+
+	db, err := sqlite.Open(context.Background(), path)
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+
+	t.Cleanup(func() { db.Close() })
+
+	return db
+}
+
+// insert добавляет строку фикстур: столбцы таблиц SQLite называются так же, как в postgres
+func insert(ctx context.Context, db *sql.DB, table string, row map[string]any) error {
+	columns := make([]string, 0, len(row))
+	for column := range row {
+		columns = append(columns, column)
+	}
+	sort.Strings(columns)
+
+	placeholders := make([]string, len(columns))
+	values := make([]any, len(columns))
+
+	for i, column := range columns {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		values[i] = row[column]
+	}
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s)",
+		table, strings.Join(columns, ", "), strings.Join(placeholders, ", "))
+
+	_, err := db.ExecContext(ctx, query, values...)
+
+	return err
+}
@@ -0,0 +1,29 @@
+package sqlite
+
+import "filmoteka/pkg/tracesql"
+
+// Имена запросов в спанах трассировки. Запросы, собранные построителем, называются
+// по операции и таблице.
+func init() {
+	tracesql.Statements(map[string]string{
+		ActorQueryFind:                 "ActorQueryFind",
+		ActorQuerySave:                 "ActorQuerySave",
+		ActorQueryDelete:               "ActorQueryDelete",
+		ActorQueryProfiles:             "ActorQueryProfiles",
+		ActorMergeQueryCount:           "ActorMergeQueryCount",
+		ActorMergeQueryFill:            "ActorMergeQueryFill",
+		ActorMergeQueryMoveMovies:      "ActorMergeQueryMoveMovies",
+		ActorMergeQueryDeleteMovies:    "ActorMergeQueryDeleteMovies",
+		ActorMergeQueryMoveExternalIDs: "ActorMergeQueryMoveExternalIDs",
+		ActorMergeQueryMoveRedirects:   "ActorMergeQueryMoveRedirects",
+		ActorMergeQueryRedirect:        "ActorMergeQueryRedirect",
+		ActorMergeQueryDelete:          "ActorMergeQueryDelete",
+		ActorMovieQuerySave:            "ActorMovieQuerySave",
+		ActorMovieQueryDelete:          "ActorMovieQueryDelete",
+		ListActorsAndMoviesQuery:       "ListActorsAndMoviesQuery",
+		MovieQueryFind:                 "MovieQueryFind",
+		MovieQueryFindMovie:            "MovieQueryFindMovie",
+		MovieQuerySave:                 "MovieQuerySave",
+		MovieQueryDelete:               "MovieQueryDelete",
+	})
+}
@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"filmoteka/internal/entity"
@@ -24,18 +26,62 @@ func NewActors(repoActors ActorsRepo, l Logger) *ActorUseCase {
 func (uc *ActorUseCase) Find(ctx context.Context, id int) (entity.Actor, error) {
 	res, err := uc.repo.Get(ctx, id)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Find returned error: %w", op, err)
+		return res, repoError("actor", "Find", err)
 	}
 
 	return res, nil
 }
 
+func (uc *ActorUseCase) FindByExternalID(ctx context.Context, source, externalID string) (entity.Actor, error) {
+	if !entity.IsExternalSource(source) {
+		return entity.Actor{}, Validation("unknown_external_source", fmt.Sprintf("unknown external source %q", source))
+	}
+
+	res, err := uc.repo.GetByExternalID(ctx, source, externalID)
+	if err != nil {
+		return res, repoError("actor", "GetByExternalID", err)
+	}
+
+	return res, nil
+}
+
+// Upsert обновляет актера, найденного по любому из внешних идентификаторов, или создает нового
+func (uc *ActorUseCase) Upsert(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	if len(data.ExternalIDs) == 0 {
+		return entity.Actor{}, Validation("external_ids_required", "external ids are required for upsert")
+	}
+
+	if err := validateActorData(data, false); err != nil {
+		return entity.Actor{}, err
+	}
+
+	id, err := uc.repo.MatchExternalIDs(ctx, data.ExternalIDs)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return uc.Save(ctx, data)
+	}
+
+	if err != nil {
+		return entity.Actor{}, repoError("actor", "MatchExternalIDs", err)
+	}
+
+	return uc.Update(ctx, entity.Actor{Id: &id, ActorData: data})
+}
+
 func (uc *ActorUseCase) Save(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	if err := validateActorData(data, false); err != nil {
+		return entity.Actor{}, err
+	}
 
 	id, err := uc.repo.Save(ctx, data)
 
+	// Актер с такими именем и фамилией уже есть: INSERT ... ON CONFLICT DO NOTHING не вернул id
+	if errors.Is(err, sql.ErrNoRows) {
+		return entity.Actor{}, Conflict("actor_already_exists", "actor already exists").Wrap(err)
+	}
+
 	if err != nil {
-		return entity.Actor{}, err
+		return entity.Actor{}, repoError("actor", "Save", err)
 	}
 
 	res := entity.Actor{
@@ -43,31 +89,45 @@ func (uc *ActorUseCase) Save(ctx context.Context, data entity.ActorData) (entity
 		ActorData: data,
 	}
 
+	uc.log.InfoContext(ctx, "actor saved", "id", id)
+
 	return res, nil
 }
 
 func (uc *ActorUseCase) Update(ctx context.Context, updates entity.Actor) (entity.Actor, error) {
+	if updates.Id == nil {
+		return entity.Actor{}, Validation("id_required", "id should be specified")
+	}
+
+	if err := validateActorData(updates.ActorData, true); err != nil {
+		return entity.Actor{}, err
+	}
+
 	res, err := uc.repo.Update(ctx, updates)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Update returned error: %w", op, err)
+		return res, repoError("actor", "Update", err)
 	}
 
+	uc.log.InfoContext(ctx, "actor updated", "id", *updates.Id)
+
 	return res, nil
 }
 
 func (uc *ActorUseCase) Delete(ctx context.Context, id int) (entity.Actor, error) {
 	res, err := uc.repo.Delete(ctx, id)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Delete returned error: %w", op, err)
+		return res, repoError("actor", "Delete", err)
 	}
 
+	uc.log.InfoContext(ctx, "actor deleted", "id", id)
+
 	return res, nil
 }
 
 func (uc *ActorUseCase) List(ctx context.Context) ([]entity.Actor, error) {
 	res, err := uc.repo.List(ctx)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.List returned error: %w", op, err)
+		return res, repoError("actor", "List", err)
 	}
 
 	return res, nil
@@ -76,8 +136,34 @@ func (uc *ActorUseCase) List(ctx context.Context) ([]entity.Actor, error) {
 func (uc *ActorUseCase) Next(ctx context.Context) ([]entity.Actor, error) {
 	res, err := uc.repo.Next(ctx)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Next returned error: %w", op, err)
+		return res, repoError("actor", "Next", err)
+	}
+
+	return res, nil
+}
+
+// Duplicates возвращает пары актеров, которые, вероятно, являются одним человеком
+func (uc *ActorUseCase) Duplicates(ctx context.Context, minScore float64) ([]entity.DuplicateActors, error) {
+	profiles, err := uc.repo.Profiles(ctx)
+	if err != nil {
+		return nil, repoError("actor", "Profiles", err)
+	}
+
+	return findDuplicates(profiles, minScore), nil
+}
+
+// Merge объединяет актера sourceID с актером targetID
+func (uc *ActorUseCase) Merge(ctx context.Context, sourceID, targetID int) (entity.Actor, error) {
+	if sourceID == targetID {
+		return entity.Actor{}, Validation("actor_merge_with_itself", "actor can NOT be merged with itself")
+	}
+
+	res, err := uc.repo.Merge(ctx, sourceID, targetID)
+	if err != nil {
+		return res, repoError("actor", "Merge", err)
 	}
 
+	uc.log.InfoContext(ctx, "actors merged", "source_id", sourceID, "target_id", targetID)
+
 	return res, nil
 }
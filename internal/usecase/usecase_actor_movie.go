@@ -2,7 +2,8 @@ package usecase
 
 import (
 	"context"
-	"fmt"
+	"database/sql"
+	"errors"
 
 	"filmoteka/internal/entity"
 )
@@ -20,13 +21,39 @@ func NewActorsMovies(repoActorsMovies ActorsMoviesRepo, l Logger) *ActorMovieUse
 }
 
 func (uc *ActorMovieUseCase) Save(ctx context.Context, data entity.ActorMovie) error {
+	if err := validateActorMovie(data); err != nil {
+		return err
+	}
 
 	err := uc.repo.Save(ctx, data)
 
+	if errors.Is(err, sql.ErrNoRows) {
+		return NotFound("actor_or_movie_not_found", "actor or movie was NOT found").Wrap(err)
+	}
+
 	if err != nil {
-		return fmt.Errorf("%s: repo.Save returned error: %w", op, err)
+		return repoError("actor_movie", "Save", err)
 	}
 
+	uc.log.InfoContext(ctx, "actor added to movie", "actor_id", *data.Actor_id, "movie_id", *data.Movie_id)
+
+	return nil
+}
+
+// Delete убирает актера из фильма
+func (uc *ActorMovieUseCase) Delete(ctx context.Context, data entity.ActorMovie) error {
+	if err := validateActorMovie(data); err != nil {
+		return err
+	}
+
+	err := uc.repo.Delete(ctx, data)
+
+	if err != nil {
+		return repoError("actor_movie", "Delete", err)
+	}
+
+	uc.log.InfoContext(ctx, "actor removed from movie", "actor_id", *data.Actor_id, "movie_id", *data.Movie_id)
+
 	return nil
 }
 
@@ -35,7 +62,31 @@ func (uc *ActorMovieUseCase) List(ctx context.Context) ([]entity.ActorMovieData,
 	res, err := uc.repo.List(ctx)
 
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.List returned error: %w", op, err)
+		return res, repoError("actor_movie", "List", err)
+	}
+
+	return res, nil
+}
+
+// Cast возвращает актеров фильмов. Ключ - id фильма.
+func (uc *ActorMovieUseCase) Cast(ctx context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+
+	res, err := uc.repo.Cast(ctx, movieIDs)
+
+	if err != nil {
+		return res, repoError("actor_movie", "Cast", err)
+	}
+
+	return res, nil
+}
+
+// Filmography возвращает фильмы актеров. Ключ - id актера.
+func (uc *ActorMovieUseCase) Filmography(ctx context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+
+	res, err := uc.repo.Filmography(ctx, actorIDs)
+
+	if err != nil {
+		return res, repoError("actor_movie", "Filmography", err)
 	}
 
 	return res, nil
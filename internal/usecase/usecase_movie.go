@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
 
 	"filmoteka/internal/entity"
@@ -22,7 +24,7 @@ func NewMovies(repoMovies MoviesRepo, l Logger) *MovieUseCase {
 func (uc *MovieUseCase) Find(ctx context.Context, id int) (entity.Movie, error) {
 	res, err := uc.repo.Get(ctx, id)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Find returned error: %w", op, err)
+		return res, repoError("movie", "Find", err)
 	}
 
 	return res, nil
@@ -31,18 +33,57 @@ func (uc *MovieUseCase) Find(ctx context.Context, id int) (entity.Movie, error)
 func (uc *MovieUseCase) FindMovie(ctx context.Context) ([]entity.Movie, error) {
 	res, err := uc.repo.GetMovie(ctx)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Find returned error: %w", op, err)
+		return res, repoError("movie", "Find", err)
 	}
 
 	return res, nil
 }
 
+func (uc *MovieUseCase) FindByExternalID(ctx context.Context, source, externalID string) (entity.Movie, error) {
+	if !entity.IsExternalSource(source) {
+		return entity.Movie{}, Validation("unknown_external_source", fmt.Sprintf("unknown external source %q", source))
+	}
+
+	res, err := uc.repo.GetByExternalID(ctx, source, externalID)
+	if err != nil {
+		return res, repoError("movie", "GetByExternalID", err)
+	}
+
+	return res, nil
+}
+
+// Upsert обновляет фильм, найденный по любому из внешних идентификаторов, или создает новый
+func (uc *MovieUseCase) Upsert(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	if len(data.ExternalIDs) == 0 {
+		return entity.Movie{}, Validation("external_ids_required", "external ids are required for upsert")
+	}
+
+	if err := validateMovieData(data, false); err != nil {
+		return entity.Movie{}, err
+	}
+
+	id, err := uc.repo.MatchExternalIDs(ctx, data.ExternalIDs)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return uc.Save(ctx, data)
+	}
+
+	if err != nil {
+		return entity.Movie{}, repoError("movie", "MatchExternalIDs", err)
+	}
+
+	return uc.Update(ctx, entity.Movie{Id: &id, MovieData: data})
+}
+
 func (uc *MovieUseCase) Save(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	if err := validateMovieData(data, false); err != nil {
+		return entity.Movie{}, err
+	}
 
 	id, err := uc.repo.Save(ctx, data)
 
 	if err != nil {
-		return entity.Movie{}, fmt.Errorf("%s: usecase.Save returned error: %w", op, err)
+		return entity.Movie{}, repoError("movie", "Save", err)
 	}
 
 	res := entity.Movie{
@@ -50,31 +91,45 @@ func (uc *MovieUseCase) Save(ctx context.Context, data entity.MovieData) (entity
 		MovieData: data,
 	}
 
+	uc.log.InfoContext(ctx, "movie saved", "id", id)
+
 	return res, nil
 }
 
 func (uc *MovieUseCase) Update(ctx context.Context, updates entity.Movie) (entity.Movie, error) {
+	if updates.Id == nil {
+		return entity.Movie{}, Validation("id_required", "id should be specified")
+	}
+
+	if err := validateMovieData(updates.MovieData, true); err != nil {
+		return entity.Movie{}, err
+	}
+
 	res, err := uc.repo.Update(ctx, updates)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Update returned error: %w", op, err)
+		return res, repoError("movie", "Update", err)
 	}
 
+	uc.log.InfoContext(ctx, "movie updated", "id", *updates.Id)
+
 	return res, nil
 }
 
 func (uc *MovieUseCase) Delete(ctx context.Context, id int) (entity.Movie, error) {
 	res, err := uc.repo.Delete(ctx, id)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Delete returned error: %w", op, err)
+		return res, repoError("movie", "Delete", err)
 	}
 
+	uc.log.InfoContext(ctx, "movie deleted", "id", id)
+
 	return res, nil
 }
 
 func (uc *MovieUseCase) List(ctx context.Context) ([]entity.Movie, error) {
 	res, err := uc.repo.List(ctx)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Find returned error: %w", op, err)
+		return res, repoError("movie", "Find", err)
 	}
 
 	return res, nil
@@ -83,7 +138,7 @@ func (uc *MovieUseCase) List(ctx context.Context) ([]entity.Movie, error) {
 func (uc *MovieUseCase) Next(ctx context.Context) ([]entity.Movie, error) {
 	res, err := uc.repo.Next(ctx)
 	if err != nil {
-		return res, fmt.Errorf("%s: repo.Find returned error: %w", op, err)
+		return res, repoError("movie", "Find", err)
 	}
 
 	return res, nil
@@ -0,0 +1,123 @@
+package usecase
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"filmoteka/internal/entity"
+	"filmoteka/pkg/validator"
+)
+
+// Ограничения совпадают с ограничениями столбцов в БД.
+// Экспортируются, чтобы документация API описывала те же ограничения.
+const (
+	MaxNameLen        = 50
+	MaxTitleLen       = 150
+	MaxDescriptionLen = 1000
+	MaxExternalIDLen  = 64
+	MinRating         = 0
+	MaxRating         = 10
+)
+
+// invalidInput возвращает ошибку со списком всех неверных полей или nil, если ошибок нет
+func invalidInput(errs []validator.FieldError) error {
+	if len(errs) == 0 {
+		return nil
+	}
+
+	e := Validation("invalid_input", "request data is invalid")
+	e.Fields = errs
+
+	return e
+}
+
+// validateActorData проверяет данные актера. При partial (частичное обновление)
+// поля необязательны, но должно быть передано хотя бы одно.
+func validateActorData(data entity.ActorData, partial bool) error {
+	v := validator.New()
+
+	name := []validator.StringRule{validator.Required(), validator.Length(1, MaxNameLen)}
+	if partial {
+		name[0] = validator.NotEmpty()
+	}
+
+	v.String("name", data.Name, name...)
+	v.String("surname", data.Surname, name...)
+	v.String("patronymic", data.Patronymic, validator.Length(0, MaxNameLen))
+	v.String("gender", data.Gender, validator.OneOf("male", "female"))
+	validateDate(v, "date_of_birth", data.DateOfBirth, true)
+	validateExternalIDs(v, data.ExternalIDs)
+
+	if partial && data.Name == nil && data.Surname == nil && data.Patronymic == nil &&
+		data.Gender == nil && data.DateOfBirth == nil && len(data.ExternalIDs) == 0 {
+		v.Add("", "no_fields", "at least one field should be specified for update")
+	}
+
+	return invalidInput(v.Errors())
+}
+
+// validateMovieData проверяет данные фильма, partial - как в validateActorData
+func validateMovieData(data entity.MovieData, partial bool) error {
+	v := validator.New()
+
+	title := []validator.StringRule{validator.Required(), validator.Length(1, MaxTitleLen)}
+	if partial {
+		title[0] = validator.NotEmpty()
+	}
+
+	v.String("title", data.Title, title...)
+	v.String("description", data.Description, validator.Length(0, MaxDescriptionLen))
+	validateDate(v, "release_date", data.ReleaseDate, false)
+	v.Int("rating", data.Rating, validator.Range(MinRating, MaxRating))
+	validateExternalIDs(v, data.ExternalIDs)
+
+	if partial && data.Title == nil && data.Description == nil && data.ReleaseDate == nil &&
+		data.Rating == nil && len(data.ExternalIDs) == 0 {
+		v.Add("", "no_fields", "at least one field should be specified for update")
+	}
+
+	return invalidInput(v.Errors())
+}
+
+func validateActorMovie(data entity.ActorMovie) error {
+	v := validator.New()
+
+	v.Int("actor_id", data.Actor_id, validator.RequiredInt(), validator.Min(1))
+	v.Int("movie_id", data.Movie_id, validator.RequiredInt(), validator.Min(1))
+
+	return invalidInput(v.Errors())
+}
+
+// validateDate проверяет дату, полученную от клиента. Неполная дата считается
+// датой в будущем, только если весь ее период (год или месяц) еще не начался.
+func validateDate(v *validator.Validator, field string, d *entity.Date, notInFuture bool) {
+	switch {
+	case d == nil:
+	case !d.Valid():
+		v.Add(field, "date", "value should be a valid date in YYYY-MM-DD, YYYY-MM, YYYY or DD.MM.YYYY format")
+	case notInFuture && d.Time().After(time.Now()):
+		v.Add(field, "future_date", "date should NOT be in the future")
+	}
+}
+
+func validateExternalIDs(v *validator.Validator, ids entity.ExternalIDs) {
+	// Порядок ошибок в ответе не должен зависеть от порядка обхода map
+	sources := make([]string, 0, len(ids))
+	for source := range ids {
+		sources = append(sources, source)
+	}
+	sort.Strings(sources)
+
+	for _, source := range sources {
+		id := ids[source]
+		field := "external_ids." + source
+
+		if !entity.IsExternalSource(source) {
+			v.Add(field, "unknown_external_source", fmt.Sprintf("unknown external source %q", source))
+			continue
+		}
+
+		v.String(field, &id, validator.Required(), validator.Length(1, MaxExternalIDLen))
+	}
+}
@@ -0,0 +1,114 @@
+package usecase
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"filmoteka/internal/entity"
+)
+
+func str(s string) *string { return &s }
+
+func num(n int) *int { return &n }
+
+// fieldCodes возвращает поля и коды ошибок проверки в виде field:code
+func fieldCodes(t *testing.T, err error) []string {
+	t.Helper()
+
+	if err == nil {
+		return nil
+	}
+
+	e := AsError(err)
+	if e.Kind != KindValidation || e.Code != "invalid_input" {
+		t.Fatalf("error = %+v, want invalid_input validation error", e)
+	}
+
+	res := make([]string, 0, len(e.Fields))
+	for _, f := range e.Fields {
+		res = append(res, f.Field+":"+f.Code)
+	}
+
+	return res
+}
+
+func TestValidateActorData(t *testing.T) {
+	future := entity.Date{Year: time.Now().Year() + 1}
+	thisYear := entity.Date{Year: time.Now().Year()}
+	invalidDate := entity.Date{Year: 1979, Month: 13}
+
+	tests := []struct {
+		name    string
+		data    entity.ActorData
+		partial bool
+		want    []string
+	}{
+		{name: "valid", data: entity.ActorData{Name: str("Сергей"), Surname: str("Бодров"), Gender: str("male")}},
+		{name: "missing required", data: entity.ActorData{}, want: []string{"name:required", "surname:required"}},
+		{name: "too long and unknown gender", data: entity.ActorData{
+			Name: str(strings.Repeat("я", MaxNameLen+1)), Surname: str("Бодров"), Gender: str("unknown"),
+		}, want: []string{"name:length", "gender:enum"}},
+		{name: "future birth date", data: entity.ActorData{Name: str("A"), Surname: str("B"), DateOfBirth: &future},
+			want: []string{"date_of_birth:future_date"}},
+		// Неполная дата текущего года уже началась
+		{name: "birth year is this year", data: entity.ActorData{Name: str("A"), Surname: str("B"), DateOfBirth: &thisYear}},
+		{name: "invalid birth date", data: entity.ActorData{Name: str("A"), Surname: str("B"), DateOfBirth: &invalidDate},
+			want: []string{"date_of_birth:date"}},
+		{name: "unknown external source", data: entity.ActorData{Name: str("A"), Surname: str("B"),
+			ExternalIDs: entity.ExternalIDs{"wiki": "1", entity.SourceIMDb: ""}},
+			want: []string{"external_ids.imdb:required", "external_ids.wiki:unknown_external_source"}},
+
+		{name: "partial single field", data: entity.ActorData{Patronymic: str("Сергеевич")}, partial: true},
+		{name: "partial only external ids", data: entity.ActorData{ExternalIDs: entity.ExternalIDs{entity.SourceIMDb: "nm1"}}, partial: true},
+		{name: "partial no fields", data: entity.ActorData{}, partial: true, want: []string{":no_fields"}},
+		{name: "partial empty external ids", data: entity.ActorData{ExternalIDs: entity.ExternalIDs{}}, partial: true,
+			want: []string{":no_fields"}},
+		{name: "partial empty name", data: entity.ActorData{Name: str(" ")}, partial: true, want: []string{"name:empty"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldCodes(t, validateActorData(tt.data, tt.partial))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateMovieData(t *testing.T) {
+	future := entity.Date{Year: time.Now().Year() + 1}
+
+	tests := []struct {
+		name    string
+		data    entity.MovieData
+		partial bool
+		want    []string
+	}{
+		{name: "valid", data: entity.MovieData{Title: str("Брат"), Rating: num(8)}},
+		// Фильм может выйти в будущем
+		{name: "future release", data: entity.MovieData{Title: str("Брат 3"), ReleaseDate: &future}},
+		{name: "missing title", data: entity.MovieData{Rating: num(8)}, want: []string{"title:required"}},
+		{name: "all invalid", data: entity.MovieData{
+			Title:       str(strings.Repeat("a", MaxTitleLen+1)),
+			Description: str(strings.Repeat("a", MaxDescriptionLen+1)),
+			Rating:      num(MaxRating + 1),
+		}, want: []string{"title:length", "description:length", "rating:range"}},
+		{name: "rating bounds", data: entity.MovieData{Title: str("Брат"), Rating: num(MinRating)}},
+
+		{name: "partial rating only", data: entity.MovieData{Rating: num(0)}, partial: true},
+		{name: "partial no fields", data: entity.MovieData{}, partial: true, want: []string{":no_fields"}},
+		{name: "partial empty title", data: entity.MovieData{Title: str("")}, partial: true, want: []string{"title:empty"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := fieldCodes(t, validateMovieData(tt.data, tt.partial))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("errors = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
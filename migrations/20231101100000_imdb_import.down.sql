@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS imdb_import_progress;
+
+ALTER TABLE actors DROP COLUMN IF EXISTS imdb_id;
+
+ALTER TABLE movies DROP COLUMN IF EXISTS imdb_id;
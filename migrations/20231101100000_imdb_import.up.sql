@@ -0,0 +1,10 @@
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS imdb_id VARCHAR(16) UNIQUE;
+
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS imdb_id VARCHAR(16) UNIQUE;
+
+CREATE TABLE IF NOT EXISTS imdb_import_progress (
+    dataset VARCHAR(64) PRIMARY KEY,
+    rows_done BIGINT NOT NULL DEFAULT 0,
+    finished BOOLEAN NOT NULL DEFAULT FALSE,
+    updated_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
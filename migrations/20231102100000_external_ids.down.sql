@@ -0,0 +1,15 @@
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS imdb_id VARCHAR(16) UNIQUE;
+
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS imdb_id VARCHAR(16) UNIQUE;
+
+UPDATE movies SET imdb_id = e.external_id
+FROM movies_external_ids e
+WHERE e.movie_id = movies.id AND e.source = 'imdb';
+
+UPDATE actors SET imdb_id = e.external_id
+FROM actors_external_ids e
+WHERE e.actor_id = actors.id AND e.source = 'imdb';
+
+DROP TABLE IF EXISTS movies_external_ids, actors_external_ids;
+
+DROP TYPE IF EXISTS external_source;
@@ -0,0 +1,27 @@
+CREATE TYPE external_source AS ENUM ('imdb', 'tmdb', 'kinopoisk');
+
+CREATE TABLE IF NOT EXISTS movies_external_ids (
+    movie_id INT NOT NULL REFERENCES movies(id) ON DELETE CASCADE,
+    source external_source NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    PRIMARY KEY (movie_id, source),
+    CONSTRAINT unique_movie_external_id UNIQUE (source, external_id)
+);
+
+CREATE TABLE IF NOT EXISTS actors_external_ids (
+    actor_id INT NOT NULL REFERENCES actors(id) ON DELETE CASCADE,
+    source external_source NOT NULL,
+    external_id VARCHAR(64) NOT NULL,
+    PRIMARY KEY (actor_id, source),
+    CONSTRAINT unique_actor_external_id UNIQUE (source, external_id)
+);
+
+INSERT INTO movies_external_ids (movie_id, source, external_id)
+SELECT id, 'imdb', imdb_id FROM movies WHERE imdb_id IS NOT NULL;
+
+INSERT INTO actors_external_ids (actor_id, source, external_id)
+SELECT id, 'imdb', imdb_id FROM actors WHERE imdb_id IS NOT NULL;
+
+ALTER TABLE movies DROP COLUMN IF EXISTS imdb_id;
+
+ALTER TABLE actors DROP COLUMN IF EXISTS imdb_id;
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS actor_redirects (
+    old_id INT PRIMARY KEY,
+    new_id INT NOT NULL REFERENCES actors(id) ON DELETE CASCADE,
+    merged_at TIMESTAMP NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS actor_redirects_new_id ON actor_redirects (new_id);
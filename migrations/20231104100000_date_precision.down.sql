@@ -0,0 +1,4 @@
+ALTER TABLE actors DROP COLUMN IF EXISTS date_of_birth_precision;
+ALTER TABLE movies DROP COLUMN IF EXISTS release_date_precision;
+
+DROP TYPE IF EXISTS date_precision;
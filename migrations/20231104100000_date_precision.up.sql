@@ -0,0 +1,13 @@
+CREATE TYPE date_precision AS ENUM ('day', 'month', 'year');
+
+ALTER TABLE movies ADD COLUMN IF NOT EXISTS release_date_precision date_precision NOT NULL DEFAULT 'day';
+ALTER TABLE actors ADD COLUMN IF NOT EXISTS date_of_birth_precision date_precision NOT NULL DEFAULT 'day';
+
+-- Импорт IMDb сохранял вместо неизвестных дня и месяца 1 января
+UPDATE movies SET release_date_precision = 'year'
+WHERE EXTRACT(DOY FROM release_date) = 1
+AND id IN (SELECT movie_id FROM movies_external_ids WHERE source = 'imdb');
+
+UPDATE actors SET date_of_birth_precision = 'year'
+WHERE EXTRACT(DOY FROM date_of_birth) = 1
+AND id IN (SELECT actor_id FROM actors_external_ids WHERE source = 'imdb');
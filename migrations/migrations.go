@@ -0,0 +1,8 @@
+// Package migrations содержит миграции схемы БД. Файлы встраиваются в бинарный файл приложения,
+// поэтому для команды migrate каталог migrations рядом с ним не нужен.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
@@ -0,0 +1,124 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ActorsService - запросы к актерам
+type ActorsService struct {
+	c *Client
+}
+
+// actorResponse - ответ маршрутов v1
+type actorResponse struct {
+	Actor *Actor `json:"actor"`
+}
+
+type duplicatesResponse struct {
+	Duplicates []DuplicateActors `json:"duplicates"`
+}
+
+type actorsMoviesResponse struct {
+	Data []MoviesOfActor `json:"movies_of_actor"`
+}
+
+// Get возвращает актера по id. Для объединенного актера возвращается актер, с которым он объединен.
+func (s *ActorsService) Get(ctx context.Context, id int) (Actor, error) {
+	var res Actor
+	err := s.c.do(ctx, http.MethodGet, apiV2+"/actors/"+strconv.Itoa(id), nil, nil, &res)
+
+	return res, err
+}
+
+// GetByExternalID возвращает актера по идентификатору во внешнем каталоге (imdb, tmdb, kinopoisk)
+func (s *ActorsService) GetByExternalID(ctx context.Context, source, externalID string) (Actor, error) {
+	var res actorResponse
+	err := s.c.do(ctx, http.MethodGet, "/actor/find_by_external_id/"+url.PathEscape(source+":"+externalID), nil, nil, &res)
+
+	return deref(res.Actor), err
+}
+
+// List перебирает всех актеров по возрастанию id
+func (s *ActorsService) List(ctx context.Context, opts *ListOptions) *Iterator[Actor] {
+	return newIterator(ctx, func(ctx context.Context, next int) ([]Actor, int, error) {
+		q := opts.query()
+		q.Set("next_person_id", strconv.Itoa(next))
+
+		var res actorList
+		err := s.c.do(ctx, http.MethodGet, apiV2+"/actors", q, nil, &res)
+
+		return res.Actors, res.NextID, err
+	})
+}
+
+// Create добавляет актера
+func (s *ActorsService) Create(ctx context.Context, data ActorData) (Actor, error) {
+	var res Actor
+	err := s.c.do(ctx, http.MethodPost, apiV2+"/actors", nil, data, &res)
+
+	return res, err
+}
+
+// Update изменяет переданные поля актера
+func (s *ActorsService) Update(ctx context.Context, id int, data ActorData) (Actor, error) {
+	var res Actor
+	err := s.c.do(ctx, http.MethodPatch, apiV2+"/actors/"+strconv.Itoa(id), nil, data, &res)
+
+	return res, err
+}
+
+// Upsert добавляет актера или обновляет актера с теми же внешними идентификаторами
+func (s *ActorsService) Upsert(ctx context.Context, data ActorData) (Actor, error) {
+	var res actorResponse
+	err := s.c.do(ctx, http.MethodPut, "/actor/upsert", nil, data, &res)
+
+	return deref(res.Actor), err
+}
+
+// Delete удаляет актера
+func (s *ActorsService) Delete(ctx context.Context, id int) error {
+	return s.c.do(ctx, http.MethodDelete, apiV2+"/actors/"+strconv.Itoa(id), nil, nil, nil)
+}
+
+// Movies возвращает фильмы актера
+func (s *ActorsService) Movies(ctx context.Context, id int) ([]Movie, error) {
+	var res movieList
+	err := s.c.do(ctx, http.MethodGet, apiV2+"/actors/"+strconv.Itoa(id)+"/movies", nil, nil, &res)
+
+	return res.Movies, err
+}
+
+// Filmographies возвращает актеров с названиями фильмов, в которых они снимались
+func (s *ActorsService) Filmographies(ctx context.Context) ([]MoviesOfActor, error) {
+	var res actorsMoviesResponse
+	err := s.c.do(ctx, http.MethodGet, "/actor_movie/list", nil, nil, &res)
+
+	return res.Data, err
+}
+
+// Duplicates возвращает пары актеров со сходством не ниже minScore (от 0 до 1).
+// При minScore = 0 используется порог сервера по умолчанию.
+func (s *ActorsService) Duplicates(ctx context.Context, minScore float64) ([]DuplicateActors, error) {
+	q := url.Values{}
+	if minScore > 0 {
+		q.Set("min_score", strconv.FormatFloat(minScore, 'f', -1, 64))
+	}
+
+	var res duplicatesResponse
+	err := s.c.do(ctx, http.MethodGet, "/actors/duplicates", q, nil, &res)
+
+	return res.Duplicates, err
+}
+
+// Merge объединяет актера sourceID с актером targetID и возвращает объединенного актера
+func (s *ActorsService) Merge(ctx context.Context, sourceID, targetID int) (Actor, error) {
+	body := map[string]int{"source_id": sourceID, "target_id": targetID}
+
+	var res actorResponse
+	err := s.c.do(ctx, http.MethodPost, "/actor/merge", nil, body, &res)
+
+	return deref(res.Actor), err
+}
@@ -0,0 +1,255 @@
+// Package client - клиент HTTP API filmoteka.
+//
+// Клиент работает с ресурсами /api/v2, а возможности, которых в v2 нет
+// (поиск, upsert, поиск по внешнему id, дубликаты и слияние актеров), запрашивает у маршрутов v1.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Client - клиент API. Безопасен для одновременного использования.
+type Client struct {
+	baseURL *url.URL
+	http    *http.Client
+	auth    Authenticator
+	retry   RetryPolicy
+
+	Movies *MoviesService
+	Actors *ActorsService
+}
+
+// Option настраивает клиент
+type Option func(*Client)
+
+// WithHTTPClient задает http.Client, через который отправляются запросы
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.http = hc
+	}
+}
+
+// WithAuth задает способ аутентификации запросов
+func WithAuth(a Authenticator) Option {
+	return func(c *Client) {
+		c.auth = a
+	}
+}
+
+// WithBasicAuth аутентифицирует запросы учетными данными администратора
+func WithBasicAuth(user, pass string) Option {
+	return WithAuth(BasicAuth{User: user, Pass: pass})
+}
+
+// WithRetry задает политику повторов идемпотентных запросов
+func WithRetry(p RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = p
+	}
+}
+
+// New создает клиент для сервера baseURL, например http://localhost:8080
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client - New - url.Parse: %w", err)
+	}
+
+	if u.Scheme == "" || u.Host == "" {
+		return nil, fmt.Errorf("client - New: base url %q should be absolute", baseURL)
+	}
+
+	c := &Client{
+		baseURL: u,
+		http:    &http.Client{Timeout: 30 * time.Second},
+		retry:   DefaultRetryPolicy,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	c.Movies = &MoviesService{c: c}
+	c.Actors = &ActorsService{c: c}
+
+	return c, nil
+}
+
+// Authenticator добавляет к запросу учетные данные
+type Authenticator interface {
+	Authenticate(r *http.Request) error
+}
+
+// BasicAuth - учетные данные администратора (HTTP Basic Auth)
+type BasicAuth struct {
+	User string
+	Pass string
+}
+
+func (a BasicAuth) Authenticate(r *http.Request) error {
+	r.SetBasicAuth(a.User, a.Pass)
+	return nil
+}
+
+// BearerToken - токен доступа в заголовке Authorization: Bearer
+type BearerToken string
+
+func (t BearerToken) Authenticate(r *http.Request) error {
+	r.Header.Set("Authorization", "Bearer "+string(t))
+	return nil
+}
+
+// do отправляет запрос и декодирует JSON ответа в out (если out не nil).
+// Ответ со статусом 4xx/5xx возвращается как *Error.
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out any) error {
+	var payload []byte
+	if body != nil {
+		var err error
+		if payload, err = json.Marshal(body); err != nil {
+			return fmt.Errorf("client - %s %s - json.Marshal: %w", method, path, err)
+		}
+	}
+
+	u := *c.baseURL
+	u.Path += path
+	u.RawQuery = query.Encode()
+
+	attempts := 1
+	if idempotent(method) && c.retry.MaxAttempts > 1 {
+		attempts = c.retry.MaxAttempts
+	}
+
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		var resp *http.Response
+		var retryAfter time.Duration
+
+		resp, err = c.send(ctx, method, u.String(), payload)
+		if err == nil {
+			err = decode(resp, out)
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		}
+
+		if err == nil || !retryable(err) || attempt == attempts-1 {
+			break
+		}
+
+		delay := c.retry.delay(attempt)
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+
+		if err := sleep(ctx, delay); err != nil {
+			return err
+		}
+	}
+
+	return err
+}
+
+func (c *Client) send(ctx context.Context, method, u string, payload []byte) (*http.Response, error) {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
+	if err != nil {
+		return nil, fmt.Errorf("client - %s %s - http.NewRequest: %w", method, u, err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	if c.auth != nil {
+		if err = c.auth.Authenticate(req); err != nil {
+			return nil, fmt.Errorf("client - %s %s - Authenticate: %w", method, u, err)
+		}
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, &transportError{err: err}
+	}
+
+	return resp, nil
+}
+
+func decode(resp *http.Response, out any) error {
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &transportError{err: err}
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return newError(resp.StatusCode, data)
+	}
+
+	if out == nil || len(data) == 0 {
+		return nil
+	}
+
+	if err = json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("client - failed to decode response: %w", err)
+	}
+
+	return nil
+}
+
+// transportError - ошибка сети: запрос мог не дойти до сервера
+type transportError struct {
+	err error
+}
+
+func (e *transportError) Error() string {
+	return e.err.Error()
+}
+
+func (e *transportError) Unwrap() error {
+	return e.err
+}
+
+// retryable сообщает, имеет ли смысл повторить запрос
+func retryable(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var te *transportError
+	if errors.As(err, &te) {
+		return true
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		switch e.Status {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+
+	return false
+}
+
+// idempotent сообщает, можно ли безопасно повторить запрос с этим методом
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions:
+		return true
+	}
+
+	return false
+}
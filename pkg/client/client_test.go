@@ -0,0 +1,524 @@
+package client_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"filmoteka/config"
+	"filmoteka/internal/controller/api"
+	"filmoteka/internal/controller/middleware/pagination"
+	"filmoteka/internal/entity"
+	"filmoteka/internal/usecase"
+	"filmoteka/pkg/client"
+	"filmoteka/pkg/logger"
+	"filmoteka/pkg/validator"
+)
+
+const (
+	adminUser = "admin"
+	adminPass = "secret"
+	pageSize  = 2
+)
+
+// store - usecase в памяти: клиент проверяется с настоящим роутером, но без БД
+type store struct {
+	mu     sync.Mutex
+	nextID int
+	movies map[int]entity.Movie
+	actors map[int]entity.Actor
+	cast   map[int][]int
+}
+
+func newStore() *store {
+	return &store{
+		movies: make(map[int]entity.Movie),
+		actors: make(map[int]entity.Actor),
+		cast:   make(map[int][]int),
+	}
+}
+
+func (s *store) id() int {
+	s.nextID++
+	return s.nextID
+}
+
+func page[T any](ctx context.Context, items map[int]T) []T {
+	from, _ := ctx.Value(pagination.NextPersonID).(int)
+
+	ids := []int{}
+	for id := range items {
+		if id >= from {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	res := []T{}
+	for _, id := range ids {
+		if len(res) == pageSize {
+			break
+		}
+		res = append(res, items[id])
+	}
+
+	return res
+}
+
+type movies struct{ *store }
+
+func (s movies) Save(_ context.Context, data entity.MovieData) (entity.Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if data.Title == nil {
+		err := usecase.Validation("invalid_input", "request data is invalid")
+		err.Fields = []validator.FieldError{{Field: "title", Code: "required", Message: "title is required"}}
+		return entity.Movie{}, err
+	}
+
+	id := s.id()
+	m := entity.Movie{Id: &id, MovieData: data}
+	s.movies[id] = m
+
+	return m, nil
+}
+
+func (s movies) Update(_ context.Context, upd entity.Movie) (entity.Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.movies[*upd.Id]
+	if !ok {
+		return entity.Movie{}, usecase.NotFound("movie_not_found", "movie was NOT found")
+	}
+
+	if upd.Title != nil {
+		m.Title = upd.Title
+	}
+	if upd.Rating != nil {
+		m.Rating = upd.Rating
+	}
+	s.movies[*upd.Id] = m
+
+	return m, nil
+}
+
+func (s movies) Delete(_ context.Context, id int) (entity.Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.movies[id]
+	if !ok {
+		return entity.Movie{}, usecase.NotFound("movie_not_found", "movie was NOT found")
+	}
+	delete(s.movies, id)
+
+	return m, nil
+}
+
+func (s movies) Find(_ context.Context, id int) (entity.Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	m, ok := s.movies[id]
+	if !ok {
+		return entity.Movie{}, usecase.NotFound("movie_not_found", "movie was NOT found")
+	}
+
+	return m, nil
+}
+
+func (s movies) FindByExternalID(_ context.Context, source, externalID string) (entity.Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, m := range s.movies {
+		if m.ExternalIDs[source] == externalID {
+			return m, nil
+		}
+	}
+
+	return entity.Movie{}, usecase.NotFound("movie_not_found", "movie was NOT found")
+}
+
+func (s movies) Upsert(ctx context.Context, data entity.MovieData) (entity.Movie, error) {
+	return s.Save(ctx, data)
+}
+
+func (s movies) FindMovie(context.Context) ([]entity.Movie, error) {
+	return nil, nil
+}
+
+func (s movies) List(ctx context.Context) ([]entity.Movie, error) {
+	return s.Next(ctx)
+}
+
+func (s movies) Next(ctx context.Context) ([]entity.Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return page(ctx, s.movies), nil
+}
+
+type actors struct{ *store }
+
+func (s actors) Save(_ context.Context, data entity.ActorData) (entity.Actor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := s.id()
+	a := entity.Actor{Id: &id, ActorData: data}
+	s.actors[id] = a
+
+	return a, nil
+}
+
+func (s actors) Update(context.Context, entity.Actor) (entity.Actor, error) {
+	return entity.Actor{}, errors.New("not implemented")
+}
+
+func (s actors) Delete(context.Context, int) (entity.Actor, error) {
+	return entity.Actor{}, errors.New("not implemented")
+}
+
+func (s actors) Find(_ context.Context, id int) (entity.Actor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.actors[id]
+	if !ok {
+		return entity.Actor{}, usecase.NotFound("actor_not_found", "actor was NOT found")
+	}
+
+	return a, nil
+}
+
+func (s actors) FindByExternalID(context.Context, string, string) (entity.Actor, error) {
+	return entity.Actor{}, usecase.NotFound("actor_not_found", "actor was NOT found")
+}
+
+func (s actors) Upsert(ctx context.Context, data entity.ActorData) (entity.Actor, error) {
+	return s.Save(ctx, data)
+}
+
+func (s actors) List(ctx context.Context) ([]entity.Actor, error) {
+	return s.Next(ctx)
+}
+
+func (s actors) Next(ctx context.Context) ([]entity.Actor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return page(ctx, s.actors), nil
+}
+
+func (s actors) Duplicates(context.Context, float64) ([]entity.DuplicateActors, error) {
+	return nil, nil
+}
+
+func (s actors) Merge(context.Context, int, int) (entity.Actor, error) {
+	return entity.Actor{}, errors.New("not implemented")
+}
+
+type actorsMovies struct{ *store }
+
+func (s actorsMovies) Save(_ context.Context, data entity.ActorMovie) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.actors[*data.Actor_id]; !ok {
+		return usecase.NotFound("actor_movie_not_found", "actor movie was NOT found")
+	}
+
+	s.cast[*data.Movie_id] = append(s.cast[*data.Movie_id], *data.Actor_id)
+
+	return nil
+}
+
+func (s actorsMovies) Delete(_ context.Context, data entity.ActorMovie) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cast := s.cast[*data.Movie_id]
+	for i, id := range cast {
+		if id == *data.Actor_id {
+			s.cast[*data.Movie_id] = append(cast[:i], cast[i+1:]...)
+			return nil
+		}
+	}
+
+	return usecase.NotFound("actor_movie_not_found", "actor movie was NOT found")
+}
+
+func (s actorsMovies) List(context.Context) ([]entity.ActorMovieData, error) {
+	return nil, nil
+}
+
+func (s actorsMovies) Cast(_ context.Context, movieIDs []int) (map[int][]entity.Actor, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make(map[int][]entity.Actor)
+	for _, id := range movieIDs {
+		for _, actorID := range s.cast[id] {
+			res[id] = append(res[id], s.actors[actorID])
+		}
+	}
+
+	return res, nil
+}
+
+func (s actorsMovies) Filmography(_ context.Context, actorIDs []int) (map[int][]entity.Movie, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := make(map[int][]entity.Movie)
+	for _, id := range actorIDs {
+		for movieID, cast := range s.cast {
+			for _, actorID := range cast {
+				if actorID == id {
+					res[id] = append(res[id], s.movies[movieID])
+				}
+			}
+		}
+	}
+
+	return res, nil
+}
+
+func newRouter() http.Handler {
+	s := newStore()
+
+	cfg := &config.Config{}
+	cfg.HTTPServer.User = adminUser
+	cfg.HTTPServer.Pass = adminPass
+
+	router := chi.NewRouter()
+	api.NewRouter(cfg, router, logger.New("prod"), actors{s}, movies{s}, actorsMovies{s}, nil, nil, nil, nil)
+
+	return router
+}
+
+func newClient(t *testing.T, h http.Handler, opts ...client.Option) *client.Client {
+	t.Helper()
+
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	c, err := client.New(srv.URL, opts...)
+	if err != nil {
+		t.Fatalf("client.New: %v", err)
+	}
+
+	return c
+}
+
+func TestMovieLifecycle(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, newRouter(), client.WithBasicAuth(adminUser, adminPass))
+
+	created, err := c.Movies.Create(ctx, client.MovieData{
+		Title:       client.String("Alien"),
+		Rating:      client.Int(8),
+		ReleaseDate: client.DateOf(client.NewDate(1979, time.May, 25)),
+	})
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	got, err := c.Movies.Get(ctx, *created.Id)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if *got.Title != "Alien" || got.ReleaseDate.String() != "1979-05-25" {
+		t.Fatalf("Get returned %+v", got)
+	}
+
+	updated, err := c.Movies.Update(ctx, *created.Id, client.MovieData{Rating: client.Int(9)})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if *updated.Rating != 9 || *updated.Title != "Alien" {
+		t.Fatalf("Update returned %+v", updated)
+	}
+
+	if err = c.Movies.Delete(ctx, *created.Id); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+
+	_, err = c.Movies.Get(ctx, *created.Id)
+	if !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("Get after Delete: expected ErrNotFound, got %v", err)
+	}
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || apiErr.Code != "movie_not_found" {
+		t.Fatalf("expected movie_not_found, got %#v", err)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	c := newClient(t, newRouter(), client.WithBasicAuth(adminUser, adminPass))
+
+	_, err := c.Movies.Create(context.Background(), client.MovieData{})
+	if !errors.Is(err, client.ErrBadRequest) {
+		t.Fatalf("expected ErrBadRequest, got %v", err)
+	}
+
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) || len(apiErr.Fields) != 1 || apiErr.Fields[0].Field != "title" {
+		t.Fatalf("expected title field error, got %#v", err)
+	}
+}
+
+func TestUnauthorized(t *testing.T) {
+	h := newRouter()
+
+	for _, opt := range []client.Option{
+		client.WithBasicAuth(adminUser, "wrong"),
+		client.WithAuth(client.BearerToken("token")),
+	} {
+		c := newClient(t, h, opt)
+
+		_, err := c.Actors.Create(context.Background(), client.ActorData{Name: client.String("Sigourney")})
+		if !errors.Is(err, client.ErrUnauthorized) {
+			t.Fatalf("expected ErrUnauthorized, got %v", err)
+		}
+	}
+}
+
+func TestListFollowsPagination(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, newRouter(), client.WithBasicAuth(adminUser, adminPass))
+
+	const total = 5
+	for i := 0; i < total; i++ {
+		if _, err := c.Actors.Create(ctx, client.ActorData{Name: client.String("actor")}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+	}
+
+	all, err := c.Actors.List(ctx, nil).All()
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+
+	if len(all) != total {
+		t.Fatalf("expected %d actors over %d-item pages, got %d", total, pageSize, len(all))
+	}
+
+	for i := 1; i < len(all); i++ {
+		if *all[i].Id <= *all[i-1].Id {
+			t.Fatalf("actors are not ordered by id: %d after %d", *all[i].Id, *all[i-1].Id)
+		}
+	}
+}
+
+func TestCast(t *testing.T) {
+	ctx := context.Background()
+	c := newClient(t, newRouter(), client.WithBasicAuth(adminUser, adminPass))
+
+	movie, err := c.Movies.Create(ctx, client.MovieData{Title: client.String("Aliens")})
+	if err != nil {
+		t.Fatalf("Create movie: %v", err)
+	}
+
+	actor, err := c.Actors.Create(ctx, client.ActorData{Name: client.String("Sigourney")})
+	if err != nil {
+		t.Fatalf("Create actor: %v", err)
+	}
+
+	if err = c.Movies.AddCast(ctx, *movie.Id, *actor.Id); err != nil {
+		t.Fatalf("AddCast: %v", err)
+	}
+
+	cast, err := c.Movies.Cast(ctx, *movie.Id)
+	if err != nil || len(cast) != 1 || *cast[0].Id != *actor.Id {
+		t.Fatalf("Cast returned %+v, %v", cast, err)
+	}
+
+	filmography, err := c.Actors.Movies(ctx, *actor.Id)
+	if err != nil || len(filmography) != 1 || *filmography[0].Id != *movie.Id {
+		t.Fatalf("Movies returned %+v, %v", filmography, err)
+	}
+
+	if err = c.Movies.RemoveCast(ctx, *movie.Id, *actor.Id); err != nil {
+		t.Fatalf("RemoveCast: %v", err)
+	}
+
+	if err = c.Movies.RemoveCast(ctx, *movie.Id, *actor.Id); !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("second RemoveCast returned %v, want ErrNotFound", err)
+	}
+}
+
+// flaky отвечает 503 на первые failures запросов
+func flaky(h http.Handler, failures int32, calls *int32) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(calls, 1) <= failures {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+func TestRetryIdempotent(t *testing.T) {
+	var calls int32
+	c := newClient(t, flaky(newRouter(), 2, &calls), client.WithRetry(client.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+	}))
+
+	_, err := c.Movies.Get(context.Background(), 1)
+	if !errors.Is(err, client.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after retries, got %v", err)
+	}
+
+	if calls != 3 {
+		t.Fatalf("expected 3 attempts, got %d", calls)
+	}
+}
+
+func TestNoRetryForPost(t *testing.T) {
+	var calls int32
+	c := newClient(t, flaky(newRouter(), 2, &calls),
+		client.WithBasicAuth(adminUser, adminPass),
+		client.WithRetry(client.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond}),
+	)
+
+	_, err := c.Movies.Create(context.Background(), client.MovieData{Title: client.String("Alien")})
+	if !errors.Is(err, client.ErrUnavailable) {
+		t.Fatalf("expected ErrUnavailable, got %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("POST should not be retried, got %d attempts", calls)
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	var calls int32
+	c := newClient(t, flaky(newRouter(), 100, &calls), client.WithRetry(client.RetryPolicy{
+		MaxAttempts: 5,
+		BaseDelay:   time.Second,
+	}))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err := c.Movies.Get(ctx, 1)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Ошибки по статусу ответа. Проверяются через errors.Is:
+//
+//	if errors.Is(err, client.ErrNotFound) { ... }
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+	ErrRateLimited  = errors.New("rate limited")
+	ErrUnavailable  = errors.New("service unavailable")
+	ErrServer       = errors.New("server error")
+)
+
+// FieldError - ошибка отдельного поля запроса
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error - ответ сервера с описанием ошибки (RFC 7807).
+// Code - стабильный машиночитаемый код ошибки, например movie_not_found.
+type Error struct {
+	Status int          `json:"status"`
+	Code   string       `json:"code"`
+	Title  string       `json:"title"`
+	Detail string       `json:"detail"`
+	Fields []FieldError `json:"errors"`
+}
+
+func newError(status int, body []byte) *Error {
+	e := &Error{}
+	if json.Unmarshal(body, e) != nil || e.Code == "" {
+		e = &Error{Title: http.StatusText(status)}
+	}
+
+	e.Status = status
+
+	return e
+}
+
+func (e *Error) Error() string {
+	msg := e.Detail
+	if msg == "" {
+		msg = e.Title
+	}
+
+	if e.Code == "" {
+		return fmt.Sprintf("filmoteka: %d %s", e.Status, msg)
+	}
+
+	return fmt.Sprintf("filmoteka: %d %s: %s", e.Status, e.Code, msg)
+}
+
+// Is сопоставляет ошибку с ErrNotFound, ErrConflict и т.п. по статусу ответа
+func (e *Error) Is(target error) bool {
+	return statusError(e.Status) == target
+}
+
+func statusError(status int) error {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return ErrUnauthorized
+	case status == http.StatusNotFound:
+		return ErrNotFound
+	case status == http.StatusConflict:
+		return ErrConflict
+	case status == http.StatusTooManyRequests:
+		return ErrRateLimited
+	case status == http.StatusServiceUnavailable:
+		return ErrUnavailable
+	case status >= http.StatusInternalServerError:
+		return ErrServer
+	case status >= http.StatusBadRequest:
+		return ErrBadRequest
+	}
+
+	return nil
+}
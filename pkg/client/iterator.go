@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+)
+
+// Iterator перебирает все записи списка, запрашивая страницы по мере необходимости:
+//
+//	it := c.Movies.List(ctx, nil)
+//	for it.Next() {
+//		movie := it.Value()
+//	}
+//	if err := it.Err(); err != nil { ... }
+type Iterator[T any] struct {
+	ctx   context.Context
+	fetch func(ctx context.Context, next int) ([]T, int, error)
+
+	page []T
+	next int
+	cur  T
+	done bool
+	err  error
+}
+
+func newIterator[T any](ctx context.Context, fetch func(ctx context.Context, next int) ([]T, int, error)) *Iterator[T] {
+	return &Iterator[T]{ctx: ctx, fetch: fetch}
+}
+
+// Next переходит к следующей записи. Возвращает false, когда записи закончились или произошла ошибка.
+func (it *Iterator[T]) Next() bool {
+	for len(it.page) == 0 {
+		if it.done || it.err != nil {
+			return false
+		}
+
+		page, next, err := it.fetch(it.ctx, it.next)
+		if err != nil {
+			it.err = err
+			return false
+		}
+
+		// Пустая страница или страница без продолжения - последняя
+		if len(page) == 0 || next <= it.next {
+			it.done = true
+		}
+
+		it.page, it.next = page, next
+	}
+
+	it.cur, it.page = it.page[0], it.page[1:]
+
+	return true
+}
+
+// Value возвращает текущую запись
+func (it *Iterator[T]) Value() T {
+	return it.cur
+}
+
+// Err возвращает ошибку, на которой остановился перебор
+func (it *Iterator[T]) Err() error {
+	return it.err
+}
+
+// All перебирает все записи и возвращает их списком
+func (it *Iterator[T]) All() ([]T, error) {
+	var res []T
+	for it.Next() {
+		res = append(res, it.Value())
+	}
+
+	return res, it.Err()
+}
@@ -0,0 +1,176 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+const apiV2 = "/api/v2"
+
+// ListOptions - фильтры списка: название поля - значение, например {"title": "Alien"}
+type ListOptions struct {
+	Filter map[string]string
+}
+
+func (o *ListOptions) query() url.Values {
+	q := url.Values{}
+	if o != nil {
+		for k, v := range o.Filter {
+			q.Set(k, v)
+		}
+	}
+
+	return q
+}
+
+// SortOptions - сортировка списка фильмов по полю title, rating или release_date
+type SortOptions struct {
+	By   string
+	Desc bool
+}
+
+// MoviesService - запросы к фильмам
+type MoviesService struct {
+	c *Client
+}
+
+type movieList struct {
+	Movies []Movie `json:"movies"`
+	NextID int     `json:"next_id"`
+}
+
+type actorList struct {
+	Actors []Actor `json:"actors"`
+	NextID int     `json:"next_id"`
+}
+
+// movieResponse - ответ маршрутов v1
+type movieResponse struct {
+	Movie  *Movie  `json:"movie"`
+	Movies []Movie `json:"movies"`
+}
+
+// Get возвращает фильм по id
+func (s *MoviesService) Get(ctx context.Context, id int) (Movie, error) {
+	var res Movie
+	err := s.c.do(ctx, http.MethodGet, apiV2+"/movies/"+strconv.Itoa(id), nil, nil, &res)
+
+	return res, err
+}
+
+// GetByExternalID возвращает фильм по идентификатору во внешнем каталоге (imdb, tmdb, kinopoisk)
+func (s *MoviesService) GetByExternalID(ctx context.Context, source, externalID string) (Movie, error) {
+	var res movieResponse
+	err := s.c.do(ctx, http.MethodGet, "/movie/find_by_external_id/"+url.PathEscape(source+":"+externalID), nil, nil, &res)
+
+	return deref(res.Movie), err
+}
+
+// List перебирает все фильмы по возрастанию id
+func (s *MoviesService) List(ctx context.Context, opts *ListOptions) *Iterator[Movie] {
+	return newIterator(ctx, func(ctx context.Context, next int) ([]Movie, int, error) {
+		q := opts.query()
+		q.Set("next_person_id", strconv.Itoa(next))
+
+		var res movieList
+		err := s.c.do(ctx, http.MethodGet, apiV2+"/movies", q, nil, &res)
+
+		return res.Movies, res.NextID, err
+	})
+}
+
+// Top возвращает первую страницу списка фильмов в заданном порядке (по умолчанию - по убыванию рейтинга).
+// Следующих страниц у такого списка нет: List перебирает фильмы только по возрастанию id.
+func (s *MoviesService) Top(ctx context.Context, opts *ListOptions, sort ...SortOptions) ([]Movie, error) {
+	if len(sort) == 0 {
+		sort = []SortOptions{{By: "rating", Desc: true}}
+	}
+
+	q := opts.query()
+	for _, o := range sort {
+		order := "asc"
+		if o.Desc {
+			order = "desc"
+		}
+
+		q.Add("sort_by", o.By)
+		q.Add("sort_order", order)
+	}
+
+	var res movieList
+	err := s.c.do(ctx, http.MethodGet, apiV2+"/movies", q, nil, &res)
+
+	return res.Movies, err
+}
+
+// Search ищет фильмы по фрагменту названия и (или) фрагменту имени актера
+func (s *MoviesService) Search(ctx context.Context, title, actorName string) ([]Movie, error) {
+	q := url.Values{}
+	q.Set("title", title)
+	q.Set("actor_name", actorName)
+
+	var res movieResponse
+	err := s.c.do(ctx, http.MethodGet, "/movie/find/", q, nil, &res)
+
+	return res.Movies, err
+}
+
+// Create добавляет фильм
+func (s *MoviesService) Create(ctx context.Context, data MovieData) (Movie, error) {
+	var res Movie
+	err := s.c.do(ctx, http.MethodPost, apiV2+"/movies", nil, data, &res)
+
+	return res, err
+}
+
+// Update изменяет переданные поля фильма
+func (s *MoviesService) Update(ctx context.Context, id int, data MovieData) (Movie, error) {
+	var res Movie
+	err := s.c.do(ctx, http.MethodPatch, apiV2+"/movies/"+strconv.Itoa(id), nil, data, &res)
+
+	return res, err
+}
+
+// Upsert добавляет фильм или обновляет фильм с теми же внешними идентификаторами
+func (s *MoviesService) Upsert(ctx context.Context, data MovieData) (Movie, error) {
+	var res movieResponse
+	err := s.c.do(ctx, http.MethodPut, "/movie/upsert", nil, data, &res)
+
+	return deref(res.Movie), err
+}
+
+// Delete удаляет фильм
+func (s *MoviesService) Delete(ctx context.Context, id int) error {
+	return s.c.do(ctx, http.MethodDelete, apiV2+"/movies/"+strconv.Itoa(id), nil, nil, nil)
+}
+
+// Cast возвращает актеров фильма
+func (s *MoviesService) Cast(ctx context.Context, id int) ([]Actor, error) {
+	var res actorList
+	err := s.c.do(ctx, http.MethodGet, apiV2+"/movies/"+strconv.Itoa(id)+"/cast", nil, nil, &res)
+
+	return res.Actors, err
+}
+
+// AddCast добавляет актера в фильм
+func (s *MoviesService) AddCast(ctx context.Context, movieID, actorID int) error {
+	body := map[string]int{"actor_id": actorID}
+
+	return s.c.do(ctx, http.MethodPost, apiV2+"/movies/"+strconv.Itoa(movieID)+"/cast", nil, body, nil)
+}
+
+// RemoveCast убирает актера из фильма
+func (s *MoviesService) RemoveCast(ctx context.Context, movieID, actorID int) error {
+	return s.c.do(ctx, http.MethodDelete, apiV2+"/movies/"+strconv.Itoa(movieID)+"/cast/"+strconv.Itoa(actorID), nil, nil, nil)
+}
+
+func deref[T any](v *T) T {
+	if v == nil {
+		var zero T
+		return zero
+	}
+
+	return *v
+}
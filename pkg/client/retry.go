@@ -0,0 +1,65 @@
+package client
+
+import (
+	"context"
+	"math/rand"
+	"strconv"
+	"time"
+)
+
+// RetryPolicy - повторы идемпотентных запросов (GET, PUT, DELETE) при ошибках сети
+// и ответах 429, 502, 503, 504. Задержка растет экспоненциально от BaseDelay до MaxDelay
+// со случайным разбросом; заголовок Retry-After ответа увеличивает задержку.
+type RetryPolicy struct {
+	// Сколько раз отправить запрос, включая первый. 0 и 1 отключают повторы.
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+// DefaultRetryPolicy - политика повторов по умолчанию
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   100 * time.Millisecond,
+	MaxDelay:    2 * time.Second,
+}
+
+// NoRetry отключает повторы
+var NoRetry = RetryPolicy{MaxAttempts: 1}
+
+// delay возвращает задержку перед повтором номер attempt+1
+func (p RetryPolicy) delay(attempt int) time.Duration {
+	d := p.BaseDelay << attempt
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+
+	if d <= 0 {
+		return 0
+	}
+
+	// Разброс от d/2 до d, чтобы клиенты не повторяли запросы одновременно
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+// parseRetryAfter разбирает Retry-After в секундах. Дата HTTP не поддерживается.
+func parseRetryAfter(v string) time.Duration {
+	sec, err := strconv.Atoi(v)
+	if err != nil || sec < 0 {
+		return 0
+	}
+
+	return time.Duration(sec) * time.Second
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
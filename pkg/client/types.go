@@ -0,0 +1,32 @@
+package client
+
+import (
+	"filmoteka/internal/entity"
+)
+
+// Типы ресурсов API. Это псевдонимы типов сервера, поэтому их не нужно объявлять заново:
+// псевдонимы доступны и за пределами модуля filmoteka, хотя пакет entity внутренний.
+type (
+	Movie           = entity.Movie
+	MovieData       = entity.MovieData
+	Actor           = entity.Actor
+	ActorData       = entity.ActorData
+	MoviesOfActor   = entity.MoviesOfActor
+	DuplicateActors = entity.DuplicateActors
+	ExternalIDs     = entity.ExternalIDs
+	Date            = entity.Date
+)
+
+// NewDate создает дату с точностью до дня, ParseDate разбирает дату
+// в формате ISO 8601 (2006-01-02, 2006-01, 2006) или DD.MM.YYYY
+var (
+	NewDate   = entity.NewDate
+	ParseDate = entity.ParseDate
+)
+
+// String, Int и DateOf возвращают указатели для необязательных полей MovieData и ActorData
+func String(v string) *string { return &v }
+
+func Int(v int) *int { return &v }
+
+func DateOf(v Date) *Date { return &v }
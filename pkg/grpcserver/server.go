@@ -0,0 +1,67 @@
+package grpcserver
+
+import (
+	"net"
+	"time"
+
+	"google.golang.org/grpc"
+
+	"filmoteka/config"
+)
+
+type Server struct {
+	server          *grpc.Server
+	address         string
+	notify          chan error
+	shutdownTimeout time.Duration
+}
+
+func New(server *grpc.Server, cfg *config.Config) *Server {
+	s := &Server{
+		server:          server,
+		address:         cfg.GRPC.Address,
+		notify:          make(chan error, 1),
+		shutdownTimeout: cfg.HTTPServer.IdleTimeout,
+	}
+
+	s.start()
+
+	return s
+}
+
+func (s *Server) start() {
+	go func() {
+		lis, err := net.Listen("tcp", s.address)
+		if err != nil {
+			s.notify <- err
+			close(s.notify)
+			return
+		}
+
+		s.notify <- s.server.Serve(lis)
+		close(s.notify)
+	}()
+}
+
+func (s *Server) Notify() <-chan error {
+	return s.notify
+}
+
+// Shutdown дожидается завершения начатых вызовов, но не дольше shutdownTimeout.
+// Потоки, которые не успели завершиться, закрываются принудительно.
+func (s *Server) Shutdown() error {
+	stopped := make(chan struct{})
+
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+	case <-time.After(s.shutdownTimeout):
+		s.server.Stop()
+	}
+
+	return nil
+}
@@ -14,7 +14,17 @@ type Server struct {
 	shutdownTimeout time.Duration
 }
 
-func New(router http.Handler, cfg *config.Config) *Server {
+// Option изменяет настройки сервера, взятые из конфигурации
+type Option func(*http.Server)
+
+// Address задает адрес сервера вместо HTTP_ADDRESS
+func Address(addr string) Option {
+	return func(s *http.Server) {
+		s.Addr = addr
+	}
+}
+
+func New(router http.Handler, cfg *config.Config, opts ...Option) *Server {
 	srv := &http.Server{
 		Addr:         cfg.Address,
 		Handler:      router,
@@ -23,6 +33,10 @@ func New(router http.Handler, cfg *config.Config) *Server {
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
 	}
 
+	for _, opt := range opts {
+		opt(srv)
+	}
+
 	s := &Server{
 		server:          srv,
 		notify:          make(chan error, 1),
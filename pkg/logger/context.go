@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"context"
+
+	"golang.org/x/exp/slog"
+)
+
+// Fields возвращает поля, которые добавляются ко всем записям с контекстом.
+// Вызывается при каждой записи, поэтому поля могут меняться (например, время с начала запроса).
+type Fields func() []slog.Attr
+
+type fieldsKey struct{}
+
+// WithFields добавляет к контексту поля записей журнала. Поля родительского контекста сохраняются.
+func WithFields(ctx context.Context, f Fields) context.Context {
+	prev := fields(ctx)
+
+	return context.WithValue(ctx, fieldsKey{}, append(prev[:len(prev):len(prev)], f))
+}
+
+func fields(ctx context.Context) []Fields {
+	f, _ := ctx.Value(fieldsKey{}).([]Fields)
+	return f
+}
+
+// contextHandler добавляет к записи поля из контекста, с которым она сделана
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h contextHandler) Handle(ctx context.Context, r slog.Record) error {
+	if ctx != nil {
+		for _, f := range fields(ctx) {
+			r.AddAttrs(f()...)
+		}
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return contextHandler{h.Handler.WithAttrs(attrs)}
+}
+
+func (h contextHandler) WithGroup(name string) slog.Handler {
+	return contextHandler{h.Handler.WithGroup(name)}
+}
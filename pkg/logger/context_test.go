@@ -0,0 +1,83 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"golang.org/x/exp/slog"
+)
+
+func newTestLogger(buf *bytes.Buffer, opts ...Option) *Logger {
+	return New(envDev, append([]Option{Output(buf)}, opts...)...)
+}
+
+func decode(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+
+	var res []map[string]any
+
+	dec := json.NewDecoder(buf)
+	for dec.More() {
+		rec := map[string]any{}
+		if err := dec.Decode(&rec); err != nil {
+			t.Fatalf("failed to decode record: %s", err)
+		}
+		res = append(res, rec)
+	}
+
+	return res
+}
+
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf)
+
+	n := 0
+	ctx := WithFields(context.Background(), func() []slog.Attr {
+		n++
+		return []slog.Attr{slog.String("request_id", "req-1"), slog.Int("call", n)}
+	})
+	child := WithFields(ctx, func() []slog.Attr {
+		return []slog.Attr{slog.String("principal", "user")}
+	})
+
+	l.InfoContext(ctx, "first")
+	l.DebugContext(child, "second")
+	l.Info("without context")
+
+	recs := decode(t, &buf)
+	if len(recs) != 3 {
+		t.Fatalf("got %d records, want 3", len(recs))
+	}
+
+	if recs[0]["request_id"] != "req-1" || recs[0]["call"] != 1.0 || recs[0]["principal"] != nil {
+		t.Errorf("first record = %v, want request_id req-1, call 1 and no principal", recs[0])
+	}
+
+	// Поля вычисляются при каждой записи, поля родителя сохраняются
+	if recs[1]["call"] != 2.0 || recs[1]["principal"] != "user" {
+		t.Errorf("second record = %v, want call 2 and principal user", recs[1])
+	}
+
+	if _, ok := recs[2]["request_id"]; ok {
+		t.Errorf("record without context has request fields: %v", recs[2])
+	}
+}
+
+func TestWithFieldsAfterWith(t *testing.T) {
+	var buf bytes.Buffer
+	l := &Logger{logger: newTestLogger(&buf).logger.With("component", "http")}
+
+	ctx := WithFields(context.Background(), func() []slog.Attr {
+		return []slog.Attr{slog.String("request_id", "req-1")}
+	})
+
+	l.WarnContext(ctx, "warning")
+
+	recs := decode(t, &buf)
+	if len(recs) != 1 || recs[0]["component"] != "http" || recs[0]["request_id"] != "req-1" {
+		t.Fatalf("records = %v, want component and request_id", recs)
+	}
+}
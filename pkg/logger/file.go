@@ -0,0 +1,131 @@
+package logger
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+)
+
+// File - файл журнала с ротацией по размеру. Когда запись не помещается в maxSize байт,
+// файл переименовывается в path.1 (прежний path.1 - в path.2 и т.д.), а запись продолжается
+// в новый файл. Хранится не больше maxBackups старых файлов. Если ротация не удалась,
+// запись продолжается в прежний файл.
+type File struct {
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	mu sync.Mutex
+	// nil, если файл не удалось открыть заново после ротации
+	f    *os.File
+	size int64
+}
+
+// OpenFile открывает файл журнала path для дописывания, создает его, если его нет.
+// maxSize = 0 выключает ротацию. При ротации хранится хотя бы один старый файл:
+// иначе ротация стирала бы весь журнал, поэтому maxBackups < 1 отклоняется.
+func OpenFile(path string, maxSize int64, maxBackups int) (*File, error) {
+	if maxSize > 0 && maxBackups < 1 {
+		return nil, fmt.Errorf("logger: max backups of %s should be at least 1, got %d", path, maxBackups)
+	}
+
+	f := &File{path: path, maxSize: maxSize, maxBackups: maxBackups}
+
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *File) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return err
+	}
+
+	f.f, f.size = file, info.Size()
+
+	return nil
+}
+
+// Write дописывает p в файл. Одна запись журнала не разбивается между файлами.
+// Если ротация не удалась, запись дописывается в прежний файл, а Write возвращает ошибку ротации.
+func (f *File) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var rotateErr error
+
+	if f.f == nil {
+		// Прошлая ротация не смогла открыть файл: пробуем снова
+		if err := f.open(); err != nil {
+			return 0, fmt.Errorf("logger: failed to open %s: %w", f.path, err)
+		}
+	} else if f.maxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			rotateErr = fmt.Errorf("logger: failed to rotate %s: %w", f.path, err)
+		}
+
+		if f.f == nil {
+			return 0, rotateErr
+		}
+	}
+
+	n, err := f.f.Write(p)
+	f.size += int64(n)
+
+	return n, errors.Join(err, rotateErr)
+}
+
+// rotate сдвигает старые файлы и начинает новый. Если закрыть или сдвинуть файл не удалось,
+// файл открывается заново и запись продолжается в него. Если не удалось и это, f.f равен nil.
+func (f *File) rotate() error {
+	err := f.f.Close()
+	f.f = nil
+
+	if err == nil {
+		err = f.shift()
+	}
+
+	// После сдвига path не существует, и open создает новый файл
+	return errors.Join(err, f.open())
+}
+
+// shift переименовывает path в path.1, path.1 - в path.2 и т.д., удаляя самый старый файл
+func (f *File) shift() error {
+	backup := func(i int) string {
+		return fmt.Sprintf("%s.%d", f.path, i)
+	}
+
+	if err := os.Remove(backup(f.maxBackups)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	for i := f.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(backup(i), backup(i+1)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	return os.Rename(f.path, backup(1))
+}
+
+// Close закрывает файл
+func (f *File) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.f == nil {
+		return nil
+	}
+
+	return f.f.Close()
+}
@@ -0,0 +1,176 @@
+package logger
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := OpenFile(path, 10, 2)
+	if err != nil {
+		t.Fatalf("failed to open file: %s", err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		if _, err = f.Write([]byte(line)); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+	}
+
+	// Каждая запись не помещается в 10 байт вместе с предыдущей, самая старая удалена
+	want := map[string]string{path: "fourth\n", path + ".1": "third\n", path + ".2": "second\n"}
+	for name, content := range want {
+		b, err := os.ReadFile(name)
+		if err != nil {
+			t.Fatalf("failed to read %s: %s", name, err)
+		}
+
+		if string(b) != content {
+			t.Errorf("%s = %q, want %q", name, b, content)
+		}
+	}
+
+	if _, err = os.Stat(path + ".3"); !os.IsNotExist(err) {
+		t.Errorf("file beyond max backups exists")
+	}
+}
+
+func TestFileAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	if err := os.WriteFile(path, []byte("before\n"), 0o644); err != nil {
+		t.Fatalf("failed to write file: %s", err)
+	}
+
+	f, err := OpenFile(path, 1<<20, 1)
+	if err != nil {
+		t.Fatalf("failed to open file: %s", err)
+	}
+
+	f.Write([]byte("after\n"))
+	f.Close()
+
+	if b, _ := os.ReadFile(path); !strings.HasPrefix(string(b), "before\n") {
+		t.Errorf("file = %q, want existing records kept", b)
+	}
+}
+
+func TestFileRequiresBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	// Ротация без старых файлов стирала бы журнал
+	if _, err := OpenFile(path, 10, 0); err == nil {
+		t.Fatal("OpenFile with rotation and no backups should fail")
+	}
+
+	// Без ротации старые файлы не нужны
+	f, err := OpenFile(path, 0, 0)
+	if err != nil {
+		t.Fatalf("failed to open file: %s", err)
+	}
+	defer f.Close()
+
+	for _, line := range []string{"first\n", "second\n"} {
+		if _, err = f.Write([]byte(line)); err != nil {
+			t.Fatalf("failed to write: %s", err)
+		}
+	}
+
+	if b, _ := os.ReadFile(path); string(b) != "first\nsecond\n" {
+		t.Errorf("file = %q, want all records", b)
+	}
+}
+
+func TestFileRotationFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := OpenFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("failed to open file: %s", err)
+	}
+	defer f.Close()
+
+	if _, err = f.Write([]byte("first\n")); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	// Непустой каталог на месте path.1 не дает сдвинуть файл
+	if err = os.MkdirAll(filepath.Join(path+".1", "dir"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := f.Write([]byte("second\n"))
+	if err == nil || n != len("second\n") {
+		t.Fatalf("Write = %d, %v, want the record written and a rotation error", n, err)
+	}
+
+	if b, _ := os.ReadFile(path); string(b) != "first\nsecond\n" {
+		t.Errorf("file = %q, want records kept in the current file", b)
+	}
+
+	// Когда препятствие исчезает, ротация проходит
+	if err = os.RemoveAll(path + ".1"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = f.Write([]byte("third\n")); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	want := map[string]string{path: "third\n", path + ".1": "first\nsecond\n"}
+	for name, content := range want {
+		if b, _ := os.ReadFile(name); string(b) != content {
+			t.Errorf("%s = %q, want %q", name, b, content)
+		}
+	}
+}
+
+func TestFileReopensAfterCloseFailure(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "app.log")
+
+	f, err := OpenFile(path, 10, 1)
+	if err != nil {
+		t.Fatalf("failed to open file: %s", err)
+	}
+	defer f.Close()
+
+	if _, err = f.Write([]byte("first\n")); err != nil {
+		t.Fatalf("failed to write: %s", err)
+	}
+
+	// Закрытие при ротации вернет ошибку: файл уже закрыт
+	f.f.Close()
+
+	if _, err = f.Write([]byte("second\n")); err == nil {
+		t.Error("Write should report the rotation error")
+	}
+
+	// Файл открыт заново, и следующая ротация проходит
+	if _, err = f.Write([]byte("third\n")); err != nil {
+		t.Fatalf("failed to write after reopen: %s", err)
+	}
+
+	want := map[string]string{path: "third\n", path + ".1": "first\nsecond\n"}
+	for name, content := range want {
+		if b, _ := os.ReadFile(name); string(b) != content {
+			t.Errorf("%s = %q, want %q", name, b, content)
+		}
+	}
+
+	// Если файл не удалось открыть, следующая запись открывает его снова
+	f.f.Close()
+	f.f = nil
+
+	if _, err = f.Write([]byte("fourth\n")); err != nil {
+		t.Fatalf("failed to write after failed open: %s", err)
+	}
+
+	if b, _ := os.ReadFile(path); string(b) != "third\nfourth\n" {
+		t.Errorf("file = %q, want records appended after reopen", b)
+	}
+}
@@ -0,0 +1,127 @@
+package logger
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+
+	"golang.org/x/exp/slog"
+)
+
+// AppComponent - компонент журнала, созданного New: запуск и остановка приложения, фоновые задачи
+const AppComponent = "app"
+
+// ErrUnknownComponent - у компонента нет журнала, поэтому уровень ему задать нельзя
+var ErrUnknownComponent = errors.New("unknown log component")
+
+// Levels - уровни журналов компонентов. Их можно менять во время работы приложения.
+type Levels struct {
+	mu   sync.Mutex
+	def  slog.Level
+	vars map[string]*slog.LevelVar
+}
+
+// newLevels создает уровни компонентов: def - уровень по умолчанию, components - уровни,
+// заданные для отдельных компонентов
+func newLevels(def slog.Level, components map[string]slog.Level) *Levels {
+	lv := &Levels{def: def, vars: map[string]*slog.LevelVar{}}
+
+	for name, level := range components {
+		lv.level(name).Set(level)
+	}
+
+	return lv
+}
+
+// level возвращает уровень компонента name и создает его, если компонента еще нет
+func (lv *Levels) level(name string) *slog.LevelVar {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	v, ok := lv.vars[name]
+	if !ok {
+		v = &slog.LevelVar{}
+		v.Set(lv.def)
+		lv.vars[name] = v
+	}
+
+	return v
+}
+
+// ComponentLevel - уровень журнала компонента
+type ComponentLevel struct {
+	Component string `json:"component"`
+	Level     string `json:"level"`
+}
+
+// Get возвращает уровни компонентов, отсортированные по названию
+func (lv *Levels) Get() []ComponentLevel {
+	if lv == nil {
+		return []ComponentLevel{}
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	res := make([]ComponentLevel, 0, len(lv.vars))
+	for name, v := range lv.vars {
+		res = append(res, ComponentLevel{Component: name, Level: LevelName(v.Level())})
+	}
+
+	sort.Slice(res, func(i, j int) bool { return res[i].Component < res[j].Component })
+
+	return res
+}
+
+// Set меняет уровень компонента name. Записи ниже уровня отбрасываются сразу после вызова.
+func (lv *Levels) Set(name string, level slog.Level) error {
+	if lv == nil {
+		return ErrUnknownComponent
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	v, ok := lv.vars[name]
+	if !ok {
+		return ErrUnknownComponent
+	}
+
+	v.Set(level)
+
+	return nil
+}
+
+// ParseLevel разбирает уровень без учета регистра: debug, info, warn, error
+// или смещение от них, например debug-4
+func ParseLevel(s string) (slog.Level, error) {
+	var level slog.Level
+	err := level.UnmarshalText([]byte(s))
+
+	return level, err
+}
+
+// LevelName возвращает название уровня в нижнем регистре, как его принимает ParseLevel
+func LevelName(level slog.Level) string {
+	return strings.ToLower(level.String())
+}
+
+// levelHandler отбрасывает записи ниже уровня компонента
+type levelHandler struct {
+	slog.Handler
+	level slog.Leveler
+}
+
+func (h levelHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level() && h.Handler.Enabled(ctx, level)
+}
+
+func (h levelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return levelHandler{h.Handler.WithAttrs(attrs), h.level}
+}
+
+func (h levelHandler) WithGroup(name string) slog.Handler {
+	return levelHandler{h.Handler.WithGroup(name), h.level}
+}
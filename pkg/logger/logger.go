@@ -1,6 +1,9 @@
 package logger
 
 import (
+	"context"
+	"io"
+	"math"
 	"os"
 
 	"golang.org/x/exp/slog"
@@ -11,18 +14,56 @@ type Interface interface {
 	Info(msg string, args ...any)
 	Warn(msg string, args ...any)
 	Error(msg string, args ...any)
+	// Методы с контекстом добавляют к записи поля запроса из ctx (см. WithFields)
+	DebugContext(ctx context.Context, msg string, args ...any)
+	InfoContext(ctx context.Context, msg string, args ...any)
+	WarnContext(ctx context.Context, msg string, args ...any)
+	ErrorContext(ctx context.Context, msg string, args ...any)
 	Err(err error) slog.Attr
 }
 
 type Logger struct {
 	logger *slog.Logger
+
+	// handler - обработчик без уровня и выборки, из него создаются журналы компонентов
+	handler slog.Handler
+	levels  *Levels
+	sampler *sampler
 }
 
-func New(env string) *Logger {
+// New создает журнал компонента AppComponent. env выбирает формат записей и уровень
+// по умолчанию, opts меняют их.
+func New(env string, opts ...Option) *Logger {
+	o := options{out: os.Stdout, level: envLevel(env)}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	l := &Logger{
+		handler: setupHandler(env, o.out),
+		levels:  newLevels(o.level, o.components),
+		sampler: o.sampler,
+	}
 
-	log := setupLogger(env)
+	return l.with(AppComponent, l.handler)
+}
 
-	return &Logger{logger: log}
+// Component возвращает журнал компонента name с тем же выводом. Уровень компонента
+// меняется независимо от других (см. Levels), к записям добавляется поле component.
+func (l *Logger) Component(name string) *Logger {
+	return l.with(name, l.handler.WithAttrs([]slog.Attr{slog.String("component", name)}))
+}
+
+func (l *Logger) with(name string, h slog.Handler) *Logger {
+	res := *l
+	res.logger = slog.New(contextHandler{sampleHandler{levelHandler{h, l.levels.level(name)}, l.sampler}})
+
+	return &res
+}
+
+// Levels возвращает уровни компонентов, общие для всех журналов, созданных от одного New
+func (l *Logger) Levels() *Levels {
+	return l.levels
 }
 
 func (l *Logger) Err(err error) slog.Attr {
@@ -48,30 +89,49 @@ func (l *Logger) Error(msg string, args ...any) {
 	l.logger.Error(msg, args...)
 }
 
+func (l *Logger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.logger.DebugContext(ctx, msg, args...)
+}
+
+func (l *Logger) InfoContext(ctx context.Context, msg string, args ...any) {
+	l.logger.InfoContext(ctx, msg, args...)
+}
+
+func (l *Logger) WarnContext(ctx context.Context, msg string, args ...any) {
+	l.logger.WarnContext(ctx, msg, args...)
+}
+
+func (l *Logger) ErrorContext(ctx context.Context, msg string, args ...any) {
+	l.logger.ErrorContext(ctx, msg, args...)
+}
+
 const (
 	envLocal = "local"
 	envDev   = "dev"
 	envProd  = "prod"
 )
 
-func setupLogger(env string) *slog.Logger {
-	var log *slog.Logger
+// setupHandler выбирает формат записей по окружению: текст для local, JSON для остальных.
+// Уровень проверяет levelHandler, поэтому обработчик пропускает все записи.
+func setupHandler(env string, w io.Writer) slog.Handler {
+	opts := &slog.HandlerOptions{Level: slog.Level(math.MinInt)}
 
 	// Переключатель логов для разных серверов
+	if env == envLocal {
+		return slog.NewTextHandler(w, opts)
+	}
+
+	return slog.NewJSONHandler(w, opts)
+}
+
+// envLevel - уровень по умолчанию: debug для local и dev, info для prod и неизвестных окружений
+func envLevel(env string) slog.Level {
 	switch env {
-	case envLocal:
-		log = slog.New(
-			slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
-	case envDev:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}),
-		)
+	case envLocal, envDev:
+		return slog.LevelDebug
 	case envProd:
-		log = slog.New(
-			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
+		return slog.LevelInfo
 	}
 
-	return log
+	return slog.LevelInfo
 }
@@ -0,0 +1,103 @@
+package logger
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+func TestUnknownEnv(t *testing.T) {
+	var buf bytes.Buffer
+	l := New("staging", Output(&buf))
+
+	l.Debug("hidden")
+	l.Info("shown")
+
+	recs := decode(t, &buf)
+	if len(recs) != 1 || recs[0]["msg"] != "shown" {
+		t.Fatalf("records = %v, want only the info record in JSON", recs)
+	}
+}
+
+func TestComponentLevels(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, DefaultLevel(slog.LevelInfo), ComponentLevels(map[string]slog.Level{"repo": slog.LevelWarn}))
+
+	http, repo := l.Component("http"), l.Component("repo")
+
+	http.Debug("http debug")
+	http.Info("http info")
+	repo.Info("repo info")
+	repo.Warn("repo warn")
+
+	if err := l.Levels().Set("http", slog.LevelDebug); err != nil {
+		t.Fatalf("failed to set level: %s", err)
+	}
+
+	http.Debug("http debug after change")
+	l.Debug("app debug")
+
+	var got []string
+	for _, rec := range decode(t, &buf) {
+		got = append(got, rec["msg"].(string))
+	}
+
+	want := []string{"http info", "repo warn", "http debug after change"}
+	if len(got) != len(want) {
+		t.Fatalf("records = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("records = %v, want %v", got, want)
+		}
+	}
+
+	levels := l.Levels().Get()
+	wantLevels := []ComponentLevel{{"app", "info"}, {"http", "debug"}, {"repo", "warn"}}
+	if len(levels) != len(wantLevels) {
+		t.Fatalf("levels = %v, want %v", levels, wantLevels)
+	}
+	for i := range wantLevels {
+		if levels[i] != wantLevels[i] {
+			t.Fatalf("levels = %v, want %v", levels, wantLevels)
+		}
+	}
+
+	if err := l.Levels().Set("db", slog.LevelDebug); !errors.Is(err, ErrUnknownComponent) {
+		t.Errorf("Set of unknown component returned %v, want ErrUnknownComponent", err)
+	}
+}
+
+func TestSampling(t *testing.T) {
+	var buf bytes.Buffer
+	l := newTestLogger(&buf, Sampling(2, 3, time.Hour))
+
+	for i := 0; i < 10; i++ {
+		l.Info("repeated", "i", i)
+		l.Error("failed", "i", i)
+	}
+	l.Info("other")
+
+	counts := map[string][]float64{}
+	for _, rec := range decode(t, &buf) {
+		msg := rec["msg"].(string)
+		i, _ := rec["i"].(float64)
+		counts[msg] = append(counts[msg], i)
+	}
+
+	// Первые 2 записи, затем каждая 3-я: 5-я и 8-я
+	if got := counts["repeated"]; len(got) != 4 || got[2] != 4 || got[3] != 7 {
+		t.Errorf("repeated records = %v, want [0 1 4 7]", got)
+	}
+
+	if len(counts["failed"]) != 10 {
+		t.Errorf("got %d error records, want all 10", len(counts["failed"]))
+	}
+
+	if len(counts["other"]) != 1 {
+		t.Errorf("record with another message is sampled out")
+	}
+}
@@ -0,0 +1,48 @@
+package logger
+
+import (
+	"io"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// Option меняет параметры журнала, выбранные по окружению
+type Option func(*options)
+
+type options struct {
+	out        io.Writer
+	level      slog.Level
+	components map[string]slog.Level
+	sampler    *sampler
+}
+
+// Output задает, куда пишутся записи. По умолчанию - stdout.
+func Output(w io.Writer) Option {
+	return func(o *options) {
+		o.out = w
+	}
+}
+
+// DefaultLevel задает уровень компонентов, для которых он не задан отдельно
+func DefaultLevel(level slog.Level) Option {
+	return func(o *options) {
+		o.level = level
+	}
+}
+
+// ComponentLevels задает уровни отдельных компонентов
+func ComponentLevels(levels map[string]slog.Level) Option {
+	return func(o *options) {
+		o.components = levels
+	}
+}
+
+// Sampling ограничивает повторяющиеся записи под нагрузкой: за каждый интервал tick записываются
+// первые first записей с одинаковыми уровнем и сообщением, а из остальных - каждая thereafter-я.
+// Предупреждения и ошибки не отбрасываются. first = 0 выключает выборку.
+func Sampling(first, thereafter int, tick time.Duration) Option {
+	return func(o *options) {
+		o.sampler = newSampler(first, thereafter, tick)
+	}
+}
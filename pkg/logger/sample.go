@@ -0,0 +1,100 @@
+package logger
+
+import (
+	"context"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/exp/slog"
+)
+
+// sampleCounters - число счетчиков выборки. Записи с разными сообщениями могут попасть
+// в один счетчик, тогда они ограничиваются вместе.
+const sampleCounters = 4096
+
+// sampler считает одинаковые записи за интервал tick
+type sampler struct {
+	first      uint64
+	thereafter uint64
+	tick       time.Duration
+	counters   [sampleCounters]counter
+}
+
+type counter struct {
+	resetAt atomic.Int64
+	n       atomic.Uint64
+}
+
+// newSampler возвращает nil, если выборка выключена
+func newSampler(first, thereafter int, tick time.Duration) *sampler {
+	if first <= 0 || tick <= 0 {
+		return nil
+	}
+
+	if thereafter < 0 {
+		thereafter = 0
+	}
+
+	return &sampler{first: uint64(first), thereafter: uint64(thereafter), tick: tick}
+}
+
+// allow сообщает, нужно ли записать запись r
+func (s *sampler) allow(r slog.Record) bool {
+	if s == nil || r.Level >= slog.LevelWarn {
+		return true
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte{byte(r.Level)})
+	h.Write([]byte(r.Message))
+
+	n := s.counters[h.Sum32()%sampleCounters].inc(r.Time, s.tick)
+
+	if n <= s.first {
+		return true
+	}
+
+	return s.thereafter > 0 && (n-s.first)%s.thereafter == 0
+}
+
+// inc увеличивает счетчик и сбрасывает его, если интервал закончился
+func (c *counter) inc(t time.Time, tick time.Duration) uint64 {
+	now := t.UnixNano()
+
+	resetAt := c.resetAt.Load()
+	if resetAt > now {
+		return c.n.Add(1)
+	}
+
+	c.n.Store(1)
+
+	// Интервал мог уже сбросить другой поток, тогда запись считается в нем
+	if !c.resetAt.CompareAndSwap(resetAt, now+tick.Nanoseconds()) {
+		return c.n.Add(1)
+	}
+
+	return 1
+}
+
+// sampleHandler отбрасывает записи, не попавшие в выборку
+type sampleHandler struct {
+	slog.Handler
+	sampler *sampler
+}
+
+func (h sampleHandler) Handle(ctx context.Context, r slog.Record) error {
+	if !h.sampler.allow(r) {
+		return nil
+	}
+
+	return h.Handler.Handle(ctx, r)
+}
+
+func (h sampleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return sampleHandler{h.Handler.WithAttrs(attrs), h.sampler}
+}
+
+func (h sampleHandler) WithGroup(name string) slog.Handler {
+	return sampleHandler{h.Handler.WithGroup(name), h.sampler}
+}
@@ -0,0 +1,117 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Reflector строит схемы по типам Go с учетом тегов json.
+// Именованные структуры попадают в components.schemas и подставляются ссылкой.
+type Reflector struct {
+	schemas map[string]*Schema
+	custom  map[reflect.Type]*Schema
+}
+
+// NewReflector создает Reflector, который сохраняет схемы структур в schemas
+func NewReflector(schemas map[string]*Schema) *Reflector {
+	return &Reflector{
+		schemas: schemas,
+		custom:  make(map[reflect.Type]*Schema),
+	}
+}
+
+// Define задает схему для типа v, например для типов со своим MarshalJSON
+func (r *Reflector) Define(v any, s *Schema) {
+	r.custom[reflect.TypeOf(v)] = s
+}
+
+// Schema возвращает схему для типа значения v
+func (r *Reflector) Schema(v any) *Schema {
+	return r.schema(reflect.TypeOf(v))
+}
+
+func (r *Reflector) schema(t reflect.Type) *Schema {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	if s, ok := r.custom[t]; ok {
+		copied := *s
+		return &copied
+	}
+
+	switch t.Kind() {
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Schema{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Schema{Type: "number"}
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: r.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: r.schema(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			return r.object(t)
+		}
+
+		// Имена неэкспортируемых типов тоже начинаются с заглавной буквы
+		name := strings.ToUpper(t.Name()[:1]) + t.Name()[1:]
+
+		if _, ok := r.schemas[name]; !ok {
+			// Заглушка защищает от бесконечной рекурсии для ссылающихся на себя типов
+			r.schemas[name] = &Schema{}
+			*r.schemas[name] = *r.object(t)
+		}
+
+		return Ref(name)
+	}
+
+	return &Schema{}
+}
+
+// object строит схему структуры. Поля встроенных структур поднимаются на верхний уровень, как в encoding/json.
+func (r *Reflector) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || (!f.IsExported() && !f.Anonymous) {
+			continue
+		}
+
+		ft := f.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+
+		if f.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+			embedded := r.object(ft)
+			for k, v := range embedded.Properties {
+				s.Properties[k] = v
+			}
+			s.Required = append(s.Required, embedded.Required...)
+			continue
+		}
+
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = r.schema(f.Type)
+
+		// Обязательными считаются поля без omitempty, которые не могут быть nil
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Pointer &&
+			f.Type.Kind() != reflect.Slice && f.Type.Kind() != reflect.Map {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	return s
+}
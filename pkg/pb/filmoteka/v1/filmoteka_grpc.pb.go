@@ -0,0 +1,1148 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v4.25.1
+// source: filmoteka/v1/filmoteka.proto
+
+package filmotekav1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	ActorService_GetActor_FullMethodName             = "/filmoteka.v1.ActorService/GetActor"
+	ActorService_GetActorByExternalID_FullMethodName = "/filmoteka.v1.ActorService/GetActorByExternalID"
+	ActorService_ListActors_FullMethodName           = "/filmoteka.v1.ActorService/ListActors"
+	ActorService_CreateActor_FullMethodName          = "/filmoteka.v1.ActorService/CreateActor"
+	ActorService_UpsertActor_FullMethodName          = "/filmoteka.v1.ActorService/UpsertActor"
+	ActorService_UpdateActor_FullMethodName          = "/filmoteka.v1.ActorService/UpdateActor"
+	ActorService_DeleteActor_FullMethodName          = "/filmoteka.v1.ActorService/DeleteActor"
+	ActorService_FindDuplicates_FullMethodName       = "/filmoteka.v1.ActorService/FindDuplicates"
+	ActorService_MergeActors_FullMethodName          = "/filmoteka.v1.ActorService/MergeActors"
+)
+
+// ActorServiceClient is the client API for ActorService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ActorServiceClient interface {
+	GetActor(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Actor, error)
+	GetActorByExternalID(ctx context.Context, in *ExternalIDRequest, opts ...grpc.CallOption) (*Actor, error)
+	ListActors(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (ActorService_ListActorsClient, error)
+	CreateActor(ctx context.Context, in *ActorData, opts ...grpc.CallOption) (*Actor, error)
+	UpsertActor(ctx context.Context, in *ActorData, opts ...grpc.CallOption) (*Actor, error)
+	UpdateActor(ctx context.Context, in *UpdateActorRequest, opts ...grpc.CallOption) (*Actor, error)
+	DeleteActor(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Actor, error)
+	FindDuplicates(ctx context.Context, in *DuplicatesRequest, opts ...grpc.CallOption) (ActorService_FindDuplicatesClient, error)
+	MergeActors(ctx context.Context, in *MergeActorsRequest, opts ...grpc.CallOption) (*Actor, error)
+}
+
+type actorServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewActorServiceClient(cc grpc.ClientConnInterface) ActorServiceClient {
+	return &actorServiceClient{cc}
+}
+
+func (c *actorServiceClient) GetActor(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorService_GetActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorServiceClient) GetActorByExternalID(ctx context.Context, in *ExternalIDRequest, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorService_GetActorByExternalID_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorServiceClient) ListActors(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (ActorService_ListActorsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ActorService_ServiceDesc.Streams[0], ActorService_ListActors_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actorServiceListActorsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActorService_ListActorsClient interface {
+	Recv() (*Actor, error)
+	grpc.ClientStream
+}
+
+type actorServiceListActorsClient struct {
+	grpc.ClientStream
+}
+
+func (x *actorServiceListActorsClient) Recv() (*Actor, error) {
+	m := new(Actor)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *actorServiceClient) CreateActor(ctx context.Context, in *ActorData, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorService_CreateActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorServiceClient) UpsertActor(ctx context.Context, in *ActorData, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorService_UpsertActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorServiceClient) UpdateActor(ctx context.Context, in *UpdateActorRequest, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorService_UpdateActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorServiceClient) DeleteActor(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorService_DeleteActor_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorServiceClient) FindDuplicates(ctx context.Context, in *DuplicatesRequest, opts ...grpc.CallOption) (ActorService_FindDuplicatesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ActorService_ServiceDesc.Streams[1], ActorService_FindDuplicates_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actorServiceFindDuplicatesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActorService_FindDuplicatesClient interface {
+	Recv() (*DuplicateActors, error)
+	grpc.ClientStream
+}
+
+type actorServiceFindDuplicatesClient struct {
+	grpc.ClientStream
+}
+
+func (x *actorServiceFindDuplicatesClient) Recv() (*DuplicateActors, error) {
+	m := new(DuplicateActors)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *actorServiceClient) MergeActors(ctx context.Context, in *MergeActorsRequest, opts ...grpc.CallOption) (*Actor, error) {
+	out := new(Actor)
+	err := c.cc.Invoke(ctx, ActorService_MergeActors_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ActorServiceServer is the server API for ActorService service.
+// All implementations must embed UnimplementedActorServiceServer
+// for forward compatibility
+type ActorServiceServer interface {
+	GetActor(context.Context, *IDRequest) (*Actor, error)
+	GetActorByExternalID(context.Context, *ExternalIDRequest) (*Actor, error)
+	ListActors(*ListRequest, ActorService_ListActorsServer) error
+	CreateActor(context.Context, *ActorData) (*Actor, error)
+	UpsertActor(context.Context, *ActorData) (*Actor, error)
+	UpdateActor(context.Context, *UpdateActorRequest) (*Actor, error)
+	DeleteActor(context.Context, *IDRequest) (*Actor, error)
+	FindDuplicates(*DuplicatesRequest, ActorService_FindDuplicatesServer) error
+	MergeActors(context.Context, *MergeActorsRequest) (*Actor, error)
+	mustEmbedUnimplementedActorServiceServer()
+}
+
+// UnimplementedActorServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedActorServiceServer struct {
+}
+
+func (UnimplementedActorServiceServer) GetActor(context.Context, *IDRequest) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActor not implemented")
+}
+func (UnimplementedActorServiceServer) GetActorByExternalID(context.Context, *ExternalIDRequest) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetActorByExternalID not implemented")
+}
+func (UnimplementedActorServiceServer) ListActors(*ListRequest, ActorService_ListActorsServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListActors not implemented")
+}
+func (UnimplementedActorServiceServer) CreateActor(context.Context, *ActorData) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateActor not implemented")
+}
+func (UnimplementedActorServiceServer) UpsertActor(context.Context, *ActorData) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertActor not implemented")
+}
+func (UnimplementedActorServiceServer) UpdateActor(context.Context, *UpdateActorRequest) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateActor not implemented")
+}
+func (UnimplementedActorServiceServer) DeleteActor(context.Context, *IDRequest) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteActor not implemented")
+}
+func (UnimplementedActorServiceServer) FindDuplicates(*DuplicatesRequest, ActorService_FindDuplicatesServer) error {
+	return status.Errorf(codes.Unimplemented, "method FindDuplicates not implemented")
+}
+func (UnimplementedActorServiceServer) MergeActors(context.Context, *MergeActorsRequest) (*Actor, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MergeActors not implemented")
+}
+func (UnimplementedActorServiceServer) mustEmbedUnimplementedActorServiceServer() {}
+
+// UnsafeActorServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ActorServiceServer will
+// result in compilation errors.
+type UnsafeActorServiceServer interface {
+	mustEmbedUnimplementedActorServiceServer()
+}
+
+func RegisterActorServiceServer(s grpc.ServiceRegistrar, srv ActorServiceServer) {
+	s.RegisterService(&ActorService_ServiceDesc, srv)
+}
+
+func _ActorService_GetActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorServiceServer).GetActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorService_GetActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorServiceServer).GetActor(ctx, req.(*IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorService_GetActorByExternalID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorServiceServer).GetActorByExternalID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorService_GetActorByExternalID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorServiceServer).GetActorByExternalID(ctx, req.(*ExternalIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorService_ListActors_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActorServiceServer).ListActors(m, &actorServiceListActorsServer{stream})
+}
+
+type ActorService_ListActorsServer interface {
+	Send(*Actor) error
+	grpc.ServerStream
+}
+
+type actorServiceListActorsServer struct {
+	grpc.ServerStream
+}
+
+func (x *actorServiceListActorsServer) Send(m *Actor) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ActorService_CreateActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActorData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorServiceServer).CreateActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorService_CreateActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorServiceServer).CreateActor(ctx, req.(*ActorData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorService_UpsertActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ActorData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorServiceServer).UpsertActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorService_UpsertActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorServiceServer).UpsertActor(ctx, req.(*ActorData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorService_UpdateActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateActorRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorServiceServer).UpdateActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorService_UpdateActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorServiceServer).UpdateActor(ctx, req.(*UpdateActorRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorService_DeleteActor_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorServiceServer).DeleteActor(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorService_DeleteActor_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorServiceServer).DeleteActor(ctx, req.(*IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorService_FindDuplicates_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DuplicatesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActorServiceServer).FindDuplicates(m, &actorServiceFindDuplicatesServer{stream})
+}
+
+type ActorService_FindDuplicatesServer interface {
+	Send(*DuplicateActors) error
+	grpc.ServerStream
+}
+
+type actorServiceFindDuplicatesServer struct {
+	grpc.ServerStream
+}
+
+func (x *actorServiceFindDuplicatesServer) Send(m *DuplicateActors) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ActorService_MergeActors_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MergeActorsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorServiceServer).MergeActors(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorService_MergeActors_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorServiceServer).MergeActors(ctx, req.(*MergeActorsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ActorService_ServiceDesc is the grpc.ServiceDesc for ActorService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ActorService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "filmoteka.v1.ActorService",
+	HandlerType: (*ActorServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetActor",
+			Handler:    _ActorService_GetActor_Handler,
+		},
+		{
+			MethodName: "GetActorByExternalID",
+			Handler:    _ActorService_GetActorByExternalID_Handler,
+		},
+		{
+			MethodName: "CreateActor",
+			Handler:    _ActorService_CreateActor_Handler,
+		},
+		{
+			MethodName: "UpsertActor",
+			Handler:    _ActorService_UpsertActor_Handler,
+		},
+		{
+			MethodName: "UpdateActor",
+			Handler:    _ActorService_UpdateActor_Handler,
+		},
+		{
+			MethodName: "DeleteActor",
+			Handler:    _ActorService_DeleteActor_Handler,
+		},
+		{
+			MethodName: "MergeActors",
+			Handler:    _ActorService_MergeActors_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListActors",
+			Handler:       _ActorService_ListActors_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "FindDuplicates",
+			Handler:       _ActorService_FindDuplicates_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "filmoteka/v1/filmoteka.proto",
+}
+
+const (
+	MovieService_GetMovie_FullMethodName             = "/filmoteka.v1.MovieService/GetMovie"
+	MovieService_GetMovieByExternalID_FullMethodName = "/filmoteka.v1.MovieService/GetMovieByExternalID"
+	MovieService_ListMovies_FullMethodName           = "/filmoteka.v1.MovieService/ListMovies"
+	MovieService_SearchMovies_FullMethodName         = "/filmoteka.v1.MovieService/SearchMovies"
+	MovieService_CreateMovie_FullMethodName          = "/filmoteka.v1.MovieService/CreateMovie"
+	MovieService_UpsertMovie_FullMethodName          = "/filmoteka.v1.MovieService/UpsertMovie"
+	MovieService_UpdateMovie_FullMethodName          = "/filmoteka.v1.MovieService/UpdateMovie"
+	MovieService_DeleteMovie_FullMethodName          = "/filmoteka.v1.MovieService/DeleteMovie"
+)
+
+// MovieServiceClient is the client API for MovieService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MovieServiceClient interface {
+	GetMovie(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Movie, error)
+	GetMovieByExternalID(ctx context.Context, in *ExternalIDRequest, opts ...grpc.CallOption) (*Movie, error)
+	ListMovies(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (MovieService_ListMoviesClient, error)
+	SearchMovies(ctx context.Context, in *SearchMoviesRequest, opts ...grpc.CallOption) (MovieService_SearchMoviesClient, error)
+	CreateMovie(ctx context.Context, in *MovieData, opts ...grpc.CallOption) (*Movie, error)
+	UpsertMovie(ctx context.Context, in *MovieData, opts ...grpc.CallOption) (*Movie, error)
+	UpdateMovie(ctx context.Context, in *UpdateMovieRequest, opts ...grpc.CallOption) (*Movie, error)
+	DeleteMovie(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Movie, error)
+}
+
+type movieServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMovieServiceClient(cc grpc.ClientConnInterface) MovieServiceClient {
+	return &movieServiceClient{cc}
+}
+
+func (c *movieServiceClient) GetMovie(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Movie, error) {
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, MovieService_GetMovie_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) GetMovieByExternalID(ctx context.Context, in *ExternalIDRequest, opts ...grpc.CallOption) (*Movie, error) {
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, MovieService_GetMovieByExternalID_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) ListMovies(ctx context.Context, in *ListRequest, opts ...grpc.CallOption) (MovieService_ListMoviesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MovieService_ServiceDesc.Streams[0], MovieService_ListMovies_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &movieServiceListMoviesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MovieService_ListMoviesClient interface {
+	Recv() (*Movie, error)
+	grpc.ClientStream
+}
+
+type movieServiceListMoviesClient struct {
+	grpc.ClientStream
+}
+
+func (x *movieServiceListMoviesClient) Recv() (*Movie, error) {
+	m := new(Movie)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *movieServiceClient) SearchMovies(ctx context.Context, in *SearchMoviesRequest, opts ...grpc.CallOption) (MovieService_SearchMoviesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &MovieService_ServiceDesc.Streams[1], MovieService_SearchMovies_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &movieServiceSearchMoviesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type MovieService_SearchMoviesClient interface {
+	Recv() (*Movie, error)
+	grpc.ClientStream
+}
+
+type movieServiceSearchMoviesClient struct {
+	grpc.ClientStream
+}
+
+func (x *movieServiceSearchMoviesClient) Recv() (*Movie, error) {
+	m := new(Movie)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *movieServiceClient) CreateMovie(ctx context.Context, in *MovieData, opts ...grpc.CallOption) (*Movie, error) {
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, MovieService_CreateMovie_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) UpsertMovie(ctx context.Context, in *MovieData, opts ...grpc.CallOption) (*Movie, error) {
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, MovieService_UpsertMovie_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) UpdateMovie(ctx context.Context, in *UpdateMovieRequest, opts ...grpc.CallOption) (*Movie, error) {
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, MovieService_UpdateMovie_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *movieServiceClient) DeleteMovie(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (*Movie, error) {
+	out := new(Movie)
+	err := c.cc.Invoke(ctx, MovieService_DeleteMovie_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MovieServiceServer is the server API for MovieService service.
+// All implementations must embed UnimplementedMovieServiceServer
+// for forward compatibility
+type MovieServiceServer interface {
+	GetMovie(context.Context, *IDRequest) (*Movie, error)
+	GetMovieByExternalID(context.Context, *ExternalIDRequest) (*Movie, error)
+	ListMovies(*ListRequest, MovieService_ListMoviesServer) error
+	SearchMovies(*SearchMoviesRequest, MovieService_SearchMoviesServer) error
+	CreateMovie(context.Context, *MovieData) (*Movie, error)
+	UpsertMovie(context.Context, *MovieData) (*Movie, error)
+	UpdateMovie(context.Context, *UpdateMovieRequest) (*Movie, error)
+	DeleteMovie(context.Context, *IDRequest) (*Movie, error)
+	mustEmbedUnimplementedMovieServiceServer()
+}
+
+// UnimplementedMovieServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedMovieServiceServer struct {
+}
+
+func (UnimplementedMovieServiceServer) GetMovie(context.Context, *IDRequest) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) GetMovieByExternalID(context.Context, *ExternalIDRequest) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMovieByExternalID not implemented")
+}
+func (UnimplementedMovieServiceServer) ListMovies(*ListRequest, MovieService_ListMoviesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListMovies not implemented")
+}
+func (UnimplementedMovieServiceServer) SearchMovies(*SearchMoviesRequest, MovieService_SearchMoviesServer) error {
+	return status.Errorf(codes.Unimplemented, "method SearchMovies not implemented")
+}
+func (UnimplementedMovieServiceServer) CreateMovie(context.Context, *MovieData) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) UpsertMovie(context.Context, *MovieData) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpsertMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) UpdateMovie(context.Context, *UpdateMovieRequest) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) DeleteMovie(context.Context, *IDRequest) (*Movie, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteMovie not implemented")
+}
+func (UnimplementedMovieServiceServer) mustEmbedUnimplementedMovieServiceServer() {}
+
+// UnsafeMovieServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MovieServiceServer will
+// result in compilation errors.
+type UnsafeMovieServiceServer interface {
+	mustEmbedUnimplementedMovieServiceServer()
+}
+
+func RegisterMovieServiceServer(s grpc.ServiceRegistrar, srv MovieServiceServer) {
+	s.RegisterService(&MovieService_ServiceDesc, srv)
+}
+
+func _MovieService_GetMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).GetMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_GetMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).GetMovie(ctx, req.(*IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_GetMovieByExternalID_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExternalIDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).GetMovieByExternalID(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_GetMovieByExternalID_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).GetMovieByExternalID(ctx, req.(*ExternalIDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_ListMovies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ListRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MovieServiceServer).ListMovies(m, &movieServiceListMoviesServer{stream})
+}
+
+type MovieService_ListMoviesServer interface {
+	Send(*Movie) error
+	grpc.ServerStream
+}
+
+type movieServiceListMoviesServer struct {
+	grpc.ServerStream
+}
+
+func (x *movieServiceListMoviesServer) Send(m *Movie) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MovieService_SearchMovies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SearchMoviesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MovieServiceServer).SearchMovies(m, &movieServiceSearchMoviesServer{stream})
+}
+
+type MovieService_SearchMoviesServer interface {
+	Send(*Movie) error
+	grpc.ServerStream
+}
+
+type movieServiceSearchMoviesServer struct {
+	grpc.ServerStream
+}
+
+func (x *movieServiceSearchMoviesServer) Send(m *Movie) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _MovieService_CreateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MovieData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).CreateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_CreateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).CreateMovie(ctx, req.(*MovieData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_UpsertMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MovieData)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).UpsertMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_UpsertMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).UpsertMovie(ctx, req.(*MovieData))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_UpdateMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMovieRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).UpdateMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_UpdateMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).UpdateMovie(ctx, req.(*UpdateMovieRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MovieService_DeleteMovie_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(IDRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MovieServiceServer).DeleteMovie(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MovieService_DeleteMovie_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MovieServiceServer).DeleteMovie(ctx, req.(*IDRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MovieService_ServiceDesc is the grpc.ServiceDesc for MovieService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MovieService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "filmoteka.v1.MovieService",
+	HandlerType: (*MovieServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetMovie",
+			Handler:    _MovieService_GetMovie_Handler,
+		},
+		{
+			MethodName: "GetMovieByExternalID",
+			Handler:    _MovieService_GetMovieByExternalID_Handler,
+		},
+		{
+			MethodName: "CreateMovie",
+			Handler:    _MovieService_CreateMovie_Handler,
+		},
+		{
+			MethodName: "UpsertMovie",
+			Handler:    _MovieService_UpsertMovie_Handler,
+		},
+		{
+			MethodName: "UpdateMovie",
+			Handler:    _MovieService_UpdateMovie_Handler,
+		},
+		{
+			MethodName: "DeleteMovie",
+			Handler:    _MovieService_DeleteMovie_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListMovies",
+			Handler:       _MovieService_ListMovies_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SearchMovies",
+			Handler:       _MovieService_SearchMovies_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "filmoteka/v1/filmoteka.proto",
+}
+
+const (
+	ActorMovieService_AddCast_FullMethodName          = "/filmoteka.v1.ActorMovieService/AddCast"
+	ActorMovieService_ListActorsMovies_FullMethodName = "/filmoteka.v1.ActorMovieService/ListActorsMovies"
+	ActorMovieService_GetCast_FullMethodName          = "/filmoteka.v1.ActorMovieService/GetCast"
+	ActorMovieService_GetFilmography_FullMethodName   = "/filmoteka.v1.ActorMovieService/GetFilmography"
+)
+
+// ActorMovieServiceClient is the client API for ActorMovieService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ActorMovieServiceClient interface {
+	AddCast(ctx context.Context, in *CastLink, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListActorsMovies(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (ActorMovieService_ListActorsMoviesClient, error)
+	GetCast(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (ActorMovieService_GetCastClient, error)
+	GetFilmography(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (ActorMovieService_GetFilmographyClient, error)
+}
+
+type actorMovieServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewActorMovieServiceClient(cc grpc.ClientConnInterface) ActorMovieServiceClient {
+	return &actorMovieServiceClient{cc}
+}
+
+func (c *actorMovieServiceClient) AddCast(ctx context.Context, in *CastLink, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, ActorMovieService_AddCast_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *actorMovieServiceClient) ListActorsMovies(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (ActorMovieService_ListActorsMoviesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ActorMovieService_ServiceDesc.Streams[0], ActorMovieService_ListActorsMovies_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actorMovieServiceListActorsMoviesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActorMovieService_ListActorsMoviesClient interface {
+	Recv() (*ActorMovieData, error)
+	grpc.ClientStream
+}
+
+type actorMovieServiceListActorsMoviesClient struct {
+	grpc.ClientStream
+}
+
+func (x *actorMovieServiceListActorsMoviesClient) Recv() (*ActorMovieData, error) {
+	m := new(ActorMovieData)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *actorMovieServiceClient) GetCast(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (ActorMovieService_GetCastClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ActorMovieService_ServiceDesc.Streams[1], ActorMovieService_GetCast_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actorMovieServiceGetCastClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActorMovieService_GetCastClient interface {
+	Recv() (*Actor, error)
+	grpc.ClientStream
+}
+
+type actorMovieServiceGetCastClient struct {
+	grpc.ClientStream
+}
+
+func (x *actorMovieServiceGetCastClient) Recv() (*Actor, error) {
+	m := new(Actor)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *actorMovieServiceClient) GetFilmography(ctx context.Context, in *IDRequest, opts ...grpc.CallOption) (ActorMovieService_GetFilmographyClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ActorMovieService_ServiceDesc.Streams[2], ActorMovieService_GetFilmography_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &actorMovieServiceGetFilmographyClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ActorMovieService_GetFilmographyClient interface {
+	Recv() (*Movie, error)
+	grpc.ClientStream
+}
+
+type actorMovieServiceGetFilmographyClient struct {
+	grpc.ClientStream
+}
+
+func (x *actorMovieServiceGetFilmographyClient) Recv() (*Movie, error) {
+	m := new(Movie)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ActorMovieServiceServer is the server API for ActorMovieService service.
+// All implementations must embed UnimplementedActorMovieServiceServer
+// for forward compatibility
+type ActorMovieServiceServer interface {
+	AddCast(context.Context, *CastLink) (*emptypb.Empty, error)
+	ListActorsMovies(*emptypb.Empty, ActorMovieService_ListActorsMoviesServer) error
+	GetCast(*IDRequest, ActorMovieService_GetCastServer) error
+	GetFilmography(*IDRequest, ActorMovieService_GetFilmographyServer) error
+	mustEmbedUnimplementedActorMovieServiceServer()
+}
+
+// UnimplementedActorMovieServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedActorMovieServiceServer struct {
+}
+
+func (UnimplementedActorMovieServiceServer) AddCast(context.Context, *CastLink) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddCast not implemented")
+}
+func (UnimplementedActorMovieServiceServer) ListActorsMovies(*emptypb.Empty, ActorMovieService_ListActorsMoviesServer) error {
+	return status.Errorf(codes.Unimplemented, "method ListActorsMovies not implemented")
+}
+func (UnimplementedActorMovieServiceServer) GetCast(*IDRequest, ActorMovieService_GetCastServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetCast not implemented")
+}
+func (UnimplementedActorMovieServiceServer) GetFilmography(*IDRequest, ActorMovieService_GetFilmographyServer) error {
+	return status.Errorf(codes.Unimplemented, "method GetFilmography not implemented")
+}
+func (UnimplementedActorMovieServiceServer) mustEmbedUnimplementedActorMovieServiceServer() {}
+
+// UnsafeActorMovieServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ActorMovieServiceServer will
+// result in compilation errors.
+type UnsafeActorMovieServiceServer interface {
+	mustEmbedUnimplementedActorMovieServiceServer()
+}
+
+func RegisterActorMovieServiceServer(s grpc.ServiceRegistrar, srv ActorMovieServiceServer) {
+	s.RegisterService(&ActorMovieService_ServiceDesc, srv)
+}
+
+func _ActorMovieService_AddCast_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CastLink)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ActorMovieServiceServer).AddCast(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ActorMovieService_AddCast_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ActorMovieServiceServer).AddCast(ctx, req.(*CastLink))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ActorMovieService_ListActorsMovies_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(emptypb.Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActorMovieServiceServer).ListActorsMovies(m, &actorMovieServiceListActorsMoviesServer{stream})
+}
+
+type ActorMovieService_ListActorsMoviesServer interface {
+	Send(*ActorMovieData) error
+	grpc.ServerStream
+}
+
+type actorMovieServiceListActorsMoviesServer struct {
+	grpc.ServerStream
+}
+
+func (x *actorMovieServiceListActorsMoviesServer) Send(m *ActorMovieData) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ActorMovieService_GetCast_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActorMovieServiceServer).GetCast(m, &actorMovieServiceGetCastServer{stream})
+}
+
+type ActorMovieService_GetCastServer interface {
+	Send(*Actor) error
+	grpc.ServerStream
+}
+
+type actorMovieServiceGetCastServer struct {
+	grpc.ServerStream
+}
+
+func (x *actorMovieServiceGetCastServer) Send(m *Actor) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _ActorMovieService_GetFilmography_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(IDRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ActorMovieServiceServer).GetFilmography(m, &actorMovieServiceGetFilmographyServer{stream})
+}
+
+type ActorMovieService_GetFilmographyServer interface {
+	Send(*Movie) error
+	grpc.ServerStream
+}
+
+type actorMovieServiceGetFilmographyServer struct {
+	grpc.ServerStream
+}
+
+func (x *actorMovieServiceGetFilmographyServer) Send(m *Movie) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ActorMovieService_ServiceDesc is the grpc.ServiceDesc for ActorMovieService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ActorMovieService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "filmoteka.v1.ActorMovieService",
+	HandlerType: (*ActorMovieServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "AddCast",
+			Handler:    _ActorMovieService_AddCast_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ListActorsMovies",
+			Handler:       _ActorMovieService_ListActorsMovies_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetCast",
+			Handler:       _ActorMovieService_GetCast_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "GetFilmography",
+			Handler:       _ActorMovieService_GetFilmography_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "filmoteka/v1/filmoteka.proto",
+}
@@ -6,20 +6,76 @@ import (
 	"filmoteka/config"
 
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+
+	"filmoteka/pkg/tracesql"
 )
 
+// New открывает основную БД и проверяет соединение с ней. Так работают команды, которым без БД
+// делать нечего (seed, import-imdb, filmotekactl).
 func New(sc config.StorageConfig) (*sql.DB, error) {
 	const op = "storage.postgresql.New"
 
-	db, err := sql.Open("postgres", sc.URL)
+	db, err := Open(sc)
 	if err != nil {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	err = db.Ping()
 	if err != nil {
-		panic(err)
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return db, nil
 }
+
+// Open открывает основную БД, не проверяя соединение: сервер начинает работу и тогда, когда БД
+// еще недоступна, а ее состояние сообщает проверка готовности /readyz
+func Open(sc config.StorageConfig) (*sql.DB, error) {
+	const op = "storage.postgresql.Open"
+
+	db, err := open(sc.URL)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	configure(db, sc)
+
+	return db, nil
+}
+
+// OpenReplicas открывает реплики из sc.ReplicaURLs с теми же настройками пула, что и основная БД.
+// Соединение, как и в Open, не проверяется.
+func OpenReplicas(sc config.StorageConfig) ([]*sql.DB, error) {
+	const op = "storage.postgresql.OpenReplicas"
+
+	res := make([]*sql.DB, 0, len(sc.ReplicaURLs))
+
+	for i, url := range sc.ReplicaURLs {
+		db, err := open(url)
+		if err != nil {
+			for _, opened := range res {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("%s: replica %d: %w", op, i+1, err)
+		}
+
+		configure(db, sc)
+		res = append(res, db)
+	}
+
+	return res, nil
+}
+
+// open открывает БД, запросы к которой попадают в трассировку
+func open(url string) (*sql.DB, error) {
+	return tracesql.Open("postgres", url, semconv.DBSystemPostgreSQL)
+}
+
+// configure задает размер пула соединений и время жизни соединения
+func configure(db *sql.DB, sc config.StorageConfig) {
+	db.SetMaxOpenConns(sc.MaxOpenConns)
+	db.SetMaxIdleConns(sc.MaxIdleConns)
+	db.SetConnMaxLifetime(sc.ConnMaxLifetime)
+}
@@ -0,0 +1,523 @@
+// Package tracesql оборачивает драйвер database/sql так, что каждый запрос к БД становится спаном
+// OpenTelemetry с именем запроса и числом прочитанных или измененных строк.
+//
+// Спаны создаются только внутри уже начатой трассы (запроса HTTP, метода usecase), поэтому
+// миграции и служебные запросы без трассы в трассировку не попадают. В журнал (см. SetLogger)
+// записываются все запросы с контекстом, в котором они выполнены.
+//
+// Имена запросов регистрируют пакеты репозиториев (см. Statements). Запросы, собранные
+// построителем, называются по операции и таблице: "SELECT movies".
+package tracesql
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+const (
+	// RowsReturnedKey - число строк, прочитанных из результата запроса
+	RowsReturnedKey = attribute.Key("db.rows_returned")
+	// RowsAffectedKey - число строк, измененных запросом
+	RowsAffectedKey = attribute.Key("db.rows_affected")
+	// StatementNameKey - имя запроса, под которым его зарегистрировал репозиторий
+	StatementNameKey = attribute.Key("db.statement.name")
+)
+
+// tracer берется у глобального провайдера, поэтому БД можно открыть до настройки трассировки
+var tracer = otel.Tracer("filmoteka/pkg/tracesql")
+
+var (
+	statementsMu sync.RWMutex
+	statements   = map[string]string{}
+)
+
+// Logger записывает выполненные запросы. Контекст запроса к БД передается, чтобы журнал
+// мог связать запрос с запросом клиента.
+type Logger interface {
+	DebugContext(ctx context.Context, msg string, args ...any)
+}
+
+var (
+	loggerMu sync.RWMutex
+	logger   Logger
+)
+
+// SetLogger задает журнал запросов всех БД, открытых Open. По умолчанию запросы не записываются.
+func SetLogger(l Logger) {
+	loggerMu.Lock()
+	defer loggerMu.Unlock()
+
+	logger = l
+}
+
+// Statements регистрирует имена запросов: ключ - текст запроса, значение - его имя
+func Statements(names map[string]string) {
+	statementsMu.Lock()
+	defer statementsMu.Unlock()
+
+	for query, name := range names {
+		statements[query] = name
+	}
+}
+
+// statementName возвращает зарегистрированное имя запроса или операцию и таблицу
+func statementName(query string) string {
+	statementsMu.RLock()
+	name, ok := statements[query]
+	statementsMu.RUnlock()
+
+	if ok {
+		return name
+	}
+
+	fields := strings.Fields(query)
+	if len(fields) == 0 {
+		return "query"
+	}
+
+	operation := strings.ToUpper(fields[0])
+
+	for i := 0; i < len(fields)-1; i++ {
+		switch strings.ToUpper(fields[i]) {
+		case "FROM", "INTO", "UPDATE":
+			// Подзапрос вместо таблицы пропускается
+			if table := strings.Trim(fields[i+1], `"),;`); table != "" && !strings.HasPrefix(table, "(") {
+				return operation + " " + table
+			}
+		}
+	}
+
+	return operation
+}
+
+// Open открывает БД драйвером, зарегистрированным под именем driverName, и трассирует ее запросы.
+// system - атрибут db.system, например semconv.DBSystemPostgreSQL.
+func Open(driverName, dsn string, system attribute.KeyValue) (*sql.DB, error) {
+	// sql.Open не подключается к БД, он нужен, чтобы найти драйвер
+	db, err := sql.Open(driverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	d := db.Driver()
+	db.Close()
+
+	var c driver.Connector = dsnConnector{dsn: dsn, driver: d}
+
+	if dc, ok := d.(driver.DriverContext); ok {
+		c, err = dc.OpenConnector(dsn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sql.OpenDB(&connector{Connector: c, system: system}), nil
+}
+
+// dsnConnector подключает драйвер без driver.DriverContext, как это делает sql.Open
+type dsnConnector struct {
+	dsn    string
+	driver driver.Driver
+}
+
+func (c dsnConnector) Connect(context.Context) (driver.Conn, error) {
+	return c.driver.Open(c.dsn)
+}
+
+func (c dsnConnector) Driver() driver.Driver {
+	return c.driver
+}
+
+type connector struct {
+	driver.Connector
+	system attribute.KeyValue
+}
+
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	cn, err := c.Connector.Connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &conn{Conn: cn, system: c.system}, nil
+}
+
+// call - выполняемый запрос
+type call struct {
+	ctx   context.Context
+	span  trace.Span
+	name  string
+	start time.Time
+}
+
+// start начинает запрос query. Спан создается, если ctx принадлежит трассе.
+func start(ctx context.Context, query string, system attribute.KeyValue) (context.Context, *call) {
+	c := &call{ctx: ctx, span: noop.Span{}, name: statementName(query), start: time.Now()}
+
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		return ctx, c
+	}
+
+	fields := strings.Fields(query)
+	operation := ""
+	if len(fields) > 0 {
+		operation = strings.ToUpper(fields[0])
+	}
+
+	ctx, c.span = tracer.Start(ctx, c.name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			system,
+			StatementNameKey.String(c.name),
+			semconv.DBOperation(operation),
+			semconv.DBStatement(query),
+		),
+	)
+
+	return ctx, c
+}
+
+// fail отмечает спан ошибкой. driver.ErrSkip - не ошибка, а просьба к database/sql
+// выполнить запрос иначе.
+func fail(span trace.Span, err error) {
+	if errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// end заканчивает спан запроса и записывает запрос в журнал. rows - прочитанные или
+// измененные строки, если их число известно.
+func (c *call) end(err error, rows ...attribute.KeyValue) {
+	c.span.SetAttributes(rows...)
+	if err != nil {
+		fail(c.span, err)
+	}
+	c.span.End()
+
+	loggerMu.RLock()
+	l := logger
+	loggerMu.RUnlock()
+
+	if l == nil || errors.Is(err, driver.ErrSkip) {
+		return
+	}
+
+	args := []any{
+		"statement", c.name,
+		"duration_ms", float64(time.Since(c.start).Microseconds()) / 1000,
+	}
+
+	for _, kv := range rows {
+		args = append(args, string(kv.Key), kv.Value.AsInterface())
+	}
+
+	if err != nil {
+		args = append(args, "error", err.Error())
+	}
+
+	l.DebugContext(c.ctx, "query", args...)
+}
+
+// query оборачивает строки результата, чтобы запрос закончился при их закрытии
+func query(c *call, res driver.Rows, err error) (driver.Rows, error) {
+	if err != nil {
+		c.end(err)
+		return nil, err
+	}
+
+	return &rows{Rows: res, call: c}, nil
+}
+
+// exec заканчивает запрос без результата
+func exec(c *call, res driver.Result, err error) (driver.Result, error) {
+	if err != nil {
+		c.end(err)
+		return nil, err
+	}
+
+	if n, err := res.RowsAffected(); err == nil {
+		c.end(nil, RowsAffectedKey.Int64(n))
+	} else {
+		c.end(nil)
+	}
+
+	return res, nil
+}
+
+// conn передает вызовы соединению драйвера. Необязательные интерфейсы, которых у драйвера нет,
+// ведут себя так же, как database/sql без них.
+type conn struct {
+	driver.Conn
+	system attribute.KeyValue
+}
+
+var (
+	_ driver.ConnBeginTx        = (*conn)(nil)
+	_ driver.ConnPrepareContext = (*conn)(nil)
+	_ driver.QueryerContext     = (*conn)(nil)
+	_ driver.ExecerContext      = (*conn)(nil)
+	_ driver.Pinger             = (*conn)(nil)
+	_ driver.SessionResetter    = (*conn)(nil)
+	_ driver.Validator          = (*conn)(nil)
+	_ driver.NamedValueChecker  = (*conn)(nil)
+)
+
+func (c *conn) QueryContext(ctx context.Context, q string, args []driver.NamedValue) (driver.Rows, error) {
+	queryer, ok := c.Conn.(driver.QueryerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, done := start(ctx, q, c.system)
+	res, err := queryer.QueryContext(ctx, q, args)
+
+	return query(done, res, err)
+}
+
+func (c *conn) ExecContext(ctx context.Context, q string, args []driver.NamedValue) (driver.Result, error) {
+	execer, ok := c.Conn.(driver.ExecerContext)
+	if !ok {
+		return nil, driver.ErrSkip
+	}
+
+	ctx, done := start(ctx, q, c.system)
+	res, err := execer.ExecContext(ctx, q, args)
+
+	return exec(done, res, err)
+}
+
+func (c *conn) PrepareContext(ctx context.Context, q string) (driver.Stmt, error) {
+	var (
+		s   driver.Stmt
+		err error
+	)
+
+	if preparer, ok := c.Conn.(driver.ConnPrepareContext); ok {
+		s, err = preparer.PrepareContext(ctx, q)
+	} else {
+		s, err = c.Conn.Prepare(q)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &stmt{Stmt: s, query: q, system: c.system}, nil
+}
+
+func (c *conn) BeginTx(ctx context.Context, opts driver.TxOptions) (driver.Tx, error) {
+	if beginner, ok := c.Conn.(driver.ConnBeginTx); ok {
+		return beginner.BeginTx(ctx, opts)
+	}
+
+	if opts.Isolation != driver.IsolationLevel(sql.LevelDefault) || opts.ReadOnly {
+		return nil, errors.New("tracesql: driver does not support transaction options")
+	}
+
+	return c.Conn.Begin()
+}
+
+func (c *conn) Ping(ctx context.Context) error {
+	if pinger, ok := c.Conn.(driver.Pinger); ok {
+		return pinger.Ping(ctx)
+	}
+
+	return nil
+}
+
+func (c *conn) ResetSession(ctx context.Context) error {
+	if resetter, ok := c.Conn.(driver.SessionResetter); ok {
+		return resetter.ResetSession(ctx)
+	}
+
+	return nil
+}
+
+func (c *conn) IsValid() bool {
+	if validator, ok := c.Conn.(driver.Validator); ok {
+		return validator.IsValid()
+	}
+
+	return true
+}
+
+func (c *conn) CheckNamedValue(v *driver.NamedValue) error {
+	if checker, ok := c.Conn.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(v)
+	}
+
+	return driver.ErrSkip
+}
+
+// stmt - подготовленный запрос. database/sql готовит запрос сам, если драйвер не умеет
+// выполнять запросы без подготовки.
+type stmt struct {
+	driver.Stmt
+	query  string
+	system attribute.KeyValue
+}
+
+var (
+	_ driver.StmtQueryContext  = (*stmt)(nil)
+	_ driver.StmtExecContext   = (*stmt)(nil)
+	_ driver.NamedValueChecker = (*stmt)(nil)
+)
+
+func (s *stmt) QueryContext(ctx context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	ctx, done := start(ctx, s.query, s.system)
+
+	if queryer, ok := s.Stmt.(driver.StmtQueryContext); ok {
+		res, err := queryer.QueryContext(ctx, args)
+		return query(done, res, err)
+	}
+
+	values, err := values(args)
+	if err != nil {
+		return query(done, nil, err)
+	}
+
+	res, err := s.Stmt.Query(values)
+	return query(done, res, err)
+}
+
+func (s *stmt) ExecContext(ctx context.Context, args []driver.NamedValue) (driver.Result, error) {
+	ctx, done := start(ctx, s.query, s.system)
+
+	if execer, ok := s.Stmt.(driver.StmtExecContext); ok {
+		res, err := execer.ExecContext(ctx, args)
+		return exec(done, res, err)
+	}
+
+	values, err := values(args)
+	if err != nil {
+		return exec(done, nil, err)
+	}
+
+	res, err := s.Stmt.Exec(values)
+	return exec(done, res, err)
+}
+
+func (s *stmt) CheckNamedValue(v *driver.NamedValue) error {
+	if checker, ok := s.Stmt.(driver.NamedValueChecker); ok {
+		return checker.CheckNamedValue(v)
+	}
+
+	return driver.ErrSkip
+}
+
+func values(args []driver.NamedValue) ([]driver.Value, error) {
+	res := make([]driver.Value, len(args))
+
+	for i, arg := range args {
+		if arg.Name != "" {
+			return nil, errors.New("tracesql: driver does not support named parameters")
+		}
+
+		res[i] = arg.Value
+	}
+
+	return res, nil
+}
+
+// rows считает прочитанные строки. Запрос заканчивается при закрытии строк,
+// поэтому в его время входит и чтение результата.
+type rows struct {
+	driver.Rows
+	call *call
+	n    int
+	err  error
+}
+
+func (r *rows) Next(dest []driver.Value) error {
+	err := r.Rows.Next(dest)
+
+	switch {
+	case err == nil:
+		r.n++
+	case err != io.EOF:
+		r.err = err
+	}
+
+	return err
+}
+
+func (r *rows) Close() error {
+	err := r.Rows.Close()
+
+	r.call.end(r.err, RowsReturnedKey.Int(r.n))
+
+	return err
+}
+
+func (r *rows) HasNextResultSet() bool {
+	if set, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return set.HasNextResultSet()
+	}
+
+	return false
+}
+
+func (r *rows) NextResultSet() error {
+	if set, ok := r.Rows.(driver.RowsNextResultSet); ok {
+		return set.NextResultSet()
+	}
+
+	return io.EOF
+}
+
+func (r *rows) ColumnTypeScanType(index int) reflect.Type {
+	if t, ok := r.Rows.(driver.RowsColumnTypeScanType); ok {
+		return t.ColumnTypeScanType(index)
+	}
+
+	return reflect.TypeOf(new(any)).Elem()
+}
+
+func (r *rows) ColumnTypeDatabaseTypeName(index int) string {
+	if t, ok := r.Rows.(driver.RowsColumnTypeDatabaseTypeName); ok {
+		return t.ColumnTypeDatabaseTypeName(index)
+	}
+
+	return ""
+}
+
+func (r *rows) ColumnTypeLength(index int) (int64, bool) {
+	if t, ok := r.Rows.(driver.RowsColumnTypeLength); ok {
+		return t.ColumnTypeLength(index)
+	}
+
+	return 0, false
+}
+
+func (r *rows) ColumnTypeNullable(index int) (bool, bool) {
+	if t, ok := r.Rows.(driver.RowsColumnTypeNullable); ok {
+		return t.ColumnTypeNullable(index)
+	}
+
+	return false, false
+}
+
+func (r *rows) ColumnTypePrecisionScale(index int) (int64, int64, bool) {
+	if t, ok := r.Rows.(driver.RowsColumnTypePrecisionScale); ok {
+		return t.ColumnTypePrecisionScale(index)
+	}
+
+	return 0, 0, false
+}
@@ -0,0 +1,240 @@
+package tracesql
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.21.0"
+	"go.opentelemetry.io/otel/trace"
+	_ "modernc.org/sqlite"
+)
+
+const (
+	querySave  = `INSERT INTO movies (title) VALUES ($1), ($2)`
+	queryTitle = `SELECT title FROM movies ORDER BY title`
+)
+
+// Глобальный провайдер задается один раз: tracer пакета привязывается к первому провайдеру
+var recorder = tracetest.NewSpanRecorder()
+
+func init() {
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	Statements(map[string]string{querySave: "querySave", queryTitle: "queryTitle"})
+}
+
+func open(t *testing.T) *sql.DB {
+	t.Helper()
+
+	db, err := Open("sqlite", "file:"+filepath.Join(t.TempDir(), "test.db"), semconv.DBSystemSqlite)
+	if err != nil {
+		t.Fatalf("failed to open database: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	if _, err = db.Exec(`CREATE TABLE movies (id INTEGER PRIMARY KEY, title TEXT NOT NULL)`); err != nil {
+		t.Fatalf("failed to create table: %s", err)
+	}
+
+	return db
+}
+
+// traced выполняет f внутри корневого спана и возвращает спаны запросов его трассы
+func traced(t *testing.T, f func(ctx context.Context)) []sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	ctx, root := otel.Tracer("test").Start(context.Background(), "root")
+	f(ctx)
+	root.End()
+
+	var res []sdktrace.ReadOnlySpan
+	for _, s := range recorder.Ended() {
+		if s.SpanContext().TraceID() == root.SpanContext().TraceID() && s.Name() != "root" {
+			res = append(res, s)
+		}
+	}
+
+	return res
+}
+
+func attr(s sdktrace.ReadOnlySpan, key attribute.Key) attribute.Value {
+	for _, kv := range s.Attributes() {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func TestStatements(t *testing.T) {
+	db := open(t)
+
+	spans := traced(t, func(ctx context.Context) {
+		if _, err := db.ExecContext(ctx, querySave, "Брат", "Брат 2"); err != nil {
+			t.Fatalf("failed to save movies: %s", err)
+		}
+
+		var titles []string
+
+		rows, err := db.QueryContext(ctx, queryTitle)
+		if err != nil {
+			t.Fatalf("failed to query movies: %s", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var title string
+			rows.Scan(&title)
+			titles = append(titles, title)
+		}
+
+		if len(titles) != 2 {
+			t.Fatalf("got %d movies, want 2", len(titles))
+		}
+	})
+
+	if len(spans) != 2 {
+		t.Fatalf("got %d spans, want 2", len(spans))
+	}
+
+	save, title := spans[0], spans[1]
+
+	if save.Name() != "querySave" || attr(save, RowsAffectedKey).AsInt64() != 2 {
+		t.Errorf("save span = %s with %d rows affected, want querySave with 2", save.Name(), attr(save, RowsAffectedKey).AsInt64())
+	}
+
+	if title.Name() != "queryTitle" || attr(title, RowsReturnedKey).AsInt64() != 2 {
+		t.Errorf("title span = %s with %d rows returned, want queryTitle with 2", title.Name(), attr(title, RowsReturnedKey).AsInt64())
+	}
+
+	if got := attr(title, semconv.DBSystemKey).AsString(); got != "sqlite" {
+		t.Errorf("db.system = %q, want sqlite", got)
+	}
+
+	if title.SpanKind() != trace.SpanKindClient {
+		t.Errorf("span kind = %s, want client", title.SpanKind())
+	}
+}
+
+func TestBuiltStatement(t *testing.T) {
+	db := open(t)
+
+	spans := traced(t, func(ctx context.Context) {
+		var count int
+		db.QueryRowContext(ctx, `SELECT COUNT(*) FROM (SELECT id FROM movies) AS m`).Scan(&count)
+		db.ExecContext(ctx, `UPDATE movies SET title = $1`, "Брат")
+		db.ExecContext(ctx, `DELETE FROM unknown`)
+	})
+
+	if len(spans) != 3 {
+		t.Fatalf("got %d spans, want 3", len(spans))
+	}
+
+	for i, want := range []string{"SELECT movies", "UPDATE movies", "DELETE unknown"} {
+		if spans[i].Name() != want {
+			t.Errorf("span %d = %q, want %q", i, spans[i].Name(), want)
+		}
+	}
+
+	if attr(spans[0], RowsReturnedKey).AsInt64() != 1 {
+		t.Errorf("count span has %d rows returned, want 1", attr(spans[0], RowsReturnedKey).AsInt64())
+	}
+
+	if spans[2].Status().Code != codes.Error {
+		t.Errorf("failed query span status = %s, want Error", spans[2].Status().Code)
+	}
+}
+
+func TestWithoutTrace(t *testing.T) {
+	db := open(t)
+	before := len(recorder.Ended())
+
+	if _, err := db.ExecContext(context.Background(), querySave, "Брат", "Брат 2"); err != nil {
+		t.Fatalf("failed to save movies: %s", err)
+	}
+
+	if got := len(recorder.Ended()) - before; got != 0 {
+		t.Fatalf("query without trace created %d spans, want 0", got)
+	}
+}
+
+type ctxKey struct{}
+
+// record - запись журнала запросов
+type record struct {
+	ctx  context.Context
+	msg  string
+	args []any
+}
+
+type testLogger struct {
+	records []record
+}
+
+func (l *testLogger) DebugContext(ctx context.Context, msg string, args ...any) {
+	l.records = append(l.records, record{ctx: ctx, msg: msg, args: args})
+}
+
+func (r record) arg(key string) any {
+	for i := 0; i+1 < len(r.args); i += 2 {
+		if r.args[i] == key {
+			return r.args[i+1]
+		}
+	}
+
+	return nil
+}
+
+func TestLogger(t *testing.T) {
+	db := open(t)
+
+	l := &testLogger{}
+	SetLogger(l)
+	defer SetLogger(nil)
+
+	ctx := context.WithValue(context.Background(), ctxKey{}, "request")
+
+	if _, err := db.ExecContext(ctx, querySave, "Брат", "Брат 2"); err != nil {
+		t.Fatalf("failed to save movies: %s", err)
+	}
+
+	rows, err := db.QueryContext(ctx, queryTitle)
+	if err != nil {
+		t.Fatalf("failed to query movies: %s", err)
+	}
+	for rows.Next() {
+	}
+	rows.Close()
+
+	db.ExecContext(ctx, `DELETE FROM unknown`)
+
+	if len(l.records) != 3 {
+		t.Fatalf("got %d records, want 3", len(l.records))
+	}
+
+	save, title, failed := l.records[0], l.records[1], l.records[2]
+
+	if save.ctx.Value(ctxKey{}) != "request" {
+		t.Errorf("query is logged without the caller context")
+	}
+
+	if save.arg("statement") != "querySave" || save.arg(string(RowsAffectedKey)) != int64(2) {
+		t.Errorf("save record = %v, want querySave with 2 rows affected", save.args)
+	}
+
+	if title.arg("statement") != "queryTitle" || title.arg(string(RowsReturnedKey)) != int64(2) {
+		t.Errorf("title record = %v, want queryTitle with 2 rows returned", title.args)
+	}
+
+	if failed.arg("error") == nil {
+		t.Errorf("failed query record = %v, want error", failed.args)
+	}
+}
@@ -0,0 +1,160 @@
+package validator
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// FieldError - ошибка проверки одного поля
+type FieldError struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// StringRule проверяет строковое поле. nil означает, что поле не передано.
+type StringRule func(val *string) *FieldError
+
+// IntRule проверяет целочисленное поле. nil означает, что поле не передано.
+type IntRule func(val *int) *FieldError
+
+// Validator собирает ошибки всех проверяемых полей, чтобы вернуть их клиенту разом.
+//
+//	v := validator.New()
+//	v.String("title", data.Title, validator.Required(), validator.Length(1, 150))
+//	v.Int("rating", data.Rating, validator.Range(0, 10))
+//	errs := v.Errors()
+type Validator struct {
+	errors []FieldError
+}
+
+func New() *Validator {
+	return &Validator{}
+}
+
+// String проверяет поле правилами по порядку. После первого нарушенного правила
+// остальные правила этого поля не проверяются.
+func (v *Validator) String(field string, val *string, rules ...StringRule) {
+	for _, rule := range rules {
+		if err := rule(val); err != nil {
+			v.add(field, err)
+			return
+		}
+	}
+}
+
+// Int проверяет поле правилами по порядку
+func (v *Validator) Int(field string, val *int, rules ...IntRule) {
+	for _, rule := range rules {
+		if err := rule(val); err != nil {
+			v.add(field, err)
+			return
+		}
+	}
+}
+
+// Add добавляет ошибку, найденную без помощи правил
+func (v *Validator) Add(field, code, message string) {
+	v.errors = append(v.errors, FieldError{Field: field, Code: code, Message: message})
+}
+
+// Errors возвращает найденные ошибки или nil, если их нет
+func (v *Validator) Errors() []FieldError {
+	return v.errors
+}
+
+func (v *Validator) add(field string, err *FieldError) {
+	err.Field = field
+	v.errors = append(v.errors, *err)
+}
+
+// Required требует, чтобы поле было передано и не было пустым
+func Required() StringRule {
+	return func(val *string) *FieldError {
+		if val == nil || strings.TrimSpace(*val) == "" {
+			return &FieldError{Code: "required", Message: "field is required"}
+		}
+		return nil
+	}
+}
+
+// NotEmpty требует, чтобы переданное поле не было пустым
+func NotEmpty() StringRule {
+	return func(val *string) *FieldError {
+		if val != nil && strings.TrimSpace(*val) == "" {
+			return &FieldError{Code: "empty", Message: "field should NOT be empty"}
+		}
+		return nil
+	}
+}
+
+// Length ограничивает длину строки в символах
+func Length(min, max int) StringRule {
+	return func(val *string) *FieldError {
+		if val == nil {
+			return nil
+		}
+
+		if n := utf8.RuneCountInString(*val); n < min || n > max {
+			return &FieldError{
+				Code:    "length",
+				Message: fmt.Sprintf("length should be from %d to %d characters", min, max),
+			}
+		}
+		return nil
+	}
+}
+
+// OneOf ограничивает значение поля списком допустимых
+func OneOf(values ...string) StringRule {
+	return func(val *string) *FieldError {
+		if val == nil {
+			return nil
+		}
+
+		for _, allowed := range values {
+			if *val == allowed {
+				return nil
+			}
+		}
+
+		return &FieldError{
+			Code:    "enum",
+			Message: "value should be one of: " + strings.Join(values, ", "),
+		}
+	}
+}
+
+// RequiredInt требует, чтобы поле было передано
+func RequiredInt() IntRule {
+	return func(val *int) *FieldError {
+		if val == nil {
+			return &FieldError{Code: "required", Message: "field is required"}
+		}
+		return nil
+	}
+}
+
+// Range ограничивает значение поля
+func Range(min, max int) IntRule {
+	return func(val *int) *FieldError {
+		if val != nil && (*val < min || *val > max) {
+			return &FieldError{
+				Code:    "range",
+				Message: fmt.Sprintf("value should be from %d to %d", min, max),
+			}
+		}
+		return nil
+	}
+}
+
+// Min ограничивает значение поля снизу
+func Min(min int) IntRule {
+	return func(val *int) *FieldError {
+		if val != nil && *val < min {
+			return &FieldError{Code: "min", Message: fmt.Sprintf("value should be at least %d", min)}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,118 @@
+package validator
+
+import (
+	"reflect"
+	"testing"
+)
+
+func str(s string) *string { return &s }
+
+func num(n int) *int { return &n }
+
+func TestStringRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule StringRule
+		val  *string
+		code string
+	}{
+		{"required missing", Required(), nil, "required"},
+		{"required empty", Required(), str(""), "required"},
+		{"required spaces", Required(), str("  \t"), "required"},
+		{"required ok", Required(), str("Alien"), ""},
+
+		{"not empty missing", NotEmpty(), nil, ""},
+		{"not empty empty", NotEmpty(), str(""), "empty"},
+		{"not empty spaces", NotEmpty(), str(" "), "empty"},
+		{"not empty ok", NotEmpty(), str("Alien"), ""},
+
+		{"length missing", Length(1, 5), nil, ""},
+		{"length too short", Length(1, 5), str(""), "length"},
+		{"length min", Length(1, 5), str("a"), ""},
+		{"length max", Length(1, 5), str("abcde"), ""},
+		{"length too long", Length(1, 5), str("abcdef"), "length"},
+		// Длина считается в символах, а не в байтах
+		{"length in runes", Length(1, 5), str("Данил"), ""},
+		{"length in runes too long", Length(1, 5), str("Данила"), "length"},
+
+		{"one of missing", OneOf("male", "female"), nil, ""},
+		{"one of first", OneOf("male", "female"), str("male"), ""},
+		{"one of last", OneOf("male", "female"), str("female"), ""},
+		{"one of case sensitive", OneOf("male", "female"), str("Male"), "enum"},
+		{"one of empty", OneOf("male", "female"), str(""), "enum"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule(tt.val)
+
+			switch {
+			case tt.code == "" && err != nil:
+				t.Errorf("unexpected error %+v", err)
+			case tt.code != "" && (err == nil || err.Code != tt.code):
+				t.Errorf("error = %+v, want code %q", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestIntRules(t *testing.T) {
+	tests := []struct {
+		name string
+		rule IntRule
+		val  *int
+		code string
+	}{
+		{"required missing", RequiredInt(), nil, "required"},
+		{"required zero", RequiredInt(), num(0), ""},
+
+		{"range missing", Range(0, 10), nil, ""},
+		{"range below", Range(0, 10), num(-1), "range"},
+		{"range min", Range(0, 10), num(0), ""},
+		{"range max", Range(0, 10), num(10), ""},
+		{"range above", Range(0, 10), num(11), "range"},
+
+		{"min missing", Min(1), nil, ""},
+		{"min below", Min(1), num(0), "min"},
+		{"min ok", Min(1), num(1), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.rule(tt.val)
+
+			switch {
+			case tt.code == "" && err != nil:
+				t.Errorf("unexpected error %+v", err)
+			case tt.code != "" && (err == nil || err.Code != tt.code):
+				t.Errorf("error = %+v, want code %q", err, tt.code)
+			}
+		})
+	}
+}
+
+func TestValidator(t *testing.T) {
+	v := New()
+
+	if v.Errors() != nil {
+		t.Fatalf("new validator has errors: %v", v.Errors())
+	}
+
+	// После первого нарушенного правила остальные правила поля не проверяются
+	v.String("title", nil, Required(), Length(1, 150))
+	v.String("gender", str("other"), OneOf("male", "female"))
+	v.String("name", str("Данила"), Required(), Length(1, 50))
+	v.Int("rating", num(11), RequiredInt(), Range(0, 10), Min(20))
+	v.Add("", "no_fields", "at least one field should be specified")
+
+	want := []FieldError{
+		{Field: "title", Code: "required", Message: "field is required"},
+		{Field: "gender", Code: "enum", Message: "value should be one of: male, female"},
+		{Field: "rating", Code: "range", Message: "value should be from 0 to 10"},
+		{Field: "", Code: "no_fields", Message: "at least one field should be specified"},
+	}
+
+	if got := v.Errors(); !reflect.DeepEqual(got, want) {
+		t.Errorf("errors = %+v\nwant %+v", got, want)
+	}
+}